@@ -7,6 +7,7 @@ import (
 	"log"
 	"log/slog"
 	"math/rand"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,8 +18,16 @@ import (
 	"github.com/Werneck0live/cadastro-empresa/internal/broker"
 	"github.com/Werneck0live/cadastro-empresa/internal/config"
 	"github.com/Werneck0live/cadastro-empresa/internal/db"
+	"github.com/Werneck0live/cadastro-empresa/internal/docs"
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/gql"
 	"github.com/Werneck0live/cadastro-empresa/internal/handlers"
 	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/rpc"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+
+	"google.golang.org/grpc"
 )
 
 // var _ handlers.Publisher = (*NoopPublisher)(nil)
@@ -49,7 +58,20 @@ func main() {
 	defer func() { _ = client.Disconnect(context.Background()) }()
 
 	// repo ANTES do switch (seed precisa dele)
-	repo := repository.NewCompanyRepository(client.Database(cfg.MongoDB))
+	database := client.Database(cfg.MongoDB)
+	repo := repository.NewCompanyRepository(database)
+	idemRepo := repository.NewIdempotencyRepository(database, cfg.IdempotencyTTL)
+	employeeRepo := repository.NewEmployeeRepository(database)
+	contactRepo := repository.NewContactRepository(database)
+	partnerRepo := repository.NewPartnerRepository(database)
+	documentRepo := repository.NewDocumentRepository(database)
+	customFieldRepo := repository.NewCustomFieldRepository(database)
+	mergeRepo := repository.NewMergeRepository(database)
+	ownershipRepo := repository.NewOwnershipRepository(database)
+	noteRepo := repository.NewNoteRepository(database)
+	eventRepo := repository.NewEventRepository(database)
+	changeRequestRepo := repository.NewChangeRequestRepository(database)
+	tx := repository.NewTransactor(client) // fusão de empresas numa transação (com replica set)
 
 	// --- ADMIN TASKS Ex.: rodar as seeds - (rodam e saem)
 	switch *task {
@@ -64,9 +86,108 @@ func main() {
 		slog.Info("seed_done")
 		return
 
-	case "migrate", "index":
-		slog.Info("task_not_implemented", "task", *task)
+	case "index":
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := repo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "companies", "err", err)
+			os.Exit(1)
+		}
+		if err := idemRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "idempotency_keys", "err", err)
+			os.Exit(1)
+		}
+		if err := employeeRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "employees", "err", err)
+			os.Exit(1)
+		}
+		if err := contactRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "contacts", "err", err)
+			os.Exit(1)
+		}
+		if err := partnerRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "partners", "err", err)
+			os.Exit(1)
+		}
+		if err := documentRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "documents.files", "err", err)
+			os.Exit(1)
+		}
+		if err := mergeRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "company_merges", "err", err)
+			os.Exit(1)
+		}
+		if err := ownershipRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "company_ownerships", "err", err)
+			os.Exit(1)
+		}
+		if err := noteRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "notes", "err", err)
+			os.Exit(1)
+		}
+		if err := eventRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "company_events", "err", err)
+			os.Exit(1)
+		}
+		if err := changeRequestRepo.EnsureIndexes(ctx); err != nil {
+			slog.Error("index_error", "collection", "change_requests", "err", err)
+			os.Exit(1)
+		}
+		slog.Info("index_done")
 		return
+
+	case "migrate":
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := repo.EnsureValidator(ctx, schema.CompanyMongoValidator()); err != nil {
+			slog.Error("migrate_error", "collection", "companies", "err", err)
+			os.Exit(1)
+		}
+		slog.Info("migrate_done")
+		return
+	}
+
+	// índice TTL das Idempotency-Keys (sem ele as chaves nunca expiram),
+	// CPF único por empresa nos funcionários, CPF/CNPJ único por empresa no QSA
+	// e $jsonSchema da coleção companies (mesmos schemas da validação HTTP)
+	{
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := idemRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("idempotency_index_error", "err", err)
+		}
+		if err := employeeRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("employees_index_error", "err", err)
+		}
+		if err := contactRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("contacts_index_error", "err", err)
+		}
+		if err := partnerRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("partners_index_error", "err", err)
+		}
+		if err := documentRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("documents_index_error", "err", err)
+		}
+		if err := mergeRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("company_merges_index_error", "err", err)
+		}
+		if err := ownershipRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("company_ownerships_index_error", "err", err)
+		}
+		if err := noteRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("notes_index_error", "err", err)
+		}
+		if err := eventRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("company_events_index_error", "err", err)
+		}
+		if err := changeRequestRepo.EnsureIndexes(ctx); err != nil {
+			slog.Warn("change_requests_index_error", "err", err)
+		}
+		if err := repo.EnsureValidator(ctx, schema.CompanyMongoValidator()); err != nil {
+			slog.Warn("companies_validator_error", "err", err)
+		}
+		cancel()
 	}
 
 	// --- Modo servidor normal: conecta Rabbit com backoff
@@ -75,14 +196,30 @@ func main() {
 		slog.Error("rabbitmq_connect_error", "uri", cfg.RabbitURI, "err", err)
 		os.Exit(1)
 	}
-	defer pub.Close()
+	// o bus repassa os eventos ao Rabbit (gravando antes os da linha do tempo)
+	// e entrega cópias às subscriptions do GraphQL
+	bus := events.NewBus(&events.Log{Next: pub, Store: eventRepo})
+	defer bus.Close()
+
+	h := &handlers.CompanyHandler{Repo: repo, Pub: bus, Employees: employeeRepo, Contacts: contactRepo, Partners: partnerRepo, Documents: documentRepo, Merges: mergeRepo, Ownerships: ownershipRepo, Notes: noteRepo, Events: eventRepo, Tx: tx, ChangeRequests: changeRequestRepo, CustomFields: customFieldRepo, EventLang: cfg.EventLang, PorteThresholds: cfg.PorteThresholds, Approvals: cfg.Approvals, DocumentMaxBytes: cfg.DocumentMaxBytes}
+	idem := &handlers.Idempotency{Store: idemRepo}
 
-	h := &handlers.CompanyHandler{Repo: repo, Pub: pub}
+	// rotas da API registradas uma vez; /api/v1 e /api/v2 são reescritos para elas
+	api := http.NewServeMux()
+	h.Register(api, idem.Wrap)
+	versioning := &handlers.Versioning{V1DeprecatedAt: cfg.APIV1DeprecatedAt, V1Sunset: cfg.APIV1Sunset}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/healthz", h.Health)
-	mux.HandleFunc("/api/companies", h.Companies)
-	mux.HandleFunc("/api/companies/", h.CompanyByID)
+	mux.Handle("/", versioning.Wrap(api))
+	docs.Register(mux) // /openapi.json e /docs
+
+	svc := &service.Companies{Repo: repo, Pub: bus, Employees: employeeRepo, Contacts: contactRepo, Partners: partnerRepo, Documents: documentRepo, Merges: mergeRepo, Ownerships: ownershipRepo, Notes: noteRepo, Events: eventRepo, Tx: tx, ChangeRequests: changeRequestRepo, CustomFields: customFieldRepo, EventLang: cfg.EventLang, PorteThresholds: cfg.PorteThresholds, Approvals: cfg.Approvals}
+	gqlSchema, err := gql.NewSchema(svc, bus)
+	if err != nil {
+		slog.Error("graphql_schema_error", "err", err)
+		os.Exit(1)
+	}
+	(&gql.Handler{Schema: gqlSchema}).Register(mux) // /graphql (HTTP e websocket)
 
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
@@ -97,6 +234,23 @@ func main() {
 		}
 	}()
 
+	// gRPC (porta própria): mesmas regras e eventos da API HTTP
+	var grpcSrv *grpc.Server
+	if cfg.GRPCPort != "off" {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			slog.Error("grpc_listen_error", "port", cfg.GRPCPort, "err", err)
+			os.Exit(1)
+		}
+		grpcSrv = grpc.NewServer()
+		(&rpc.Server{Svc: svc, Bus: bus}).Register(grpcSrv)
+		go func() {
+			if err := grpcSrv.Serve(lis); err != nil {
+				log.Fatalf("grpc server error: %v", err)
+			}
+		}()
+	}
+
 	// graceful shutdown
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -107,6 +261,19 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		slog.Error("graceful_shutdown_error", "err", err)
 	}
+	if grpcSrv != nil {
+		// streams abertos (WatchEvents) não terminam sozinhos: passado o prazo, derruba
+		done := make(chan struct{})
+		go func() {
+			grpcSrv.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			grpcSrv.Stop()
+		}
+	}
 }
 
 func logMiddleware(next http.Handler) http.Handler {
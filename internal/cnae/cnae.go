@@ -0,0 +1,226 @@
+// Package cnae: tabela CNAE 2.3 (IBGE/CONCLA) embutida no binário, usada para
+// validar as atividades econômicas das empresas e nas buscas de /api/cnae.
+//
+// Hierarquia: seção (letra) > divisão (2 dígitos) > grupo > classe (5 dígitos)
+// > subclasse (7 dígitos). As empresas gravam subclasses, só com dígitos.
+package cnae
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:embed cnae.json
+var tableJSON []byte
+
+// Versão da classificação embutida
+var Versao string
+
+// Tipos de entrada da tabela (ordem da hierarquia)
+const (
+	TipoSecao     = "secao"
+	TipoDivisao   = "divisao"
+	TipoClasse    = "classe"
+	TipoSubclasse = "subclasse"
+)
+
+var Tipos = []string{TipoSecao, TipoDivisao, TipoClasse, TipoSubclasse}
+
+// Entry: uma linha da tabela. Codigo vem formatado como no IBGE
+// (ex.: "J", "62", "62.01-5", "6201-5/01"); os demais códigos são os ancestrais.
+type Entry struct {
+	Codigo    string `json:"codigo"`
+	Tipo      string `json:"tipo"`
+	Descricao string `json:"descricao"`
+	Secao     string `json:"secao"`
+	Divisao   string `json:"divisao,omitempty"`
+	Classe    string `json:"classe,omitempty"`
+}
+
+var (
+	entries    []Entry            // seções, divisões, classes e subclasses, nessa ordem
+	byDigits   = map[string]int{} // dígitos (ou letra da seção) -> índice em entries
+	secaoOfDiv = map[string]string{}
+)
+
+func init() {
+	var t struct {
+		Versao string `json:"versao"`
+		Secoes []struct {
+			Codigo    string   `json:"codigo"`
+			Descricao string   `json:"descricao"`
+			Divisoes  []string `json:"divisoes"`
+		} `json:"secoes"`
+		Divisoes   []item `json:"divisoes"`
+		Classes    []item `json:"classes"`
+		Subclasses []item `json:"subclasses"`
+	}
+	if err := json.Unmarshal(tableJSON, &t); err != nil {
+		panic(fmt.Sprintf("cnae: %v", err))
+	}
+	Versao = t.Versao
+
+	add := func(key string, e Entry) {
+		byDigits[key] = len(entries)
+		entries = append(entries, e)
+	}
+	for _, s := range t.Secoes {
+		add(s.Codigo, Entry{Codigo: s.Codigo, Tipo: TipoSecao, Descricao: s.Descricao, Secao: s.Codigo})
+		for _, d := range s.Divisoes {
+			secaoOfDiv[d] = s.Codigo
+		}
+	}
+	for _, d := range t.Divisoes {
+		add(d.Codigo, Entry{Codigo: d.Codigo, Tipo: TipoDivisao, Descricao: d.Descricao, Secao: secaoOfDiv[d.Codigo]})
+	}
+	for _, c := range t.Classes {
+		dig := Sanitize(c.Codigo)
+		add(dig, Entry{Codigo: c.Codigo, Tipo: TipoClasse, Descricao: c.Descricao,
+			Secao: secaoOfDiv[dig[:2]], Divisao: dig[:2]})
+	}
+	for _, s := range t.Subclasses {
+		dig := Sanitize(s.Codigo)
+		add(dig, Entry{Codigo: s.Codigo, Tipo: TipoSubclasse, Descricao: s.Descricao,
+			Secao: secaoOfDiv[dig[:2]], Divisao: dig[:2], Classe: FormatClasse(dig[:5])})
+	}
+}
+
+type item struct {
+	Codigo    string `json:"codigo"`
+	Descricao string `json:"descricao"`
+}
+
+// Sanitize: só os dígitos do código ("6201-5/01" -> "6201501")
+func Sanitize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// FormatSubclasse aplica a máscara 0000-0/00 (entrada com 7 dígitos).
+// Fora do tamanho esperado devolve a entrada sem mudança.
+func FormatSubclasse(d string) string {
+	if len(d) != 7 {
+		return d
+	}
+	return d[:4] + "-" + d[4:5] + "/" + d[5:]
+}
+
+// FormatClasse aplica a máscara 00.00-0 (entrada com 5 dígitos).
+func FormatClasse(d string) string {
+	if len(d) != 5 {
+		return d
+	}
+	return d[:2] + "." + d[2:4] + "-" + d[4:]
+}
+
+// Subclasse devolve a subclasse pelos dígitos (entrada sanitizada).
+func Subclasse(digits string) (Entry, bool) {
+	return lookup(digits, TipoSubclasse)
+}
+
+// ValidSubclasse: o código (com ou sem máscara) é uma subclasse da tabela
+func ValidSubclasse(code string) bool {
+	_, ok := Subclasse(Sanitize(code))
+	return ok
+}
+
+// ValidSecao, ValidDivisao e ValidClasse: usados nos filtros da listagem
+func ValidSecao(s string) bool { _, ok := lookup(strings.ToUpper(s), TipoSecao); return ok }
+
+func ValidDivisao(d string) bool { _, ok := lookup(d, TipoDivisao); return ok }
+
+func ValidClasse(c string) bool { _, ok := lookup(c, TipoClasse); return ok }
+
+// DivisoesDaSecao: divisões (2 dígitos) de uma seção, em ordem
+func DivisoesDaSecao(secao string) []string {
+	secao = strings.ToUpper(secao)
+	var out []string
+	for d, s := range secaoOfDiv {
+		if s == secao {
+			out = append(out, d)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func lookup(key, tipo string) (Entry, bool) {
+	i, ok := byDigits[key]
+	if !ok || entries[i].Tipo != tipo {
+		return Entry{}, false
+	}
+	return entries[i], true
+}
+
+// Search: entradas cujo código começa com q (com ou sem máscara; letra = seção)
+// ou cuja descrição contém todas as palavras de q, sem diferenciar maiúsculas
+// nem acentos. tipo "" = todos. Ordem: hierarquia, depois código.
+func Search(q, tipo string, limit int) []Entry {
+	q = strings.TrimSpace(q)
+	digits := Sanitize(q)
+	codeQuery := q != "" && len(digits) == len(strings.Map(keepCodeChars, q))
+	words := strings.Fields(fold(q))
+
+	out := []Entry{}
+	for _, e := range entries {
+		if tipo != "" && e.Tipo != tipo {
+			continue
+		}
+		if !matches(e, q, digits, codeQuery, words) {
+			continue
+		}
+		out = append(out, e)
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+func matches(e Entry, q, digits string, codeQuery bool, words []string) bool {
+	switch {
+	case q == "":
+		return true
+	case codeQuery: // "62", "6201", "6201-5/01"...
+		return e.Tipo != TipoSecao && strings.HasPrefix(Sanitize(e.Codigo), digits)
+	case len(q) == 1: // letra = só a seção
+		return e.Tipo == TipoSecao && strings.EqualFold(q, e.Codigo)
+	}
+	desc := fold(e.Descricao)
+	for _, w := range words {
+		if !strings.Contains(desc, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// keepCodeChars: descarta a máscara (".", "-", "/") ao decidir se q é um código
+func keepCodeChars(r rune) rune {
+	if r == '.' || r == '-' || r == '/' || r == ' ' {
+		return -1
+	}
+	return r
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e",
+	"í", "i", "ì", "i", "î", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c",
+)
+
+// fold: minúsculas sem acento (comparação das descrições)
+func fold(s string) string {
+	return accents.Replace(strings.ToLower(s))
+}
@@ -0,0 +1,82 @@
+package cnae
+
+/*
+
+go test -run 'TestSearch|TestValid|TestTable' -v ./internal/cnae -count=1
+
+*/
+
+import "testing"
+
+func TestTableConsistency(t *testing.T) {
+	if Versao != "2.3" {
+		t.Fatalf("versao = %q", Versao)
+	}
+	if n := len(Search("", TipoSecao, 0)); n != 21 {
+		t.Fatalf("secoes = %d, want 21", n)
+	}
+	if n := len(Search("", TipoDivisao, 0)); n != 87 {
+		t.Fatalf("divisoes = %d, want 87", n)
+	}
+	for _, e := range entries {
+		if e.Secao == "" {
+			t.Errorf("%s sem seção", e.Codigo)
+		}
+		if e.Tipo == TipoSubclasse && !ValidClasse(Sanitize(e.Classe)) {
+			t.Errorf("%s: classe %s fora da tabela", e.Codigo, e.Classe)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for code, want := range map[string]bool{
+		"6201-5/01": true,
+		"6201501":   true,
+		"62015":     false, // classe, não subclasse
+		"6201-5/09": false,
+		"":          false,
+	} {
+		if got := ValidSubclasse(code); got != want {
+			t.Errorf("ValidSubclasse(%q) = %v, want %v", code, got, want)
+		}
+	}
+	if !ValidSecao("j") || ValidSecao("Z") {
+		t.Error("ValidSecao")
+	}
+	if !ValidDivisao("62") || ValidDivisao("04") {
+		t.Error("ValidDivisao")
+	}
+	if !ValidClasse("62015") || ValidClasse("6201501") {
+		t.Error("ValidClasse")
+	}
+	if got := DivisoesDaSecao("h"); len(got) != 5 || got[0] != "49" || got[4] != "53" {
+		t.Errorf("DivisoesDaSecao(H) = %v", got)
+	}
+	e, ok := Subclasse("6201501")
+	if !ok || e.Secao != "J" || e.Divisao != "62" || e.Classe != "62.01-5" {
+		t.Errorf("Subclasse = %+v", e)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	// código, com ou sem máscara
+	got := Search("6201", "", 0)
+	if len(got) != 3 || got[0].Codigo != "62.01-5" || got[1].Codigo != "6201-5/01" {
+		t.Fatalf("Search(6201) = %+v", got)
+	}
+	if got := Search("6201-5/02", TipoSubclasse, 0); len(got) != 1 || got[0].Descricao != "Web design" {
+		t.Fatalf("Search(6201-5/02) = %+v", got)
+	}
+	// descrição sem acento e sem diferenciar maiúsculas
+	got = Search("INFORMACAO tecnologia", TipoDivisao, 0)
+	if len(got) != 1 || got[0].Codigo != "62" {
+		t.Fatalf("Search(informacao tecnologia) = %+v", got)
+	}
+	// letra = seção
+	if got := Search("j", "", 0); len(got) != 1 || got[0].Tipo != TipoSecao {
+		t.Fatalf("Search(j) = %+v", got)
+	}
+	if got := Search("comércio", "", 2); len(got) != 2 {
+		t.Fatalf("limit: %d", len(got))
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
 )
 
 func getenv(k, def string) string {
@@ -32,6 +34,37 @@ func parseInt(env string, def int) int {
 	return def
 }
 
+func parseFloat(env string, def float64) float64 {
+	if v := os.Getenv(env); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// data no formato 2006-01-02; "off" desliga (time.Time zero)
+func parseDate(env string, def time.Time) time.Time {
+	v := os.Getenv(env)
+	switch v {
+	case "":
+		return def
+	case "off":
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t
+	}
+	return def
+}
+
+func parseLang(env string, def i18n.Lang) i18n.Lang {
+	if lang, ok := i18n.Parse(os.Getenv(env)); ok {
+		return lang
+	}
+	return def
+}
+
 func parseLevel(s string) slog.Level {
 	switch s {
 	case "debug":
@@ -0,0 +1,44 @@
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Especificação OpenAPI mantida à mão junto com os handlers.
+// O teste internal/handlers/openapi_test.go falha se rotas/DTOs e o spec divergirem.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Página de documentação (Redoc) que lê /openapi.json
+//
+//go:embed index.html
+var indexHTML []byte
+
+func Spec() []byte { return openAPISpec }
+
+func Register(mux *http.ServeMux) {
+	mux.HandleFunc("/openapi.json", OpenAPI)
+	mux.HandleFunc("/docs", UI)
+}
+
+func OpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodHead)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+func UI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodHead)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(indexHTML)
+}
@@ -0,0 +1,146 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Bus decora o Publisher da API: repassa cada evento ao broker (Next) e entrega
+// uma cópia aos assinantes locais (ex.: subscriptions do GraphQL).
+// Só enxerga os eventos desta instância da API; com várias réplicas cada uma
+// entrega as mudanças que ela mesma fez.
+type Bus struct {
+	Next Publisher // nil = só fanout local
+
+	mu     sync.RWMutex
+	subs   map[uint64]chan Event
+	nextID uint64
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, body string, headers amqp.Table) error
+	Close() error
+}
+
+// Ações (mesmos valores do header "action" do evento)
+const (
+	ActionCreated = "cadastro"
+	ActionUpdated = "edição"
+	ActionDeleted = "exclusão"
+
+	// Mudança na cota PCD (mínimo exigido ou faixa); corpo em JSON (service.PCDThresholdEvent)
+	ActionPCDThreshold = "cota_pcd"
+
+	// Contatos da empresa (headers com contact_id e contact_nome)
+	ActionContactCreated = "contato_cadastro"
+	ActionContactUpdated = "contato_edição"
+	ActionContactDeleted = "contato_exclusão"
+
+	// Documentos anexados (headers com document_id, document_nome e sha256)
+	ActionDocumentCreated = "documento_cadastro"
+	ActionDocumentDeleted = "documento_exclusão"
+
+	// Fusão de duplicatas (company_id = a que ficou; headers com merged_id e merged_cnpj)
+	ActionMerged = "fusão"
+)
+
+// Event: o evento publicado no broker (texto + headers) já decodificado
+type Event struct {
+	Action    string
+	CompanyID string
+	CNPJ      string
+	Nome      string
+	Lang      string
+	Message   string
+	Timestamp time.Time
+}
+
+func NewBus(next Publisher) *Bus {
+	return &Bus{Next: next, subs: map[uint64]chan Event{}}
+}
+
+func (b *Bus) Publish(ctx context.Context, body string, headers amqp.Table) error {
+	var err error
+	if b.Next != nil {
+		err = b.Next.Publish(ctx, body, headers)
+	}
+	b.fanout(eventFromHeaders(body, headers))
+	return err
+}
+
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	for id, ch := range b.subs {
+		close(ch)
+		delete(b.subs, id)
+	}
+	b.mu.Unlock()
+
+	if b.Next != nil {
+		return b.Next.Close()
+	}
+	return nil
+}
+
+// Subscribe devolve um canal com os próximos eventos e a função que cancela a assinatura.
+// Assinante lento perde eventos (o canal tem buffer e o envio não bloqueia a API).
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	b.mu.Lock()
+	b.nextID++
+	id := b.nextID
+	b.subs[id] = ch
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			if _, ok := b.subs[id]; ok {
+				delete(b.subs, id)
+				close(ch)
+			}
+			b.mu.Unlock()
+		})
+	}
+	return ch, cancel
+}
+
+func (b *Bus) fanout(ev Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+func eventFromHeaders(body string, h amqp.Table) Event {
+	ev := Event{
+		Action:    header(h, "action"),
+		CompanyID: header(h, "company_id"),
+		CNPJ:      header(h, "cnpj"),
+		Nome:      header(h, "nome"),
+		Lang:      header(h, "lang"),
+		Message:   body,
+		Timestamp: time.Now().UTC(),
+	}
+	if ts, err := time.Parse(time.RFC3339, header(h, "timestamp")); err == nil {
+		ev.Timestamp = ts
+	}
+	return ev
+}
+
+func header(h amqp.Table, k string) string {
+	if v, ok := h[k]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+)
+
+// Log decora o Publisher: grava cada evento de empresa (header company_id) no Store
+// para a linha do tempo e repassa ao Next. Falha ao gravar não impede a publicação.
+type Log struct {
+	Next  Publisher // nil = só grava
+	Store EventStore
+}
+
+type EventStore interface {
+	Append(ctx context.Context, ev *models.CompanyEvent) error
+}
+
+// headers que já viram campos do CompanyEvent
+var logFields = map[string]bool{"action": true, "company_id": true, "timestamp": true}
+
+func (l *Log) Publish(ctx context.Context, body string, headers amqp.Table) error {
+	if id := header(headers, "company_id"); id != "" && l.Store != nil {
+		ev := models.CompanyEvent{
+			CompanyID: id,
+			Action:    header(headers, "action"),
+			Message:   body,
+			// hora da gravação (o header timestamp só tem segundos e desempata mal com as notas)
+			Timestamp: time.Now().UTC(),
+		}
+		for k := range headers {
+			if v := header(headers, k); v != "" && !logFields[k] {
+				if ev.Headers == nil {
+					ev.Headers = map[string]string{}
+				}
+				ev.Headers[k] = v
+			}
+		}
+		if err := l.Store.Append(ctx, &ev); err != nil {
+			slog.Warn("event_log_error", "company_id", id, "action", ev.Action, "err", err)
+		}
+	}
+	if l.Next != nil {
+		return l.Next.Publish(ctx, body, headers)
+	}
+	return nil
+}
+
+func (l *Log) Close() error {
+	if l.Next != nil {
+		return l.Next.Close()
+	}
+	return nil
+}
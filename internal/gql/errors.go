@@ -0,0 +1,116 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Erros das resolvers no formato GraphQL:
+//
+//	{"message": "...", "extensions": {"code": "validation_failed", "errors": [{field, code, message}]}}
+//
+// code e errors[] são os mesmos da API REST (RFC 7807); os textos seguem o Accept-Language.
+type Error struct {
+	Code   string
+	Msg    string
+	Fields []utils.FieldError
+	Extra  map[string]any // outras extensions (ex.: change_request_id)
+}
+
+func (e *Error) Error() string { return e.Msg }
+
+func (e *Error) Extensions() map[string]any {
+	ext := map[string]any{"code": e.Code}
+	if len(e.Fields) > 0 {
+		ext["errors"] = e.Fields
+	}
+	for k, v := range e.Extra {
+		ext[k] = v
+	}
+	return ext
+}
+
+var errSubscriptionsDisabled = errors.New("subscriptions disabled")
+
+type langKey struct{}
+
+func withLang(ctx context.Context, lang i18n.Lang) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+func langFrom(ctx context.Context) i18n.Lang {
+	if l, ok := ctx.Value(langKey{}).(i18n.Lang); ok {
+		return l
+	}
+	return i18n.DefaultHTTPLang
+}
+
+// texto padrão do code (detail do catálogo; sem ele, o title); cause != nil usa a mensagem dele
+func newError(ctx context.Context, code string, cause error) *Error {
+	if cause != nil {
+		return &Error{Code: code, Msg: cause.Error()}
+	}
+	lang := langFrom(ctx)
+	msg := i18n.T(lang, "problem."+code+".detail")
+	if msg == "problem."+code+".detail" {
+		msg = i18n.T(lang, "problem."+code)
+	}
+	return &Error{Code: code, Msg: msg}
+}
+
+func validationError(ctx context.Context, errs []utils.FieldError) *Error {
+	lang := langFrom(ctx)
+	out := make([]utils.FieldError, len(errs))
+	for i, e := range errs {
+		e.Field = graphQLField(e.Field)
+		if e.Message == "" {
+			e.Message = i18n.T(lang, "field."+e.Code, append([]any{e.Field}, e.Args...)...)
+		}
+		out[i] = e
+	}
+	err := newError(ctx, utils.CodeValidationFailed, nil)
+	err.Fields = out
+	return err
+}
+
+func serviceError(ctx context.Context, err error) *Error {
+	var cf *service.CustomFieldsError
+	var pending *service.ApprovalRequiredError
+	switch {
+	case errors.As(err, &pending):
+		// a mudança não foi aplicada: virou um pedido de aprovação (/api/change-requests)
+		e := newError(ctx, utils.CodeApprovalRequired, nil)
+		e.Extra = map[string]any{"change_request_id": pending.Request.ID}
+		return e
+	case errors.Is(err, service.ErrUserRequired):
+		return validationError(ctx, []utils.FieldError{{Field: "X-User", Code: utils.FieldRequired}})
+	case errors.Is(err, service.ErrNotFound):
+		return newError(ctx, utils.CodeNotFound, nil)
+	case errors.Is(err, repository.ErrDuplicateCNPJ):
+		return newError(ctx, utils.CodeCNPJConflict, nil)
+	case errors.As(err, &cf):
+		return validationError(ctx, cf.Errors)
+	default:
+		return newError(ctx, utils.CodeInternalError, err)
+	}
+}
+
+// nome_fantasia -> nomeFantasia (os paths de errors[] vêm do JSON Schema)
+func graphQLField(path string) string {
+	parts := strings.Split(path, ".")
+	for i, p := range parts {
+		for gql, snake := range inputFields {
+			if p == snake {
+				parts[i] = gql
+				break
+			}
+		}
+	}
+	return strings.Join(parts, ".")
+}
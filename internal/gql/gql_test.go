@@ -0,0 +1,466 @@
+package gql
+
+/*
+
+go test -v ./internal/gql -count=1
+
+*/
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+const (
+	validCNPJ = "11.222.333/0001-81"
+	companyID = "11222333000181"
+)
+
+// repositório em memória (só o necessário para os testes)
+type memRepo struct {
+	mu       sync.Mutex
+	docs     map[string]*models.Company
+	lastFind *models.CompanyFilter
+}
+
+func newMemRepo(cs ...models.Company) *memRepo {
+	r := &memRepo{docs: map[string]*models.Company{}}
+	for i := range cs {
+		c := cs[i]
+		r.docs[c.ID] = &c
+	}
+	return r
+}
+
+func (r *memRepo) GetAll(ctx context.Context, limit, skip int64) ([]models.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.Company
+	for _, c := range r.docs {
+		out = append(out, *c)
+	}
+	return out, nil
+}
+
+func (r *memRepo) Find(ctx context.Context, f models.CompanyFilter, limit, skip int64) ([]models.Company, error) {
+	r.mu.Lock()
+	r.lastFind = &f
+	r.mu.Unlock()
+	return r.GetAll(ctx, limit, skip)
+}
+
+func (r *memRepo) Create(ctx context.Context, c *models.Company) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.docs[c.ID]; ok {
+		return "", repository.ErrDuplicateCNPJ
+	}
+	cp := *c
+	r.docs[c.ID] = &cp
+	return c.ID, nil
+}
+
+func (r *memRepo) GetByID(ctx context.Context, id string) (*models.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.docs[id]
+	if !ok {
+		return nil, repository.ErrCompanyNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+// FindDuplicateCandidates: sem o pré-filtro (todas menos a própria)
+func (r *memRepo) FindDuplicateCandidates(ctx context.Context, q models.DuplicateQuery, limit int64) ([]models.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.Company{}
+	for id, c := range r.docs {
+		if id != q.ExcludeID && int64(len(out)) < limit {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) GetMany(ctx context.Context, ids []string) ([]models.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.Company{}
+	for _, id := range ids {
+		if c, ok := r.docs[id]; ok {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) Update(ctx context.Context, id string, upd *models.Company, always ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.docs[id] = service.PatchedCompany(r.docs[id], upd, always...)
+	return nil
+}
+
+func (r *memRepo) Replace(ctx context.Context, id string, doc *models.Company) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *doc
+	r.docs[id] = &cp
+	return nil
+}
+
+func (r *memRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.docs, id)
+	return nil
+}
+
+func (r *memRepo) Stats(ctx context.Context, f models.CompanyFilter, groupBy []string) (*models.CompanyStats, error) {
+	return nil, errors.New("not implemented")
+}
+
+func seedCompany() models.Company {
+	return models.Company{
+		ID: companyID, CNPJ: companyID, NomeFantasia: "ACME", RazaoSocial: "ACME LTDA",
+		Endereco: "Rua A, 1", NumeroFuncionarios: 10,
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func newTestHandler(t *testing.T, repo *memRepo) (*Handler, *events.Bus) {
+	t.Helper()
+	bus := events.NewBus(nil)
+	s, err := NewSchema(&service.Companies{Repo: repo, Pub: bus}, bus)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	return &Handler{Schema: s}, bus
+}
+
+type gqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code   string             `json:"code"`
+			Errors []utils.FieldError `json:"errors"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, h http.Handler, query string, vars map[string]any) (*httptest.ResponseRecorder, gqlResponse) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": vars})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	var out gqlResponse
+	if rr.Code == http.StatusOK {
+		if err := json.Unmarshal(rr.Body.Bytes(), &out); err != nil {
+			t.Fatalf("decode: %v body=%s", err, rr.Body.String())
+		}
+	}
+	return rr, out
+}
+
+func TestGraphQL_Company(t *testing.T) {
+	h, _ := newTestHandler(t, newMemRepo(seedCompany()))
+
+	_, res := post(t, h, `query($id: ID!) { company(id: $id) { id cnpjFormatado nomeFantasia createdAt } missing: company(id: "x") { id } }`,
+		map[string]any{"id": companyID})
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors: %+v", res.Errors)
+	}
+	var c struct {
+		ID            string `json:"id"`
+		CNPJFormatado string `json:"cnpjFormatado"`
+		NomeFantasia  string `json:"nomeFantasia"`
+		CreatedAt     string `json:"createdAt"`
+	}
+	_ = json.Unmarshal(res.Data["company"], &c)
+	if c.ID != companyID || c.CNPJFormatado != validCNPJ || c.NomeFantasia != "ACME" || c.CreatedAt != "2025-01-02T03:04:05Z" {
+		t.Fatalf("company = %+v", c)
+	}
+	if string(res.Data["missing"]) != "null" {
+		t.Fatalf("missing = %s, want null", res.Data["missing"])
+	}
+}
+
+func TestGraphQL_Companies_Filter(t *testing.T) {
+	repo := newMemRepo(seedCompany())
+	h, _ := newTestHandler(t, repo)
+
+	_, res := post(t, h, `{ companies(filter: {nome: "acme", cnpjPrefix: "11.222", minFuncionarios: 5}) { id } }`, nil)
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors: %+v", res.Errors)
+	}
+	f := repo.lastFind
+	if f == nil {
+		t.Fatal("Find não foi chamado")
+	}
+	if f.Nome != "acme" || f.CNPJPrefix != "11222" || f.MinFuncionarios == nil || *f.MinFuncionarios != 5 || f.MaxFuncionarios != nil {
+		t.Fatalf("filter = %+v", f)
+	}
+}
+
+func TestGraphQL_CreateCompany(t *testing.T) {
+	repo := newMemRepo()
+	h, bus := newTestHandler(t, repo)
+	evs, cancel := bus.Subscribe(1)
+	defer cancel()
+
+	_, res := post(t, h, `mutation($in: CompanyCreateInput!) { createCompany(input: $in) { id endereco enderecoEstruturado { uf cep } } }`,
+		map[string]any{"in": map[string]any{
+			"cnpj": validCNPJ, "nomeFantasia": "ACME", "razaoSocial": "ACME LTDA", "numeroFuncionarios": 10,
+			"endereco": map[string]any{"logradouro": "Rua A", "numero": "1", "uf": "SP", "cep": "01000-000"},
+		}})
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors: %+v", res.Errors)
+	}
+	if _, err := repo.GetByID(context.Background(), companyID); err != nil {
+		t.Fatal("empresa não foi gravada")
+	}
+	if !strings.Contains(string(res.Data["createCompany"]), `"cep":"01000000"`) {
+		t.Fatalf("createCompany = %s", res.Data["createCompany"])
+	}
+
+	select {
+	case ev := <-evs:
+		if ev.Action != events.ActionCreated || ev.CompanyID != companyID {
+			t.Fatalf("event = %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("evento não publicado")
+	}
+}
+
+func TestGraphQL_CreateCompany_ValidationError(t *testing.T) {
+	h, _ := newTestHandler(t, newMemRepo())
+
+	_, res := post(t, h, `mutation { createCompany(input: {cnpj: "123", nomeFantasia: ""}) { id } }`, nil)
+	if len(res.Errors) != 1 {
+		t.Fatalf("errors = %+v", res.Errors)
+	}
+	e := res.Errors[0]
+	if e.Extensions.Code != utils.CodeValidationFailed {
+		t.Fatalf("code = %q", e.Extensions.Code)
+	}
+	fields := map[string]bool{}
+	for _, fe := range e.Extensions.Errors {
+		fields[fe.Field] = true
+	}
+	if !fields["cnpj"] || !fields["nomeFantasia"] {
+		t.Fatalf("fields = %+v", e.Extensions.Errors)
+	}
+}
+
+func TestGraphQL_Mutations_NotFoundAndConflict(t *testing.T) {
+	h, _ := newTestHandler(t, newMemRepo(seedCompany()))
+
+	_, res := post(t, h, `mutation { deleteCompany(id: "00000000000000") { id } }`, nil)
+	if len(res.Errors) != 1 || res.Errors[0].Extensions.Code != utils.CodeNotFound {
+		t.Fatalf("delete errors = %+v", res.Errors)
+	}
+
+	_, res = post(t, h, `mutation { createCompany(input: {cnpj: "`+validCNPJ+`", nomeFantasia: "X", razaoSocial: "X LTDA", numeroFuncionarios: 1, endereco: {logradouro: "Rua B"}}) { id } }`, nil)
+	if len(res.Errors) != 1 || res.Errors[0].Extensions.Code != utils.CodeCNPJConflict {
+		t.Fatalf("create errors = %+v", res.Errors)
+	}
+
+	_, res = post(t, h, `mutation { replaceCompany(id: "`+companyID+`", input: {cnpj: "00.000.000/0001-91", nomeFantasia: "X", razaoSocial: "X LTDA", numeroFuncionarios: 1, endereco: {logradouro: "Rua B"}}) { id } }`, nil)
+	if len(res.Errors) != 1 || res.Errors[0].Extensions.Code != utils.CodeValidationFailed {
+		t.Fatalf("replace errors = %+v", res.Errors)
+	}
+}
+
+// o input só tem os campos básicos: o replace não pode apagar os demais
+func TestGraphQL_ReplaceCompany_KeepsExtras(t *testing.T) {
+	seed := seedCompany()
+	pcd, fat := 3, 5_000_000.0
+	seed.NumeroPCDContratados, seed.FaturamentoAnual = &pcd, &fat
+	seed.CNAEPrincipal, seed.CNAESecundarios = "6201501", []string{"6202300"}
+	seed.InscricaoEstadual, seed.InscricaoEstadualUF, seed.RegimeTributario = "110042490114", "SP", "simples_nacional"
+	seed.Tags, seed.CustomFields = []string{"cliente"}, map[string]any{"gerente": "Ana"}
+	repo := newMemRepo(seed)
+	h, _ := newTestHandler(t, repo)
+
+	_, res := post(t, h, `mutation { replaceCompany(id: "`+companyID+`", input: {nomeFantasia: "Nova", razaoSocial: "Nova LTDA", numeroFuncionarios: 10, endereco: {logradouro: "Rua B"}})
+		{ nomeFantasia numeroPcdContratados cnaePrincipal cnaesSecundarios inscricaoEstadual regimeTributario faturamentoAnual tags customFields } }`, nil)
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors: %+v", res.Errors)
+	}
+	want := `{"cnaePrincipal":"6201501","cnaesSecundarios":["6202300"],"customFields":"{\"gerente\":\"Ana\"}","faturamentoAnual":5000000,` +
+		`"inscricaoEstadual":"110042490114","nomeFantasia":"Nova","numeroPcdContratados":3,"regimeTributario":"simples_nacional","tags":["cliente"]}`
+	if got := string(res.Data["replaceCompany"]); got != want {
+		t.Fatalf("replaceCompany = %s\nwant %s", got, want)
+	}
+	if c := repo.docs[companyID]; c.NomeFantasia != "Nova" || c.InscricaoEstadualUF != "SP" || c.CustomFields["gerente"] != "Ana" {
+		t.Fatalf("gravada = %+v", c)
+	}
+}
+
+func TestGraphQL_HTTPMethods(t *testing.T) {
+	h, _ := newTestHandler(t, newMemRepo(seedCompany()))
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query="+`mutation{deleteCompany(id:"1"){id}}`, nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET mutation status = %d", rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/graphql?query="+`{company(id:"`+companyID+`"){id}}`, nil)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), companyID) {
+		t.Fatalf("GET query status = %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr, _ = post(t, h, "  ", nil)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("empty query status = %d", rr.Code)
+	}
+}
+
+func TestGraphQL_SubscriptionOverWebSocket(t *testing.T) {
+	repo := newMemRepo(seedCompany())
+	h, _ := newTestHandler(t, repo)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{wsProtocol}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/graphql", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	mustWrite := func(m wsMessage) {
+		if err := conn.WriteJSON(m); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	mustRead := func(wantType string) wsMessage {
+		var m wsMessage
+		if err := conn.ReadJSON(&m); err != nil {
+			t.Fatalf("read (%s): %v", wantType, err)
+		}
+		if m.Type != wantType {
+			t.Fatalf("type = %q (%s), want %q", m.Type, m.Payload, wantType)
+		}
+		return m
+	}
+
+	mustWrite(wsMessage{Type: msgConnectionInit})
+	mustRead(msgConnectionAck)
+
+	sub, _ := json.Marshal(Request{Query: `subscription { companyChanged(actions: [UPDATED]) { action companyId company { numeroFuncionarios } } }`})
+	mustWrite(wsMessage{ID: "s1", Type: msgSubscribe, Payload: sub})
+
+	// a assinatura é registrada em background: repete a mutation até o evento chegar
+	mut, _ := json.Marshal(Request{Query: `mutation { patchCompany(id: "` + companyID + `", input: {numeroFuncionarios: 250}) { id } }`})
+	var ev wsMessage
+	for i := 0; ev.ID != "s1"; i++ {
+		if i == 20 {
+			t.Fatal("evento não recebido")
+		}
+		id := "m" + string(rune('a'+i))
+		mustWrite(wsMessage{ID: id, Type: msgSubscribe, Payload: mut})
+		for {
+			var m wsMessage
+			if err := conn.ReadJSON(&m); err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			// lê até o fim da mutation: next/complete dela não podem sobrar para o ping
+			if m.ID == "s1" && m.Type == msgNext && ev.ID == "" {
+				ev = m
+			}
+			if m.ID == id && m.Type == msgComplete {
+				break
+			}
+		}
+		if ev.ID == "" {
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	var payload struct {
+		Data struct {
+			CompanyChanged struct {
+				Action    string `json:"action"`
+				CompanyID string `json:"companyId"`
+				Company   struct {
+					NumeroFuncionarios int `json:"numeroFuncionarios"`
+				} `json:"company"`
+			} `json:"companyChanged"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	got := payload.Data.CompanyChanged
+	if got.Action != "UPDATED" || got.CompanyID != companyID || got.Company.NumeroFuncionarios != 250 {
+		t.Fatalf("event = %+v", got)
+	}
+
+	mustWrite(wsMessage{ID: "s1", Type: msgComplete})
+	mustWrite(wsMessage{Type: msgPing})
+	// eventos de mutations anteriores ainda podem estar a caminho
+	for {
+		var m wsMessage
+		if err := conn.ReadJSON(&m); err != nil {
+			t.Fatalf("read (pong): %v", err)
+		}
+		if m.ID == "s1" {
+			continue
+		}
+		if m.Type != msgPong {
+			t.Fatalf("type = %q (%s), want %q", m.Type, m.Payload, msgPong)
+		}
+		break
+	}
+}
+
+func TestGraphQL_WebSocket_RequiresInit(t *testing.T) {
+	h, _ := newTestHandler(t, newMemRepo())
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{wsProtocol}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/graphql", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	sub, _ := json.Marshal(Request{Query: `{ companies { id } }`})
+	_ = conn.WriteJSON(wsMessage{ID: "1", Type: msgSubscribe, Payload: sub})
+
+	_, _, err = conn.ReadMessage()
+	var ce *websocket.CloseError
+	if !errors.As(err, &ce) || ce.Code != closeUnauthorized {
+		t.Fatalf("err = %v, want close %d", err, closeUnauthorized)
+	}
+}
@@ -0,0 +1,95 @@
+package gql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+const maxRequestBytes = 1 << 20
+
+// Handler atende /graphql:
+//   - POST application/json {"query", "variables", "operationName"}: queries e mutations
+//   - GET ?query=...: só queries (mutations via GET são recusadas)
+//   - GET com Upgrade: websocket (protocolo graphql-transport-ws): subscriptions, queries e mutations
+type Handler struct {
+	Schema graphql.Schema
+
+	// Origens aceitas no websocket; vazio = qualquer uma
+	AllowedOrigins []string
+}
+
+// Request: corpo do POST /graphql (e payload do "subscribe" no websocket)
+type Request struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.Handle("/graphql", h)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := withLang(r.Context(), i18n.FromRequest(r))
+	// usuário das mutations que podem exigir aprovação (maker-checker)
+	ctx = service.WithUser(ctx, strings.TrimSpace(r.Header.Get("X-User")))
+
+	var req Request
+	switch r.Method {
+	case http.MethodGet:
+		if isWebSocketUpgrade(r) {
+			h.serveWS(w, r.WithContext(ctx))
+			return
+		}
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				utils.InvalidJSON(w, r, err)
+				return
+			}
+		}
+		if op := operationType(req.Query, req.OperationName); op != "" && op != "query" {
+			utils.MethodNotAllowed(w, r, http.MethodPost)
+			return
+		}
+
+	case http.MethodPost:
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			utils.InvalidJSON(w, r, err)
+			return
+		}
+
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+		return
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		utils.BadRequest(w, r, "detail.graphql_query_required")
+		return
+	}
+
+	// Erros de GraphQL (validação, resolvers) vão em "errors" com status 200, como manda a especificação
+	res := graphql.Do(graphql.Params{
+		Schema:         h.Schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+	utils.WriteJSON(w, http.StatusOK, res)
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
@@ -0,0 +1,528 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Schema GraphQL do cadastro. Mesmas regras da API REST (service.Companies)
+// e mesmos JSON Schemas da /api/v2 para validar os inputs.
+// Nomes em camelCase (convenção GraphQL); o endereço é sempre o estruturado.
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+var addressType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Address",
+	Fields: graphql.Fields{
+		"logradouro":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"numero":      &graphql.Field{Type: graphql.String},
+		"complemento": &graphql.Field{Type: graphql.String},
+		"bairro":      &graphql.Field{Type: graphql.String},
+		"municipio":   &graphql.Field{Type: graphql.String},
+		"uf":          &graphql.Field{Type: graphql.String},
+		"cep":         &graphql.Field{Type: graphql.String, Description: "Apenas dígitos"},
+	},
+})
+
+func company(p graphql.ResolveParams) *models.Company {
+	c, _ := p.Source.(*models.Company)
+	return c
+}
+
+func timeField(get func(*models.Company) time.Time) *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.String,
+		Description: "RFC 3339",
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			if t := get(company(p)); !t.IsZero() {
+				return t.UTC().Format(time.RFC3339), nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+var companyType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Company",
+	Fields: graphql.Fields{
+		"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"cnpj": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Apenas dígitos"},
+		"cnpjFormatado": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return utils.FormatCNPJ(company(p).CNPJ), nil
+			},
+		},
+		"nomeFantasia": &graphql.Field{Type: graphql.String},
+		"razaoSocial":  &graphql.Field{Type: graphql.String},
+		"endereco":     &graphql.Field{Type: graphql.String, Description: "Endereço em texto (o mesmo da API v1)"},
+		"enderecoEstruturado": &graphql.Field{
+			Type: addressType,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if a := company(p).EnderecoEstruturado; a != nil {
+					return a, nil
+				}
+				return nil, nil
+			},
+		},
+		"numeroFuncionarios":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"numeroMinimoPcdExigidos": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"numeroPcdContratados": &graphql.Field{
+			Type: graphql.Int,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if n := company(p).NumeroPCDContratados; n != nil {
+					return *n, nil
+				}
+				return nil, nil
+			},
+		},
+		"cnaePrincipal": &graphql.Field{Type: graphql.String, Description: "Subclasse CNAE 2.3, apenas dígitos"},
+		"cnaesSecundarios": &graphql.Field{
+			Type: graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return company(p).CNAESecundarios, nil
+			},
+		},
+		"inscricaoEstadual":   &graphql.Field{Type: graphql.String},
+		"inscricaoEstadualUf": &graphql.Field{Type: graphql.String},
+		"inscricaoMunicipal":  &graphql.Field{Type: graphql.String},
+		"regimeTributario":    &graphql.Field{Type: graphql.String},
+		"faturamentoAnual": &graphql.Field{
+			Type: graphql.Float,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if f := company(p).FaturamentoAnual; f != nil {
+					return *f, nil
+				}
+				return nil, nil
+			},
+		},
+		"porte": &graphql.Field{Type: graphql.String},
+		"tags":  &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"customFields": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Objeto JSON (chave -> valor), como o custom_fields da REST",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				fields := company(p).CustomFields
+				if len(fields) == 0 {
+					return nil, nil
+				}
+				b, err := json.Marshal(fields)
+				return string(b), err
+			},
+		},
+		"createdAt": timeField(func(c *models.Company) time.Time { return c.CreatedAt }),
+		"updatedAt": timeField(func(c *models.Company) time.Time { return c.UpdatedAt }),
+	},
+})
+
+var addressInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "AddressInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"logradouro":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"numero":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"complemento": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"bairro":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"municipio":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"uf":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"cep":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+func companyInput(name string, cnpj graphql.Input) *graphql.InputObject {
+	return graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: name,
+		Fields: graphql.InputObjectConfigFieldMap{
+			"cnpj":               &graphql.InputObjectFieldConfig{Type: cnpj},
+			"nomeFantasia":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"razaoSocial":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"endereco":           &graphql.InputObjectFieldConfig{Type: addressInput},
+			"numeroFuncionarios": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+}
+
+var (
+	companyCreateInput = companyInput("CompanyCreateInput", graphql.NewNonNull(graphql.String))
+	companyPatchInput  = companyInput("CompanyPatchInput", graphql.String)
+	companyPutInput    = companyInput("CompanyPutInput", graphql.String)
+)
+
+var companyFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CompanyFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"nome":            &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Trecho do nome fantasia ou da razão social"},
+		"cnpjPrefix":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"uf":              &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"minFuncionarios": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"maxFuncionarios": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+	},
+})
+
+// ações com valor em CompanyAction (as demais não chegam às subscriptions)
+var companyActions = map[string]bool{events.ActionCreated: true, events.ActionUpdated: true, events.ActionDeleted: true}
+
+var companyActionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "CompanyAction",
+	Values: graphql.EnumValueConfigMap{
+		"CREATED": &graphql.EnumValueConfig{Value: events.ActionCreated},
+		"UPDATED": &graphql.EnumValueConfig{Value: events.ActionUpdated},
+		"DELETED": &graphql.EnumValueConfig{Value: events.ActionDeleted},
+	},
+})
+
+// NewSchema monta o schema. bus == nil desliga as subscriptions (o campo existe, mas falha).
+func NewSchema(svc *service.Companies, bus *events.Bus) (graphql.Schema, error) {
+	r := &resolver{svc: svc, bus: bus}
+
+	eventType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CompanyEvent",
+		Fields: graphql.Fields{
+			"action":    &graphql.Field{Type: graphql.NewNonNull(companyActionEnum)},
+			"companyId": &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"cnpj":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"nome":      &graphql.Field{Type: graphql.String},
+			"message":   &graphql.Field{Type: graphql.String, Description: "Texto do evento (o mesmo publicado no RabbitMQ)"},
+			"lang":      &graphql.Field{Type: graphql.String},
+			"timestamp": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(events.Event).Timestamp.Format(time.RFC3339), nil
+				},
+			},
+			"company": &graphql.Field{
+				Type:        companyType,
+				Description: "Estado atual da empresa (null na exclusão)",
+				Resolve:     r.eventCompany,
+			},
+		},
+	})
+
+	pageArgs := graphql.FieldConfigArgument{
+		"filter": &graphql.ArgumentConfig{Type: companyFilterInput},
+		"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultLimit, Description: "1-200"},
+		"skip":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+	}
+	idArg := &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"company": &graphql.Field{
+				Type:    companyType,
+				Args:    graphql.FieldConfigArgument{"id": idArg},
+				Resolve: r.company,
+			},
+			"companies": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(companyType))),
+				Args:    pageArgs,
+				Resolve: r.companies,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createCompany": &graphql.Field{
+				Type:    graphql.NewNonNull(companyType),
+				Args:    graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(companyCreateInput)}},
+				Resolve: r.createCompany,
+			},
+			"patchCompany": &graphql.Field{
+				Type: companyType,
+				Args: graphql.FieldConfigArgument{
+					"id":    idArg,
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(companyPatchInput)},
+				},
+				Resolve: r.patchCompany,
+			},
+			"replaceCompany": &graphql.Field{
+				Type: graphql.NewNonNull(companyType),
+				Args: graphql.FieldConfigArgument{
+					"id":    idArg,
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(companyPutInput)},
+				},
+				Resolve: r.replaceCompany,
+			},
+			"deleteCompany": &graphql.Field{
+				Type:        graphql.NewNonNull(companyType),
+				Description: "Devolve a empresa removida",
+				Args:        graphql.FieldConfigArgument{"id": idArg},
+				Resolve:     r.deleteCompany,
+			},
+		},
+	})
+
+	subscription := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"companyChanged": &graphql.Field{
+				Type: graphql.NewNonNull(eventType),
+				Args: graphql.FieldConfigArgument{
+					"actions":   &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(companyActionEnum))},
+					"companyId": &graphql.ArgumentConfig{Type: graphql.ID},
+				},
+				Subscribe: r.subscribeCompanyChanged,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:        query,
+		Mutation:     mutation,
+		Subscription: subscription,
+	})
+}
+
+type resolver struct {
+	svc *service.Companies
+	bus *events.Bus
+}
+
+func (r *resolver) company(p graphql.ResolveParams) (any, error) {
+	c, err := r.svc.Get(p.Context, p.Args["id"].(string))
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, nil // query: ausente = null (mutations devolvem erro not_found)
+	}
+	return c, err
+}
+
+func (r *resolver) companies(p graphql.ResolveParams) (any, error) {
+	limit, _ := p.Args["limit"].(int)
+	skip, _ := p.Args["skip"].(int)
+	if limit <= 0 || limit > maxLimit {
+		limit = defaultLimit
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	var f models.CompanyFilter
+	if in, ok := p.Args["filter"].(map[string]any); ok {
+		f.Nome, _ = in["nome"].(string)
+		f.CNPJPrefix, _ = in["cnpjPrefix"].(string)
+		f.CNPJPrefix = utils.SanitizeCNPJ(f.CNPJPrefix)
+		f.UF, _ = in["uf"].(string)
+		if v, ok := in["minFuncionarios"].(int); ok {
+			f.MinFuncionarios = &v
+		}
+		if v, ok := in["maxFuncionarios"].(int); ok {
+			f.MaxFuncionarios = &v
+		}
+	}
+
+	list, err := r.svc.List(p.Context, f, int64(limit), int64(skip))
+	if err != nil {
+		return nil, newError(p.Context, utils.CodeInternalError, err)
+	}
+	out := make([]*models.Company, len(list))
+	for i := range list {
+		out[i] = &list[i]
+	}
+	return out, nil
+}
+
+func (r *resolver) createCompany(p graphql.ResolveParams) (any, error) {
+	in, err := decodeInput(p.Context, schema.CompanyCreateV2, p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.svc.Create(p.Context, service.CompanyInput{
+		CNPJ:                deref(in.CNPJ),
+		NomeFantasia:        deref(in.NomeFantasia),
+		RazaoSocial:         deref(in.RazaoSocial),
+		EnderecoEstruturado: in.Endereco,
+		NumeroFuncionarios:  deref(in.NumeroFuncionarios),
+	})
+	if err != nil {
+		return nil, serviceError(p.Context, err)
+	}
+	return c, nil
+}
+
+func (r *resolver) patchCompany(p graphql.ResolveParams) (any, error) {
+	in, err := decodeInput(p.Context, schema.CompanyPatchV2, p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.svc.Patch(p.Context, p.Args["id"].(string), service.CompanyPatch{
+		CNPJ:                in.CNPJ,
+		NomeFantasia:        in.NomeFantasia,
+		RazaoSocial:         in.RazaoSocial,
+		EnderecoEstruturado: in.Endereco,
+		NumeroFuncionarios:  in.NumeroFuncionarios,
+	})
+	if err != nil {
+		return nil, serviceError(p.Context, err)
+	}
+	if c == nil {
+		return nil, nil
+	}
+	return c, nil
+}
+
+func (r *resolver) replaceCompany(p graphql.ResolveParams) (any, error) {
+	id := p.Args["id"].(string)
+	in, err := decodeInput(p.Context, schema.CompanyPutV2, p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+	// mesma regra do PUT: cnpj, se vier, deve ser o id
+	if in.CNPJ != nil && utils.SanitizeCNPJ(*in.CNPJ) != id {
+		return nil, validationError(p.Context, []utils.FieldError{{Field: "cnpj", Code: utils.FieldMismatch}})
+	}
+	c, err := r.svc.Replace(p.Context, id, service.CompanyInput{
+		NomeFantasia:        deref(in.NomeFantasia),
+		RazaoSocial:         deref(in.RazaoSocial),
+		EnderecoEstruturado: in.Endereco,
+		NumeroFuncionarios:  deref(in.NumeroFuncionarios),
+		KeepExtras:          true, // CNAEs, IE/IM, tags etc. não estão no input
+	})
+	if err != nil {
+		return nil, serviceError(p.Context, err)
+	}
+	return c, nil
+}
+
+func (r *resolver) deleteCompany(p graphql.ResolveParams) (any, error) {
+	c, err := r.svc.Delete(p.Context, p.Args["id"].(string))
+	if err != nil {
+		return nil, serviceError(p.Context, err)
+	}
+	return c, nil
+}
+
+// ---------- subscriptions
+
+func (r *resolver) subscribeCompanyChanged(p graphql.ResolveParams) (any, error) {
+	if r.bus == nil {
+		return nil, newError(p.Context, utils.CodeInternalError, errSubscriptionsDisabled)
+	}
+
+	actions := map[string]bool{}
+	if list, ok := p.Args["actions"].([]any); ok {
+		for _, a := range list {
+			if s, ok := a.(string); ok {
+				actions[s] = true
+			}
+		}
+	}
+	companyID, _ := p.Args["companyId"].(string)
+
+	evs, cancel := r.bus.Subscribe(64)
+	out := make(chan any)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for {
+			select {
+			case <-p.Context.Done():
+				return
+			case ev, ok := <-evs:
+				if !ok {
+					return
+				}
+				if !companyActions[ev.Action] {
+					continue // ex.: cota_pcd, sem valor em CompanyAction
+				}
+				if len(actions) > 0 && !actions[ev.Action] {
+					continue
+				}
+				if companyID != "" && ev.CompanyID != companyID {
+					continue
+				}
+				select {
+				case out <- ev:
+				case <-p.Context.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (r *resolver) eventCompany(p graphql.ResolveParams) (any, error) {
+	ev := p.Source.(events.Event)
+	if ev.Action == events.ActionDeleted {
+		return nil, nil
+	}
+	c, err := r.svc.Get(p.Context, ev.CompanyID)
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ---------- inputs
+
+// payload no formato JSON da /api/v2 (snake_case), para reaproveitar os JSON Schemas
+type companyPayload struct {
+	CNPJ               *string         `json:"cnpj"`
+	NomeFantasia       *string         `json:"nome_fantasia"`
+	RazaoSocial        *string         `json:"razao_social"`
+	Endereco           *models.Address `json:"endereco"`
+	NumeroFuncionarios *int            `json:"numero_funcionarios"`
+}
+
+// camelCase (GraphQL) <-> snake_case (JSON Schema)
+var inputFields = map[string]string{
+	"cnpj":               "cnpj",
+	"nomeFantasia":       "nome_fantasia",
+	"razaoSocial":        "razao_social",
+	"endereco":           "endereco",
+	"numeroFuncionarios": "numero_funcionarios",
+}
+
+func decodeInput(ctx context.Context, s *schema.Schema, arg any) (*companyPayload, error) {
+	in, _ := arg.(map[string]any)
+	doc := make(map[string]any, len(in))
+	for k, v := range in {
+		if name, ok := inputFields[k]; ok {
+			doc[name] = v
+		}
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, newError(ctx, utils.CodeInternalError, err)
+	}
+	errs, err := s.ValidateJSON(b)
+	if err != nil {
+		return nil, newError(ctx, utils.CodeInternalError, err)
+	}
+	if len(errs) > 0 {
+		return nil, validationError(ctx, errs)
+	}
+	var out companyPayload
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, newError(ctx, utils.CodeInternalError, err)
+	}
+	return &out, nil
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
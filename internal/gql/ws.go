@@ -0,0 +1,279 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// Protocolo graphql-transport-ws (o mesmo da lib graphql-ws / Apollo):
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const wsProtocol = "graphql-transport-ws"
+
+const (
+	msgConnectionInit = "connection_init"
+	msgConnectionAck  = "connection_ack"
+	msgPing           = "ping"
+	msgPong           = "pong"
+	msgSubscribe      = "subscribe"
+	msgNext           = "next"
+	msgError          = "error"
+	msgComplete       = "complete"
+)
+
+// códigos de fechamento definidos pelo protocolo
+const (
+	closeBadRequest      = 4400
+	closeUnauthorized    = 4401
+	closeBadProtocol     = 4406
+	closeInitTimeout     = 4408
+	closeDuplicatedID    = 4409
+	closeTooManyInitReqs = 4429
+)
+
+var wsInitTimeout = 10 * time.Second
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type wsSession struct {
+	h    *Handler
+	conn *websocket.Conn
+	ctx  context.Context
+
+	writeMu sync.Mutex
+
+	mu    sync.Mutex
+	acked bool
+	ops   map[string]context.CancelFunc
+	wg    sync.WaitGroup
+}
+
+func (h *Handler) serveWS(w http.ResponseWriter, r *http.Request) {
+	up := websocket.Upgrader{
+		Subprotocols: []string{wsProtocol},
+		CheckOrigin:  h.checkOrigin,
+	}
+	conn, err := up.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade já respondeu o erro HTTP
+	}
+	defer conn.Close()
+
+	if conn.Subprotocol() != wsProtocol {
+		closeWS(conn, closeBadProtocol, "Subprotocol not acceptable")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	s := &wsSession{h: h, conn: conn, ctx: ctx, ops: map[string]context.CancelFunc{}}
+	defer func() {
+		cancel()
+		s.wg.Wait()
+	}()
+	s.readLoop()
+}
+
+func (h *Handler) checkOrigin(r *http.Request) bool {
+	if len(h.AllowedOrigins) == 0 {
+		return true
+	}
+	origin := r.Header.Get("Origin")
+	for _, o := range h.AllowedOrigins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *wsSession) readLoop() {
+	_ = s.conn.SetReadDeadline(time.Now().Add(wsInitTimeout))
+	for {
+		var msg wsMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			if ne, ok := err.(interface{ Timeout() bool }); ok && ne.Timeout() && !s.isAcked() {
+				closeWS(s.conn, closeInitTimeout, "Connection initialisation timeout")
+			} else if _, ok := err.(*json.SyntaxError); ok {
+				closeWS(s.conn, closeBadRequest, "Invalid message received")
+			}
+			return
+		}
+
+		switch msg.Type {
+		case msgConnectionInit:
+			if s.isAcked() {
+				closeWS(s.conn, closeTooManyInitReqs, "Too many initialisation requests")
+				return
+			}
+			s.mu.Lock()
+			s.acked = true
+			s.mu.Unlock()
+			_ = s.conn.SetReadDeadline(time.Time{})
+			s.write(wsMessage{Type: msgConnectionAck})
+
+		case msgPing:
+			s.write(wsMessage{Type: msgPong})
+
+		case msgPong:
+
+		case msgSubscribe:
+			if !s.isAcked() {
+				closeWS(s.conn, closeUnauthorized, "Unauthorized")
+				return
+			}
+			var req Request
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil {
+				closeWS(s.conn, closeBadRequest, "Invalid message received")
+				return
+			}
+			if !s.start(msg.ID, req) {
+				closeWS(s.conn, closeDuplicatedID, "Subscriber for "+msg.ID+" already exists")
+				return
+			}
+
+		case msgComplete:
+			s.stop(msg.ID)
+
+		default:
+			closeWS(s.conn, closeBadRequest, "Invalid message received")
+			return
+		}
+	}
+}
+
+func (s *wsSession) isAcked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.acked
+}
+
+// start executa a operação em background; false se o id já estiver em uso
+func (s *wsSession) start(id string, req Request) bool {
+	s.mu.Lock()
+	if _, dup := s.ops[id]; dup {
+		s.mu.Unlock()
+		return false
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.ops[id] = cancel
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.finish(id)
+
+		params := graphql.Params{
+			Schema:         s.h.Schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        ctx,
+		}
+
+		if operationType(req.Query, req.OperationName) != "subscription" {
+			s.send(ctx, id, graphql.Do(params))
+			return
+		}
+
+		first := true
+		// o canal precisa ser drenado até fechar, mesmo depois do cancelamento
+		for res := range graphql.Subscribe(params) {
+			if first && res.Data == nil && len(res.Errors) > 0 {
+				// falhou antes de começar (validação, argumentos...): mensagem "error", sem "complete"
+				s.writeErrors(ctx, id, res.Errors)
+				s.stop(id)
+			} else {
+				s.send(ctx, id, res)
+			}
+			first = false
+		}
+	}()
+	return true
+}
+
+func (s *wsSession) send(ctx context.Context, id string, res *graphql.Result) {
+	if ctx.Err() != nil {
+		return
+	}
+	b, _ := json.Marshal(res)
+	s.write(wsMessage{ID: id, Type: msgNext, Payload: b})
+}
+
+func (s *wsSession) writeErrors(ctx context.Context, id string, errs []gqlerrors.FormattedError) {
+	if ctx.Err() != nil {
+		return
+	}
+	b, _ := json.Marshal(errs)
+	s.write(wsMessage{ID: id, Type: msgError, Payload: b})
+}
+
+// finish encerra a operação; se ela terminou sozinha (não foi o cliente), avisa com "complete"
+func (s *wsSession) finish(id string) {
+	s.mu.Lock()
+	cancel, running := s.ops[id]
+	delete(s.ops, id)
+	s.mu.Unlock()
+	if !running {
+		return
+	}
+	cancel()
+	if s.ctx.Err() == nil {
+		s.write(wsMessage{ID: id, Type: msgComplete})
+	}
+}
+
+// stop: "complete" enviado pelo cliente
+func (s *wsSession) stop(id string) {
+	s.mu.Lock()
+	cancel, ok := s.ops[id]
+	delete(s.ops, id)
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (s *wsSession) write(msg wsMessage) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := s.conn.WriteJSON(msg); err != nil {
+		slog.Debug("graphql_ws_write_error", "err", err)
+	}
+}
+
+func closeWS(conn *websocket.Conn, code int, reason string) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+}
+
+// operationType: "query", "mutation" ou "subscription" da operação escolhida ("" se não der para parsear)
+func operationType(query, operationName string) string {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return ""
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+			return op.Operation
+		}
+	}
+	return ""
+}
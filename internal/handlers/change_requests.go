@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Pedidos de mudança (maker-checker): /api/change-requests[/{request_id}] e
+// POST /api/change-requests/{request_id}/approve|reject. Quem pede e quem decide
+// vêm do header X-User; o pedido precisa ser decidido por outro usuário.
+
+// Body de POST .../approve e .../reject (validado por schema/change_decision.json)
+type ChangeDecisionDTO struct {
+	Comment string `json:"comment"`
+}
+
+// ChangeRequestV2: pedido com as empresas no formato da v2
+type ChangeRequestV2 struct {
+	ID          string     `json:"id"`
+	CompanyID   string     `json:"company_id"`
+	Action      string     `json:"action"`
+	Reasons     []string   `json:"reasons"`
+	Before      CompanyV2  `json:"before"`
+	After       *CompanyV2 `json:"after,omitempty"`
+	MergeInto   string     `json:"merge_into,omitempty"`
+	Status      string     `json:"status"`
+	RequestedBy string     `json:"requested_by"`
+	RequestedAt time.Time  `json:"requested_at"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+func toChangeRequestV2(cr *models.ChangeRequest) ChangeRequestV2 {
+	out := ChangeRequestV2{
+		ID: cr.ID, CompanyID: cr.CompanyID, Action: cr.Action, Reasons: cr.Reasons,
+		Before: toCompanyV2(&cr.Before), MergeInto: cr.MergeInto, Status: cr.Status,
+		RequestedBy: cr.RequestedBy, RequestedAt: cr.RequestedAt,
+		DecidedBy: cr.DecidedBy, DecidedAt: cr.DecidedAt, Comment: cr.Comment, Error: cr.Error,
+	}
+	if cr.After != nil {
+		after := toCompanyV2(cr.After)
+		out.After = &after
+	}
+	return out
+}
+
+func writeChangeRequest(w http.ResponseWriter, r *http.Request, status int, cr *models.ChangeRequest) {
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, status, cr)
+		return
+	}
+	writeData(w, r, status, toChangeRequestV2(cr))
+}
+
+// withUser: contexto da requisição com o usuário do header X-User
+func withUser(r *http.Request) context.Context {
+	return service.WithUser(r.Context(), strings.TrimSpace(r.Header.Get("X-User")))
+}
+
+// GET /api/change-requests?status=pending&company_id=...
+func (h *CompanyHandler) ChangeRequestList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	q := r.URL.Query()
+	f := models.ChangeRequestFilter{Status: q.Get("status"), CompanyID: utils.SanitizeCNPJ(q.Get("company_id"))}
+	if f.Status != "" && !slices.Contains(models.ChangeStatuses, f.Status) {
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "status", Code: utils.FieldNotInEnum, Args: []any{strings.Join(models.ChangeStatuses, ", ")}}})
+		return
+	}
+	limit, skip := pagination(q)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().ListChangeRequests(ctx, f, limit, skip)
+	if err != nil {
+		writeChangeError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	out := make([]ChangeRequestV2, len(list))
+	for i := range list {
+		out[i] = toChangeRequestV2(&list[i])
+	}
+	count := len(out)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: out,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
+
+// GET /api/change-requests/{request_id}
+func (h *CompanyHandler) ChangeRequestByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	cr, err := h.service().GetChangeRequest(ctx, r.PathValue("request_id"))
+	if err != nil {
+		writeChangeError(w, r, err)
+		return
+	}
+	writeChangeRequest(w, r, http.StatusOK, cr)
+}
+
+// POST /api/change-requests/{request_id}/approve: aplica a mudança
+func (h *CompanyHandler) ApproveChangeRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideChange(w, r, h.service().ApproveChange)
+}
+
+// POST /api/change-requests/{request_id}/reject
+func (h *CompanyHandler) RejectChangeRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideChange(w, r, h.service().RejectChange)
+}
+
+func (h *CompanyHandler) decideChange(w http.ResponseWriter, r *http.Request, decide func(context.Context, string, string) (*models.ChangeRequest, error)) {
+	if r.Method != http.MethodPost {
+		utils.MethodNotAllowed(w, r, http.MethodPost)
+		return
+	}
+	schema.Validate(schema.ChangeDecision, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var dto ChangeDecisionDTO
+		if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+			utils.InvalidJSON(w, r, err)
+			return
+		}
+		ctx, cancel := context.WithTimeout(withUser(r), 10*time.Second)
+		defer cancel()
+		cr, err := decide(ctx, r.PathValue("request_id"), strings.TrimSpace(dto.Comment))
+		if err != nil {
+			writeChangeError(w, r, err)
+			return
+		}
+		writeChangeRequest(w, r, http.StatusOK, cr)
+	})).ServeHTTP(w, r)
+}
+
+// Sem X-User -> 400, decidido pelo mesmo usuário -> 403, pedido inexistente -> 404,
+// pedido já decidido ou empresa alterada depois do pedido -> 409; falha ao aplicar
+// a mudança aprovada segue writeRepoError (ex.: CNPJ já cadastrado -> 409)
+func writeChangeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, service.ErrSameUser):
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusForbidden, utils.CodeApprovalSameUser, ""))
+	case errors.Is(err, repository.ErrChangeRequestNotFound):
+		utils.NotFound(w, r)
+	case errors.Is(err, service.ErrChangeDecided), errors.Is(err, repository.ErrChangeRequestNotPending):
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusConflict, utils.CodeChangeDecided, ""))
+	case errors.Is(err, service.ErrChangeStale):
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusConflict, utils.CodeChangeStale, ""))
+	default:
+		writeRepoError(w, r, err)
+	}
+}
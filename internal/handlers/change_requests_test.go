@@ -0,0 +1,233 @@
+package handlers
+
+/*
+
+go test -run 'TestChangeRequests_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// empresa em memória com a política padrão (exclusão, troca de CNPJ, queda >= 50%)
+type changeFixture struct {
+	mux    http.Handler
+	crs    *changeRequestRepoMock
+	store  *companyStore
+	events eventLog
+}
+
+func newChangeFixture() *changeFixture {
+	c := storedCompany()
+	c.UpdatedAt = time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	f := &changeFixture{crs: newChangeRequestRepoMock(), store: newCompanyStore(*c)}
+	h := &CompanyHandler{Repo: f.store.repo(), Pub: f.events.pub(), ChangeRequests: f.crs, Approvals: models.ApprovalPolicy{
+		Delete: true, CNPJChange: true, HeadcountDropPercent: 50,
+	}}
+	f.mux = versionedMux(h)
+	return f
+}
+
+// do: requisição em nome de user (X-User; vazio = sem o header)
+func (f *changeFixture) do(method, path, user, body string) *httptest.ResponseRecorder {
+	return doJSON(f.mux, method, path, body, "X-User", user)
+}
+
+func decodeChangeRequest(t *testing.T, rr *httptest.ResponseRecorder) models.ChangeRequest {
+	t.Helper()
+	var cr models.ChangeRequest
+	if err := json.Unmarshal(rr.Body.Bytes(), &cr); err != nil {
+		t.Fatalf("pedido inválido: %v (%s)", err, rr.Body.String())
+	}
+	return cr
+}
+
+func TestChangeRequests_DeleteNeedsAnotherUser(t *testing.T) {
+	f := newChangeFixture()
+
+	rr := f.do(http.MethodDelete, "/api/companies/"+companyID, "", "")
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["X-User"] != utils.FieldRequired {
+		t.Fatalf("sem X-User: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = f.do(http.MethodDelete, "/api/companies/"+companyID, "ana", "")
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	cr := decodeChangeRequest(t, rr)
+	if rr.Header().Get("Location") != "/api/change-requests/"+cr.ID || cr.Status != models.ChangeStatusPending ||
+		cr.Action != models.ChangeActionDelete || cr.RequestedBy != "ana" || f.store.get(companyID) == nil || len(f.events.headers) != 0 {
+		t.Fatalf("pedido = %+v (location %q, empresa %v, eventos %v)", cr, rr.Header().Get("Location"), f.store.get(companyID), f.events.actions())
+	}
+
+	approve := "/api/change-requests/" + cr.ID + "/approve"
+	if rr = f.do(http.MethodPost, approve, "ana", `{}`); rr.Code != http.StatusForbidden || decodeProblem(t, rr).Code != utils.CodeApprovalSameUser {
+		t.Fatalf("mesmo usuário: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = f.do(http.MethodPost, approve, "bia", `{"comment":"encerrada"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("approve status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if cr = decodeChangeRequest(t, rr); cr.Status != models.ChangeStatusApproved || cr.DecidedBy != "bia" || cr.Comment != "encerrada" {
+		t.Fatalf("aprovado = %+v", cr)
+	}
+	if f.store.get(companyID) != nil || len(f.events.headers) != 1 || f.events.actions()[0] != "exclusão" {
+		t.Fatalf("empresa %v, eventos %v", f.store.get(companyID), f.events.actions())
+	}
+	if rr = f.do(http.MethodPost, approve, "caio", `{}`); rr.Code != http.StatusConflict || decodeProblem(t, rr).Code != utils.CodeChangeDecided {
+		t.Fatalf("segunda decisão: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestChangeRequests_PatchRules(t *testing.T) {
+	f := newChangeFixture()
+	path := "/api/companies/" + companyID
+
+	// queda pequena: aplica na hora
+	if rr := f.do(http.MethodPatch, path, "ana", `{"numero_funcionarios":120}`); rr.Code != http.StatusOK {
+		t.Fatalf("queda de 20%%: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	// 120 -> 60: queda de 50% vira pedido
+	rr := f.do(http.MethodPatch, path, "ana", `{"numero_funcionarios":60}`)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("queda de 50%%: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	drop := decodeChangeRequest(t, rr)
+	if n := f.store.companies[companyID].NumeroFuncionarios; len(drop.Reasons) != 1 || drop.Reasons[0] != models.ApprovalHeadcountDrop || n != 120 {
+		t.Fatalf("pedido = %+v, funcionários = %d", drop, n)
+	}
+	rr = f.do(http.MethodPost, "/api/change-requests/"+drop.ID+"/reject", "bia", `{"comment":"conferir"}`)
+	if cr := decodeChangeRequest(t, rr); rr.Code != http.StatusOK || cr.Status != models.ChangeStatusRejected {
+		t.Fatalf("reject: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = f.do(http.MethodPatch, path, "ana", `{"cnpj":"11.222.333/0002-62"}`)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("troca de CNPJ: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	cnpj := decodeChangeRequest(t, rr)
+	if rr = f.do(http.MethodPost, "/api/v2/change-requests/"+cnpj.ID+"/approve", "bia", `{}`); rr.Code != http.StatusOK {
+		t.Fatalf("approve status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var env struct {
+		Data ChangeRequestV2 `json:"data"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	c := f.store.get(companyID)
+	if env.Data.Status != models.ChangeStatusApproved || env.Data.After == nil || c == nil || c.CNPJ != "11222333000262" ||
+		c.ID != companyID || c.NumeroFuncionarios != 120 {
+		t.Fatalf("aprovado = %s, empresa = %+v", rr.Body.String(), c)
+	}
+
+	rr = f.do(http.MethodGet, "/api/v2/change-requests?status=rejected", "", "")
+	var list struct {
+		Data []ChangeRequestV2 `json:"data"`
+		Meta Meta              `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &list)
+	if rr.Code != http.StatusOK || *list.Meta.Count != 1 || list.Data[0].ID != drop.ID {
+		t.Fatalf("lista = %s", rr.Body.String())
+	}
+	rr = f.do(http.MethodGet, "/api/change-requests?status=aberto", "", "")
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["status"] != utils.FieldNotInEnum {
+		t.Fatalf("status inválido: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestChangeRequests_StaleCompany(t *testing.T) {
+	f := newChangeFixture()
+
+	rr := f.do(http.MethodDelete, "/api/companies/"+companyID, "ana", "")
+	cr := decodeChangeRequest(t, rr)
+	// editada depois do pedido: a exclusão não pode ser aprovada às cegas
+	if rr = f.do(http.MethodPatch, "/api/companies/"+companyID, "ana", `{"numero_funcionarios":140}`); rr.Code != http.StatusOK {
+		t.Fatalf("PATCH status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = f.do(http.MethodPost, "/api/change-requests/"+cr.ID+"/approve", "bia", `{}`)
+	if rr.Code != http.StatusConflict || decodeProblem(t, rr).Code != utils.CodeChangeStale || f.store.get(companyID) == nil {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := f.crs.requests[cr.ID]; got.Status != models.ChangeStatusPending {
+		t.Fatalf("pedido = %+v", got)
+	}
+}
+
+// a fusão remove a absorvida: com a aprovação de exclusão ligada, vira pedido
+func TestChangeRequests_MergeNeedsApproval(t *testing.T) {
+	f := newDuplicatesFixture()
+	crs := newChangeRequestRepoMock()
+	f.h.ChangeRequests, f.h.Approvals = crs, models.ApprovalPolicy{Delete: true}
+	path := "/api/companies/" + companyID + "/merge"
+	body := `{"duplicate_id":"` + dupTypoID + `"}`
+
+	if errs := problemErrors(t, doJSON(f.mux, http.MethodPost, path, body, "X-User", "")); errs["X-User"] != utils.FieldRequired {
+		t.Fatalf("sem X-User: errors=%+v", errs)
+	}
+	rr := doJSON(f.mux, http.MethodPost, path, body, "X-User", "ana")
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	cr := decodeChangeRequest(t, rr)
+	if cr.Action != models.ChangeActionMerge || cr.CompanyID != dupTypoID || cr.MergeInto != companyID ||
+		len(cr.Reasons) != 1 || cr.Reasons[0] != models.ApprovalDelete {
+		t.Fatalf("pedido = %+v", cr)
+	}
+	if _, ok := f.store.companies[dupTypoID]; !ok || len(f.merges.merges) != 0 {
+		t.Fatal("fusão aplicada sem aprovação")
+	}
+
+	rr = doJSON(f.mux, http.MethodPost, "/api/change-requests/"+cr.ID+"/approve", `{}`, "X-User", "bia")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("approve status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if _, ok := f.store.companies[dupTypoID]; ok || len(f.merges.merges) != 1 || f.merges.merges[0].TargetID != companyID {
+		t.Fatalf("depois da aprovação: empresas=%v fusões=%+v", f.store.companies, f.merges.merges)
+	}
+}
+
+// o recálculo pelos funcionários segue a regra do PATCH: a queda grande vira pedido
+func TestChangeRequests_EmployeeDeleteNeedsApproval(t *testing.T) {
+	c := storedCompany()
+	c.NumeroFuncionarios = 2
+	store, crs, events := newCompanyStore(*c), newChangeRequestRepoMock(), &eventLog{}
+	emps := newEmployeeRepoMock(
+		models.Employee{ID: "e1", CompanyID: companyID, Nome: "Ana", CPF: "52998224725", Admissao: "2020-01-10"},
+		models.Employee{ID: "e2", CompanyID: companyID, Nome: "Bia", CPF: "11144477735", Admissao: "2020-01-10"},
+	)
+	mux := versionedMux(&CompanyHandler{Repo: store.repo(), Pub: events.pub(), Employees: emps, ChangeRequests: crs,
+		Approvals: models.ApprovalPolicy{HeadcountDropPercent: 50}})
+	path := "/api/companies/" + companyID + "/employees/e1"
+
+	if errs := problemErrors(t, doJSON(mux, http.MethodDelete, path, "")); errs["X-User"] != utils.FieldRequired || len(emps.emps) != 2 {
+		t.Fatalf("sem X-User: errors=%+v funcionários=%d", errs, len(emps.emps))
+	}
+	rr := doJSON(mux, http.MethodDelete, path, "", "X-User", "ana")
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	cr := decodeChangeRequest(t, rr)
+	if cr.Action != models.ChangeActionUpdate || len(cr.Reasons) != 1 || cr.Reasons[0] != models.ApprovalHeadcountDrop ||
+		cr.After == nil || cr.After.NumeroFuncionarios != 1 {
+		t.Fatalf("pedido = %+v", cr)
+	}
+	// o funcionário sai; os números da empresa esperam a aprovação
+	if n := store.get(companyID).NumeroFuncionarios; len(emps.emps) != 1 || n != 2 || len(events.headers) != 0 {
+		t.Fatalf("funcionários=%d empresa=%d eventos=%v", len(emps.emps), n, events.actions())
+	}
+
+	rr = doJSON(mux, http.MethodPost, "/api/change-requests/"+cr.ID+"/approve", `{}`, "X-User", "bia")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("approve status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if n := store.get(companyID).NumeroFuncionarios; n != 1 {
+		t.Fatalf("depois da aprovação: funcionários = %d", n)
+	}
+}
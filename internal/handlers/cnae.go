@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/cnae"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// GET /api/cnae?q=software&tipo=subclasse: busca na tabela CNAE 2.3 embutida.
+// q é um código (com ou sem máscara, por prefixo; letra = seção) ou palavras
+// da descrição (sem diferenciar maiúsculas nem acentos). Sem q lista a tabela.
+func (h *CompanyHandler) CNAE(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	q := r.URL.Query()
+	tipo := strings.ToLower(strings.TrimSpace(q.Get("tipo")))
+	if tipo != "" && !slices.Contains(cnae.Tipos, tipo) {
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "tipo", Code: utils.FieldNotInEnum, Args: []any{strings.Join(cnae.Tipos, ", ")}}})
+		return
+	}
+	limit, skip := pagination(q)
+
+	list := cnae.Search(q.Get("q"), tipo, int(limit+skip))
+	list = list[min(int(skip), len(list)):]
+
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: list,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
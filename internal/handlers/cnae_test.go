@@ -0,0 +1,140 @@
+package handlers
+
+/*
+
+go test -run 'TestCNAE_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/cnae"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+func TestCNAE_Search(t *testing.T) {
+	mux := versionedMux(&CompanyHandler{Repo: &repoMock{}})
+
+	rr := doJSON(mux, http.MethodGet, "/api/cnae?q=6201&tipo=subclasse", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var list []cnae.Entry
+	_ = json.Unmarshal(rr.Body.Bytes(), &list)
+	if len(list) != 2 || list[0].Codigo != "6201-5/01" || list[0].Secao != "J" || list[0].Classe != "62.01-5" {
+		t.Fatalf("lista = %+v", list)
+	}
+
+	rr = doJSON(mux, http.MethodGet, "/api/v2/cnae?q=informacao&tipo=divisao&limit=1", "")
+	var env struct {
+		Data []cnae.Entry `json:"data"`
+		Meta Meta         `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if rr.Code != http.StatusOK || len(env.Data) != 1 || *env.Meta.Count != 1 || *env.Meta.Limit != 1 {
+		t.Fatalf("v2: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = doJSON(mux, http.MethodGet, "/api/cnae?tipo=grupo", "")
+	if rr.Code != http.StatusBadRequest || problemErrors(t, rr)["tipo"] != utils.FieldNotInEnum {
+		t.Fatalf("tipo inválido: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCNAE_CompanyFields(t *testing.T) {
+	var created *models.Company
+	rm := &repoMock{CreateFn: func(_ context.Context, c *models.Company) (string, error) {
+		created = c
+		return c.ID, nil
+	}}
+	mux := versionedMux(&CompanyHandler{Repo: rm, Pub: &pubMock{}})
+
+	// secundárias repetidas e iguais à principal são descartadas; gravadas só com dígitos
+	rr := doJSON(mux, http.MethodPost, "/api/v2/companies",
+		`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","cnae_principal":"6201-5/01","cnaes_secundarios":["6204-0/00","6201501","6204000"]}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if created.CNAEPrincipal != "6201501" || len(created.CNAESecundarios) != 1 || created.CNAESecundarios[0] != "6204000" {
+		t.Fatalf("gravado = %q %v", created.CNAEPrincipal, created.CNAESecundarios)
+	}
+	var env struct {
+		Data CompanyV2 `json:"data"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if env.Data.CNAEPrincipal != "6201-5/01" || env.Data.CNAESecundarios[0] != "6204-0/00" {
+		t.Fatalf("v2 = %+v", env.Data)
+	}
+
+	rr = doJSON(mux, http.MethodPost, "/api/companies",
+		`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","cnae_principal":"9999-9/99","cnaes_secundarios":["6204-0/00","123"]}`)
+	if rr.Code != http.StatusBadRequest ||
+		problemErrors(t, rr)["cnae_principal"] != utils.FieldInvalidCNAE ||
+		problemErrors(t, rr)["cnaes_secundarios[1]"] != utils.FieldInvalidCNAE {
+		t.Fatalf("inválido: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCNAE_PatchSecondaries(t *testing.T) {
+	stored := storedCompany()
+	stored.CNAEPrincipal, stored.CNAESecundarios = "6201501", []string{"6204000", "6209100"}
+	var upd *models.Company
+	rm := &repoMock{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return stored, nil },
+		UpdateFn: func(_ context.Context, _ string, u *models.Company, _ []string) error {
+			upd = u
+			return nil
+		},
+	}
+	mux := versionedMux(&CompanyHandler{Repo: rm, Pub: &pubMock{}})
+
+	// nova principal que já era secundária sai da lista (a antiga não vira secundária)
+	rr := doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"cnae_principal":"6204-0/00"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if upd.CNAEPrincipal != "6204000" || len(upd.CNAESecundarios) != 1 || upd.CNAESecundarios[0] != "6209100" {
+		t.Fatalf("update = %q %v", upd.CNAEPrincipal, upd.CNAESecundarios)
+	}
+
+	// [] remove as secundárias (lista vazia, não nil: o repositório faz $unset)
+	rr = doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"cnaes_secundarios":[]}`)
+	if rr.Code != http.StatusOK || upd.CNAESecundarios == nil || len(upd.CNAESecundarios) != 0 {
+		t.Fatalf("status=%d update=%v", rr.Code, upd.CNAESecundarios)
+	}
+
+	// campos omitidos não mexem nos CNAEs
+	rr = doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"nome_fantasia":"ACME 2"}`)
+	if rr.Code != http.StatusOK || upd.CNAEPrincipal != "" || upd.CNAESecundarios != nil {
+		t.Fatalf("status=%d update=%q %v", rr.Code, upd.CNAEPrincipal, upd.CNAESecundarios)
+	}
+}
+
+func TestCNAE_ListFilters(t *testing.T) {
+	var got models.CompanyFilter
+	rm := &repoMock{FindFn: func(_ context.Context, f models.CompanyFilter, _, _ int64) ([]models.Company, error) {
+		got = f
+		return []models.Company{}, nil
+	}}
+	mux := versionedMux(&CompanyHandler{Repo: rm})
+
+	rr := doJSON(mux, http.MethodGet, "/api/companies?cnae_secao=j&cnae_classe=62.01-5&cnae_secundarios=true&uf=sp", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got.CNAESecao != "J" || got.CNAEClasse != "62015" || !got.CNAESecundarios || got.UF != "SP" {
+		t.Fatalf("filtro = %+v", got)
+	}
+
+	rr = doJSON(mux, http.MethodGet, "/api/companies?cnae_divisao=04&cnae_secao=Z", "")
+	if rr.Code != http.StatusBadRequest ||
+		problemErrors(t, rr)["cnae_divisao"] != utils.FieldNotInTable ||
+		problemErrors(t, rr)["cnae_secao"] != utils.FieldNotInTable {
+		t.Fatalf("inválido: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
@@ -4,26 +4,56 @@ package handlers
 //
 // numero_minimo_pcd_exigidos NÃO vem do cliente (calculado no servidor)
 type CompanyCreateDTO struct {
-	CNPJ               string `json:"cnpj"`
-	NomeFantasia       string `json:"nome_fantasia"`
-	RazaoSocial        string `json:"razao_social"`
-	Endereco           string `json:"endereco"`
-	NumeroFuncionarios int    `json:"numero_funcionarios"`
+	CNPJ                 string         `json:"cnpj"`
+	NomeFantasia         string         `json:"nome_fantasia"`
+	RazaoSocial          string         `json:"razao_social"`
+	Endereco             string         `json:"endereco"`
+	NumeroFuncionarios   int            `json:"numero_funcionarios"`
+	NumeroPCDContratados *int           `json:"numero_pcd_contratados,omitempty"`
+	CNAEPrincipal        string         `json:"cnae_principal,omitempty"`
+	CNAESecundarios      []string       `json:"cnaes_secundarios,omitempty"`
+	InscricaoEstadual    string         `json:"inscricao_estadual,omitempty"`
+	InscricaoEstadualUF  string         `json:"inscricao_estadual_uf,omitempty"`
+	InscricaoMunicipal   string         `json:"inscricao_municipal,omitempty"`
+	RegimeTributario     string         `json:"regime_tributario,omitempty"`
+	FaturamentoAnual     *float64       `json:"faturamento_anual,omitempty"`
+	Tags                 []string       `json:"tags,omitempty"`
+	CustomFields         map[string]any `json:"custom_fields,omitempty"`
 }
 
 // Update parcial; ponteiros distinguem "omitido" de "informado".
 type CompanyPatchDTO struct {
-	CNPJ               *string `json:"cnpj,omitempty"`
-	NomeFantasia       *string `json:"nome_fantasia,omitempty"`
-	RazaoSocial        *string `json:"razao_social,omitempty"`
-	Endereco           *string `json:"endereco,omitempty"`
-	NumeroFuncionarios *int    `json:"numero_funcionarios,omitempty"`
+	CNPJ                 *string        `json:"cnpj,omitempty"`
+	NomeFantasia         *string        `json:"nome_fantasia,omitempty"`
+	RazaoSocial          *string        `json:"razao_social,omitempty"`
+	Endereco             *string        `json:"endereco,omitempty"`
+	NumeroFuncionarios   *int           `json:"numero_funcionarios,omitempty"`
+	NumeroPCDContratados *int           `json:"numero_pcd_contratados,omitempty"`
+	CNAEPrincipal        *string        `json:"cnae_principal,omitempty"`
+	CNAESecundarios      *[]string      `json:"cnaes_secundarios,omitempty"` // [] remove as secundárias
+	InscricaoEstadual    *string        `json:"inscricao_estadual,omitempty"`
+	InscricaoEstadualUF  *string        `json:"inscricao_estadual_uf,omitempty"`
+	InscricaoMunicipal   *string        `json:"inscricao_municipal,omitempty"`
+	RegimeTributario     *string        `json:"regime_tributario,omitempty"`
+	FaturamentoAnual     *float64       `json:"faturamento_anual,omitempty"`
+	Tags                 *[]string      `json:"tags,omitempty"`          // [] remove as tags
+	CustomFields         map[string]any `json:"custom_fields,omitempty"` // null remove o campo
 }
 
 type CompanyPutDTO struct {
-	CNPJ               *string `json:"cnpj,omitempty"`
-	NomeFantasia       string  `json:"nome_fantasia"`
-	RazaoSocial        string  `json:"razao_social"`
-	Endereco           string  `json:"endereco"`
-	NumeroFuncionarios int     `json:"numero_funcionarios"`
+	CNPJ                 *string        `json:"cnpj,omitempty"`
+	NomeFantasia         string         `json:"nome_fantasia"`
+	RazaoSocial          string         `json:"razao_social"`
+	Endereco             string         `json:"endereco"`
+	NumeroFuncionarios   int            `json:"numero_funcionarios"`
+	NumeroPCDContratados *int           `json:"numero_pcd_contratados,omitempty"`
+	CNAEPrincipal        string         `json:"cnae_principal,omitempty"`
+	CNAESecundarios      []string       `json:"cnaes_secundarios,omitempty"`
+	InscricaoEstadual    string         `json:"inscricao_estadual,omitempty"`
+	InscricaoEstadualUF  string         `json:"inscricao_estadual_uf,omitempty"`
+	InscricaoMunicipal   string         `json:"inscricao_municipal,omitempty"`
+	RegimeTributario     string         `json:"regime_tributario,omitempty"`
+	FaturamentoAnual     *float64       `json:"faturamento_anual,omitempty"`
+	Tags                 []string       `json:"tags,omitempty"`
+	CustomFields         map[string]any `json:"custom_fields,omitempty"`
 }
@@ -3,46 +3,137 @@ package handlers
 import (
 	"context"
 	"errors"
-	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/rabbitmq/amqp091-go"
-	amqp "github.com/rabbitmq/amqp091-go"
-
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
 	"github.com/Werneck0live/cadastro-empresa/internal/models"
 	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
 	"github.com/Werneck0live/cadastro-empresa/internal/utils"
 )
 
-type Repository interface {
-	GetAll(ctx context.Context, limit, skip int64) ([]models.Company, error)
-	Create(ctx context.Context, c *models.Company) (string, error)
-	GetByID(ctx context.Context, id string) (*models.Company, error)
-	Update(ctx context.Context, id string, upd *models.Company) error
-	Replace(ctx context.Context, id string, doc *models.Company) error
-	Delete(ctx context.Context, id string) error
+// Interfaces do service (mantidas aqui com o nome usado pelos handlers e pelo cmd/api)
+type (
+	Repository              = service.Repository
+	Publisher               = service.Publisher
+	EmployeeRepository      = service.EmployeeRepository
+	ContactRepository       = service.ContactRepository
+	PartnerRepository       = service.PartnerRepository
+	DocumentRepository      = service.DocumentRepository
+	CustomFieldRepository   = service.CustomFieldRepository
+	MergeRepository         = service.MergeRepository
+	OwnershipRepository     = service.OwnershipRepository
+	NoteRepository          = service.NoteRepository
+	EventRepository         = service.EventRepository
+	ChangeRequestRepository = service.ChangeRequestRepository
+	Transactor              = service.Transactor
+)
+
+type CompanyHandler struct {
+	Repo       Repository
+	Pub        Publisher
+	Employees  EmployeeRepository  // sub-recurso /employees
+	Contacts   ContactRepository   // sub-recurso /contacts
+	Partners   PartnerRepository   // sub-recurso /partners (QSA)
+	Documents  DocumentRepository  // sub-recurso /documents (GridFS)
+	Merges     MergeRepository     // histórico das fusões (/merge, /merges)
+	Ownerships OwnershipRepository // grupo econômico (/subsidiaries, /ancestors, /descendants, /group)
+	Notes      NoteRepository      // sub-recurso /notes e /timeline
+	Events     EventRepository     // eventos gravados para a /timeline (nil = só as notas)
+	Tx         Transactor          // transação da fusão (nil = sem transação)
+
+	// Pedidos de mudança (/api/change-requests; nil = nada exige aprovação) e quais operações viram pedido
+	ChangeRequests ChangeRequestRepository
+	Approvals      models.ApprovalPolicy
+
+	// Definições dos campos personalizados (/api/custom-fields)
+	CustomFields CustomFieldRepository
+
+	// Idioma do texto dos eventos publicados (padrão pt-BR)
+	EventLang i18n.Lang
+	// Limites do porte (zero = utils.DefaultPorteThresholds)
+	PorteThresholds utils.PorteThresholds
+	// Tamanho máximo de um documento enviado (zero = 10 MB)
+	DocumentMaxBytes int64
 }
 
-// type Publisher interface {
-// 	Publish(ctx context.Context, payload []byte) error
-// 	Close() error
-// }
+func NewCompanyHandler(repo Repository, pub Publisher) *CompanyHandler {
+	return &CompanyHandler{Repo: repo, Pub: pub}
+}
 
-type Publisher interface {
-	Publish(ctx context.Context, body string, headers amqp091.Table) error
-	Close() error
+// regras do cadastro (as mesmas usadas pelo GraphQL)
+func (h *CompanyHandler) service() *service.Companies {
+	return &service.Companies{Repo: h.Repo, Pub: h.Pub, Employees: h.Employees, Contacts: h.Contacts, Partners: h.Partners, Documents: h.Documents, Merges: h.Merges, Ownerships: h.Ownerships, Notes: h.Notes, Events: h.Events, Tx: h.Tx, ChangeRequests: h.ChangeRequests, Approvals: h.Approvals, CustomFields: h.CustomFields, EventLang: h.EventLang, PorteThresholds: h.PorteThresholds}
 }
 
-type CompanyHandler struct {
-	Repo Repository
-	Pub  Publisher
+// Register registra as rotas do handler no mux.
+// mw (opcional) envolve as rotas de /api (ex.: Idempotency.Wrap).
+func (h *CompanyHandler) Register(mux *http.ServeMux, mw ...func(http.Handler) http.Handler) {
+	wrap := func(next http.Handler) http.Handler {
+		for i := len(mw) - 1; i >= 0; i-- {
+			next = mw[i](next)
+		}
+		return next
+	}
+	mux.HandleFunc("/healthz", h.Health)
+	mux.Handle("/api/companies", negotiate(wrap(http.HandlerFunc(h.Companies)), http.MethodGet))
+	mux.Handle("/api/companies/", negotiate(wrap(http.HandlerFunc(h.CompanyByID))))
+	mux.Handle("/api/companies/stats", negotiate(wrap(http.HandlerFunc(h.Stats))))
+	mux.Handle("/api/companies/report", wrap(http.HandlerFunc(h.PortfolioReport)))
+	mux.Handle("/api/companies/{id}/report", wrap(http.HandlerFunc(h.CompanyReport)))
+	mux.Handle("/api/companies/{id}/employees", negotiate(wrap(http.HandlerFunc(h.CompanyEmployees)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/employees/import", negotiate(wrap(http.HandlerFunc(h.ImportEmployees))))
+	mux.Handle("/api/companies/{id}/employees/recount", negotiate(wrap(http.HandlerFunc(h.RecountEmployees))))
+	mux.Handle("/api/companies/{id}/employees/{employee_id}", negotiate(wrap(http.HandlerFunc(h.CompanyEmployeeByID))))
+	mux.Handle("/api/companies/{id}/contacts", negotiate(wrap(http.HandlerFunc(h.CompanyContacts)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/contacts/{contact_id}", negotiate(wrap(http.HandlerFunc(h.CompanyContactByID))))
+	mux.Handle("/api/companies/{id}/partners", negotiate(wrap(http.HandlerFunc(h.CompanyPartners)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/partners/{partner_id}", negotiate(wrap(http.HandlerFunc(h.CompanyPartnerByID))))
+	// upload sem o middleware de idempotência: ele guarda o body em memória (até 1 MB)
+	mux.Handle("/api/companies/{id}/documents", negotiate(http.HandlerFunc(h.CompanyDocuments), http.MethodGet))
+	mux.Handle("/api/companies/{id}/documents/{document_id}", negotiate(wrap(http.HandlerFunc(h.CompanyDocumentByID))))
+	mux.Handle("/api/companies/{id}/documents/{document_id}/content", http.HandlerFunc(h.DocumentContent))
+	mux.Handle("/api/companies/{id}/duplicates", negotiate(wrap(http.HandlerFunc(h.CompanyDuplicates)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/merge", negotiate(wrap(http.HandlerFunc(h.MergeCompany))))
+	mux.Handle("/api/companies/{id}/merges", negotiate(wrap(http.HandlerFunc(h.CompanyMerges)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/subsidiaries", negotiate(wrap(http.HandlerFunc(h.CompanySubsidiaries)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/subsidiaries/{child_id}", negotiate(wrap(http.HandlerFunc(h.CompanySubsidiaryByID))))
+	mux.Handle("/api/companies/{id}/ancestors", negotiate(wrap(http.HandlerFunc(h.CompanyAncestors)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/descendants", negotiate(wrap(http.HandlerFunc(h.CompanyDescendants)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/group", negotiate(wrap(http.HandlerFunc(h.CompanyGroup))))
+	mux.Handle("/api/companies/{id}/group/cycles", negotiate(wrap(http.HandlerFunc(h.CompanyGroupCycles)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/notes", negotiate(wrap(http.HandlerFunc(h.CompanyNotes)), http.MethodGet))
+	mux.Handle("/api/companies/{id}/notes/{note_id}", negotiate(wrap(http.HandlerFunc(h.CompanyNoteByID))))
+	mux.Handle("/api/companies/{id}/timeline", negotiate(wrap(http.HandlerFunc(h.CompanyTimeline)), http.MethodGet))
+	mux.Handle("/api/change-requests", negotiate(wrap(http.HandlerFunc(h.ChangeRequestList)), http.MethodGet))
+	mux.Handle("/api/change-requests/{request_id}", negotiate(wrap(http.HandlerFunc(h.ChangeRequestByID))))
+	mux.Handle("/api/change-requests/{request_id}/approve", negotiate(wrap(http.HandlerFunc(h.ApproveChangeRequest))))
+	mux.Handle("/api/change-requests/{request_id}/reject", negotiate(wrap(http.HandlerFunc(h.RejectChangeRequest))))
+	mux.Handle("/api/partners/{documento}/companies", negotiate(wrap(http.HandlerFunc(h.PartnerCompanies)), http.MethodGet))
+	mux.Handle("/api/custom-fields", negotiate(wrap(http.HandlerFunc(h.CustomFieldDefinitions)), http.MethodGet))
+	mux.Handle("/api/custom-fields/{key}", negotiate(wrap(http.HandlerFunc(h.CustomFieldDefinitionByKey))))
+	mux.Handle("/api/cnae", negotiate(wrap(http.HandlerFunc(h.CNAE)), http.MethodGet))
+	mux.Handle("/api/pcd/simulate", negotiate(wrap(http.HandlerFunc(h.SimulatePCD))))
 }
 
-func NewCompanyHandler(repo Repository, pub Publisher) *CompanyHandler {
-	return &CompanyHandler{Repo: repo, Pub: pub}
+// negotiate recusa (406) um Accept que não serve para a resposta da rota antes de
+// executar a operação (assim um POST com Accept: text/csv não grava nada nem fica na
+// Idempotency-Key). lists: métodos que respondem uma lista; nos demais a resposta é
+// um objeto (ex.: CSV só vale para listas).
+func negotiate(next http.Handler, lists ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !utils.AcceptableFor(r, slices.Contains(lists, r.Method)) {
+			utils.NotAcceptable(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
 }
 
 // garantir que a requisição venha no padrão /api/companies/{id_company}
@@ -55,8 +146,11 @@ func parseIDFromPath(path string) (string, bool) {
 }
 
 func (h *CompanyHandler) Health(w http.ResponseWriter, r *http.Request) {
-	utils.WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
-
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodHead)
+		return
+	}
+	utils.WriteResponse(w, r, http.StatusOK, map[string]string{"status": "ok"})
 }
 
 func (h *CompanyHandler) Companies(w http.ResponseWriter, r *http.Request) {
@@ -65,76 +159,21 @@ func (h *CompanyHandler) Companies(w http.ResponseWriter, r *http.Request) {
 
 	// "getAll", "getAll-pagination"(skip, limit)
 	case http.MethodGet:
-		q := r.URL.Query()
-		limit := int64(50)
-		skip := int64(0)
-		if l := q.Get("limit"); l != "" {
-			if v, err := strconv.ParseInt(l, 10, 64); err == nil && v > 0 && v <= 200 {
-				limit = v
-			}
-		}
-		if s := q.Get("skip"); s != "" {
-			if v, err := strconv.ParseInt(s, 10, 64); err == nil && v >= 0 {
-				skip = v
-			}
-		}
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
-		list, err := h.Repo.GetAll(ctx, limit, skip)
-		if err != nil {
-			utils.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			return
-		}
-		utils.WriteJSON(w, http.StatusOK, list)
+		h.list(w, r)
 
-	// create
+	// create (body validado por schema/company_create.json ou company_create_v2.json)
 	case http.MethodPost:
-		var dto CompanyCreateDTO
-		if err := utils.DecodeStrict(r.Body, &dto); err != nil {
-			utils.BadRequest(w, utils.FormatUnknownFieldError(err))
-			return
-		}
-		if err := validateCreateDTO(dto); err != nil {
-			utils.BadRequest(w, err.Error())
-			return
-		}
-
-		c := models.Company{
-			CNPJ:               utils.SanitizeCNPJ(dto.CNPJ),
-			NomeFantasia:       dto.NomeFantasia,
-			RazaoSocial:        dto.RazaoSocial,
-			Endereco:           dto.Endereco,
-			NumeroFuncionarios: dto.NumeroFuncionarios,
-		}
-		if !utils.ValidateCNPJ(c.CNPJ) {
-			utils.BadRequest(w, "invalid cnpj")
-			return
-		}
-		c.ID = c.CNPJ
-
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
-		if _, err := h.Repo.Create(ctx, &c); err != nil {
-			if errors.Is(err, repository.ErrDuplicateCNPJ) {
-				utils.WriteJSON(w, http.StatusConflict, map[string]string{"error": "cnpj already exists"})
-				return
-			}
-			utils.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			return
-		}
-
-		h.publishEvent("Cadastro", &c)
-		utils.WriteJSON(w, http.StatusCreated, c)
+		schema.Validate(schemasFor(r).create, http.HandlerFunc(h.create)).ServeHTTP(w, r)
 
 	default:
-		w.WriteHeader(http.StatusMethodNotAllowed)
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
 	}
 }
 
 func (h *CompanyHandler) CompanyByID(w http.ResponseWriter, r *http.Request) {
 	id, ok := parseIDFromPath(r.URL.Path)
 	if !ok {
-		utils.WriteJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		utils.NotFound(w, r)
 		return
 	}
 
@@ -142,205 +181,228 @@ func (h *CompanyHandler) CompanyByID(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodGet:
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
-		c, err := h.Repo.GetByID(ctx, id)
-		if err != nil {
-			utils.WriteJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
-			return
-		}
-		utils.WriteJSON(w, http.StatusOK, c)
+		h.get(w, r, id)
 
 	case http.MethodPatch:
-		var dto CompanyPatchDTO
-		if err := utils.DecodeStrict(r.Body, &dto); err != nil {
-			utils.BadRequest(w, utils.FormatUnknownFieldError(err))
-			return
-		}
-
-		if err := validateUpdateDTO(dto); err != nil {
-			utils.BadRequest(w, err.Error())
-			return
-		}
-
-		// Buscar atual para validar CNPJ e (opcional) recalcular PCD
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
-
-		existing, err := h.Repo.GetByID(ctx, id)
-		if err != nil {
-			utils.WriteJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
-			return
-		}
-
-		// Monta o modelo para update apenas com campos presentes
-		upd := models.Company{}
-
-		if dto.CNPJ != nil {
-			cnpj := utils.SanitizeCNPJ(*dto.CNPJ)
-			if !utils.ValidateCNPJ(cnpj) {
-				utils.BadRequest(w, "invalid cnpj")
-				return
-			}
-			// Só tente mudar se for diferente do atual
-			if cnpj != existing.CNPJ {
-				upd.CNPJ = cnpj
-			}
-		}
-		if dto.NomeFantasia != nil {
-			upd.NomeFantasia = *dto.NomeFantasia
-		}
-		if dto.RazaoSocial != nil {
-			upd.RazaoSocial = *dto.RazaoSocial
-		}
-		if dto.Endereco != nil {
-			upd.Endereco = *dto.Endereco
-		}
-
-		if dto.NumeroFuncionarios != nil {
-			upd.NumeroFuncionarios = *dto.NumeroFuncionarios
-
-			upd.NumeroMinimoPCDExigidos = utils.ComputeMinPCD(upd.NumeroFuncionarios)
-		}
-
-		if err := h.Repo.Update(ctx, id, &upd); err != nil {
-			if errors.Is(err, repository.ErrDuplicateCNPJ) {
-				utils.WriteJSON(w, http.StatusConflict, map[string]string{"error": "cnpj already exists"})
-				return
-			}
-			utils.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			return
-		}
-
-		// Retorna o doc atualizado
-		c2, _ := h.Repo.GetByID(ctx, id)
-		if c2 != nil {
-			h.publishEvent("Edição", c2)
-			utils.WriteJSON(w, http.StatusOK, c2)
-			return
-		}
-		utils.WriteJSON(w, http.StatusOK, map[string]string{"id": id})
+		schema.Validate(schemasFor(r).patch, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.patch(w, r, id)
+		})).ServeHTTP(w, r)
 
 	case http.MethodPut:
-		var dto CompanyPutDTO
-		if err := utils.DecodeStrict(r.Body, &dto); err != nil {
-			utils.BadRequest(w, utils.FormatUnknownFieldError(err))
-			return
-		}
-		if err := validatePutDTO(dto); err != nil {
-			// slog.Info("\n\n\n\n\n\n\n")
-			// slog.Info("%v", err)
-			// slog.Info("Teste")
-			utils.BadRequest(w, err.Error())
-			return
-		}
-
-		// {id} da rota
-		id, ok := parseIDFromPath(r.URL.Path)
-		if !ok {
-			utils.WriteJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
-			return
-		}
+		schema.Validate(schemasFor(r).put, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.replace(w, r, id)
+		})).ServeHTTP(w, r)
 
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
+	case http.MethodDelete:
+		h.delete(w, r, id)
 
-		current, err := h.Repo.GetByID(ctx, id)
-		if err != nil {
-			utils.WriteJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
-			return
-		}
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPatch, http.MethodPut, http.MethodDelete)
+	}
+}
 
-		// Regras para CNPJ:
-		// - se não vier no body, usar o {id}
-		// - se vier, deve ser igual ao {id}
-		var cnpj string
-		if dto.CNPJ == nil {
-			cnpj = id
-		} else {
-			cnpj = utils.SanitizeCNPJ(*dto.CNPJ)
-			if cnpj != id {
-				utils.BadRequest(w, "cnpj in body must match the resource id in path")
-				return
-			}
-		}
-		if !utils.ValidateCNPJ(cnpj) {
-			utils.BadRequest(w, "invalid cnpj")
-			return
-		}
+func (h *CompanyHandler) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f, errs := parseCompanyFilter(q)
+	if len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
+	limit, skip := pagination(q)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().List(ctx, f, limit, skip)
+	if err != nil {
+		utils.InternalError(w, r, err)
+		return
+	}
+	writeCompanies(w, r, list, limit, skip)
+}
 
-		// monta o documento COMPLETO que substituirá o atual (PUT = replace)
-		newDoc := models.Company{
-			ID:                      id, // preserva o mesmo _id
-			CNPJ:                    cnpj,
-			NomeFantasia:            dto.NomeFantasia,
-			RazaoSocial:             dto.RazaoSocial,
-			Endereco:                dto.Endereco,
-			NumeroFuncionarios:      dto.NumeroFuncionarios,
-			NumeroMinimoPCDExigidos: utils.ComputeMinPCD(dto.NumeroFuncionarios), // se você estiver usando compute
-			CreatedAt:               current.CreatedAt,                           // preserva criação
-			UpdatedAt:               time.Now(),
+// ?limit (1..200, padrão 50) e ?skip; valores inválidos ficam no padrão
+func pagination(q url.Values) (limit, skip int64) {
+	limit = 50
+	if l := q.Get("limit"); l != "" {
+		if v, err := strconv.ParseInt(l, 10, 64); err == nil && v > 0 && v <= 200 {
+			limit = v
 		}
-
-		if err := h.Repo.Replace(ctx, id, &newDoc); err != nil {
-			if errors.Is(err, repository.ErrDuplicateCNPJ) {
-				utils.WriteJSON(w, http.StatusConflict, map[string]string{"error": "cnpj already exists"})
-				return
-			}
-			utils.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			return
+	}
+	if s := q.Get("skip"); s != "" {
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil && v >= 0 {
+			skip = v
 		}
+	}
+	return limit, skip
+}
 
-		h.publishEvent("Edição", &newDoc)
-		utils.WriteJSON(w, http.StatusOK, newDoc)
-
-	case http.MethodDelete:
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
+func (h *CompanyHandler) create(w http.ResponseWriter, r *http.Request) {
+	dto, addr, err := decodeCreate(r)
+	if err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	if errs := validateIE(dto.InscricaoEstadual, dto.InscricaoEstadualUF); len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
 
-		// Busca antes de deletar para logar o nome
-		c, err := h.Repo.GetByID(ctx, id)
-		if err != nil {
-			utils.WriteJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
-			return
-		}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	c, err := h.service().Create(ctx, service.CompanyInput{
+		CNPJ:                 dto.CNPJ,
+		NomeFantasia:         dto.NomeFantasia,
+		RazaoSocial:          dto.RazaoSocial,
+		Endereco:             dto.Endereco,
+		EnderecoEstruturado:  addr,
+		NumeroFuncionarios:   dto.NumeroFuncionarios,
+		NumeroPCDContratados: dto.NumeroPCDContratados,
+		CNAEPrincipal:        dto.CNAEPrincipal,
+		CNAESecundarios:      dto.CNAESecundarios,
+		InscricaoEstadual:    dto.InscricaoEstadual,
+		InscricaoEstadualUF:  dto.InscricaoEstadualUF,
+		InscricaoMunicipal:   dto.InscricaoMunicipal,
+		RegimeTributario:     dto.RegimeTributario,
+		FaturamentoAnual:     dto.FaturamentoAnual,
+		Tags:                 dto.Tags,
+		CustomFields:         dto.CustomFields,
+	})
+	if err != nil {
+		writeRepoError(w, r, err)
+		return
+	}
+	writeCompany(w, r, http.StatusCreated, c)
+}
 
-		if err := h.Repo.Delete(ctx, id); err != nil {
-			utils.WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			return
-		}
+func (h *CompanyHandler) get(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	c, err := h.service().Get(ctx, id)
+	if err != nil {
+		writeRepoError(w, r, err)
+		return
+	}
+	writeCompany(w, r, http.StatusOK, c)
+}
 
-		h.publishEvent("Exclusão", c)
-		w.WriteHeader(http.StatusNoContent)
+func (h *CompanyHandler) patch(w http.ResponseWriter, r *http.Request, id string) {
+	dto, addr, err := decodePatch(r)
+	if err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	if errs := validateIE(deref(dto.InscricaoEstadual), deref(dto.InscricaoEstadualUF)); len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
 
-	default:
-		w.WriteHeader(http.StatusMethodNotAllowed)
+	ctx, cancel := context.WithTimeout(withUser(r), 5*time.Second)
+	defer cancel()
+	c, err := h.service().Patch(ctx, id, service.CompanyPatch{
+		CNPJ:                 dto.CNPJ,
+		NomeFantasia:         dto.NomeFantasia,
+		RazaoSocial:          dto.RazaoSocial,
+		Endereco:             dto.Endereco,
+		EnderecoEstruturado:  addr,
+		NumeroFuncionarios:   dto.NumeroFuncionarios,
+		NumeroPCDContratados: dto.NumeroPCDContratados,
+		CNAEPrincipal:        dto.CNAEPrincipal,
+		CNAESecundarios:      dto.CNAESecundarios,
+		InscricaoEstadual:    dto.InscricaoEstadual,
+		InscricaoEstadualUF:  dto.InscricaoEstadualUF,
+		InscricaoMunicipal:   dto.InscricaoMunicipal,
+		RegimeTributario:     dto.RegimeTributario,
+		FaturamentoAnual:     dto.FaturamentoAnual,
+		Tags:                 dto.Tags,
+		CustomFields:         dto.CustomFields,
+	})
+	if err != nil {
+		writeRepoError(w, r, err)
+		return
 	}
+	if c == nil {
+		utils.WriteResponse(w, r, http.StatusOK, map[string]string{"id": id})
+		return
+	}
+	writeCompany(w, r, http.StatusOK, c)
 }
 
-func (h *CompanyHandler) publishEvent(acao string, c *models.Company) {
-	if h.Pub == nil || c == nil {
+func (h *CompanyHandler) replace(w http.ResponseWriter, r *http.Request, id string) {
+	dto, addr, err := decodePut(r)
+	if err != nil {
+		utils.InvalidJSON(w, r, err)
 		return
 	}
-	// Escolhe o nome a exibir
-	empresa := c.NomeFantasia
-	if empresa == "" {
-		if c.RazaoSocial != "" {
-			empresa = c.RazaoSocial
-		} else {
-			empresa = c.CNPJ
-		}
+	// Regras para CNPJ:
+	// - se não vier no body, usar o {id}
+	// - se vier, deve ser igual ao {id}
+	if errs := append(validatePutCNPJ(dto, id), validateIE(dto.InscricaoEstadual, dto.InscricaoEstadualUF)...); len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
 	}
-	msg := fmt.Sprintf("%s de EMPRESA %s", acao, empresa)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(withUser(r), 5*time.Second)
 	defer cancel()
-
-	_ = h.Pub.Publish(ctx, msg, amqp.Table{
-		"action":     strings.ToLower(acao), // cadastro|edição|exclusão
-		"company_id": c.ID,
-		"cnpj":       c.CNPJ,
-		"nome":       empresa,
-		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+	c, err := h.service().Replace(ctx, id, service.CompanyInput{
+		NomeFantasia:         dto.NomeFantasia,
+		RazaoSocial:          dto.RazaoSocial,
+		Endereco:             dto.Endereco,
+		EnderecoEstruturado:  addr,
+		NumeroFuncionarios:   dto.NumeroFuncionarios,
+		NumeroPCDContratados: dto.NumeroPCDContratados,
+		CNAEPrincipal:        dto.CNAEPrincipal,
+		CNAESecundarios:      dto.CNAESecundarios,
+		InscricaoEstadual:    dto.InscricaoEstadual,
+		InscricaoEstadualUF:  dto.InscricaoEstadualUF,
+		InscricaoMunicipal:   dto.InscricaoMunicipal,
+		RegimeTributario:     dto.RegimeTributario,
+		FaturamentoAnual:     dto.FaturamentoAnual,
+		Tags:                 dto.Tags,
+		CustomFields:         dto.CustomFields,
 	})
+	if err != nil {
+		writeRepoError(w, r, err)
+		return
+	}
+	writeCompany(w, r, http.StatusOK, c)
+}
+
+func (h *CompanyHandler) delete(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(withUser(r), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.service().Delete(ctx, id); err != nil {
+		writeRepoError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Erros do service/repositório: não encontrada -> 404, CNPJ duplicado -> 409,
+// campos personalizados fora das definições -> 400, o resto -> 500.
+// Mudança que virou pedido de aprovação -> 202 com o pedido (sem X-User -> 400).
+func writeRepoError(w http.ResponseWriter, r *http.Request, err error) {
+	var pending *service.ApprovalRequiredError
+	if errors.As(err, &pending) {
+		w.Header().Set("Location", "/api/change-requests/"+pending.Request.ID)
+		writeChangeRequest(w, r, http.StatusAccepted, pending.Request)
+		return
+	}
+	if errors.Is(err, service.ErrUserRequired) {
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "X-User", Code: utils.FieldRequired}})
+		return
+	}
+	if errors.Is(err, service.ErrNotFound) {
+		utils.NotFound(w, r)
+		return
+	}
+	if errors.Is(err, repository.ErrDuplicateCNPJ) {
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusConflict, utils.CodeCNPJConflict, ""))
+		return
+	}
+	var cf *service.CustomFieldsError
+	if errors.As(err, &cf) {
+		utils.ValidationFailed(w, r, cf.Errors)
+		return
+	}
+	utils.InternalError(w, r, err)
 }
@@ -19,6 +19,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
 	"github.com/Werneck0live/cadastro-empresa/internal/models"
 	"github.com/Werneck0live/cadastro-empresa/internal/repository"
 	"github.com/Werneck0live/cadastro-empresa/internal/utils"
@@ -138,6 +139,9 @@ func TestCompanies_MethodNotAllowed(t *testing.T) {
 	if rr.Code != http.StatusMethodNotAllowed {
 		t.Fatalf("status=%d want=%d", rr.Code, http.StatusMethodNotAllowed)
 	}
+	if allow := rr.Header().Get("Allow"); allow != "GET, POST" {
+		t.Fatalf("Allow=%q want=%q", allow, "GET, POST")
+	}
 }
 
 // 2) GET (ById{id}) - go test -run 'TestCompanyByID_Get_' -v ./internal/handlers -count=1
@@ -180,7 +184,7 @@ func TestCompanyByID_Get_Found(t *testing.T) {
 func TestCompanyByID_Get_NotFound(t *testing.T) {
 	rm := &repoMock{
 		GetByIDFn: func(_ context.Context, id string) (*models.Company, error) {
-			return nil, errors.New("not found")
+			return nil, repository.ErrCompanyNotFound
 		},
 	}
 	h := &CompanyHandler{Repo: rm, Pub: &pubMock{}}
@@ -295,6 +299,90 @@ func TestCompanies_Create_InvalidCNPJ(t *testing.T) {
 	}
 }
 
+// ---------- 400 BAD REQUEST (problem+json listando todos os campos inválidos)
+func TestCompanies_Create_ValidationErrors(t *testing.T) {
+	h := &CompanyHandler{Repo: &repoMock{}, Pub: &pubMock{}}
+
+	body := bytes.NewBufferString(`{"cnpj": "xx", "numero_funcionarios": -1}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/companies", body)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	h.Companies(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status=%d want=%d body=%s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != utils.ProblemContentType {
+		t.Fatalf("content-type=%q want=%q", ct, utils.ProblemContentType)
+	}
+
+	var p utils.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil {
+		t.Fatalf("json inválido: %v", err)
+	}
+	if p.Status != http.StatusBadRequest || p.Code != utils.CodeValidationFailed || p.Type != utils.ProblemTypePrefix+utils.CodeValidationFailed {
+		t.Fatalf("problem inesperado: %#v", p)
+	}
+
+	got := map[string]string{}
+	for _, e := range p.Errors {
+		got[e.Field] = e.Code
+	}
+	want := map[string]string{
+		"cnpj":                utils.FieldInvalidCNPJ,
+		"nome_fantasia":       utils.FieldRequiredOneOf,
+		"razao_social":        utils.FieldRequiredOneOf,
+		"numero_funcionarios": utils.FieldMustBeNonNeg,
+	}
+	for f, code := range want {
+		if got[f] != code {
+			t.Fatalf("campo %s: code=%q want=%q (errors=%#v)", f, got[f], code, p.Errors)
+		}
+	}
+}
+
+// ---------- 400 BAD REQUEST (campo desconhecido vira errors[] junto das demais violações do schema)
+func TestCompanies_Create_UnknownField(t *testing.T) {
+	h := &CompanyHandler{Repo: &repoMock{}, Pub: &pubMock{}}
+
+	body := bytes.NewBufferString(`{"cnpj": "` + validCNPJ + `", "nome_fantasia": "ACME", "foo": 1}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/companies", body)
+	rr := httptest.NewRecorder()
+
+	h.Companies(rr, req)
+
+	var p utils.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil {
+		t.Fatalf("json inválido: %v", err)
+	}
+	if p.Code != utils.CodeValidationFailed || len(p.Errors) != 1 || p.Errors[0].Field != "foo" || p.Errors[0].Code != utils.FieldUnknown {
+		t.Fatalf("problem inesperado: %#v", p)
+	}
+}
+
+// ---------- 400 BAD REQUEST (mensagens no idioma do Accept-Language)
+func TestCompanies_Create_ValidationErrors_PtBR(t *testing.T) {
+	h := &CompanyHandler{Repo: &repoMock{}, Pub: &pubMock{}}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/companies", bytes.NewBufferString(`{"nome_fantasia": "ACME"}`))
+	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9,en;q=0.8")
+	rr := httptest.NewRecorder()
+
+	h.Companies(rr, req)
+
+	var p utils.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil {
+		t.Fatalf("json inválido: %v", err)
+	}
+	if p.Title != "Falha de validação" || len(p.Errors) != 1 || p.Errors[0].Message != "cnpj é obrigatório" {
+		t.Fatalf("problem inesperado: %#v", p)
+	}
+	if rr.Header().Get("Content-Language") != "pt-BR" {
+		t.Fatalf("Content-Language=%q", rr.Header().Get("Content-Language"))
+	}
+}
+
 // ---------- 409 CONFLICT (CNPJ duplicado)
 func TestCompanies_Create_DuplicateCNPJ(t *testing.T) {
 	rm := &repoMock{
@@ -319,6 +407,34 @@ func TestCompanies_Create_DuplicateCNPJ(t *testing.T) {
 	}
 }
 
+// ---------- texto do evento no idioma configurado
+func TestCompanies_Create_EventLang(t *testing.T) {
+	cases := []struct {
+		lang i18n.Lang
+		want string
+	}{
+		{"", "Cadastro de EMPRESA ACME"}, // padrão pt-BR
+		{i18n.PtBR, "Cadastro de EMPRESA ACME"},
+		{i18n.En, "Company ACME created"},
+	}
+	for _, tc := range cases {
+		var got string
+		rm := &repoMock{CreateFn: func(_ context.Context, c *models.Company) (string, error) { return c.CNPJ, nil }}
+		pm := &pubMock{PublishFn: func(_ context.Context, body string, _ amqp091.Table) error {
+			got = body
+			return nil
+		}}
+		h := &CompanyHandler{Repo: rm, Pub: pm, EventLang: tc.lang}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/companies", bytes.NewBufferString(`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME"}`))
+		h.Companies(httptest.NewRecorder(), req)
+
+		if got != tc.want {
+			t.Fatalf("lang=%q want=%q got=%q", tc.lang, tc.want, got)
+		}
+	}
+}
+
 // 4) PUT - (replace) - go test -run 'TestCompanyByID_Put_' -v ./internal/handlers -count=1
 // ---------- 200 OK (replace válido)
 func TestCompanyByID_Put_Replace_OK(t *testing.T) {
@@ -416,7 +532,7 @@ func TestCompanyByID_Put_CNPJMismatch(t *testing.T) {
 func TestCompanyByID_Put_NotFoundCurrent(t *testing.T) {
 	rm := &repoMock{
 		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) {
-			return nil, errors.New("not found")
+			return nil, repository.ErrCompanyNotFound
 		},
 	}
 	h := &CompanyHandler{Repo: rm, Pub: &pubMock{}}
@@ -469,7 +585,7 @@ func TestCompanyByID_Patch_OK(t *testing.T) {
 			// após Update, o handler busca de novo para retornar ao cliente
 			return &models.Company{ID: id, CNPJ: validCNPJ, NomeFantasia: "NEW"}, nil
 		},
-		UpdateFn: func(_ context.Context, id string, upd *models.Company) error {
+		UpdateFn: func(_ context.Context, id string, upd *models.Company, _ []string) error {
 			if id != companyID {
 				t.Fatalf("id inesperado: %s", id)
 			}
@@ -517,7 +633,7 @@ func TestCompanyByID_Patch_InvalidJSON(t *testing.T) {
 func TestCompanyByID_Patch_NotFound(t *testing.T) {
 	rm := &repoMock{
 		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) {
-			return nil, errors.New("not found")
+			return nil, repository.ErrCompanyNotFound
 		},
 	}
 	h := &CompanyHandler{Repo: rm, Pub: &pubMock{}}
@@ -558,7 +674,7 @@ func TestCompanyByID_Patch_DuplicateCNPJ(t *testing.T) {
 		GetByIDFn: func(_ context.Context, id string) (*models.Company, error) {
 			return &models.Company{ID: id, CNPJ: validCNPJ}, nil
 		},
-		UpdateFn: func(_ context.Context, _ string, _ *models.Company) error {
+		UpdateFn: func(_ context.Context, _ string, _ *models.Company, _ []string) error {
 			return repository.ErrDuplicateCNPJ
 		},
 	}
@@ -608,7 +724,7 @@ func TestCompanyByID_Delete_OK(t *testing.T) {
 // ---------- 404 Not Found (não existe)
 func TestCompanyByID_Delete_NotFound(t *testing.T) {
 	rm := &repoMock{
-		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return nil, errors.New("not found") },
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return nil, repository.ErrCompanyNotFound },
 	}
 	h := &CompanyHandler{Repo: rm, Pub: &pubMock{}}
 
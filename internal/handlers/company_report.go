@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/report"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Relatórios de cumprimento da cota PCD em HTML ou PDF (?format=html|pdf).
+// Não passam pelo negotiate: o formato vem do parâmetro, não dos encoders da API.
+
+const (
+	reportHTML = "html"
+	reportPDF  = "pdf"
+
+	reportPageSize     int64 = 200
+	reportMaxCompanies       = 5000 // carteira maior que isso sai truncada (com aviso no relatório)
+)
+
+var reportFormats = []string{reportHTML, reportPDF}
+
+type reportRenderer interface {
+	HTML(w io.Writer, lang i18n.Lang) error
+	PDF(w io.Writer, lang i18n.Lang) error
+}
+
+// GET /api/companies/{id}/report
+func (h *CompanyHandler) CompanyReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	format, errs := reportFormat(r)
+	if len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	c, err := h.service().Get(ctx, r.PathValue("id"))
+	if errors.Is(err, service.ErrNotFound) {
+		utils.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		utils.InternalError(w, r, err)
+		return
+	}
+	writeReport(w, r, format, "pcd-report-"+c.ID, report.NewCompanyReport(c, time.Now()))
+}
+
+// GET /api/companies/report (carteira; mesmos filtros das estatísticas)
+func (h *CompanyHandler) PortfolioReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	format, errs := reportFormat(r)
+	f, ferrs := parseCompanyFilter(r.URL.Query())
+	if errs = append(errs, ferrs...); len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	list, truncated, err := h.listAll(ctx, f)
+	if err != nil {
+		utils.InternalError(w, r, err)
+		return
+	}
+	rep := report.NewPortfolioReport(list, time.Now())
+	if truncated {
+		rep.Truncated = reportMaxCompanies
+	}
+	writeReport(w, r, format, "pcd-report", rep)
+}
+
+// listAll pagina a listagem até reportMaxCompanies (truncated = havia mais)
+func (h *CompanyHandler) listAll(ctx context.Context, f models.CompanyFilter) ([]models.Company, bool, error) {
+	var all []models.Company
+	for skip := int64(0); len(all) <= reportMaxCompanies; skip += reportPageSize {
+		page, err := h.service().List(ctx, f, reportPageSize, skip)
+		if err != nil {
+			return nil, false, err
+		}
+		all = append(all, page...)
+		if int64(len(page)) < reportPageSize {
+			break
+		}
+	}
+	if len(all) > reportMaxCompanies {
+		return all[:reportMaxCompanies], true, nil
+	}
+	return all, false, nil
+}
+
+// reportFormat: ?format=html|pdf; sem o parâmetro, PDF só se o Accept pedir application/pdf
+func reportFormat(r *http.Request) (string, []utils.FieldError) {
+	switch v := r.URL.Query().Get("format"); v {
+	case reportHTML, reportPDF:
+		return v, nil
+	case "":
+		if strings.Contains(r.Header.Get("Accept"), "application/pdf") {
+			return reportPDF, nil
+		}
+		return reportHTML, nil
+	default:
+		return "", []utils.FieldError{{Field: "format", Code: utils.FieldNotInEnum, Args: []any{strings.Join(reportFormats, ", ")}}}
+	}
+}
+
+// O relatório é entregue ao cliente: sem Accept-Language, sai em pt-BR (como os eventos).
+func writeReport(w http.ResponseWriter, r *http.Request, format, name string, rep reportRenderer) {
+	lang := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"), i18n.PtBR)
+
+	// renderiza antes de escrever: erro no meio vira 500 em vez de documento cortado
+	var buf bytes.Buffer
+	ct, ext := "text/html; charset=utf-8", ".html"
+	render := rep.HTML
+	if format == reportPDF {
+		ct, ext = "application/pdf", ".pdf"
+		render = rep.PDF
+	}
+	if err := render(&buf, lang); err != nil {
+		utils.InternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("Content-Language", string(lang))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": name + ext}))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
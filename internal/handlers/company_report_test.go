@@ -0,0 +1,129 @@
+package handlers
+
+/*
+
+go test -run 'TestReport_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+func TestReport_Company(t *testing.T) {
+	h := &CompanyHandler{Repo: &repoMock{
+		GetByIDFn: func(_ context.Context, id string) (*models.Company, error) {
+			switch id {
+			case companyID:
+				return storedCompany(), nil
+			case otherCompany:
+				return nil, context.DeadlineExceeded // Mongo fora do ar
+			}
+			return nil, repository.ErrCompanyNotFound
+		},
+	}}
+	mux := versionedMux(h)
+
+	// padrão: HTML em pt-BR
+	req := httptest.NewRequest(http.MethodGet, "/api/companies/"+companyID+"/report", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Fatalf("html: status=%d ct=%q body=%s", rr.Code, rr.Header().Get("Content-Type"), rr.Body.String())
+	}
+	if rr.Header().Get("Content-Language") != "pt-BR" || !strings.Contains(rr.Body.String(), "Mínimo de PCD exigido") {
+		t.Fatalf("html: lang=%q", rr.Header().Get("Content-Language"))
+	}
+
+	// PDF pela v2, em inglês
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/companies/"+companyID+"/report?format=pdf", nil)
+	req.Header.Set("Accept-Language", "en")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "application/pdf" {
+		t.Fatalf("pdf: status=%d ct=%q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if !bytes.HasPrefix(rr.Body.Bytes(), []byte("%PDF-")) {
+		t.Fatal("pdf: corpo não é PDF")
+	}
+	if cd := rr.Header().Get("Content-Disposition"); cd != `inline; filename=pcd-report-`+companyID+`.pdf` {
+		t.Fatalf("Content-Disposition = %q", cd)
+	}
+
+	// Accept: application/pdf sem ?format (o negotiate da API não se aplica aqui)
+	req = httptest.NewRequest(http.MethodGet, "/api/companies/"+companyID+"/report", nil)
+	req.Header.Set("Accept", "application/pdf")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "application/pdf" {
+		t.Fatalf("accept pdf: status=%d ct=%q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/companies/99999999000199/report", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("inexistente: status = %d", rr.Code)
+	}
+	// falha na busca não é "não encontrada"
+	req = httptest.NewRequest(http.MethodGet, "/api/companies/"+otherCompany+"/report", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("erro do repositório: status = %d", rr.Code)
+	}
+}
+
+func TestReport_Portfolio(t *testing.T) {
+	var gotF models.CompanyFilter
+	var calls int
+	h := &CompanyHandler{Repo: &repoMock{
+		FindFn: func(_ context.Context, f models.CompanyFilter, limit, skip int64) ([]models.Company, error) {
+			gotF = f
+			calls++
+			if skip > 0 {
+				return nil, nil
+			}
+			list := make([]models.Company, limit) // página cheia: pede a próxima
+			for i := range list {
+				list[i] = *storedCompany()
+			}
+			return list, nil
+		},
+	}}
+	mux := versionedMux(h)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/companies/report?uf=sp&format=html", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rr.Code, rr.Body.String())
+	}
+	if gotF.UF != "SP" || calls != 2 {
+		t.Fatalf("filtro=%+v chamadas=%d", gotF, calls)
+	}
+	if strings.Count(rr.Body.String(), "11.222.333/0001-81") != int(reportPageSize) {
+		t.Fatal("html sem todas as empresas da carteira")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/companies/report?format=docx&min_funcionarios=x", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("inválido: status = %d", rr.Code)
+	}
+	var p utils.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil || len(p.Errors) != 2 || p.Errors[0].Field != "format" {
+		t.Fatalf("problem = %+v err=%v", p, err)
+	}
+}
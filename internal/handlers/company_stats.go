@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/cnae"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// agrupamentos quando group_by não é informado (o crescimento anual fica sob pedido)
+var defaultStatsGroupBy = []string{models.StatsByUF, models.StatsByHeadcountBand, models.StatsByPCDBand, models.StatsByMonth}
+
+const statsDateLayout = "2006-01-02"
+
+// GET /api/companies/stats?uf=SP&min_funcionarios=100&created_from=2025-01-01&group_by=uf,pcd_band
+func (h *CompanyHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	f, groupBy, errs := parseStatsQuery(r)
+	if len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	st, err := h.service().Stats(ctx, f, groupBy)
+	if err != nil {
+		utils.InternalError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, st)
+		return
+	}
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{Data: st, Meta: Meta{APIVersion: V2}})
+}
+
+// parseStatsQuery: filtros (parseCompanyFilter) e group_by da query string.
+func parseStatsQuery(r *http.Request) (models.CompanyFilter, []string, []utils.FieldError) {
+	q := r.URL.Query()
+	f, errs := parseCompanyFilter(q)
+
+	groupBy := defaultStatsGroupBy
+	if v := q.Get("group_by"); v != "" {
+		groupBy = nil
+		for _, g := range strings.Split(v, ",") {
+			g = strings.TrimSpace(g)
+			if !slices.Contains(models.StatsGroupings, g) {
+				errs = append(errs, utils.FieldError{Field: "group_by", Code: utils.FieldNotInEnum, Args: []any{strings.Join(models.StatsGroupings, ", ")}})
+				break
+			}
+			if !slices.Contains(groupBy, g) {
+				groupBy = append(groupBy, g)
+			}
+		}
+	}
+	return f, groupBy, errs
+}
+
+// parseCompanyFilter: filtros comuns da listagem, estatísticas e relatórios
+// (nome, cnpj_prefix, uf, min/max_funcionarios, created_from/created_to,
+// regime_tributario, porte, tags, cnae_secao/cnae_divisao/cnae_classe e cnae_secundarios).
+// created_to é inclusivo (a data inteira entra no intervalo).
+func parseCompanyFilter(q url.Values) (models.CompanyFilter, []utils.FieldError) {
+	var f models.CompanyFilter
+	var errs []utils.FieldError
+
+	f.Nome = strings.TrimSpace(q.Get("nome"))
+	if v := q.Get("cnpj_prefix"); v != "" {
+		f.CNPJPrefix = utils.SanitizeCNPJ(v)
+		if f.CNPJPrefix == "" || len(f.CNPJPrefix) > 14 {
+			errs = append(errs, utils.FieldError{Field: "cnpj_prefix", Code: utils.FieldInvalidFormat})
+		}
+	}
+	if v := q.Get("uf"); v != "" {
+		f.UF = strings.ToUpper(strings.TrimSpace(v))
+		if len(f.UF) != 2 {
+			errs = append(errs, utils.FieldError{Field: "uf", Code: utils.FieldInvalidFormat})
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  **int
+	}{{"min_funcionarios", &f.MinFuncionarios}, {"max_funcionarios", &f.MaxFuncionarios}} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		switch {
+		case err != nil:
+			errs = append(errs, utils.FieldError{Field: p.name, Code: utils.FieldInvalidType, Args: []any{"integer"}})
+		case n < 0:
+			errs = append(errs, utils.FieldError{Field: p.name, Code: utils.FieldMustBeNonNeg})
+		default:
+			*p.dst = &n
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+		add  int // dias somados (created_to inclusivo -> limite exclusivo no dia seguinte)
+	}{{"created_from", &f.CreatedFrom, 0}, {"created_to", &f.CreatedTo, 1}} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(statsDateLayout, v)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: p.name, Code: utils.FieldInvalidFormat})
+			continue
+		}
+		t = t.AddDate(0, 0, p.add)
+		*p.dst = &t
+	}
+	for _, p := range []struct {
+		name    string
+		dst     *string
+		allowed []string
+	}{{"regime_tributario", &f.RegimeTributario, models.RegimesTributarios}, {"porte", &f.Porte, utils.Portes}} {
+		v := strings.ToLower(strings.TrimSpace(q.Get(p.name)))
+		if v == "" {
+			continue
+		}
+		if !slices.Contains(p.allowed, v) {
+			errs = append(errs, utils.FieldError{Field: p.name, Code: utils.FieldNotInEnum, Args: []any{strings.Join(p.allowed, ", ")}})
+			continue
+		}
+		*p.dst = v
+	}
+	// tags=a,b: a empresa tem de ter todas
+	if v := q.Get("tags"); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if !tagPattern.MatchString(t) {
+				errs = append(errs, utils.FieldError{Field: "tags", Code: utils.FieldInvalidFormat})
+				break
+			}
+			if !slices.Contains(f.Tags, t) {
+				f.Tags = append(f.Tags, t)
+			}
+		}
+	}
+	errs = append(errs, parseCNAEFilter(q, &f)...)
+	return f, errs
+}
+
+// mesmo formato do schema das empresas (já em minúsculas)
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// níveis da CNAE aceitos nos filtros: o código tem de constar da tabela embutida
+func parseCNAEFilter(q url.Values, f *models.CompanyFilter) []utils.FieldError {
+	var errs []utils.FieldError
+	for _, p := range []struct {
+		name, table string
+		dst         *string
+		norm        func(string) string
+		valid       func(string) bool
+	}{
+		{"cnae_secao", "CNAE 2.3 (seções)", &f.CNAESecao, func(v string) string { return strings.ToUpper(strings.TrimSpace(v)) }, cnae.ValidSecao},
+		{"cnae_divisao", "CNAE 2.3 (divisões)", &f.CNAEDivisao, cnae.Sanitize, cnae.ValidDivisao},
+		{"cnae_classe", "CNAE 2.3 (classes)", &f.CNAEClasse, cnae.Sanitize, cnae.ValidClasse},
+	} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		if *p.dst = p.norm(v); !p.valid(*p.dst) {
+			errs = append(errs, utils.FieldError{Field: p.name, Code: utils.FieldNotInTable, Args: []any{p.table}})
+		}
+	}
+	if v := q.Get("cnae_secundarios"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "cnae_secundarios", Code: utils.FieldInvalidType, Args: []any{"boolean"}})
+		}
+		f.CNAESecundarios = b
+	}
+	return errs
+}
@@ -0,0 +1,126 @@
+package handlers
+
+/*
+
+go test -run 'TestStats_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+func TestStats_FiltersAndGroupBy(t *testing.T) {
+	var gotF models.CompanyFilter
+	var gotGroup []string
+	h := &CompanyHandler{Repo: &repoMock{
+		StatsFn: func(_ context.Context, f models.CompanyFilter, groupBy []string) (*models.CompanyStats, error) {
+			gotF, gotGroup = f, groupBy
+			return &models.CompanyStats{
+				Totals: models.StatsTotals{Companies: 2, Funcionarios: 300, PCDRequired: 6},
+				ByUF:   []models.StatsBucket{{Key: "SP", Companies: 2, Funcionarios: 300, PCDRequired: 6}},
+			}, nil
+		},
+	}}
+	mux := versionedMux(h)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/companies/stats?uf=sp&min_funcionarios=100&cnpj_prefix=11.222&created_from=2025-01-01&created_to=2025-01-31&group_by=uf,year,uf", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rr.Code, rr.Body.String())
+	}
+	if gotF.UF != "SP" || gotF.CNPJPrefix != "11222" || gotF.MinFuncionarios == nil || *gotF.MinFuncionarios != 100 || gotF.MaxFuncionarios != nil {
+		t.Fatalf("filtro = %+v", gotF)
+	}
+	// created_to inclusivo: limite exclusivo no dia seguinte
+	if !gotF.CreatedFrom.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) || !gotF.CreatedTo.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("datas = %v %v", gotF.CreatedFrom, gotF.CreatedTo)
+	}
+	if !slices.Equal(gotGroup, []string{"uf", "year"}) {
+		t.Fatalf("group_by = %v", gotGroup)
+	}
+	var st models.CompanyStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Totals.PCDRequired != 6 || len(st.ByUF) != 1 || st.ByUF[0].Key != "SP" {
+		t.Fatalf("stats = %+v", st)
+	}
+
+	// sem group_by: agrupamentos padrão; v2 com envelope
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/companies/stats", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("v2: status = %d body=%s", rr.Code, rr.Body.String())
+	}
+	if !slices.Equal(gotGroup, defaultStatsGroupBy) || !gotF.IsZero() {
+		t.Fatalf("padrão: filtro=%+v group_by=%v", gotF, gotGroup)
+	}
+	var env struct {
+		Data models.CompanyStats `json:"data"`
+		Meta Meta                `json:"meta"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &env); err != nil || env.Meta.APIVersion != V2 || env.Data.Totals.Companies != 2 {
+		t.Fatalf("envelope = %+v err=%v", env, err)
+	}
+}
+
+func TestStats_InvalidQuery(t *testing.T) {
+	called := false
+	h := &CompanyHandler{Repo: &repoMock{
+		StatsFn: func(_ context.Context, _ models.CompanyFilter, _ []string) (*models.CompanyStats, error) {
+			called = true
+			return &models.CompanyStats{}, nil
+		},
+	}}
+	mux := versionedMux(h)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/stats?min_funcionarios=abc&max_funcionarios=-1&created_from=01/01/2025&uf=SAO&group_by=uf,cidade", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d body=%s", rr.Code, rr.Body.String())
+	}
+	var p utils.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"min_funcionarios": utils.FieldInvalidType,
+		"max_funcionarios": utils.FieldMustBeNonNeg,
+		"created_from":     utils.FieldInvalidFormat,
+		"uf":               utils.FieldInvalidFormat,
+		"group_by":         utils.FieldNotInEnum,
+	}
+	if len(p.Errors) != len(want) {
+		t.Fatalf("errors = %+v", p.Errors)
+	}
+	for _, e := range p.Errors {
+		if want[e.Field] != e.Code || e.Message == "" {
+			t.Fatalf("erro inesperado: %+v", e)
+		}
+	}
+	if called {
+		t.Fatal("Stats executado com query inválida")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/companies/stats", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST: status = %d", rr.Code)
+	}
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/cnae"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Contrato da /api/v2. A lógica dos handlers é a mesma da v1: a v2 só muda
+// o formato do body (endereço estruturado) e da resposta (envelope {data, meta}).
+
+type CompanyCreateV2DTO struct {
+	CNPJ                 string          `json:"cnpj"`
+	NomeFantasia         string          `json:"nome_fantasia"`
+	RazaoSocial          string          `json:"razao_social"`
+	Endereco             *models.Address `json:"endereco"`
+	NumeroFuncionarios   int             `json:"numero_funcionarios"`
+	NumeroPCDContratados *int            `json:"numero_pcd_contratados,omitempty"`
+	CNAEPrincipal        string          `json:"cnae_principal,omitempty"`
+	CNAESecundarios      []string        `json:"cnaes_secundarios,omitempty"`
+	InscricaoEstadual    string          `json:"inscricao_estadual,omitempty"`
+	InscricaoEstadualUF  string          `json:"inscricao_estadual_uf,omitempty"`
+	InscricaoMunicipal   string          `json:"inscricao_municipal,omitempty"`
+	RegimeTributario     string          `json:"regime_tributario,omitempty"`
+	FaturamentoAnual     *float64        `json:"faturamento_anual,omitempty"`
+	Tags                 []string        `json:"tags,omitempty"`
+	CustomFields         map[string]any  `json:"custom_fields,omitempty"`
+}
+
+type CompanyPatchV2DTO struct {
+	CNPJ                 *string         `json:"cnpj,omitempty"`
+	NomeFantasia         *string         `json:"nome_fantasia,omitempty"`
+	RazaoSocial          *string         `json:"razao_social,omitempty"`
+	Endereco             *models.Address `json:"endereco,omitempty"`
+	NumeroFuncionarios   *int            `json:"numero_funcionarios,omitempty"`
+	NumeroPCDContratados *int            `json:"numero_pcd_contratados,omitempty"`
+	CNAEPrincipal        *string         `json:"cnae_principal,omitempty"`
+	CNAESecundarios      *[]string       `json:"cnaes_secundarios,omitempty"`
+	InscricaoEstadual    *string         `json:"inscricao_estadual,omitempty"`
+	InscricaoEstadualUF  *string         `json:"inscricao_estadual_uf,omitempty"`
+	InscricaoMunicipal   *string         `json:"inscricao_municipal,omitempty"`
+	RegimeTributario     *string         `json:"regime_tributario,omitempty"`
+	FaturamentoAnual     *float64        `json:"faturamento_anual,omitempty"`
+	Tags                 *[]string       `json:"tags,omitempty"`
+	CustomFields         map[string]any  `json:"custom_fields,omitempty"`
+}
+
+type CompanyPutV2DTO struct {
+	CNPJ                 *string         `json:"cnpj,omitempty"`
+	NomeFantasia         string          `json:"nome_fantasia"`
+	RazaoSocial          string          `json:"razao_social"`
+	Endereco             *models.Address `json:"endereco"`
+	NumeroFuncionarios   int             `json:"numero_funcionarios"`
+	NumeroPCDContratados *int            `json:"numero_pcd_contratados,omitempty"`
+	CNAEPrincipal        string          `json:"cnae_principal,omitempty"`
+	CNAESecundarios      []string        `json:"cnaes_secundarios,omitempty"`
+	InscricaoEstadual    string          `json:"inscricao_estadual,omitempty"`
+	InscricaoEstadualUF  string          `json:"inscricao_estadual_uf,omitempty"`
+	InscricaoMunicipal   string          `json:"inscricao_municipal,omitempty"`
+	RegimeTributario     string          `json:"regime_tributario,omitempty"`
+	FaturamentoAnual     *float64        `json:"faturamento_anual,omitempty"`
+	Tags                 []string        `json:"tags,omitempty"`
+	CustomFields         map[string]any  `json:"custom_fields,omitempty"`
+}
+
+// Empresa na v2: CNPJ e CNAEs com máscara e endereço como objeto
+type CompanyV2 struct {
+	ID                      string          `json:"id"`
+	CNPJ                    string          `json:"cnpj"`
+	NomeFantasia            string          `json:"nome_fantasia"`
+	RazaoSocial             string          `json:"razao_social"`
+	Endereco                *models.Address `json:"endereco"`
+	NumeroFuncionarios      int             `json:"numero_funcionarios"`
+	NumeroMinimoPCDExigidos int             `json:"numero_minimo_pcd_exigidos"`
+	NumeroPCDContratados    *int            `json:"numero_pcd_contratados,omitempty"`
+	CNAEPrincipal           string          `json:"cnae_principal,omitempty"`
+	CNAESecundarios         []string        `json:"cnaes_secundarios,omitempty"`
+	InscricaoEstadual       string          `json:"inscricao_estadual,omitempty"`
+	InscricaoEstadualUF     string          `json:"inscricao_estadual_uf,omitempty"`
+	InscricaoMunicipal      string          `json:"inscricao_municipal,omitempty"`
+	RegimeTributario        string          `json:"regime_tributario,omitempty"`
+	FaturamentoAnual        *float64        `json:"faturamento_anual,omitempty"`
+	Porte                   string          `json:"porte,omitempty"`
+	Tags                    []string        `json:"tags,omitempty"`
+	CustomFields            map[string]any  `json:"custom_fields,omitempty"`
+	CreatedAt               time.Time       `json:"created_at"`
+	UpdatedAt               time.Time       `json:"updated_at"`
+}
+
+type Envelope struct {
+	Data any  `json:"data"`
+	Meta Meta `json:"meta"`
+}
+
+// Rows: a lista do envelope (para o CSV)
+func (e Envelope) Rows() any { return e.Data }
+
+type Meta struct {
+	APIVersion APIVersion `json:"api_version"`
+	Limit      *int64     `json:"limit,omitempty"`
+	Skip       *int64     `json:"skip,omitempty"`
+	Count      *int       `json:"count,omitempty"`
+	NextCursor string     `json:"next_cursor,omitempty"` // paginação por cursor (/timeline)
+	Truncated  bool       `json:"truncated,omitempty"`   // só parte das candidatas foi comparada (/duplicates)
+}
+
+func toCompanyV2(c *models.Company) CompanyV2 {
+	addr := c.EnderecoEstruturado
+	if addr == nil && c.Endereco != "" {
+		// cadastrada pela v1: só existe o texto livre
+		addr = &models.Address{Logradouro: c.Endereco}
+	}
+	return CompanyV2{
+		ID:                      c.ID,
+		CNPJ:                    utils.FormatCNPJ(c.CNPJ),
+		NomeFantasia:            c.NomeFantasia,
+		RazaoSocial:             c.RazaoSocial,
+		Endereco:                addr,
+		NumeroFuncionarios:      c.NumeroFuncionarios,
+		NumeroMinimoPCDExigidos: c.NumeroMinimoPCDExigidos,
+		NumeroPCDContratados:    c.NumeroPCDContratados,
+		CNAEPrincipal:           cnae.FormatSubclasse(c.CNAEPrincipal),
+		CNAESecundarios:         formatCNAEs(c.CNAESecundarios),
+		InscricaoEstadual:       c.InscricaoEstadual,
+		InscricaoEstadualUF:     c.InscricaoEstadualUF,
+		InscricaoMunicipal:      c.InscricaoMunicipal,
+		RegimeTributario:        c.RegimeTributario,
+		FaturamentoAnual:        c.FaturamentoAnual,
+		Porte:                   c.Porte,
+		Tags:                    c.Tags,
+		CustomFields:            c.CustomFields,
+		CreatedAt:               c.CreatedAt,
+		UpdatedAt:               c.UpdatedAt,
+	}
+}
+
+func formatCNAEs(codes []string) []string {
+	if len(codes) == 0 {
+		return nil
+	}
+	out := make([]string, len(codes))
+	for i, c := range codes {
+		out[i] = cnae.FormatSubclasse(c)
+	}
+	return out
+}
+
+// ---------- entrada por versão (tudo vira o DTO da v1 + endereço estruturado)
+
+type companySchemas struct {
+	create, put, patch *schema.Schema
+}
+
+var schemasByVersion = map[APIVersion]companySchemas{
+	V1: {create: schema.CompanyCreate, put: schema.CompanyPut, patch: schema.CompanyPatch},
+	V2: {create: schema.CompanyCreateV2, put: schema.CompanyPutV2, patch: schema.CompanyPatchV2},
+}
+
+func schemasFor(r *http.Request) companySchemas {
+	return schemasByVersion[APIVersionFrom(r.Context())]
+}
+
+// O texto de endereco da v1 é gerado pelo service a partir do estruturado.
+func decodeCreate(r *http.Request) (CompanyCreateDTO, *models.Address, error) {
+	if APIVersionFrom(r.Context()) != V2 {
+		var dto CompanyCreateDTO
+		err := utils.DecodeStrict(r.Body, &dto)
+		return dto, nil, err
+	}
+	var v2 CompanyCreateV2DTO
+	if err := utils.DecodeStrict(r.Body, &v2); err != nil {
+		return CompanyCreateDTO{}, nil, err
+	}
+	return CompanyCreateDTO{
+		CNPJ:                 v2.CNPJ,
+		NomeFantasia:         v2.NomeFantasia,
+		RazaoSocial:          v2.RazaoSocial,
+		NumeroFuncionarios:   v2.NumeroFuncionarios,
+		NumeroPCDContratados: v2.NumeroPCDContratados,
+		CNAEPrincipal:        v2.CNAEPrincipal,
+		CNAESecundarios:      v2.CNAESecundarios,
+		InscricaoEstadual:    v2.InscricaoEstadual,
+		InscricaoEstadualUF:  v2.InscricaoEstadualUF,
+		InscricaoMunicipal:   v2.InscricaoMunicipal,
+		RegimeTributario:     v2.RegimeTributario,
+		FaturamentoAnual:     v2.FaturamentoAnual,
+		Tags:                 v2.Tags,
+		CustomFields:         v2.CustomFields,
+	}, v2.Endereco, nil
+}
+
+func decodePatch(r *http.Request) (CompanyPatchDTO, *models.Address, error) {
+	if APIVersionFrom(r.Context()) != V2 {
+		var dto CompanyPatchDTO
+		err := utils.DecodeStrict(r.Body, &dto)
+		return dto, nil, err
+	}
+	var v2 CompanyPatchV2DTO
+	if err := utils.DecodeStrict(r.Body, &v2); err != nil {
+		return CompanyPatchDTO{}, nil, err
+	}
+	return CompanyPatchDTO{
+		CNPJ:                 v2.CNPJ,
+		NomeFantasia:         v2.NomeFantasia,
+		RazaoSocial:          v2.RazaoSocial,
+		NumeroFuncionarios:   v2.NumeroFuncionarios,
+		NumeroPCDContratados: v2.NumeroPCDContratados,
+		CNAEPrincipal:        v2.CNAEPrincipal,
+		CNAESecundarios:      v2.CNAESecundarios,
+		InscricaoEstadual:    v2.InscricaoEstadual,
+		InscricaoEstadualUF:  v2.InscricaoEstadualUF,
+		InscricaoMunicipal:   v2.InscricaoMunicipal,
+		RegimeTributario:     v2.RegimeTributario,
+		FaturamentoAnual:     v2.FaturamentoAnual,
+		Tags:                 v2.Tags,
+		CustomFields:         v2.CustomFields,
+	}, v2.Endereco, nil
+}
+
+func decodePut(r *http.Request) (CompanyPutDTO, *models.Address, error) {
+	if APIVersionFrom(r.Context()) != V2 {
+		var dto CompanyPutDTO
+		err := utils.DecodeStrict(r.Body, &dto)
+		return dto, nil, err
+	}
+	var v2 CompanyPutV2DTO
+	if err := utils.DecodeStrict(r.Body, &v2); err != nil {
+		return CompanyPutDTO{}, nil, err
+	}
+	return CompanyPutDTO{
+		CNPJ:                 v2.CNPJ,
+		NomeFantasia:         v2.NomeFantasia,
+		RazaoSocial:          v2.RazaoSocial,
+		NumeroFuncionarios:   v2.NumeroFuncionarios,
+		NumeroPCDContratados: v2.NumeroPCDContratados,
+		CNAEPrincipal:        v2.CNAEPrincipal,
+		CNAESecundarios:      v2.CNAESecundarios,
+		InscricaoEstadual:    v2.InscricaoEstadual,
+		InscricaoEstadualUF:  v2.InscricaoEstadualUF,
+		InscricaoMunicipal:   v2.InscricaoMunicipal,
+		RegimeTributario:     v2.RegimeTributario,
+		FaturamentoAnual:     v2.FaturamentoAnual,
+		Tags:                 v2.Tags,
+		CustomFields:         v2.CustomFields,
+	}, v2.Endereco, nil
+}
+
+// ---------- saída por versão
+
+// formato da resposta pelo Accept (JSON, XML, CSV ou MessagePack)
+
+func writeCompany(w http.ResponseWriter, r *http.Request, status int, c *models.Company) {
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, status, c)
+		return
+	}
+	utils.WriteResponse(w, r, status, Envelope{Data: toCompanyV2(c), Meta: Meta{APIVersion: V2}})
+}
+
+func writeCompanies(w http.ResponseWriter, r *http.Request, list []models.Company, limit, skip int64) {
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	data := make([]CompanyV2, len(list))
+	for i := range list {
+		data[i] = toCompanyV2(&list[i])
+	}
+	count := len(data)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: data,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Contatos da empresa: /api/companies/{id}/contacts[/{contact_id}].
+// Cada mudança publica um evento (contato_cadastro|contato_edição|contato_exclusão).
+
+// Body de POST e PUT (validado por schema/contact.json); igual na v1 e na v2
+type ContactDTO struct {
+	Nome               string `json:"nome"`
+	Cargo              string `json:"cargo"`
+	Email              string `json:"email"`
+	Telefone           string `json:"telefone"`
+	Principal          bool   `json:"principal"`
+	RepresentanteLegal bool   `json:"representante_legal"`
+}
+
+func (d ContactDTO) input() service.ContactInput {
+	return service.ContactInput{
+		Nome: d.Nome, Cargo: d.Cargo, Email: d.Email, Telefone: d.Telefone,
+		Principal: d.Principal, RepresentanteLegal: d.RepresentanteLegal,
+	}
+}
+
+// GET (lista) e POST /api/companies/{id}/contacts
+func (h *CompanyHandler) CompanyContacts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listContacts(w, r)
+	case http.MethodPost:
+		schema.Validate(schema.Contact, http.HandlerFunc(h.createContact)).ServeHTTP(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// GET, PUT e DELETE /api/companies/{id}/contacts/{contact_id}
+func (h *CompanyHandler) CompanyContactByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getContact(w, r)
+	case http.MethodPut:
+		schema.Validate(schema.Contact, http.HandlerFunc(h.replaceContact)).ServeHTTP(w, r)
+	case http.MethodDelete:
+		h.deleteContact(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (h *CompanyHandler) listContacts(w http.ResponseWriter, r *http.Request) {
+	limit, skip := pagination(r.URL.Query())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().ListContacts(ctx, r.PathValue("id"), limit, skip)
+	if err != nil {
+		writeContactError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: list,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
+
+func (h *CompanyHandler) createContact(w http.ResponseWriter, r *http.Request) {
+	var dto ContactDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	c, err := h.service().CreateContact(ctx, r.PathValue("id"), dto.input())
+	if err != nil {
+		writeContactError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusCreated, c)
+}
+
+func (h *CompanyHandler) getContact(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	c, err := h.service().GetContact(ctx, r.PathValue("id"), r.PathValue("contact_id"))
+	if err != nil {
+		writeContactError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, c)
+}
+
+func (h *CompanyHandler) replaceContact(w http.ResponseWriter, r *http.Request) {
+	var dto ContactDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	c, err := h.service().ReplaceContact(ctx, r.PathValue("id"), r.PathValue("contact_id"), dto.input())
+	if err != nil {
+		writeContactError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, c)
+}
+
+func (h *CompanyHandler) deleteContact(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if err := h.service().DeleteContact(ctx, r.PathValue("id"), r.PathValue("contact_id")); err != nil {
+		writeContactError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Empresa ou contato inexistente -> 404, o resto -> 500
+func writeContactError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, service.ErrNotFound) || errors.Is(err, repository.ErrContactNotFound) {
+		utils.NotFound(w, r)
+		return
+	}
+	utils.InternalError(w, r, err)
+}
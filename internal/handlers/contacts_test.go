@@ -0,0 +1,124 @@
+package handlers
+
+/*
+
+go test -run 'TestContacts_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+const contactsPath = "/api/companies/" + companyID + "/contacts"
+
+// reaproveita o fixture dos funcionários (empresa em memória + eventos)
+func newContactsFixture(list ...models.Contact) (*employeesFixture, *contactRepoMock, *[]amqp091.Table) {
+	f := newEmployeesFixture()
+	cm := newContactRepoMock(list...)
+	f.mux = versionedMux(&CompanyHandler{Repo: f.store.repo(), Pub: f.events.pub(), Contacts: cm})
+	return f, cm, &f.events.headers
+}
+
+func TestContacts_CRUDAndPrimary(t *testing.T) {
+	f, cm, published := newContactsFixture(models.Contact{ID: "old", CompanyID: companyID, Nome: "Zeca", Email: "zeca@acme.com", Principal: true})
+
+	rr := f.do(http.MethodPost, contactsPath, "application/json",
+		`{"nome":"Ana Lima","cargo":"RH","email":"Ana@ACME.com.br","telefone":"+55 (11) 98765-4321","principal":true,"representante_legal":true}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var c models.Contact
+	_ = json.Unmarshal(rr.Body.Bytes(), &c)
+	if c.ID == "" || c.Email != "ana@acme.com.br" || c.Telefone != "11987654321" || !c.Principal || !c.RepresentanteLegal {
+		t.Fatalf("contato = %+v", c)
+	}
+	if old, _ := cm.Get(context.Background(), companyID, "old"); old.Principal {
+		t.Fatal("o principal anterior continua marcado")
+	}
+	h := (*published)[0]
+	if h["action"] != events.ActionContactCreated || h["contact_id"] != c.ID || h["company_id"] != companyID {
+		t.Fatalf("evento = %v", h)
+	}
+
+	// lista v2: principal primeiro
+	rr = f.do(http.MethodGet, "/api/v2/companies/"+companyID+"/contacts", "", "")
+	var env struct {
+		Data []models.Contact `json:"data"`
+		Meta Meta             `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 2 || env.Data[0].ID != c.ID || env.Meta.APIVersion != V2 || *env.Meta.Count != 2 {
+		t.Fatalf("lista = %+v", env)
+	}
+
+	path := contactsPath + "/" + c.ID
+	rr = f.do(http.MethodPut, path, "application/json", `{"nome":"Ana Lima","telefone":"(11) 3456-7890"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var put models.Contact
+	_ = json.Unmarshal(rr.Body.Bytes(), &put)
+	if put.ID != c.ID || put.Email != "" || put.Principal || put.Telefone != "1134567890" {
+		t.Fatalf("PUT = %+v", put)
+	}
+
+	if rr := f.do(http.MethodDelete, path, "", ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status=%d", rr.Code)
+	}
+	if rr := f.do(http.MethodGet, path, "", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("GET após DELETE status=%d", rr.Code)
+	}
+	var actions []any
+	for _, h := range *published {
+		actions = append(actions, h["action"])
+	}
+	if len(actions) != 3 || actions[1] != events.ActionContactUpdated || actions[2] != events.ActionContactDeleted {
+		t.Fatalf("eventos = %v", actions)
+	}
+}
+
+func TestContacts_Invalid(t *testing.T) {
+	f, _, published := newContactsFixture()
+
+	cases := []struct {
+		body   string
+		errors map[string]string
+	}{
+		{`{"nome":"Ana","email":"ana@acme"}`, map[string]string{"email": utils.FieldInvalidEmail}},
+		{`{"nome":"Ana","telefone":"98765-4321"}`, map[string]string{"telefone": utils.FieldInvalidPhone}},
+		{`{"nome":"Ana"}`, map[string]string{"email": utils.FieldRequiredOneOf, "telefone": utils.FieldRequiredOneOf}},
+		{`{"email":"ana@acme.com","site":"x"}`, map[string]string{"nome": utils.FieldRequired, "site": utils.FieldUnknown}},
+	}
+	for _, tc := range cases {
+		rr := f.do(http.MethodPost, contactsPath, "application/json", tc.body)
+		errs := problemErrors(t, rr)
+		if rr.Code != http.StatusBadRequest || len(errs) != len(tc.errors) {
+			t.Fatalf("%s: status=%d errors=%v", tc.body, rr.Code, errs)
+		}
+		for field, code := range tc.errors {
+			if errs[field] != code {
+				t.Fatalf("%s: %s = %q, want %q", tc.body, field, errs[field], code)
+			}
+		}
+	}
+
+	if rr := f.do(http.MethodPost, "/api/companies/76986532000101/contacts", "application/json", `{"nome":"Ana","email":"ana@acme.com"}`); rr.Code != http.StatusNotFound {
+		t.Fatalf("empresa inexistente: status=%d", rr.Code)
+	}
+	if rr := f.do(http.MethodPut, contactsPath+"/nope", "application/json", `{"nome":"Ana","email":"ana@acme.com"}`); rr.Code != http.StatusNotFound {
+		t.Fatalf("contato inexistente: status=%d", rr.Code)
+	}
+	if len(*published) != 0 {
+		t.Fatalf("eventos publicados em requisições inválidas: %v", *published)
+	}
+}
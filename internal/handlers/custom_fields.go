@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Definições dos campos personalizados das empresas: /api/custom-fields[/{key}].
+// Os valores ficam em custom_fields de cada empresa e são conferidos com estas definições.
+
+// Body de POST e PUT (validado por schema/custom_field.json); igual na v1 e na v2
+type CustomFieldDTO struct {
+	Key      string   `json:"key,omitempty"` // obrigatória no POST; no PUT, se vier, igual à da rota
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options,omitempty"` // só (e obrigatório) no tipo enum
+	Required bool     `json:"required"`
+}
+
+func (d CustomFieldDTO) input() service.CustomFieldInput {
+	return service.CustomFieldInput{Label: d.Label, Type: d.Type, Options: d.Options, Required: d.Required}
+}
+
+// GET (lista) e POST /api/custom-fields
+func (h *CompanyHandler) CustomFieldDefinitions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listCustomFields(w, r)
+	case http.MethodPost:
+		schema.Validate(schema.CustomField, http.HandlerFunc(h.createCustomField)).ServeHTTP(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// GET, PUT e DELETE /api/custom-fields/{key}
+func (h *CompanyHandler) CustomFieldDefinitionByKey(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getCustomField(w, r)
+	case http.MethodPut:
+		schema.Validate(schema.CustomField, http.HandlerFunc(h.replaceCustomField)).ServeHTTP(w, r)
+	case http.MethodDelete:
+		h.deleteCustomField(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (h *CompanyHandler) listCustomFields(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().ListCustomFields(ctx)
+	if err != nil {
+		writeCustomFieldError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{Data: list, Meta: Meta{APIVersion: V2, Count: &count}})
+}
+
+func (h *CompanyHandler) createCustomField(w http.ResponseWriter, r *http.Request) {
+	var dto CustomFieldDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	errs := validateCustomFieldOptions(dto)
+	if dto.Key == "" {
+		errs = append([]utils.FieldError{{Field: "key", Code: utils.FieldRequired}}, errs...)
+	}
+	if len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	d, err := h.service().CreateCustomField(ctx, dto.Key, dto.input())
+	if err != nil {
+		writeCustomFieldError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusCreated, d)
+}
+
+func (h *CompanyHandler) getCustomField(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	d, err := h.service().GetCustomField(ctx, r.PathValue("key"))
+	if err != nil {
+		writeCustomFieldError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, d)
+}
+
+func (h *CompanyHandler) replaceCustomField(w http.ResponseWriter, r *http.Request) {
+	var dto CustomFieldDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	key := r.PathValue("key")
+	errs := validateCustomFieldOptions(dto)
+	if dto.Key != "" && dto.Key != key {
+		errs = append([]utils.FieldError{{Field: "key", Code: utils.FieldMismatch}}, errs...)
+	}
+	if len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	d, err := h.service().ReplaceCustomField(ctx, key, dto.input())
+	if err != nil {
+		writeCustomFieldError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, d)
+}
+
+func (h *CompanyHandler) deleteCustomField(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if err := h.service().DeleteCustomField(ctx, r.PathValue("key")); err != nil {
+		writeCustomFieldError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// options só no tipo enum (e obrigatório nele)
+func validateCustomFieldOptions(d CustomFieldDTO) []utils.FieldError {
+	switch {
+	case d.Type == models.CustomFieldEnum && len(d.Options) == 0:
+		return []utils.FieldError{{Field: "options", Code: utils.FieldRequired}}
+	case d.Type != models.CustomFieldEnum && d.Options != nil:
+		return []utils.FieldError{{Field: "options", Code: utils.FieldMutuallyExclusive, Args: []any{"type=" + d.Type}}}
+	}
+	return nil
+}
+
+// Definição inexistente -> 404, key repetida -> 409, o resto -> 500
+func writeCustomFieldError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, repository.ErrCustomFieldNotFound):
+		utils.NotFound(w, r)
+	case errors.Is(err, repository.ErrDuplicateCustomField):
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusConflict, utils.CodeCustomFieldConflict, ""))
+	default:
+		utils.InternalError(w, r, err)
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+/*
+
+go test -run 'TestCustomFields_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+func TestCustomFields_DefinitionsCRUD(t *testing.T) {
+	cm := newCustomFieldRepoMock()
+	mux := versionedMux(&CompanyHandler{Repo: &repoMock{}, CustomFields: cm})
+
+	rr := doJSON(mux, http.MethodPost, "/api/v2/custom-fields",
+		`{"key":"segmento","label":"Segmento","type":"enum","options":["varejo","industria","varejo"],"required":true}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var created struct {
+		Data models.CustomFieldDefinition `json:"data"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &created)
+	if d := created.Data; d.Key != "segmento" || !d.Required || !slices.Equal(d.Options, []string{"varejo", "industria"}) || d.CreatedAt.IsZero() {
+		t.Fatalf("definição = %+v", d)
+	}
+
+	// key repetida
+	rr = doJSON(mux, http.MethodPost, "/api/custom-fields", `{"key":"segmento","label":"Outro","type":"string"}`)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("repetida: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	// PUT troca o tipo; options some e created_at fica
+	rr = doJSON(mux, http.MethodPut, "/api/custom-fields/segmento", `{"label":"Segmento","type":"string"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if d := cm.defs["segmento"]; d.Type != models.CustomFieldString || d.Options != nil || d.Required || !d.CreatedAt.Equal(created.Data.CreatedAt) {
+		t.Fatalf("depois do PUT = %+v", d)
+	}
+
+	rr = doJSON(mux, http.MethodGet, "/api/v2/custom-fields", "")
+	var env struct {
+		Data []models.CustomFieldDefinition `json:"data"`
+		Meta Meta                           `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 1 || *env.Meta.Count != 1 {
+		t.Fatalf("lista = %+v", env)
+	}
+
+	if rr = doJSON(mux, http.MethodDelete, "/api/custom-fields/segmento", ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status=%d", rr.Code)
+	}
+	for _, m := range []string{http.MethodGet, http.MethodDelete} {
+		if rr = doJSON(mux, m, "/api/custom-fields/segmento", ""); rr.Code != http.StatusNotFound {
+			t.Fatalf("%s depois do DELETE: status=%d", m, rr.Code)
+		}
+	}
+	if rr = doJSON(mux, http.MethodPut, "/api/custom-fields/segmento", `{"label":"x","type":"date"}`); rr.Code != http.StatusNotFound {
+		t.Fatalf("PUT inexistente: status=%d", rr.Code)
+	}
+}
+
+func TestCustomFields_DefinitionValidation(t *testing.T) {
+	mux := versionedMux(&CompanyHandler{Repo: &repoMock{}, CustomFields: newCustomFieldRepoMock()})
+
+	cases := []struct {
+		name, method, path, body string
+		field, code              string
+	}{
+		{"sem key", http.MethodPost, "/api/custom-fields", `{"label":"x","type":"string"}`, "key", utils.FieldRequired},
+		{"key inválida", http.MethodPost, "/api/custom-fields", `{"key":"Segmento","label":"x","type":"string"}`, "key", utils.FieldInvalidFormat},
+		{"tipo", http.MethodPost, "/api/custom-fields", `{"key":"a","label":"x","type":"texto"}`, "type", utils.FieldNotInEnum},
+		{"enum sem options", http.MethodPost, "/api/custom-fields", `{"key":"a","label":"x","type":"enum"}`, "options", utils.FieldRequired},
+		{"options fora do enum", http.MethodPost, "/api/custom-fields", `{"key":"a","label":"x","type":"number","options":["1"]}`, "options", utils.FieldMutuallyExclusive},
+		{"key diferente da rota", http.MethodPut, "/api/custom-fields/a", `{"key":"b","label":"x","type":"string"}`, "key", utils.FieldMismatch},
+	}
+	for _, tc := range cases {
+		rr := doJSON(mux, tc.method, tc.path, tc.body)
+		if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs[tc.field] != tc.code {
+			t.Errorf("%s: status=%d body=%s", tc.name, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func newCustomFieldsCompanyMux(stored *models.Company) (http.Handler, *models.Company, *models.Company) {
+	var created, updated models.Company
+	rm := &repoMock{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) {
+			c := *stored
+			return &c, nil
+		},
+		CreateFn: func(_ context.Context, c *models.Company) (string, error) {
+			created = *c
+			return c.ID, nil
+		},
+		UpdateFn: func(_ context.Context, _ string, u *models.Company, _ []string) error {
+			updated = *u
+			return nil
+		},
+	}
+	cm := newCustomFieldRepoMock(
+		models.CustomFieldDefinition{Key: "segmento", Type: models.CustomFieldEnum, Options: []string{"varejo", "industria"}, Required: true},
+		models.CustomFieldDefinition{Key: "filiais", Type: models.CustomFieldInteger},
+		models.CustomFieldDefinition{Key: "auditada_em", Type: models.CustomFieldDate},
+		models.CustomFieldDefinition{Key: "observacao", Type: models.CustomFieldString},
+	)
+	return versionedMux(&CompanyHandler{Repo: rm, Pub: &pubMock{}, CustomFields: cm}), &created, &updated
+}
+
+func TestCustomFields_CompanyCreate(t *testing.T) {
+	mux, created, _ := newCustomFieldsCompanyMux(storedCompany())
+
+	rr := doJSON(mux, http.MethodPost, "/api/v2/companies",
+		`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","tags":["Cliente-VIP","Fornecedor","cliente-vip"],"custom_fields":{"segmento":"varejo","filiais":12,"auditada_em":"2025-03-01"}}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if !slices.Equal(created.Tags, []string{"cliente-vip", "fornecedor"}) {
+		t.Fatalf("tags = %v", created.Tags)
+	}
+	if v, ok := created.CustomFields["filiais"].(int64); !ok || v != 12 || created.CustomFields["segmento"] != "varejo" {
+		t.Fatalf("custom_fields = %#v", created.CustomFields)
+	}
+	var env struct {
+		Data CompanyV2 `json:"data"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data.Tags) != 2 || env.Data.CustomFields["auditada_em"] != "2025-03-01" {
+		t.Fatalf("v2 = %+v", env.Data)
+	}
+
+	rr = doJSON(mux, http.MethodPost, "/api/companies",
+		`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","custom_fields":{"segmento":"servicos","filiais":1.5,"auditada_em":"01/03/2025","cor":"azul"}}`)
+	errs := problemErrors(t, rr)
+	if rr.Code != http.StatusBadRequest ||
+		errs["custom_fields.segmento"] != utils.FieldNotInEnum || errs["custom_fields.filiais"] != utils.FieldInvalidType ||
+		errs["custom_fields.auditada_em"] != utils.FieldInvalidDate || errs["custom_fields.cor"] != utils.FieldUnknown {
+		t.Fatalf("inválidos: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	// obrigatório ausente
+	rr = doJSON(mux, http.MethodPost, "/api/companies", `{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME"}`)
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["custom_fields.segmento"] != utils.FieldRequired {
+		t.Fatalf("obrigatório: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = doJSON(mux, http.MethodPost, "/api/companies", `{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","tags":["com espaço"]}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("tag inválida: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCustomFields_CompanyPatch(t *testing.T) {
+	stored := storedCompany()
+	stored.Tags = []string{"cliente-vip"}
+	stored.CustomFields = map[string]any{"segmento": "varejo", "filiais": int64(3), "antigo": "x"}
+	mux, _, updated := newCustomFieldsCompanyMux(stored)
+
+	// merge por chave; null remove (inclusive campo sem definição)
+	rr := doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"custom_fields":{"filiais":4,"antigo":null}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if v, ok := updated.CustomFields["antigo"]; !ok || v != nil || updated.CustomFields["filiais"] != int64(4) || len(updated.CustomFields) != 2 {
+		t.Fatalf("custom_fields = %#v", updated.CustomFields)
+	}
+	if updated.Tags != nil {
+		t.Fatalf("tags sem mudança = %v", updated.Tags)
+	}
+
+	// obrigatório não pode ser removido
+	rr = doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"custom_fields":{"segmento":null}}`)
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["custom_fields.segmento"] != utils.FieldRequired {
+		t.Fatalf("remoção de obrigatório: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	// tags: a lista inteira é trocada; [] remove
+	rr = doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"tags":[]}`)
+	if rr.Code != http.StatusOK || updated.Tags == nil || len(updated.Tags) != 0 {
+		t.Fatalf("tags vazias: status=%d tags=%#v", rr.Code, updated.Tags)
+	}
+}
+
+func TestCustomFields_TagsFilter(t *testing.T) {
+	var got models.CompanyFilter
+	rm := &repoMock{FindFn: func(_ context.Context, f models.CompanyFilter, _, _ int64) ([]models.Company, error) {
+		got = f
+		return []models.Company{}, nil
+	}}
+	mux := versionedMux(&CompanyHandler{Repo: rm})
+
+	rr := doJSON(mux, http.MethodGet, "/api/companies?tags=Cliente-VIP,fornecedor,cliente-vip", "")
+	if rr.Code != http.StatusOK || !slices.Equal(got.Tags, []string{"cliente-vip", "fornecedor"}) {
+		t.Fatalf("status=%d filtro=%+v", rr.Code, got)
+	}
+	rr = doJSON(mux, http.MethodGet, "/api/companies?tags=com%20espaço", "")
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["tags"] != utils.FieldInvalidFormat {
+		t.Fatalf("inválida: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Documentos da empresa: /api/companies/{id}/documents[/{document_id}[/content]].
+// Upload em multipart/form-data (parte "file" e, opcional, "categoria"); o download
+// em /content atende Range. Upload e exclusão publicam evento (documento_cadastro|documento_exclusão).
+
+const (
+	defaultDocumentMaxBytes = 10 << 20
+	documentMaxNameLen      = 255
+	documentMultipartSlack  = 64 << 10 // cabeçalhos e boundaries do multipart
+)
+
+// content-types aceitos -> o que http.DetectContentType devolve para o conteúdo
+var documentContentTypes = map[string]string{
+	"application/pdf": "application/pdf",
+	"image/png":       "image/png",
+	"image/jpeg":      "image/jpeg",
+	"text/plain":      "text/plain",
+	"text/csv":        "text/plain",
+	"application/xml": "text/xml",
+	"text/xml":        "text/xml",
+}
+
+func (h *CompanyHandler) documentMaxBytes() int64 {
+	if h.DocumentMaxBytes > 0 {
+		return h.DocumentMaxBytes
+	}
+	return defaultDocumentMaxBytes
+}
+
+// GET (lista) e POST (upload) /api/companies/{id}/documents
+func (h *CompanyHandler) CompanyDocuments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listDocuments(w, r)
+	case http.MethodPost:
+		h.uploadDocument(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// GET (metadados) e DELETE /api/companies/{id}/documents/{document_id}
+func (h *CompanyHandler) CompanyDocumentByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getDocument(w, r)
+	case http.MethodDelete:
+		h.deleteDocument(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodDelete)
+	}
+}
+
+func (h *CompanyHandler) listDocuments(w http.ResponseWriter, r *http.Request) {
+	limit, skip := pagination(r.URL.Query())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().ListDocuments(ctx, r.PathValue("id"), limit, skip)
+	if err != nil {
+		writeDocumentError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: list,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
+
+func (h *CompanyHandler) uploadDocument(w http.ResponseWriter, r *http.Request) {
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "multipart/form-data" {
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "Content-Type", Code: utils.FieldNotInEnum, Args: []any{"multipart/form-data"}}})
+		return
+	}
+	maxBytes := h.documentMaxBytes()
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+documentMultipartSlack)
+
+	in, errs, err := readDocumentUpload(r, maxBytes)
+	var tooBig *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooBig):
+		errs = []utils.FieldError{{Field: "file", Code: utils.FieldTooLarge, Args: []any{byteSize(maxBytes)}}}
+	case err != nil:
+		utils.BadRequest(w, r, "detail.body_unreadable")
+		return
+	}
+	if len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	d, err := h.service().UploadDocument(ctx, r.PathValue("id"), in)
+	if err != nil {
+		writeDocumentError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusCreated, d)
+}
+
+// readDocumentUpload lê as partes do multipart e confere arquivo, tamanho,
+// content-type (declarado x detectado pelo conteúdo) e categoria.
+func readDocumentUpload(r *http.Request, maxBytes int64) (service.DocumentInput, []utils.FieldError, error) {
+	var in service.DocumentInput
+	var errs []utils.FieldError
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return in, nil, err
+	}
+	hasFile := false
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return in, nil, err
+		}
+		switch name := part.FormName(); name {
+		case "file":
+			if hasFile {
+				errs = append(errs, utils.FieldError{Field: "file", Code: utils.FieldTooManyItems, Args: []any{1}})
+				continue
+			}
+			hasFile = true
+			content, err := io.ReadAll(io.LimitReader(part, maxBytes+1))
+			if err != nil {
+				return in, nil, err
+			}
+			if int64(len(content)) > maxBytes {
+				errs = append(errs, utils.FieldError{Field: "file", Code: utils.FieldTooLarge, Args: []any{byteSize(maxBytes)}})
+				continue
+			}
+			in.NomeArquivo = documentFileName(part.FileName())
+			in.ContentType = part.Header.Get("Content-Type")
+			in.Content = content
+		case "categoria":
+			v, err := io.ReadAll(io.LimitReader(part, 64))
+			if err != nil {
+				return in, nil, err
+			}
+			in.Categoria = strings.ToLower(strings.TrimSpace(string(v)))
+		default:
+			errs = append(errs, utils.FieldError{Field: name, Code: utils.FieldUnknown})
+		}
+	}
+
+	switch {
+	case !hasFile:
+		errs = append(errs, utils.FieldError{Field: "file", Code: utils.FieldRequired})
+	case in.Content == nil && len(errs) > 0:
+		// arquivo recusado acima (tamanho ou repetido)
+	case len(in.Content) == 0:
+		errs = append(errs, utils.FieldError{Field: "file", Code: utils.FieldRequired})
+	default:
+		if in.NomeArquivo == "" {
+			errs = append(errs, utils.FieldError{Field: "file.filename", Code: utils.FieldRequired})
+		} else if len([]rune(in.NomeArquivo)) > documentMaxNameLen {
+			errs = append(errs, utils.FieldError{Field: "file.filename", Code: utils.FieldTooLong, Args: []any{documentMaxNameLen}})
+		}
+		ct, fe := documentContentType(in.ContentType, in.Content)
+		if fe != nil {
+			errs = append(errs, *fe)
+		}
+		in.ContentType = ct
+	}
+	if in.Categoria != "" && !slices.Contains(models.DocumentCategorias, in.Categoria) {
+		errs = append(errs, utils.FieldError{Field: "categoria", Code: utils.FieldNotInEnum, Args: []any{strings.Join(models.DocumentCategorias, ", ")}})
+	}
+	return in, errs, nil
+}
+
+// documentContentType: o content-type declarado na parte tem de estar na lista e
+// conferir com o conteúdo; sem declaração (ou octet-stream) vale o detectado.
+func documentContentType(declared string, content []byte) (string, *utils.FieldError) {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(content))
+	ct, _, _ := mime.ParseMediaType(declared)
+	if ct == "" || ct == "application/octet-stream" {
+		ct = sniffed
+	}
+	want, ok := documentContentTypes[ct]
+	if !ok {
+		allowed := make([]string, 0, len(documentContentTypes))
+		for k := range documentContentTypes {
+			allowed = append(allowed, k)
+		}
+		sort.Strings(allowed)
+		return "", &utils.FieldError{Field: "file.content_type", Code: utils.FieldNotInEnum, Args: []any{strings.Join(allowed, ", ")}}
+	}
+	if sniffed != want {
+		return "", &utils.FieldError{Field: "file.content_type", Code: utils.FieldContentMismatch, Args: []any{sniffed}}
+	}
+	return ct, nil
+}
+
+// só o nome do arquivo, sem diretórios (inclusive os do Windows)
+func documentFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+// tamanho legível para as mensagens de erro (10 MB, 512 KB, 100 bytes)
+func byteSize(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
+func (h *CompanyHandler) getDocument(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	d, err := h.service().GetDocument(ctx, r.PathValue("id"), r.PathValue("document_id"))
+	if err != nil {
+		writeDocumentError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, d)
+}
+
+func (h *CompanyHandler) deleteDocument(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	if err := h.service().DeleteDocument(ctx, r.PathValue("id"), r.PathValue("document_id")); err != nil {
+		writeDocumentError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/companies/{id}/documents/{document_id}/content
+// Devolve o arquivo como foi enviado; Range, If-Range e If-None-Match (ETag = SHA-256)
+// ficam com http.ServeContent.
+func (h *CompanyHandler) DocumentContent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodHead)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+	d, content, err := h.service().OpenDocument(ctx, r.PathValue("id"), r.PathValue("document_id"))
+	if err != nil {
+		writeDocumentError(w, r, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", d.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": d.NomeArquivo}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", `"`+d.SHA256+`"`)
+	if sum, err := hex.DecodeString(d.SHA256); err == nil {
+		w.Header().Set("Repr-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(sum)+":")
+	}
+	http.ServeContent(w, r, d.NomeArquivo, d.CreatedAt, content)
+}
+
+// Empresa ou documento inexistente -> 404, o resto -> 500
+func writeDocumentError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, service.ErrNotFound) || errors.Is(err, repository.ErrDocumentNotFound) {
+		utils.NotFound(w, r)
+		return
+	}
+	utils.InternalError(w, r, err)
+}
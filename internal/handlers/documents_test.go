@@ -0,0 +1,200 @@
+package handlers
+
+/*
+
+go test -run 'TestDocuments_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+const documentsPath = "/api/companies/" + companyID + "/documents"
+
+// início de um PDF (o suficiente para http.DetectContentType)
+var pdfContent = []byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\nendobj\n%%EOF\n")
+
+func newDocumentsFixture(maxBytes int64) (*employeesFixture, *documentRepoMock, *[]amqp091.Table) {
+	f := newEmployeesFixture()
+	dm := newDocumentRepoMock()
+	f.mux = versionedMux(&CompanyHandler{Repo: f.store.repo(), Pub: f.events.pub(), Documents: dm, DocumentMaxBytes: maxBytes})
+	return f, dm, &f.events.headers
+}
+
+// multipartBody monta o upload; fields são partes simples (categoria, ...)
+func multipartBody(t *testing.T, filename, contentType string, content []byte, fields map[string]string) (string, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		_ = mw.WriteField(k, v)
+	}
+	if content != nil {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", `form-data; name="file"; filename="`+filename+`"`)
+		if contentType != "" {
+			h.Set("Content-Type", contentType)
+		}
+		pw, err := mw.CreatePart(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = pw.Write(content)
+	}
+	_ = mw.Close()
+	return mw.FormDataContentType(), buf.String()
+}
+
+func TestDocuments_UploadListDownload(t *testing.T) {
+	f, _, published := newDocumentsFixture(0)
+
+	ct, body := multipartBody(t, `C:\contratos\Contrato Social.pdf`, "application/pdf", pdfContent, map[string]string{"categoria": "Contrato"})
+	rr := f.do(http.MethodPost, "/api/v2/companies/"+companyID+"/documents", ct, body)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var created struct {
+		Data models.Document `json:"data"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &created)
+	d := created.Data
+	sum := sha256.Sum256(pdfContent)
+	if d.ID == "" || d.NomeArquivo != "Contrato Social.pdf" || d.ContentType != "application/pdf" ||
+		d.Tamanho != int64(len(pdfContent)) || d.SHA256 != hex.EncodeToString(sum[:]) || d.Categoria != models.DocumentContrato {
+		t.Fatalf("documento = %+v", d)
+	}
+	if len(*published) != 1 {
+		t.Fatalf("eventos = %d", len(*published))
+	}
+	if h := (*published)[0]; h["action"] != events.ActionDocumentCreated || h["document_id"] != d.ID || h["sha256"] != d.SHA256 {
+		t.Fatalf("evento = %v", h)
+	}
+
+	// sem content-type declarado: vale o detectado
+	ct, body = multipartBody(t, "notas.txt", "", []byte("linha 1\nlinha 2\n"), nil)
+	if rr = f.do(http.MethodPost, documentsPath, ct, body); rr.Code != http.StatusCreated || !strings.Contains(rr.Body.String(), `"content_type":"text/plain"`) {
+		t.Fatalf("txt: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = f.do(http.MethodGet, "/api/v2/companies/"+companyID+"/documents", "", "")
+	var env struct {
+		Data []models.Document `json:"data"`
+		Meta Meta              `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 2 || env.Meta.APIVersion != V2 || *env.Meta.Count != 2 {
+		t.Fatalf("lista = %+v", env)
+	}
+
+	content := documentsPath + "/" + d.ID + "/content"
+	rr = f.do(http.MethodGet, content, "", "")
+	if rr.Code != http.StatusOK || !bytes.Equal(rr.Body.Bytes(), pdfContent) {
+		t.Fatalf("download: status=%d", rr.Code)
+	}
+	if rr.Header().Get("Content-Type") != "application/pdf" || rr.Header().Get("ETag") != `"`+d.SHA256+`"` ||
+		!strings.HasPrefix(rr.Header().Get("Content-Disposition"), "attachment") || rr.Header().Get("Repr-Digest") == "" {
+		t.Fatalf("headers = %v", rr.Header())
+	}
+
+	// Range e If-None-Match
+	req := httptest.NewRequest(http.MethodGet, content, nil)
+	req.Header.Set("Range", "bytes=0-7")
+	rr = httptest.NewRecorder()
+	f.mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusPartialContent || rr.Body.String() != "%PDF-1.7" ||
+		rr.Header().Get("Content-Range") != "bytes 0-7/"+strconv.Itoa(len(pdfContent)) {
+		t.Fatalf("range: status=%d headers=%v body=%q", rr.Code, rr.Header(), rr.Body.String())
+	}
+	req = httptest.NewRequest(http.MethodGet, content, nil)
+	req.Header.Set("If-None-Match", `"`+d.SHA256+`"`)
+	rr = httptest.NewRecorder()
+	f.mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match: status=%d", rr.Code)
+	}
+}
+
+func TestDocuments_UploadValidation(t *testing.T) {
+	f, dm, published := newDocumentsFixture(64)
+
+	// não é multipart
+	rr := f.do(http.MethodPost, documentsPath, "application/json", `{}`)
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["Content-Type"] != utils.FieldNotInEnum {
+		t.Fatalf("json: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	cases := []struct {
+		name, filename, contentType string
+		content                     []byte
+		fields                      map[string]string
+		field, code                 string
+	}{
+		{"sem arquivo", "", "", nil, map[string]string{"categoria": "outro"}, "file", utils.FieldRequired},
+		{"vazio", "a.pdf", "application/pdf", []byte{}, nil, "file", utils.FieldRequired},
+		{"grande", "a.pdf", "application/pdf", bytes.Repeat([]byte("x"), 65), nil, "file", utils.FieldTooLarge},
+		{"tipo fora da lista", "a.exe", "application/x-msdownload", []byte("MZ\x90\x00"), nil, "file.content_type", utils.FieldNotInEnum},
+		{"conteúdo diferente", "foto.png", "image/png", pdfContent[:40], nil, "file.content_type", utils.FieldContentMismatch},
+		{"categoria", "a.txt", "text/plain", []byte("ok"), map[string]string{"categoria": "boleto"}, "categoria", utils.FieldNotInEnum},
+		{"parte desconhecida", "a.txt", "text/plain", []byte("ok"), map[string]string{"descricao": "x"}, "descricao", utils.FieldUnknown},
+	}
+	for _, tc := range cases {
+		ct, body := multipartBody(t, tc.filename, tc.contentType, tc.content, tc.fields)
+		rr := f.do(http.MethodPost, documentsPath, ct, body)
+		if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs[tc.field] != tc.code {
+			t.Errorf("%s: status=%d body=%s", tc.name, rr.Code, rr.Body.String())
+		}
+	}
+	if len(dm.docs) != 0 || len(*published) != 0 {
+		t.Fatalf("gravou %d documentos e %d eventos", len(dm.docs), len(*published))
+	}
+
+	// a mensagem traz o limite legível
+	ct, body := multipartBody(t, "a.pdf", "application/pdf", bytes.Repeat([]byte("x"), 65), nil)
+	rr = f.do(http.MethodPost, documentsPath, ct, body)
+	if !strings.Contains(rr.Body.String(), "64 bytes") {
+		t.Fatalf("mensagem = %s", rr.Body.String())
+	}
+}
+
+func TestDocuments_DeleteAndNotFound(t *testing.T) {
+	f, _, published := newDocumentsFixture(0)
+	ct, body := multipartBody(t, "cartao.pdf", "application/pdf", pdfContent, map[string]string{"categoria": "cartao_cnpj"})
+	rr := f.do(http.MethodPost, documentsPath, ct, body)
+	var d models.Document
+	_ = json.Unmarshal(rr.Body.Bytes(), &d)
+
+	if rr = f.do(http.MethodGet, documentsPath+"/"+d.ID, "", ""); rr.Code != http.StatusOK {
+		t.Fatalf("GET status=%d", rr.Code)
+	}
+	if rr = f.do(http.MethodDelete, documentsPath+"/"+d.ID, "", ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if h := (*published)[len(*published)-1]; h["action"] != events.ActionDocumentDeleted || h["document_id"] != d.ID {
+		t.Fatalf("evento = %v", h)
+	}
+	for _, p := range []string{documentsPath + "/" + d.ID, documentsPath + "/" + d.ID + "/content"} {
+		if rr = f.do(http.MethodGet, p, "", ""); rr.Code != http.StatusNotFound {
+			t.Fatalf("%s: status=%d", p, rr.Code)
+		}
+	}
+	if rr = f.do(http.MethodGet, "/api/companies/99999999000191/documents", "", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("empresa inexistente: status=%d", rr.Code)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Duplicatas: /api/companies/{id}/duplicates (candidatas por semelhança),
+// /api/companies/{id}/merge (fusão) e /api/companies/{id}/merges (histórico).
+
+// Body de POST /api/companies/{id}/merge (validado por schema/company_merge.json)
+type MergeDTO struct {
+	DuplicateID string `json:"duplicate_id"`
+}
+
+// DuplicateCandidateV2: candidata com a empresa no formato da v2
+type DuplicateCandidateV2 struct {
+	Company CompanyV2              `json:"company"`
+	Score   float64                `json:"score"`
+	Scores  models.DuplicateScores `json:"scores"`
+}
+
+// GET /api/companies/{id}/duplicates?min_score=0.6
+func (h *CompanyHandler) CompanyDuplicates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	q := r.URL.Query()
+	minScore := service.DefaultDuplicateMinScore
+	if v := q.Get("min_score"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		switch {
+		case err != nil:
+			utils.ValidationFailed(w, r, []utils.FieldError{{Field: "min_score", Code: utils.FieldInvalidType, Args: []any{"number"}}})
+			return
+		case f < 0:
+			utils.ValidationFailed(w, r, []utils.FieldError{{Field: "min_score", Code: utils.FieldTooSmall, Args: []any{0}}})
+			return
+		case f > 1:
+			utils.ValidationFailed(w, r, []utils.FieldError{{Field: "min_score", Code: utils.FieldTooLarge, Args: []any{1}}})
+			return
+		}
+		minScore = f
+	}
+	limit, skip := pagination(q)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	list, truncated, err := h.service().FindDuplicates(ctx, r.PathValue("id"), minScore, limit, skip)
+	if err != nil {
+		writeRepoError(w, r, err)
+		return
+	}
+	if truncated {
+		w.Header().Set("X-Candidates-Truncated", "true")
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	out := make([]DuplicateCandidateV2, len(list))
+	for i, c := range list {
+		out[i] = DuplicateCandidateV2{Company: toCompanyV2(&c.Company), Score: c.Score, Scores: c.Scores}
+	}
+	count := len(out)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: out,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count, Truncated: truncated},
+	})
+}
+
+// POST /api/companies/{id}/merge {"duplicate_id": "..."}: devolve a empresa que ficou
+// (ou 202 com o pedido de mudança, se a aprovação de exclusão estiver ligada)
+func (h *CompanyHandler) MergeCompany(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.MethodNotAllowed(w, r, http.MethodPost)
+		return
+	}
+	schema.Validate(schema.CompanyMerge, http.HandlerFunc(h.merge)).ServeHTTP(w, r)
+}
+
+func (h *CompanyHandler) merge(w http.ResponseWriter, r *http.Request) {
+	var dto MergeDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(withUser(r), 30*time.Second)
+	defer cancel()
+	c, _, err := h.service().MergeCompanies(ctx, r.PathValue("id"), utils.SanitizeCNPJ(dto.DuplicateID))
+	if errors.Is(err, service.ErrMergeSelf) {
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "duplicate_id", Code: utils.FieldSelfReference}})
+		return
+	}
+	if err != nil {
+		writeRepoError(w, r, err)
+		return
+	}
+	writeCompany(w, r, http.StatusOK, c)
+}
+
+// GET /api/companies/{id}/merges: fusões em que a empresa ficou, mais recentes primeiro
+func (h *CompanyHandler) CompanyMerges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	limit, skip := pagination(r.URL.Query())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().ListMerges(ctx, r.PathValue("id"), limit, skip)
+	if err != nil {
+		writeRepoError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: list,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
@@ -0,0 +1,245 @@
+package handlers
+
+/*
+
+go test -run 'TestDuplicates_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	dupTypoID   = "11222333000262" // CNPJ a três edições de companyID, mesmo nome e endereço
+	dupOtherID  = "45321456000191" // nada em comum
+	dupSimilarN = "98765432000198" // CNPJ diferente, mesmo nome escrito de outro jeito
+)
+
+// duplicatesFixture: empresas em memória com funcionários e histórico de fusões
+type duplicatesFixture struct {
+	store  *companyStore
+	emps   *employeeRepoMock
+	merges *mergeRepoMock
+	tx     *txMock
+	events eventLog
+	h      *CompanyHandler // ajustável depois (ex.: aprovação)
+	mux    http.Handler
+}
+
+func newDuplicatesFixture() *duplicatesFixture {
+	target := *storedCompany()
+	target.NomeFantasia, target.RazaoSocial = "ACME", "Acme Comércio Ltda"
+	target.Tags = []string{"cliente"}
+	target.CreatedAt = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	f := &duplicatesFixture{store: newCompanyStore(target), emps: newEmployeeRepoMock(), merges: &mergeRepoMock{}, tx: &txMock{}}
+
+	f.store.companies[dupTypoID] = models.Company{
+		ID: dupTypoID, CNPJ: dupTypoID, RazaoSocial: "ACME COMERCIO LTDA - ME",
+		Endereco:           "Avenida Paulista, 1.000 - Sao Paulo/SP",
+		InscricaoMunicipal: "12345",
+		Tags:               []string{"fornecedor"},
+		CNAEPrincipal:      "4711302",
+		CreatedAt:          time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		EnderecoEstruturado: &models.Address{
+			Logradouro: "Avenida Paulista", Numero: "1000", Municipio: "Sao Paulo", UF: "SP", CEP: "01310100",
+		},
+	}
+	f.store.companies[dupSimilarN] = models.Company{ID: dupSimilarN, CNPJ: dupSimilarN, NomeFantasia: "Acme S.A."}
+	f.store.companies[dupOtherID] = models.Company{ID: dupOtherID, CNPJ: dupOtherID, NomeFantasia: "Padaria do Bairro", Endereco: "Rua das Flores, 10 - Recife/PE"}
+
+	f.h = &CompanyHandler{Repo: f.store.repo(), Pub: f.events.pub(), Employees: f.emps, Merges: f.merges, Tx: f.tx}
+	f.mux = versionedMux(f.h)
+	return f
+}
+
+func TestDuplicates_Ranking(t *testing.T) {
+	f := newDuplicatesFixture()
+
+	rr := doJSON(f.mux, http.MethodGet, "/api/companies/"+companyID+"/duplicates?min_score=0.5", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var list []models.DuplicateCandidate
+	_ = json.Unmarshal(rr.Body.Bytes(), &list)
+	if len(list) != 2 || list[0].Company.ID != dupTypoID || list[1].Company.ID != dupSimilarN {
+		t.Fatalf("candidatas = %+v", list)
+	}
+	if s := list[0].Scores; s.CNPJ != 0.25 || s.Nome != 1 || s.Endereco != 1 || list[0].Score != 0.7 {
+		t.Fatalf("notas da primeira = %+v (score %v)", s, list[0].Score)
+	}
+	// sem endereço na candidata, o endereço fica fora da média
+	if s := list[1].Scores; s.CNPJ != 0 || s.Nome != 1 || s.Endereco != 0 || list[1].Score != 0.5 {
+		t.Fatalf("notas da segunda = %+v (score %v)", s, list[1].Score)
+	}
+
+	// o min_score padrão (0.6) corta a segunda; v2 vem no envelope
+	rr = doJSON(f.mux, http.MethodGet, "/api/v2/companies/"+companyID+"/duplicates", "")
+	var env struct {
+		Data []DuplicateCandidateV2 `json:"data"`
+		Meta Meta                   `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if rr.Code != http.StatusOK || len(env.Data) != 1 || env.Data[0].Company.ID != dupTypoID || *env.Meta.Count != 1 ||
+		env.Meta.Truncated || rr.Header().Get("X-Candidates-Truncated") != "" {
+		t.Fatalf("v2: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	if rr = doJSON(f.mux, http.MethodGet, "/api/companies/"+dupOtherID+"9/duplicates", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("inexistente: status=%d", rr.Code)
+	}
+}
+
+// o pré-filtro vai para o repositório; além do limite de candidatas, a resposta avisa
+func TestDuplicates_CandidatesPrefilter(t *testing.T) {
+	f := newDuplicatesFixture()
+	for i := range 2001 {
+		id := fmt.Sprintf("9%013d", i)
+		f.store.companies[id] = models.Company{ID: id, CNPJ: id, NomeFantasia: fmt.Sprintf("Acme Filial %d", i)}
+	}
+	rm := f.store.repo()
+	find := rm.FindDuplicateCandidatesFn
+	var got models.DuplicateQuery
+	rm.FindDuplicateCandidatesFn = func(ctx context.Context, q models.DuplicateQuery, limit int64) ([]models.Company, error) {
+		got = q
+		return find(ctx, q, limit)
+	}
+	f.h.Repo = rm
+
+	rr := doJSON(f.mux, http.MethodGet, "/api/v2/companies/"+companyID+"/duplicates?min_score=0.1", "")
+	// "comercio" é comum demais; "ltda" é sufixo
+	if got.ExcludeID != companyID || got.CNPJ != companyID || !slices.Equal(got.NameWords, []string{"acme"}) {
+		t.Fatalf("pré-filtro = %+v", got)
+	}
+	var env struct {
+		Meta Meta `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if rr.Code != http.StatusOK || rr.Header().Get("X-Candidates-Truncated") != "true" || !env.Meta.Truncated {
+		t.Fatalf("status=%d header=%q meta=%+v", rr.Code, rr.Header().Get("X-Candidates-Truncated"), env.Meta)
+	}
+}
+
+func TestDuplicates_MinScoreValidation(t *testing.T) {
+	f := newDuplicatesFixture()
+	for q, code := range map[string]string{
+		"abc": utils.FieldInvalidType,
+		"-1":  utils.FieldTooSmall,
+		"1.5": utils.FieldTooLarge,
+	} {
+		rr := doJSON(f.mux, http.MethodGet, "/api/companies/"+companyID+"/duplicates?min_score="+q, "")
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("min_score=%s: status=%d", q, rr.Code)
+		}
+		if errs := problemErrors(t, rr); len(errs) != 1 || errs["min_score"] != code {
+			t.Fatalf("min_score=%s: errors=%+v", q, errs)
+		}
+	}
+}
+
+func TestDuplicates_Merge(t *testing.T) {
+	f := newDuplicatesFixture()
+	adm := "2024-01-10"
+	f.emps.emps["e1"] = models.Employee{ID: "e1", CompanyID: companyID, Nome: "Ana", CPF: "11144477735", Admissao: adm}
+	f.emps.emps["e2"] = models.Employee{ID: "e2", CompanyID: dupTypoID, Nome: "Ana", CPF: "11144477735", Admissao: adm}
+	f.emps.emps["e3"] = models.Employee{ID: "e3", CompanyID: dupTypoID, Nome: "Bia", CPF: "52998224725", Admissao: adm, PCD: true}
+
+	rr := doJSON(f.mux, http.MethodPost, "/api/v2/companies/"+companyID+"/merge", `{"duplicate_id":"11.222.333/0002-62"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if _, ok := f.store.companies[dupTypoID]; ok {
+		t.Fatal("absorvida continua cadastrada")
+	}
+	c := f.store.companies[companyID]
+	if c.NomeFantasia != "ACME" || c.RazaoSocial != "Acme Comércio Ltda" || c.InscricaoMunicipal != "12345" || c.CNAEPrincipal != "4711302" {
+		t.Fatalf("campos = %+v", c)
+	}
+	if !slices.Equal(c.Tags, []string{"cliente", "fornecedor"}) || !c.CreatedAt.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("tags=%v created_at=%v", c.Tags, c.CreatedAt)
+	}
+	// CPF repetido fica só o da que ficou; o quadro sai dos funcionários
+	if _, ok := f.emps.emps["e2"]; ok || f.emps.emps["e3"].CompanyID != companyID {
+		t.Fatalf("funcionários = %+v", f.emps.emps)
+	}
+	if c.NumeroFuncionarios != 2 || c.NumeroPCDContratados == nil || *c.NumeroPCDContratados != 1 {
+		t.Fatalf("quadro = %d / %v", c.NumeroFuncionarios, c.NumeroPCDContratados)
+	}
+
+	if i := slices.IndexFunc(f.events.headers, func(h amqp091.Table) bool { return h["action"] == events.ActionMerged }); i < 0 ||
+		f.events.headers[i]["company_id"] != companyID || f.events.headers[i]["merged_id"] != dupTypoID {
+		t.Fatalf("eventos = %+v", f.events.headers)
+	}
+
+	// histórico com as duas como estavam antes
+	rr = doJSON(f.mux, http.MethodGet, "/api/companies/"+companyID+"/merges", "")
+	var merges []models.CompanyMerge
+	_ = json.Unmarshal(rr.Body.Bytes(), &merges)
+	if len(merges) != 1 || merges[0].SourceID != dupTypoID || merges[0].Source.RazaoSocial != "ACME COMERCIO LTDA - ME" ||
+		merges[0].Target.NumeroFuncionarios != 150 || merges[0].Moved.Employees != 1 {
+		t.Fatalf("histórico = %+v", merges)
+	}
+	// o funcionário descartado (CPF repetido) fica guardado no histórico
+	if d := merges[0].Dropped.Employees; len(d) != 1 || d[0].ID != "e2" || d[0].CompanyID != dupTypoID {
+		t.Fatalf("descartados = %+v", merges[0].Dropped)
+	}
+	// histórico gravado antes de mover qualquer coisa, tudo numa transação
+	if created := f.merges.created[0]; created.Moved != (models.MergeMoved{}) || created.Source.ID != dupTypoID || f.tx.calls != 1 {
+		t.Fatalf("Create = %+v, transações = %d", created, f.tx.calls)
+	}
+
+	// a que fica absorvida depois leva o histórico junto
+	rr = doJSON(f.mux, http.MethodPost, "/api/companies/"+dupSimilarN+"/merge", `{"duplicate_id":"`+companyID+`"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("segunda fusão: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = doJSON(f.mux, http.MethodGet, "/api/v2/companies/"+dupSimilarN+"/merges", "")
+	var env struct {
+		Data []models.CompanyMerge `json:"data"`
+		Meta Meta                  `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 2 || env.Data[0].SourceID != companyID || env.Data[0].Moved.Merges != 1 {
+		t.Fatalf("histórico v2 = %s", rr.Body.String())
+	}
+}
+
+func TestDuplicates_MergeErrors(t *testing.T) {
+	f := newDuplicatesFixture()
+	path := "/api/companies/" + companyID + "/merge"
+
+	rr := doJSON(f.mux, http.MethodPost, path, `{"duplicate_id":"`+companyID+`"}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("própria: status=%d", rr.Code)
+	}
+	if errs := problemErrors(t, rr); len(errs) != 1 || errs["duplicate_id"] != utils.FieldSelfReference {
+		t.Fatalf("própria: errors=%+v", errs)
+	}
+
+	rr = doJSON(f.mux, http.MethodPost, path, `{"duplicate_id":"123"}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("CNPJ inválido: status=%d", rr.Code)
+	}
+	if rr = doJSON(f.mux, http.MethodPost, path, `{}`); rr.Code != http.StatusBadRequest {
+		t.Fatalf("sem duplicate_id: status=%d", rr.Code)
+	}
+
+	// CNPJ válido sem cadastro
+	if rr = doJSON(f.mux, http.MethodPost, path, `{"duplicate_id":"11222333000343"}`); rr.Code != http.StatusNotFound {
+		t.Fatalf("inexistente: status=%d", rr.Code)
+	}
+	if len(f.store.companies) != 4 || len(f.merges.merges) != 0 || len(f.events.headers) != 0 {
+		t.Fatalf("erro mexeu nos dados: %d empresas, %d fusões, %d eventos", len(f.store.companies), len(f.merges.merges), len(f.events.headers))
+	}
+}
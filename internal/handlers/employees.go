@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Funcionários da empresa: /api/companies/{id}/employees[/{employee_id}].
+// Cada mudança recalcula numero_funcionarios, numero_pcd_contratados e
+// numero_minimo_pcd_exigidos da empresa (ver service.SyncHeadcount).
+
+// Body de POST e PUT (validado por schema/employee.json); igual na v1 e na v2
+type EmployeeDTO struct {
+	Nome         string  `json:"nome"`
+	CPF          string  `json:"cpf"`
+	Admissao     string  `json:"admissao"`
+	Desligamento *string `json:"desligamento"`
+	PCD          bool    `json:"pcd"`
+}
+
+func (d EmployeeDTO) input() service.EmployeeInput {
+	return service.EmployeeInput{Nome: d.Nome, CPF: d.CPF, Admissao: d.Admissao, Desligamento: d.Desligamento, PCD: d.PCD}
+}
+
+// GET (lista) e POST /api/companies/{id}/employees
+func (h *CompanyHandler) CompanyEmployees(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listEmployees(w, r)
+	case http.MethodPost:
+		schema.Validate(schema.Employee, http.HandlerFunc(h.createEmployee)).ServeHTTP(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// GET, PUT e DELETE /api/companies/{id}/employees/{employee_id}
+func (h *CompanyHandler) CompanyEmployeeByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getEmployee(w, r)
+	case http.MethodPut:
+		schema.Validate(schema.Employee, http.HandlerFunc(h.replaceEmployee)).ServeHTTP(w, r)
+	case http.MethodDelete:
+		h.deleteEmployee(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+// POST /api/companies/{id}/employees/recount: recalcula os números da empresa
+// (ex.: um desligamento com data futura chegou à data)
+func (h *CompanyHandler) RecountEmployees(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.MethodNotAllowed(w, r, http.MethodPost)
+		return
+	}
+	ctx, cancel := context.WithTimeout(withUser(r), 5*time.Second)
+	defer cancel()
+	hc, err := h.service().SyncHeadcount(ctx, r.PathValue("id"))
+	if err != nil {
+		writeEmployeeError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, hc)
+}
+
+func (h *CompanyHandler) listEmployees(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, skip := pagination(q)
+	f, errs := parseEmployeeFilter(q)
+	if len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().ListEmployees(ctx, r.PathValue("id"), f, limit, skip)
+	if err != nil {
+		writeEmployeeError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: list,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
+
+// ?active_on=YYYY-MM-DD (ativos na data), ?active=true (ativos hoje), ?pcd=true|false
+func parseEmployeeFilter(q url.Values) (models.EmployeeFilter, []utils.FieldError) {
+	var (
+		f    models.EmployeeFilter
+		errs []utils.FieldError
+	)
+	if v := q.Get("active_on"); v != "" {
+		if _, err := time.Parse(time.DateOnly, v); err != nil {
+			errs = append(errs, utils.FieldError{Field: "active_on", Code: utils.FieldInvalidDate})
+		}
+		f.ActiveOn = v
+	}
+	if v := q.Get("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		switch {
+		case err != nil:
+			errs = append(errs, utils.FieldError{Field: "active", Code: utils.FieldInvalidType, Args: []any{"boolean"}})
+		case active && f.ActiveOn == "":
+			f.ActiveOn = time.Now().Format(time.DateOnly)
+		}
+	}
+	if v := q.Get("pcd"); v != "" {
+		pcd, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "pcd", Code: utils.FieldInvalidType, Args: []any{"boolean"}})
+		}
+		f.PCD = &pcd
+	}
+	return f, errs
+}
+
+func (h *CompanyHandler) createEmployee(w http.ResponseWriter, r *http.Request) {
+	dto, ok := decodeEmployee(w, r)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(withUser(r), 5*time.Second)
+	defer cancel()
+	e, err := h.service().CreateEmployee(ctx, r.PathValue("id"), dto.input())
+	if err != nil {
+		writeEmployeeError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusCreated, e)
+}
+
+func (h *CompanyHandler) getEmployee(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	e, err := h.service().GetEmployee(ctx, r.PathValue("id"), r.PathValue("employee_id"))
+	if err != nil {
+		writeEmployeeError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, e)
+}
+
+func (h *CompanyHandler) replaceEmployee(w http.ResponseWriter, r *http.Request) {
+	dto, ok := decodeEmployee(w, r)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(withUser(r), 5*time.Second)
+	defer cancel()
+	e, err := h.service().ReplaceEmployee(ctx, r.PathValue("id"), r.PathValue("employee_id"), dto.input())
+	if err != nil {
+		writeEmployeeError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, e)
+}
+
+func (h *CompanyHandler) deleteEmployee(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(withUser(r), 5*time.Second)
+	defer cancel()
+	if err := h.service().DeleteEmployee(ctx, r.PathValue("id"), r.PathValue("employee_id")); err != nil {
+		writeEmployeeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeEmployee: body já validado pelo schema; falta a ordem das datas
+func decodeEmployee(w http.ResponseWriter, r *http.Request) (EmployeeDTO, bool) {
+	var dto EmployeeDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return dto, false
+	}
+	if errs := validateEmployeeDates(dto.Admissao, dto.Desligamento, ""); len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return dto, false
+	}
+	return dto, true
+}
+
+// writeData: v1 devolve o objeto; v2 dentro do envelope
+func writeData(w http.ResponseWriter, r *http.Request, status int, data any) {
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, status, data)
+		return
+	}
+	utils.WriteResponse(w, r, status, Envelope{Data: data, Meta: Meta{APIVersion: V2}})
+}
+
+// Empresa ou funcionário inexistente -> 404, CPF repetido na empresa -> 409, o resto -> 500.
+// Queda de funcionários que exige aprovação -> 202 com o pedido (sem X-User -> 400).
+func writeEmployeeError(w http.ResponseWriter, r *http.Request, err error) {
+	var pending *service.ApprovalRequiredError
+	switch {
+	case errors.As(err, &pending), errors.Is(err, service.ErrUserRequired):
+		writeRepoError(w, r, err)
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, repository.ErrEmployeeNotFound):
+		utils.NotFound(w, r)
+	case errors.Is(err, repository.ErrDuplicateCPF):
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusConflict, utils.CodeCPFConflict, ""))
+	default:
+		utils.InternalError(w, r, err)
+	}
+}
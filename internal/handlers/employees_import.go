@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Importação do quadro de funcionários em CSV (POST /api/companies/{id}/employees/import).
+// Cabeçalho obrigatório na 1ª linha, em qualquer ordem: nome, cpf, admissao e,
+// opcionais, desligamento e pcd. Separador "," ou ";" (detectado pelo cabeçalho).
+// Datas em YYYY-MM-DD ou DD/MM/AAAA; pcd: sim/não, s/n, true/false, 1/0 (vazio = não).
+// Tudo ou nada: qualquer linha inválida recusa o arquivo inteiro.
+
+const (
+	importMaxBytes = 5 << 20
+	importMaxRows  = 10000
+)
+
+var (
+	importColumns  = []string{"nome", "cpf", "admissao", "desligamento", "pcd"}
+	importRequired = []string{"nome", "cpf", "admissao"}
+	importBool     = map[string]bool{
+		"": false, "0": false, "n": false, "nao": false, "não": false, "false": false,
+		"1": true, "s": true, "sim": true, "true": true, "x": true,
+	}
+)
+
+func (h *CompanyHandler) ImportEmployees(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.MethodNotAllowed(w, r, http.MethodPost)
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		if mt, _, _ := mime.ParseMediaType(ct); mt != "text/csv" && mt != "text/plain" {
+			utils.ValidationFailed(w, r, []utils.FieldError{{Field: "Content-Type", Code: utils.FieldNotInEnum, Args: []any{"text/csv"}}})
+			return
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, importMaxBytes+1))
+	if err != nil {
+		utils.BadRequest(w, r, "detail.body_unreadable")
+		return
+	}
+	if len(body) > importMaxBytes {
+		utils.BadRequest(w, r, "detail.csv_too_large")
+		return
+	}
+	list, errs, err := parseEmployeesCSV(body)
+	if err != nil {
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusBadRequest, utils.CodeBadRequest, i18n.T(i18n.FromRequest(r), "detail.csv_invalid", err)))
+		return
+	}
+	if len(errs) > 0 {
+		utils.ValidationFailed(w, r, errs)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(withUser(r), 30*time.Second)
+	defer cancel()
+	res, err := h.service().ImportEmployees(ctx, r.PathValue("id"), list)
+	if err != nil {
+		writeEmployeeError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, res)
+}
+
+// parseEmployeesCSV: err = CSV ilegível; errs = violações por linha ("line[N].campo", N = linha do arquivo)
+func parseEmployeesCSV(body []byte) ([]service.EmployeeInput, []utils.FieldError, error) {
+	body = bytes.TrimPrefix(body, []byte("\ufeff")) // BOM do Excel
+	first, _, _ := bufio.NewReader(bytes.NewReader(body)).ReadLine()
+
+	cr := csv.NewReader(bytes.NewReader(body))
+	if strings.Count(string(first), ";") > strings.Count(string(first), ",") {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, []utils.FieldError{{Field: "csv", Code: utils.FieldTooFewItems, Args: []any{1}}}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	cols, errs := importHeader(header)
+	if len(errs) > 0 {
+		return nil, errs, nil
+	}
+
+	var (
+		list []service.EmployeeInput
+		cpfs = map[string]int{} // cpf -> linha em que apareceu
+	)
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if len(rec) == 1 && strings.TrimSpace(rec[0]) == "" {
+			continue // linha em branco
+		}
+		if len(list) == importMaxRows {
+			return nil, []utils.FieldError{{Field: "csv", Code: utils.FieldTooManyItems, Args: []any{importMaxRows}}}, nil
+		}
+
+		prefix := fmt.Sprintf("line[%d].", line)
+		in, rowErrs := importRow(rec, cols, prefix)
+		if n, dup := cpfs[in.CPF]; dup && in.CPF != "" {
+			rowErrs = append(rowErrs, utils.FieldError{Field: prefix + "cpf", Code: utils.FieldDuplicate, Args: []any{n}})
+		} else {
+			cpfs[in.CPF] = line
+		}
+		errs = append(errs, rowErrs...)
+		list = append(list, in)
+	}
+	if len(list) == 0 && len(errs) == 0 {
+		errs = append(errs, utils.FieldError{Field: "csv", Code: utils.FieldTooFewItems, Args: []any{1}})
+	}
+	return list, errs, nil
+}
+
+// importHeader: coluna -> índice; colunas obrigatórias ausentes ou desconhecidas são erro
+func importHeader(header []string) (map[string]int, []utils.FieldError) {
+	cols := map[string]int{}
+	var errs []utils.FieldError
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		known := false
+		for _, c := range importColumns {
+			known = known || c == name
+		}
+		if !known {
+			errs = append(errs, utils.FieldError{Field: "header." + name, Code: utils.FieldUnknown})
+			continue
+		}
+		cols[name] = i
+	}
+	for _, c := range importRequired {
+		if _, ok := cols[c]; !ok {
+			errs = append(errs, utils.FieldError{Field: "header." + c, Code: utils.FieldRequired})
+		}
+	}
+	return cols, errs
+}
+
+// importRow monta o funcionário da linha e valida com o mesmo schema do POST
+func importRow(rec []string, cols map[string]int, prefix string) (service.EmployeeInput, []utils.FieldError) {
+	get := func(col string) (string, bool) {
+		i, ok := cols[col]
+		if !ok || i >= len(rec) {
+			return "", false
+		}
+		return strings.TrimSpace(rec[i]), true
+	}
+
+	doc := map[string]any{}
+	var errs []utils.FieldError
+	for _, col := range []string{"nome", "cpf", "admissao", "desligamento"} {
+		if v, ok := get(col); ok && v != "" {
+			if col == "admissao" || col == "desligamento" {
+				v = importDate(v)
+			}
+			doc[col] = v
+		}
+	}
+	if v, ok := get("pcd"); ok {
+		pcd, known := importBool[strings.ToLower(v)]
+		if !known {
+			errs = append(errs, utils.FieldError{Field: prefix + "pcd", Code: utils.FieldNotInEnum, Args: []any{"sim, não"}})
+		}
+		doc["pcd"] = pcd
+	}
+	for _, e := range schema.Employee.Validate(doc) {
+		e.Field = prefix + e.Field
+		errs = append(errs, e)
+	}
+
+	in := service.EmployeeInput{}
+	in.Nome, _ = doc["nome"].(string)
+	if cpf, ok := doc["cpf"].(string); ok {
+		in.CPF = utils.SanitizeCNPJ(cpf)
+	}
+	in.Admissao, _ = doc["admissao"].(string)
+	if d, ok := doc["desligamento"].(string); ok {
+		in.Desligamento = &d
+	}
+	in.PCD, _ = doc["pcd"].(bool)
+	if len(errs) == 0 {
+		errs = validateEmployeeDates(in.Admissao, in.Desligamento, prefix)
+	}
+	return in, errs
+}
+
+// DD/MM/AAAA -> AAAA-MM-DD; outros formatos seguem como vieram (o schema acusa)
+func importDate(v string) string {
+	if t, err := time.Parse("02/01/2006", v); err == nil {
+		return t.Format(time.DateOnly)
+	}
+	return v
+}
@@ -0,0 +1,244 @@
+package handlers
+
+/*
+
+go test -run 'TestEmployees_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// empresa em memória + funcionários em memória; events = eventos publicados
+type employeesFixture struct {
+	mux    http.Handler
+	store  *companyStore
+	emps   *employeeRepoMock
+	events eventLog
+}
+
+func newEmployeesFixture(list ...models.Employee) *employeesFixture {
+	f := &employeesFixture{store: newCompanyStore(*storedCompany()), emps: newEmployeeRepoMock(list...)}
+	f.mux = versionedMux(&CompanyHandler{Repo: f.store.repo(), Pub: f.events.pub(), Employees: f.emps})
+	return f
+}
+
+// do: requisição com o Content-Type informado (vazio = sem corpo tipado)
+func (f *employeesFixture) do(method, path, contentType, body string) *httptest.ResponseRecorder {
+	return doJSON(f.mux, method, path, body, "Content-Type", contentType)
+}
+
+const employeesPath = "/api/companies/" + companyID + "/employees"
+
+func TestEmployees_CreateDrivesHeadcount(t *testing.T) {
+	f := newEmployeesFixture()
+
+	rr := f.do(http.MethodPost, employeesPath, "application/json",
+		`{"nome":"Ana","cpf":"529.982.247-25","admissao":"2020-01-10","pcd":true}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var e models.Employee
+	_ = json.Unmarshal(rr.Body.Bytes(), &e)
+	if e.ID == "" || e.CPF != "52998224725" || e.CompanyID != companyID || !e.PCD {
+		t.Fatalf("funcionário = %+v", e)
+	}
+
+	// 150 digitados à mão -> 1 funcionário ativo (1 PCD); cai da faixa 100-200
+	c := f.store.get(companyID)
+	if c.NumeroFuncionarios != 1 || c.NumeroMinimoPCDExigidos != 0 || c.NumeroPCDContratados == nil || *c.NumeroPCDContratados != 1 {
+		t.Fatalf("empresa = %+v", c)
+	}
+	if got := f.events.actions(); len(got) != 2 || got[0] != events.ActionUpdated || got[1] != events.ActionPCDThreshold {
+		t.Fatalf("eventos = %v", got)
+	}
+
+	// desligamento no passado: deixa de contar
+	path := employeesPath + "/" + e.ID
+	rr = f.do(http.MethodPut, path, "application/json",
+		`{"nome":"Ana","cpf":"52998224725","admissao":"2020-01-10","desligamento":"2021-05-31","pcd":true}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if c = f.store.get(companyID); c.NumeroFuncionarios != 0 || *c.NumeroPCDContratados != 0 {
+		t.Fatalf("empresa após desligamento = %+v", c)
+	}
+
+	if rr := f.do(http.MethodDelete, path, "", ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status=%d", rr.Code)
+	}
+	if rr := f.do(http.MethodGet, path, "", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("GET após DELETE status=%d", rr.Code)
+	}
+}
+
+// o recálculo grava só os números: uma edição feita depois da leitura da empresa fica
+func TestEmployees_HeadcountKeepsConcurrentEdit(t *testing.T) {
+	f := newEmployeesFixture()
+	rm := f.store.repo()
+	get, reads := rm.GetByIDFn, 0
+	rm.GetByIDFn = func(ctx context.Context, id string) (*models.Company, error) {
+		c, err := get(ctx, id)
+		// a 2ª leitura é a do recálculo: o PATCH concorrente chega logo depois dela
+		if reads++; reads == 2 {
+			patched := f.store.companies[id]
+			patched.NomeFantasia = "Acme Nova"
+			f.store.companies[id] = patched
+		}
+		return c, err
+	}
+	rm.ReplaceFn = func(context.Context, string, *models.Company) error {
+		t.Fatal("Replace no recálculo")
+		return nil
+	}
+	f.mux = versionedMux(&CompanyHandler{Repo: rm, Pub: f.events.pub(), Employees: f.emps})
+
+	rr := f.do(http.MethodPost, employeesPath, "application/json", `{"nome":"Ana","cpf":"529.982.247-25","admissao":"2020-01-10"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if c := f.store.get(companyID); c.NomeFantasia != "Acme Nova" || c.NumeroFuncionarios != 1 {
+		t.Fatalf("empresa = %+v", c)
+	}
+}
+
+func TestEmployees_Invalid(t *testing.T) {
+	f := newEmployeesFixture(models.Employee{ID: "x1", CompanyID: companyID, Nome: "Bia", CPF: "11144477735", Admissao: "2019-03-01"})
+
+	cases := []struct {
+		body  string
+		field string
+		code  string
+	}{
+		{`{"nome":"Ana","cpf":"52998224724","admissao":"2020-01-10"}`, "cpf", utils.FieldInvalidCPF},
+		{`{"nome":"Ana","cpf":"52998224725","admissao":"10/01/2020"}`, "admissao", utils.FieldInvalidDate},
+		{`{"nome":"Ana","cpf":"52998224725","admissao":"2020-01-10","desligamento":"2019-12-31"}`, "desligamento", utils.FieldTooSmall},
+		{`{"cpf":"52998224725","admissao":"2020-01-10"}`, "nome", utils.FieldRequired},
+	}
+	for _, tc := range cases {
+		rr := f.do(http.MethodPost, employeesPath, "application/json", tc.body)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status=%d", tc.body, rr.Code)
+		}
+		if got := problemErrors(t, rr)[tc.field]; got != tc.code {
+			t.Fatalf("%s: %s = %q, want %q", tc.body, tc.field, got, tc.code)
+		}
+	}
+
+	rr := f.do(http.MethodPost, employeesPath, "application/json", `{"nome":"Outra","cpf":"111.444.777-35","admissao":"2020-01-10"}`)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("CPF repetido: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = f.do(http.MethodGet, "/api/companies/76986532000101/employees", "", "")
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("empresa inexistente: status=%d", rr.Code)
+	}
+}
+
+func TestEmployees_ListFiltersV2(t *testing.T) {
+	f := newEmployeesFixture(
+		models.Employee{ID: "a", CompanyID: companyID, Nome: "Ana", CPF: "52998224725", Admissao: "2020-01-10", PCD: true},
+		models.Employee{ID: "b", CompanyID: companyID, Nome: "Bia", CPF: "11144477735", Admissao: "2019-03-01", Desligamento: ptr("2022-01-01")},
+		models.Employee{ID: "c", CompanyID: "76986532000101", Nome: "Caio", CPF: "52998224725", Admissao: "2019-03-01"},
+	)
+
+	rr := f.do(http.MethodGet, "/api/v2/companies/"+companyID+"/employees?active=true", "", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var env struct {
+		Data []models.Employee `json:"data"`
+		Meta Meta              `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 1 || env.Data[0].ID != "a" || env.Meta.APIVersion != V2 || *env.Meta.Count != 1 {
+		t.Fatalf("ativos = %+v", env)
+	}
+
+	var list []models.Employee
+	rr = f.do(http.MethodGet, employeesPath+"?active_on=2021-06-30&pcd=false", "", "")
+	_ = json.Unmarshal(rr.Body.Bytes(), &list)
+	if len(list) != 1 || list[0].ID != "b" {
+		t.Fatalf("ativos em 2021-06-30, sem PCD = %+v", list)
+	}
+
+	rr = f.do(http.MethodGet, employeesPath+"?active_on=30/06/2021&pcd=talvez", "", "")
+	errs := problemErrors(t, rr)
+	if rr.Code != http.StatusBadRequest || errs["active_on"] != utils.FieldInvalidDate || errs["pcd"] != utils.FieldInvalidType {
+		t.Fatalf("status=%d errors=%v", rr.Code, errs)
+	}
+}
+
+func TestEmployees_ImportCSV(t *testing.T) {
+	f := newEmployeesFixture(models.Employee{ID: "x1", CompanyID: companyID, Nome: "Bia", CPF: "11144477735", Admissao: "2019-03-01"})
+
+	csv := "\ufeffNome;CPF;Admissao;Desligamento;PCD\n" +
+		"Ana;529.982.247-25;10/01/2020;;sim\n" +
+		"\n" +
+		"Bia Souza;111.444.777-35;2019-03-01;;não\n" +
+		"Caio;390.533.447-05;2018-07-15;31/12/2019;\n"
+	rr := f.do(http.MethodPost, employeesPath+"/import", "text/csv; charset=utf-8", csv)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var res service.EmployeeImport
+	_ = json.Unmarshal(rr.Body.Bytes(), &res)
+	if res.Created != 2 || res.Updated != 1 || res.Headcount.NumeroFuncionarios != 2 || res.Headcount.NumeroPCDContratados != 1 ||
+		res.Headcount.AsOf != time.Now().Format(time.DateOnly) {
+		t.Fatalf("importação = %+v", res)
+	}
+	if b, _ := f.emps.Get(context.Background(), companyID, "x1"); b.Nome != "Bia Souza" {
+		t.Fatalf("CPF existente não foi atualizado: %+v", b)
+	}
+	if c := f.store.get(companyID); c.NumeroFuncionarios != 2 {
+		t.Fatalf("empresa = %+v", c)
+	}
+}
+
+func TestEmployees_ImportCSV_Invalid(t *testing.T) {
+	f := newEmployeesFixture()
+
+	csv := "nome,cpf,admissao,pcd\n" +
+		"Ana,52998224725,2020-01-10,sim\n" +
+		"Ana 2,52998224725,2020-01-10,\n" +
+		",123,2020-13-01,talvez\n"
+	rr := f.do(http.MethodPost, employeesPath+"/import", "text/csv", csv)
+	errs := problemErrors(t, rr)
+	want := map[string]string{
+		"line[3].cpf":      utils.FieldDuplicate,
+		"line[4].nome":     utils.FieldRequired,
+		"line[4].cpf":      utils.FieldInvalidCPF,
+		"line[4].admissao": utils.FieldInvalidDate,
+		"line[4].pcd":      utils.FieldNotInEnum,
+	}
+	if rr.Code != http.StatusBadRequest || len(errs) != len(want) {
+		t.Fatalf("status=%d errors=%v", rr.Code, errs)
+	}
+	for field, code := range want {
+		if errs[field] != code {
+			t.Fatalf("%s = %q, want %q (todos: %v)", field, errs[field], code, errs)
+		}
+	}
+	if n, _, _ := f.emps.Count(context.Background(), companyID, "2030-01-01"); n != 0 {
+		t.Fatalf("arquivo inválido gravou %d funcionários", n)
+	}
+
+	rr = f.do(http.MethodPost, employeesPath+"/import", "text/csv", "nome,cpf,salario\n")
+	errs = problemErrors(t, rr)
+	if errs["header.salario"] != utils.FieldUnknown || errs["header.admissao"] != utils.FieldRequired {
+		t.Fatalf("cabeçalho: errors=%v", errs)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
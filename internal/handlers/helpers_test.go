@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// companyStore: empresas em memória atrás de um repoMock (GetAll, GetByID, GetMany, Update,
+// Replace, Delete, FindDuplicateCandidates), com as regras do repositório: o Update
+// parcial é o service.PatchedCompany e toda gravação renova o updated_at
+type companyStore struct {
+	companies map[string]models.Company
+}
+
+func newCompanyStore(list ...models.Company) *companyStore {
+	s := &companyStore{companies: map[string]models.Company{}}
+	for _, c := range list {
+		s.companies[c.ID] = c
+	}
+	return s
+}
+
+// get: cópia da empresa gravada (nil se não existe)
+func (s *companyStore) get(id string) *models.Company {
+	c, ok := s.companies[id]
+	if !ok {
+		return nil
+	}
+	return &c
+}
+
+func (s *companyStore) sorted() []models.Company {
+	list := slices.Collect(maps.Values(s.companies))
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// duplicatePrefilter: o pré-filtro do repositório (trecho do CNPJ na mesma posição
+// ou palavra contida no nome, sem acento)
+func duplicatePrefilter(q models.DuplicateQuery, c models.Company) bool {
+	if len(q.CNPJ) == 14 && len(c.CNPJ) == 14 {
+		for _, b := range [][2]int{{0, 4}, {4, 8}, {8, 11}, {11, 14}} {
+			if q.CNPJ[b[0]:b[1]] == c.CNPJ[b[0]:b[1]] {
+				return true
+			}
+		}
+	}
+	names := utils.FoldText(c.NomeFantasia + " " + c.RazaoSocial)
+	return slices.ContainsFunc(q.NameWords, func(w string) bool { return strings.Contains(names, w) })
+}
+
+func (s *companyStore) repo() *repoMock {
+	return &repoMock{
+		GetAllFn: func(_ context.Context, limit, skip int64) ([]models.Company, error) {
+			list := s.sorted()
+			if skip >= int64(len(list)) {
+				return nil, nil
+			}
+			return list[skip:min(int64(len(list)), skip+limit)], nil
+		},
+		FindDuplicateCandidatesFn: func(_ context.Context, q models.DuplicateQuery, limit int64) ([]models.Company, error) {
+			out := []models.Company{}
+			for _, c := range s.sorted() {
+				if c.ID != q.ExcludeID && duplicatePrefilter(q, c) && int64(len(out)) < limit {
+					out = append(out, c)
+				}
+			}
+			return out, nil
+		},
+		GetByIDFn: func(_ context.Context, id string) (*models.Company, error) {
+			if c := s.get(id); c != nil {
+				return c, nil
+			}
+			return nil, repository.ErrCompanyNotFound
+		},
+		GetManyFn: func(_ context.Context, ids []string) ([]models.Company, error) {
+			out := []models.Company{}
+			for _, id := range ids {
+				if c := s.get(id); c != nil {
+					out = append(out, *c)
+				}
+			}
+			return out, nil
+		},
+		UpdateFn: func(_ context.Context, id string, upd *models.Company, always []string) error {
+			c := s.get(id)
+			if c == nil {
+				return repository.ErrCompanyNotFound
+			}
+			c = service.PatchedCompany(c, upd, always...)
+			c.UpdatedAt = time.Now()
+			s.companies[id] = *c
+			return nil
+		},
+		ReplaceFn: func(_ context.Context, id string, doc *models.Company) error {
+			s.companies[id] = *doc
+			return nil
+		},
+		DeleteFn: func(_ context.Context, id string) error {
+			delete(s.companies, id)
+			return nil
+		},
+	}
+}
+
+// eventLog: pubMock que guarda os headers de cada evento publicado
+type eventLog struct {
+	headers []amqp091.Table
+}
+
+func (l *eventLog) pub() *pubMock {
+	return &pubMock{PublishFn: func(_ context.Context, _ string, h amqp091.Table) error {
+		l.headers = append(l.headers, h)
+		return nil
+	}}
+}
+
+// actions: header "action" dos eventos, na ordem
+func (l *eventLog) actions() []string {
+	out := make([]string, len(l.headers))
+	for i, h := range l.headers {
+		out[i], _ = h["action"].(string)
+	}
+	return out
+}
+
+// doJSON faz a requisição no mux; body não vazio vai como application/json.
+// header: pares nome, valor (valor vazio é ignorado), ex.: "X-User", "ana"
+func doJSON(mux http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		if header[i+1] != "" {
+			req.Header.Set(header[i], header[i+1])
+		}
+	}
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+// decodeProblem: corpo problem+json da resposta
+func decodeProblem(t *testing.T, rr *httptest.ResponseRecorder) utils.Problem {
+	t.Helper()
+	var p utils.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil {
+		t.Fatalf("problem inválido: %v (%s)", err, rr.Body.String())
+	}
+	return p
+}
+
+// problemErrors: errors[] do problem, campo -> código
+func problemErrors(t *testing.T, rr *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+	out := map[string]string{}
+	for _, e := range decodeProblem(t, rr).Errors {
+		out[e.Field] = e.Code
+	}
+	return out
+}
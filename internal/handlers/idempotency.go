@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLen      = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, rec *models.IdempotencyRecord) error
+	Get(ctx context.Context, key string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, status int, contentType string, body []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency implementa o header Idempotency-Key para POST, PUT e PATCH.
+// - primeira chamada: executa o handler e guarda a resposta
+// - repetição com o mesmo payload: devolve a resposta guardada (sem executar de novo)
+// - repetição com payload diferente: 422
+// - repetição enquanto a original ainda executa: 409
+type Idempotency struct {
+	Store IdempotencyStore
+}
+
+func (m *Idempotency) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || m.Store == nil || !isIdempotentMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			utils.ValidationFailed(w, r, []utils.FieldError{{
+				Field: IdempotencyKeyHeader, Code: utils.FieldTooLong, Args: []any{maxIdempotencyKeyLen},
+			}})
+			return
+		}
+
+		// o body fica em memória para o fingerprint: maior que o limite -> 413 (nunca truncado)
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+		var tooBig *http.MaxBytesError
+		if errors.As(err, &tooBig) {
+			p := utils.NewProblem(http.StatusRequestEntityTooLarge, utils.CodePayloadTooLarge, "")
+			p.DetailArgs = []any{byteSize(maxIdempotentRequestBytes)}
+			utils.WriteProblem(w, r, p)
+			return
+		}
+		if err != nil {
+			utils.BadRequest(w, r, "detail.body_unreadable")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fp := requestFingerprint(r.Method, versionedPath(r), body)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		rec := &models.IdempotencyRecord{Key: key, Fingerprint: fp, Method: r.Method, Path: versionedPath(r)}
+		err = m.Store.Reserve(ctx, rec)
+		if errors.Is(err, repository.ErrIdempotencyKeyExists) {
+			prev, getErr := m.Store.Get(ctx, key)
+			if getErr == nil {
+				replayIdempotent(w, r, prev, fp)
+				return
+			}
+			if !errors.Is(getErr, repository.ErrIdempotencyKeyNotFound) {
+				utils.InternalError(w, r, getErr)
+				return
+			}
+			// expirou entre o insert e a leitura: segue como primeira chamada
+			err = m.Store.Reserve(ctx, rec)
+		}
+		if err != nil {
+			utils.InternalError(w, r, err)
+			return
+		}
+
+		rw := &recordingWriter{ResponseWriter: w}
+		next.ServeHTTP(rw, r)
+		if rw.status == 0 {
+			rw.status = http.StatusOK
+		}
+
+		// grava com contexto próprio: o da requisição pode já ter sido cancelado
+		sctx, scancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer scancel()
+		if rw.status >= http.StatusInternalServerError {
+			err = m.Store.Release(sctx, key)
+		} else {
+			err = m.Store.Complete(sctx, key, rw.status, rw.Header().Get("Content-Type"), rw.buf.Bytes())
+		}
+		if err != nil {
+			slog.Error("idempotency_store_error", "key", key, "err", err)
+		}
+	})
+}
+
+func replayIdempotent(w http.ResponseWriter, r *http.Request, prev *models.IdempotencyRecord, fingerprint string) {
+	if prev.Fingerprint != fingerprint {
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusUnprocessableEntity, utils.CodeIdempotencyMismatch, ""))
+		return
+	}
+	if !prev.Completed {
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusConflict, utils.CodeIdempotencyInFlight, ""))
+		return
+	}
+	if prev.ContentType != "" {
+		w.Header().Set("Content-Type", prev.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(prev.Status)
+	_, _ = w.Write(prev.Body)
+}
+
+func isIdempotentMethod(m string) bool {
+	return m == http.MethodPost || m == http.MethodPut || m == http.MethodPatch
+}
+
+// O fingerprint usa o JSON normalizado (chaves ordenadas, sem espaços),
+// para que a mesma requisição reformatada pelo cliente não seja tratada como outra.
+func requestFingerprint(method, path string, body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if norm, err := json.Marshal(v); err == nil {
+			body = norm
+		}
+	}
+	sum := sha256.New()
+	sum.Write([]byte(method + "\n" + path + "\n"))
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// Copia tudo que o handler escreve para poder guardar a resposta.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.buf.Write(b)
+	return w.ResponseWriter.Write(b)
+}
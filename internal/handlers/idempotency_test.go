@@ -0,0 +1,177 @@
+package handlers
+
+/*
+
+go test -run 'TestIdempotency_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+func idemPost(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/companies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+// ---------- retry com a mesma chave devolve a resposta original (sem novo insert/evento)
+func TestIdempotency_Create_ReplaysResponse(t *testing.T) {
+	creates, published := 0, 0
+	rm := &repoMock{
+		CreateFn: func(_ context.Context, c *models.Company) (string, error) {
+			creates++
+			if creates > 1 {
+				return "", repository.ErrDuplicateCNPJ
+			}
+			return c.CNPJ, nil
+		},
+	}
+	pm := &pubMock{PublishFn: func(_ context.Context, _ string, _ amqp091.Table) error {
+		published++
+		return nil
+	}}
+	h := &CompanyHandler{Repo: rm, Pub: pm}
+	mw := &Idempotency{Store: newIdemStoreMock()}
+	srv := mw.Wrap(http.HandlerFunc(h.Companies))
+
+	body := `{"cnpj":"` + validCNPJ + `","nome_fantasia":"ACME"}`
+	first := idemPost(srv, "key-1", body)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("status=%d want=%d body=%s", first.Code, http.StatusCreated, first.Body.String())
+	}
+
+	// mesmo payload, só reformatado
+	second := idemPost(srv, "key-1", `{ "nome_fantasia": "ACME", "cnpj": "`+validCNPJ+`" }`)
+	if second.Code != http.StatusCreated {
+		t.Fatalf("replay status=%d want=%d body=%s", second.Code, http.StatusCreated, second.Body.String())
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Fatalf("replay body diferente:\n first=%s\nsecond=%s", first.Body.String(), second.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("esperava header %s no replay", IdempotentReplayedHeader)
+	}
+	if creates != 1 || published != 1 {
+		t.Fatalf("handler executado mais de uma vez: creates=%d published=%d", creates, published)
+	}
+}
+
+// ---------- mesma chave com payload diferente -> 422
+func TestIdempotency_Create_DifferentPayload(t *testing.T) {
+	rm := &repoMock{
+		CreateFn: func(_ context.Context, c *models.Company) (string, error) { return c.CNPJ, nil },
+	}
+	h := &CompanyHandler{Repo: rm, Pub: &pubMock{}}
+	srv := (&Idempotency{Store: newIdemStoreMock()}).Wrap(http.HandlerFunc(h.Companies))
+
+	if rr := idemPost(srv, "key-2", `{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME"}`); rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d want=%d body=%s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	rr := idemPost(srv, "key-2", `{"cnpj":"`+validCNPJ+`","nome_fantasia":"OUTRA"}`)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status=%d want=%d body=%s", rr.Code, http.StatusUnprocessableEntity, rr.Body.String())
+	}
+}
+
+// ---------- chave ainda em processamento -> 409
+func TestIdempotency_InProgress(t *testing.T) {
+	store := newIdemStoreMock()
+	body := `{"cnpj":"` + validCNPJ + `","nome_fantasia":"ACME"}`
+	_ = store.Reserve(context.Background(), &models.IdempotencyRecord{
+		Key:         "key-3",
+		Fingerprint: requestFingerprint(http.MethodPost, "/api/companies", []byte(body)),
+	})
+
+	h := &CompanyHandler{Repo: &repoMock{}, Pub: &pubMock{}}
+	srv := (&Idempotency{Store: store}).Wrap(http.HandlerFunc(h.Companies))
+
+	rr := idemPost(srv, "key-3", body)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("status=%d want=%d body=%s", rr.Code, http.StatusConflict, rr.Body.String())
+	}
+}
+
+// ---------- 5xx libera a chave para um novo retry
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	calls := 0
+	rm := &repoMock{
+		CreateFn: func(_ context.Context, c *models.Company) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errors.New("boom")
+			}
+			return c.CNPJ, nil
+		},
+	}
+	h := &CompanyHandler{Repo: rm, Pub: &pubMock{}}
+	srv := (&Idempotency{Store: newIdemStoreMock()}).Wrap(http.HandlerFunc(h.Companies))
+
+	body := `{"cnpj":"` + validCNPJ + `","nome_fantasia":"ACME"}`
+	if rr := idemPost(srv, "key-4", body); rr.Code != http.StatusInternalServerError {
+		t.Fatalf("status=%d want=%d", rr.Code, http.StatusInternalServerError)
+	}
+	if rr := idemPost(srv, "key-4", body); rr.Code != http.StatusCreated {
+		t.Fatalf("retry status=%d want=%d body=%s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+}
+
+// ---------- sem header: comportamento de sempre
+func TestIdempotency_NoKeyPassThrough(t *testing.T) {
+	calls := 0
+	rm := &repoMock{
+		CreateFn: func(_ context.Context, c *models.Company) (string, error) {
+			calls++
+			return c.CNPJ, nil
+		},
+	}
+	h := &CompanyHandler{Repo: rm, Pub: &pubMock{}}
+	srv := (&Idempotency{Store: newIdemStoreMock()}).Wrap(http.HandlerFunc(h.Companies))
+
+	body := `{"cnpj":"` + validCNPJ + `","nome_fantasia":"ACME"}`
+	idemPost(srv, "", body)
+	idemPost(srv, "", body)
+	if calls != 2 {
+		t.Fatalf("calls=%d want=2", calls)
+	}
+}
+
+// ---------- body acima do limite: 413, sem executar o handler nem reservar a chave
+func TestIdempotency_BodyTooLarge(t *testing.T) {
+	calls := 0
+	rm := &repoMock{
+		CreateFn: func(_ context.Context, c *models.Company) (string, error) {
+			calls++
+			return c.CNPJ, nil
+		},
+	}
+	store := newIdemStoreMock()
+	h := &CompanyHandler{Repo: rm, Pub: &pubMock{}}
+	srv := (&Idempotency{Store: store}).Wrap(http.HandlerFunc(h.Companies))
+
+	body := `{"cnpj":"` + validCNPJ + `","nome_fantasia":"` + strings.Repeat("A", maxIdempotentRequestBytes) + `"}`
+	rr := idemPost(srv, "key-big", body)
+	if rr.Code != http.StatusRequestEntityTooLarge || calls != 0 {
+		t.Fatalf("status=%d calls=%d body=%s", rr.Code, calls, rr.Body.String())
+	}
+	if _, err := store.Get(context.Background(), "key-big"); !errors.Is(err, repository.ErrIdempotencyKeyNotFound) {
+		t.Fatalf("chave reservada: err=%v", err)
+	}
+}
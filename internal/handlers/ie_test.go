@@ -0,0 +1,77 @@
+package handlers
+
+/*
+
+go test -run 'TestIE_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+func TestIE_CompanyFields(t *testing.T) {
+	var created *models.Company
+	rm := &repoMock{CreateFn: func(_ context.Context, c *models.Company) (string, error) {
+		created = c
+		return c.ID, nil
+	}}
+	mux := versionedMux(&CompanyHandler{Repo: rm, Pub: &pubMock{}})
+
+	// IE e IM gravadas sem máscara
+	rr := doJSON(mux, http.MethodPost, "/api/companies",
+		`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","inscricao_estadual":"110.042.490.114","inscricao_estadual_uf":"SP","inscricao_municipal":"1.234.567-8"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if created.InscricaoEstadual != "110042490114" || created.InscricaoEstadualUF != "SP" || created.InscricaoMunicipal != "12345678" {
+		t.Fatalf("gravado = %q %q %q", created.InscricaoEstadual, created.InscricaoEstadualUF, created.InscricaoMunicipal)
+	}
+
+	rr = doJSON(mux, http.MethodPost, "/api/companies",
+		`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","inscricao_estadual":"isento","inscricao_estadual_uf":"MG"}`)
+	if rr.Code != http.StatusCreated || created.InscricaoEstadual != utils.IEIsento {
+		t.Fatalf("isento: status=%d ie=%q", rr.Code, created.InscricaoEstadual)
+	}
+
+	// DV que vale em SP não vale em MG
+	rr = doJSON(mux, http.MethodPost, "/api/companies",
+		`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","inscricao_estadual":"110042490114","inscricao_estadual_uf":"MG"}`)
+	if rr.Code != http.StatusBadRequest || problemErrors(t, rr)["inscricao_estadual"] != utils.FieldInvalidIE {
+		t.Fatalf("inválida: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = doJSON(mux, http.MethodPost, "/api/companies",
+		`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","inscricao_estadual":"110042490114"}`)
+	if rr.Code != http.StatusBadRequest || problemErrors(t, rr)["inscricao_estadual_uf"] != utils.FieldRequired {
+		t.Fatalf("sem UF: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestIE_Patch(t *testing.T) {
+	var upd *models.Company
+	rm := &repoMock{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return storedCompany(), nil },
+		UpdateFn: func(_ context.Context, _ string, u *models.Company, _ []string) error {
+			upd = u
+			return nil
+		},
+	}
+	mux := versionedMux(&CompanyHandler{Repo: rm, Pub: &pubMock{}})
+
+	rr := doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"inscricao_estadual":"062.307.904/0081","inscricao_estadual_uf":"MG"}`)
+	if rr.Code != http.StatusOK || upd.InscricaoEstadual != "0623079040081" || upd.InscricaoEstadualUF != "MG" {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	// IE sem a UF: o par vai sempre junto
+	rr = doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"inscricao_estadual":"0623079040081"}`)
+	if rr.Code != http.StatusBadRequest || problemErrors(t, rr)["inscricao_estadual_uf"] != utils.FieldRequired {
+		t.Fatalf("sem UF: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,1218 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+type repoMock struct {
+	GetAllFn  func(ctx context.Context, limit, skip int64) ([]models.Company, error)
+	FindFn    func(ctx context.Context, f models.CompanyFilter, limit, skip int64) ([]models.Company, error)
+	CreateFn  func(ctx context.Context, c *models.Company) (string, error)
+	GetByIDFn func(ctx context.Context, id string) (*models.Company, error)
+	GetManyFn func(ctx context.Context, ids []string) ([]models.Company, error)
+	UpdateFn  func(ctx context.Context, id string, upd *models.Company, always []string) error
+	ReplaceFn func(ctx context.Context, id string, doc *models.Company) error
+	DeleteFn  func(ctx context.Context, id string) error
+	StatsFn   func(ctx context.Context, f models.CompanyFilter, groupBy []string) (*models.CompanyStats, error)
+
+	FindDuplicateCandidatesFn func(ctx context.Context, q models.DuplicateQuery, limit int64) ([]models.Company, error)
+}
+
+func (m *repoMock) GetAll(ctx context.Context, limit, skip int64) ([]models.Company, error) {
+	if m.GetAllFn == nil {
+		return nil, errors.New("GetAllFn not set")
+	}
+	return m.GetAllFn(ctx, limit, skip)
+}
+func (m *repoMock) Find(ctx context.Context, f models.CompanyFilter, limit, skip int64) ([]models.Company, error) {
+	if m.FindFn == nil {
+		return nil, errors.New("FindFn not set")
+	}
+	return m.FindFn(ctx, f, limit, skip)
+}
+func (m *repoMock) Create(ctx context.Context, c *models.Company) (string, error) {
+	if m.CreateFn == nil {
+		return "", errors.New("CreateFn not set")
+	}
+	return m.CreateFn(ctx, c)
+}
+func (m *repoMock) GetByID(ctx context.Context, id string) (*models.Company, error) {
+	if m.GetByIDFn == nil {
+		return nil, errors.New("GetByIDFn not set")
+	}
+	return m.GetByIDFn(ctx, id)
+}
+func (m *repoMock) GetMany(ctx context.Context, ids []string) ([]models.Company, error) {
+	if m.GetManyFn == nil {
+		return nil, errors.New("GetManyFn not set")
+	}
+	return m.GetManyFn(ctx, ids)
+}
+func (m *repoMock) Update(ctx context.Context, id string, upd *models.Company, always ...string) error {
+	if m.UpdateFn == nil {
+		return errors.New("UpdateFn not set")
+	}
+	return m.UpdateFn(ctx, id, upd, always)
+}
+func (m *repoMock) Replace(ctx context.Context, id string, doc *models.Company) error {
+	if m.ReplaceFn == nil {
+		return errors.New("ReplaceFn not set")
+	}
+	return m.ReplaceFn(ctx, id, doc)
+}
+func (m *repoMock) Delete(ctx context.Context, id string) error {
+	if m.DeleteFn == nil {
+		return errors.New("DeleteFn not set")
+	}
+	return m.DeleteFn(ctx, id)
+}
+func (m *repoMock) Stats(ctx context.Context, f models.CompanyFilter, groupBy []string) (*models.CompanyStats, error) {
+	if m.StatsFn == nil {
+		return nil, errors.New("StatsFn not set")
+	}
+	return m.StatsFn(ctx, f, groupBy)
+}
+func (m *repoMock) FindDuplicateCandidates(ctx context.Context, q models.DuplicateQuery, limit int64) ([]models.Company, error) {
+	if m.FindDuplicateCandidatesFn == nil {
+		return nil, errors.New("FindDuplicateCandidatesFn not set")
+	}
+	return m.FindDuplicateCandidatesFn(ctx, q, limit)
+}
+
+type Company struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type pubMock struct {
+	PublishFn func(ctx context.Context, body string, headers amqp091.Table) error
+	CloseFn   func() error
+}
+
+func (p *pubMock) Publish(ctx context.Context, body string, headers amqp091.Table) error {
+	if p.PublishFn == nil {
+		return nil
+	}
+	return p.PublishFn(ctx, body, headers)
+}
+func (p *pubMock) Close() error {
+	if p.CloseFn == nil {
+		return nil
+	}
+	return p.CloseFn()
+}
+
+// Store de Idempotency-Key em memória
+type idemStoreMock struct {
+	mu   sync.Mutex
+	recs map[string]models.IdempotencyRecord
+}
+
+func newIdemStoreMock() *idemStoreMock {
+	return &idemStoreMock{recs: map[string]models.IdempotencyRecord{}}
+}
+
+func (s *idemStoreMock) Reserve(_ context.Context, rec *models.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.recs[rec.Key]; ok {
+		return repository.ErrIdempotencyKeyExists
+	}
+	s.recs[rec.Key] = *rec
+	return nil
+}
+func (s *idemStoreMock) Get(_ context.Context, key string) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.recs[key]
+	if !ok {
+		return nil, repository.ErrIdempotencyKeyNotFound
+	}
+	return &rec, nil
+}
+func (s *idemStoreMock) Complete(_ context.Context, key string, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := s.recs[key]
+	rec.Completed, rec.Status, rec.ContentType, rec.Body = true, status, contentType, body
+	s.recs[key] = rec
+	return nil
+}
+func (s *idemStoreMock) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.recs, key)
+	return nil
+}
+
+// Funcionários em memória (mesmas regras de filtro e de CPF único do repositório)
+type employeeRepoMock struct {
+	mu   sync.Mutex
+	seq  int
+	emps map[string]models.Employee
+}
+
+func newEmployeeRepoMock(list ...models.Employee) *employeeRepoMock {
+	m := &employeeRepoMock{emps: map[string]models.Employee{}}
+	for _, e := range list {
+		m.emps[e.ID] = e
+	}
+	return m
+}
+
+func (m *employeeRepoMock) match(companyID string, f models.EmployeeFilter, e models.Employee) bool {
+	return e.CompanyID == companyID &&
+		(f.ActiveOn == "" || e.ActiveOn(f.ActiveOn)) &&
+		(f.PCD == nil || e.PCD == *f.PCD)
+}
+
+func (m *employeeRepoMock) List(_ context.Context, companyID string, f models.EmployeeFilter, limit, skip int64) ([]models.Employee, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := []models.Employee{}
+	for _, e := range m.emps {
+		if m.match(companyID, f, e) {
+			list = append(list, e)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Nome < list[j].Nome })
+	if skip >= int64(len(list)) {
+		return []models.Employee{}, nil
+	}
+	list = list[skip:]
+	if limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *employeeRepoMock) Count(_ context.Context, companyID, activeOn string) (total, pcd int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.emps {
+		if m.match(companyID, models.EmployeeFilter{ActiveOn: activeOn}, e) {
+			total++
+			if e.PCD {
+				pcd++
+			}
+		}
+	}
+	return total, pcd, nil
+}
+
+func (m *employeeRepoMock) Get(_ context.Context, companyID, id string) (*models.Employee, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.emps[id]
+	if !ok || e.CompanyID != companyID {
+		return nil, repository.ErrEmployeeNotFound
+	}
+	return &e, nil
+}
+
+func (m *employeeRepoMock) cpfTaken(e models.Employee) bool {
+	for _, o := range m.emps {
+		if o.ID != e.ID && o.CompanyID == e.CompanyID && o.CPF == e.CPF {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *employeeRepoMock) Create(_ context.Context, e *models.Employee) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	e.ID = fmt.Sprintf("e%d", m.seq)
+	if m.cpfTaken(*e) {
+		return repository.ErrDuplicateCPF
+	}
+	m.emps[e.ID] = *e
+	return nil
+}
+
+func (m *employeeRepoMock) Replace(_ context.Context, e *models.Employee) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.emps[e.ID]; !ok || cur.CompanyID != e.CompanyID {
+		return repository.ErrEmployeeNotFound
+	}
+	if m.cpfTaken(*e) {
+		return repository.ErrDuplicateCPF
+	}
+	m.emps[e.ID] = *e
+	return nil
+}
+
+func (m *employeeRepoMock) Delete(_ context.Context, companyID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.emps[id]; !ok || e.CompanyID != companyID {
+		return repository.ErrEmployeeNotFound
+	}
+	delete(m.emps, id)
+	return nil
+}
+
+func (m *employeeRepoMock) DeleteByCompany(_ context.Context, companyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, e := range m.emps {
+		if e.CompanyID == companyID {
+			delete(m.emps, id)
+		}
+	}
+	return nil
+}
+
+func (m *employeeRepoMock) MoveToCompany(_ context.Context, from, to string) (int, []models.Employee, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	moved := 0
+	var dropped []models.Employee
+	for id, e := range m.emps {
+		if e.CompanyID != from {
+			continue
+		}
+		delete(m.emps, id)
+		if slices.ContainsFunc(slices.Collect(maps.Values(m.emps)), func(x models.Employee) bool { return x.CompanyID == to && x.CPF == e.CPF }) {
+			dropped = append(dropped, e)
+			continue
+		}
+		e.CompanyID = to
+		m.emps[id] = e
+		moved++
+	}
+	return moved, dropped, nil
+}
+
+func (m *employeeRepoMock) Upsert(_ context.Context, companyID string, list []models.Employee) (created, updated int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range list {
+		found := false
+		for id, o := range m.emps {
+			if o.CompanyID == companyID && o.CPF == e.CPF {
+				e.ID, e.CreatedAt = id, o.CreatedAt
+				m.emps[id] = e
+				found = true
+				updated++
+				break
+			}
+		}
+		if !found {
+			m.seq++
+			e.ID = fmt.Sprintf("e%d", m.seq)
+			m.emps[e.ID] = e
+			created++
+		}
+	}
+	return created, updated, nil
+}
+
+// Contatos em memória
+type contactRepoMock struct {
+	mu       sync.Mutex
+	seq      int
+	contacts map[string]models.Contact
+}
+
+func newContactRepoMock(list ...models.Contact) *contactRepoMock {
+	m := &contactRepoMock{contacts: map[string]models.Contact{}}
+	for _, c := range list {
+		m.contacts[c.ID] = c
+	}
+	return m
+}
+
+func (m *contactRepoMock) List(_ context.Context, companyID string, limit, skip int64) ([]models.Contact, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := []models.Contact{}
+	for _, c := range m.contacts {
+		if c.CompanyID == companyID {
+			list = append(list, c)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Principal != list[j].Principal {
+			return list[i].Principal
+		}
+		return list[i].Nome < list[j].Nome
+	})
+	if skip >= int64(len(list)) {
+		return []models.Contact{}, nil
+	}
+	list = list[skip:]
+	if limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *contactRepoMock) Get(_ context.Context, companyID, id string) (*models.Contact, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.contacts[id]
+	if !ok || c.CompanyID != companyID {
+		return nil, repository.ErrContactNotFound
+	}
+	return &c, nil
+}
+
+func (m *contactRepoMock) Create(_ context.Context, c *models.Contact) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	c.ID = fmt.Sprintf("c%d", m.seq)
+	m.contacts[c.ID] = *c
+	return nil
+}
+
+func (m *contactRepoMock) Replace(_ context.Context, c *models.Contact) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.contacts[c.ID]; !ok || cur.CompanyID != c.CompanyID {
+		return repository.ErrContactNotFound
+	}
+	m.contacts[c.ID] = *c
+	return nil
+}
+
+func (m *contactRepoMock) ClearPrimary(_ context.Context, companyID, exceptID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, c := range m.contacts {
+		if c.CompanyID == companyID && id != exceptID {
+			c.Principal = false
+			m.contacts[id] = c
+		}
+	}
+	return nil
+}
+
+func (m *contactRepoMock) Delete(_ context.Context, companyID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.contacts[id]; !ok || c.CompanyID != companyID {
+		return repository.ErrContactNotFound
+	}
+	delete(m.contacts, id)
+	return nil
+}
+
+func (m *contactRepoMock) DeleteByCompany(_ context.Context, companyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, c := range m.contacts {
+		if c.CompanyID == companyID {
+			delete(m.contacts, id)
+		}
+	}
+	return nil
+}
+
+func (m *contactRepoMock) MoveToCompany(_ context.Context, from, to string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hasPrincipal := false
+	for _, c := range m.contacts {
+		hasPrincipal = hasPrincipal || (c.CompanyID == to && c.Principal)
+	}
+	moved := 0
+	for id, c := range m.contacts {
+		if c.CompanyID == from {
+			c.CompanyID = to
+			c.Principal = c.Principal && !hasPrincipal
+			m.contacts[id] = c
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+// Sócios (QSA) em memória
+type partnerRepoMock struct {
+	mu       sync.Mutex
+	seq      int
+	partners map[string]models.Partner
+}
+
+func newPartnerRepoMock(list ...models.Partner) *partnerRepoMock {
+	m := &partnerRepoMock{partners: map[string]models.Partner{}}
+	for _, p := range list {
+		m.partners[p.ID] = p
+	}
+	return m
+}
+
+func (m *partnerRepoMock) filter(keep func(models.Partner) bool, limit, skip int64) []models.Partner {
+	list := []models.Partner{}
+	for _, p := range m.partners {
+		if keep(p) {
+			list = append(list, p)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CompanyID+list[i].Nome < list[j].CompanyID+list[j].Nome })
+	if skip >= int64(len(list)) {
+		return []models.Partner{}
+	}
+	list = list[skip:]
+	if limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list
+}
+
+func (m *partnerRepoMock) List(_ context.Context, companyID string, limit, skip int64) ([]models.Partner, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.filter(func(p models.Partner) bool { return p.CompanyID == companyID }, limit, skip), nil
+}
+
+func (m *partnerRepoMock) ByDocument(_ context.Context, documento string, limit, skip int64) ([]models.Partner, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.filter(func(p models.Partner) bool { return p.Documento == documento }, limit, skip), nil
+}
+
+func (m *partnerRepoMock) ShareTotal(_ context.Context, companyID, exceptID string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	total := 0.0
+	for id, p := range m.partners {
+		if p.CompanyID == companyID && id != exceptID && p.PercentualCapital != nil {
+			total += *p.PercentualCapital
+		}
+	}
+	return total, nil
+}
+
+func (m *partnerRepoMock) Get(_ context.Context, companyID, id string) (*models.Partner, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.partners[id]
+	if !ok || p.CompanyID != companyID {
+		return nil, repository.ErrPartnerNotFound
+	}
+	return &p, nil
+}
+
+func (m *partnerRepoMock) taken(p models.Partner) bool {
+	for _, o := range m.partners {
+		if o.ID != p.ID && o.CompanyID == p.CompanyID && o.Documento == p.Documento {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *partnerRepoMock) Create(_ context.Context, p *models.Partner) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	p.ID = fmt.Sprintf("p%d", m.seq)
+	if m.taken(*p) {
+		return repository.ErrDuplicatePartner
+	}
+	m.partners[p.ID] = *p
+	return nil
+}
+
+func (m *partnerRepoMock) Replace(_ context.Context, p *models.Partner) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.partners[p.ID]; !ok || cur.CompanyID != p.CompanyID {
+		return repository.ErrPartnerNotFound
+	}
+	if m.taken(*p) {
+		return repository.ErrDuplicatePartner
+	}
+	m.partners[p.ID] = *p
+	return nil
+}
+
+func (m *partnerRepoMock) Delete(_ context.Context, companyID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p, ok := m.partners[id]; !ok || p.CompanyID != companyID {
+		return repository.ErrPartnerNotFound
+	}
+	delete(m.partners, id)
+	return nil
+}
+
+func (m *partnerRepoMock) DeleteByCompany(_ context.Context, companyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, p := range m.partners {
+		if p.CompanyID == companyID {
+			delete(m.partners, id)
+		}
+	}
+	return nil
+}
+
+func (m *partnerRepoMock) MoveToCompany(_ context.Context, from, to string) (int, []models.Partner, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	moved := 0
+	var dropped []models.Partner
+	for id, p := range m.partners {
+		if p.CompanyID != from {
+			continue
+		}
+		delete(m.partners, id)
+		if slices.ContainsFunc(slices.Collect(maps.Values(m.partners)), func(x models.Partner) bool { return x.CompanyID == to && x.Documento == p.Documento }) {
+			dropped = append(dropped, p)
+			continue
+		}
+		p.CompanyID = to
+		m.partners[id] = p
+		moved++
+	}
+	return moved, dropped, nil
+}
+
+// Documentos em memória (conteúdo guardado junto)
+type documentRepoMock struct {
+	mu       sync.Mutex
+	seq      int
+	docs     map[string]models.Document
+	contents map[string][]byte
+}
+
+func newDocumentRepoMock() *documentRepoMock {
+	return &documentRepoMock{docs: map[string]models.Document{}, contents: map[string][]byte{}}
+}
+
+func (m *documentRepoMock) List(_ context.Context, companyID string, limit, skip int64) ([]models.Document, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := []models.Document{}
+	for _, d := range m.docs {
+		if d.CompanyID == companyID {
+			list = append(list, d)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID > list[j].ID })
+	if skip >= int64(len(list)) {
+		return []models.Document{}, nil
+	}
+	list = list[skip:]
+	if limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *documentRepoMock) Get(_ context.Context, companyID, id string) (*models.Document, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.docs[id]
+	if !ok || d.CompanyID != companyID {
+		return nil, repository.ErrDocumentNotFound
+	}
+	return &d, nil
+}
+
+func (m *documentRepoMock) Upload(_ context.Context, d *models.Document, content io.Reader) error {
+	b, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	d.ID = fmt.Sprintf("d%03d", m.seq)
+	d.Tamanho = int64(len(b))
+	d.CreatedAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	m.docs[d.ID] = *d
+	m.contents[d.ID] = b
+	return nil
+}
+
+func (m *documentRepoMock) Open(ctx context.Context, companyID, id string) (io.ReadSeekCloser, error) {
+	if _, err := m.Get(ctx, companyID, id); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return nopSeekCloser{bytes.NewReader(m.contents[id])}, nil
+}
+
+func (m *documentRepoMock) Delete(_ context.Context, companyID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if d, ok := m.docs[id]; !ok || d.CompanyID != companyID {
+		return repository.ErrDocumentNotFound
+	}
+	delete(m.docs, id)
+	delete(m.contents, id)
+	return nil
+}
+
+func (m *documentRepoMock) DeleteByCompany(_ context.Context, companyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, d := range m.docs {
+		if d.CompanyID == companyID {
+			delete(m.docs, id)
+			delete(m.contents, id)
+		}
+	}
+	return nil
+}
+
+func (m *documentRepoMock) MoveToCompany(_ context.Context, from, to string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	moved := 0
+	for id, d := range m.docs {
+		if d.CompanyID == from {
+			d.CompanyID = to
+			m.docs[id] = d
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+type nopSeekCloser struct{ io.ReadSeeker }
+
+func (nopSeekCloser) Close() error { return nil }
+
+type customFieldRepoMock struct {
+	mu   sync.Mutex
+	defs map[string]models.CustomFieldDefinition
+}
+
+func newCustomFieldRepoMock(defs ...models.CustomFieldDefinition) *customFieldRepoMock {
+	m := &customFieldRepoMock{defs: map[string]models.CustomFieldDefinition{}}
+	for _, d := range defs {
+		m.defs[d.Key] = d
+	}
+	return m
+}
+
+func (m *customFieldRepoMock) List(context.Context) ([]models.CustomFieldDefinition, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := []models.CustomFieldDefinition{}
+	for _, d := range m.defs {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list, nil
+}
+
+func (m *customFieldRepoMock) Get(_ context.Context, key string) (*models.CustomFieldDefinition, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.defs[key]
+	if !ok {
+		return nil, repository.ErrCustomFieldNotFound
+	}
+	return &d, nil
+}
+
+func (m *customFieldRepoMock) Create(_ context.Context, d *models.CustomFieldDefinition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.defs[d.Key]; ok {
+		return repository.ErrDuplicateCustomField
+	}
+	d.CreatedAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	d.UpdatedAt = d.CreatedAt
+	m.defs[d.Key] = *d
+	return nil
+}
+
+func (m *customFieldRepoMock) Replace(_ context.Context, d *models.CustomFieldDefinition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.defs[d.Key]; !ok {
+		return repository.ErrCustomFieldNotFound
+	}
+	d.UpdatedAt = time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+	m.defs[d.Key] = *d
+	return nil
+}
+
+func (m *customFieldRepoMock) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.defs[key]; !ok {
+		return repository.ErrCustomFieldNotFound
+	}
+	delete(m.defs, key)
+	return nil
+}
+
+// mergeRepoMock: histórico de fusões em memória (mais recentes primeiro)
+type mergeRepoMock struct {
+	mu      sync.Mutex
+	merges  []models.CompanyMerge
+	created []models.CompanyMerge // como chegaram no Create (antes de Moved/Dropped)
+}
+
+// txMock: conta as transações e roda fn direto
+type txMock struct{ calls int }
+
+func (m *txMock) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+func (m *mergeRepoMock) List(_ context.Context, targetID string, limit, skip int64) ([]models.CompanyMerge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := []models.CompanyMerge{}
+	for i := len(m.merges) - 1; i >= 0; i-- {
+		if m.merges[i].TargetID == targetID {
+			list = append(list, m.merges[i])
+		}
+	}
+	if skip >= int64(len(list)) {
+		return []models.CompanyMerge{}, nil
+	}
+	list = list[skip:]
+	if limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *mergeRepoMock) Create(_ context.Context, mg *models.CompanyMerge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mg.ID = fmt.Sprintf("merge-%d", len(m.merges)+1)
+	m.merges = append(m.merges, *mg)
+	m.created = append(m.created, *mg)
+	return nil
+}
+
+func (m *mergeRepoMock) Update(_ context.Context, mg *models.CompanyMerge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.merges {
+		if m.merges[i].ID == mg.ID {
+			m.merges[i] = *mg
+			return nil
+		}
+	}
+	return fmt.Errorf("merge %s not found", mg.ID)
+}
+
+func (m *mergeRepoMock) MoveToCompany(_ context.Context, from, to string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for i := range m.merges {
+		if m.merges[i].TargetID == from {
+			m.merges[i].TargetID = to
+			n++
+		}
+	}
+	return n, nil
+}
+
+// ownershipRepoMock: arestas do grupo econômico em memória, chave parent/child
+type ownershipRepoMock struct {
+	mu    sync.Mutex
+	edges map[[2]string]models.Ownership
+}
+
+func newOwnershipRepoMock(list ...models.Ownership) *ownershipRepoMock {
+	m := &ownershipRepoMock{edges: map[[2]string]models.Ownership{}}
+	for _, o := range list {
+		m.edges[[2]string{o.ParentID, o.ChildID}] = o
+	}
+	return m
+}
+
+func (m *ownershipRepoMock) filter(keep func(models.Ownership) bool) []models.Ownership {
+	list := []models.Ownership{}
+	for _, o := range m.edges {
+		if keep(o) {
+			list = append(list, o)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ParentID != list[j].ParentID {
+			return list[i].ParentID < list[j].ParentID
+		}
+		return list[i].ChildID < list[j].ChildID
+	})
+	return list
+}
+
+func (m *ownershipRepoMock) List(_ context.Context, parentID string, limit, skip int64) ([]models.Ownership, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := m.filter(func(o models.Ownership) bool { return o.ParentID == parentID })
+	if skip >= int64(len(list)) {
+		return []models.Ownership{}, nil
+	}
+	list = list[skip:]
+	if limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *ownershipRepoMock) ByParents(_ context.Context, ids []string) ([]models.Ownership, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.filter(func(o models.Ownership) bool { return slices.Contains(ids, o.ParentID) }), nil
+}
+
+func (m *ownershipRepoMock) ByChildren(_ context.Context, ids []string) ([]models.Ownership, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.filter(func(o models.Ownership) bool { return slices.Contains(ids, o.ChildID) }), nil
+}
+
+func (m *ownershipRepoMock) ShareTotal(_ context.Context, childID, exceptParentID string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	total := 0.0
+	for _, o := range m.edges {
+		if o.ChildID == childID && o.ParentID != exceptParentID {
+			total += o.Percentual
+		}
+	}
+	return total, nil
+}
+
+func (m *ownershipRepoMock) Get(_ context.Context, parentID, childID string) (*models.Ownership, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, ok := m.edges[[2]string{parentID, childID}]
+	if !ok {
+		return nil, repository.ErrOwnershipNotFound
+	}
+	return &o, nil
+}
+
+func (m *ownershipRepoMock) Create(_ context.Context, o *models.Ownership) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := [2]string{o.ParentID, o.ChildID}
+	if _, ok := m.edges[key]; ok {
+		return repository.ErrDuplicateOwnership
+	}
+	o.ID = fmt.Sprintf("own-%d", len(m.edges)+1)
+	o.CreatedAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	o.UpdatedAt = o.CreatedAt
+	m.edges[key] = *o
+	return nil
+}
+
+func (m *ownershipRepoMock) Replace(_ context.Context, o *models.Ownership) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := [2]string{o.ParentID, o.ChildID}
+	if _, ok := m.edges[key]; !ok {
+		return repository.ErrOwnershipNotFound
+	}
+	o.UpdatedAt = time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+	m.edges[key] = *o
+	return nil
+}
+
+func (m *ownershipRepoMock) Delete(_ context.Context, parentID, childID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := [2]string{parentID, childID}
+	if _, ok := m.edges[key]; !ok {
+		return repository.ErrOwnershipNotFound
+	}
+	delete(m.edges, key)
+	return nil
+}
+
+func (m *ownershipRepoMock) DeleteByCompany(_ context.Context, companyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, o := range m.edges {
+		if o.ParentID == companyID || o.ChildID == companyID {
+			delete(m.edges, k)
+		}
+	}
+	return nil
+}
+
+func (m *ownershipRepoMock) MoveToCompany(_ context.Context, from, to string) (int, []models.Ownership, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	moved := 0
+	var dropped []models.Ownership
+	for k, o := range m.edges {
+		if o.ParentID != from && o.ChildID != from {
+			continue
+		}
+		delete(m.edges, k)
+		orig := o
+		if o.ParentID == from {
+			o.ParentID = to
+		}
+		if o.ChildID == from {
+			o.ChildID = to
+		}
+		key := [2]string{o.ParentID, o.ChildID}
+		if _, ok := m.edges[key]; ok || o.ParentID == o.ChildID {
+			dropped = append(dropped, orig)
+			continue
+		}
+		m.edges[key] = o
+		moved++
+	}
+	return moved, dropped, nil
+}
+
+type noteRepoMock struct {
+	mu    sync.Mutex
+	seq   int
+	notes map[string]models.Note
+}
+
+func newNoteRepoMock(list ...models.Note) *noteRepoMock {
+	m := &noteRepoMock{notes: map[string]models.Note{}}
+	for _, n := range list {
+		m.notes[n.ID] = n
+	}
+	return m
+}
+
+// newest: notas da empresa, mais recentes primeiro
+func (m *noteRepoMock) newest(companyID string) []models.Note {
+	list := []models.Note{}
+	for _, n := range m.notes {
+		if n.CompanyID == companyID {
+			list = append(list, n)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		}
+		return list[i].ID > list[j].ID
+	})
+	return list
+}
+
+func (m *noteRepoMock) List(_ context.Context, companyID string, limit, skip int64) ([]models.Note, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := m.newest(companyID)
+	if skip >= int64(len(list)) {
+		return []models.Note{}, nil
+	}
+	list = list[skip:]
+	if limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *noteRepoMock) Before(_ context.Context, companyID string, at time.Time, id string, limit int64) ([]models.Note, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := []models.Note{}
+	for _, n := range m.newest(companyID) {
+		if at.IsZero() || n.CreatedAt.Before(at) || (n.CreatedAt.Equal(at) && n.ID < id) {
+			list = append(list, n)
+		}
+	}
+	if limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *noteRepoMock) Get(_ context.Context, companyID, id string) (*models.Note, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.notes[id]
+	if !ok || n.CompanyID != companyID {
+		return nil, repository.ErrNoteNotFound
+	}
+	return &n, nil
+}
+
+func (m *noteRepoMock) Create(_ context.Context, n *models.Note) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	n.ID = fmt.Sprintf("n%03d", m.seq)
+	n.CreatedAt = time.Now()
+	n.UpdatedAt = n.CreatedAt
+	m.notes[n.ID] = *n
+	return nil
+}
+
+func (m *noteRepoMock) Replace(_ context.Context, n *models.Note) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.notes[n.ID]; !ok || cur.CompanyID != n.CompanyID {
+		return repository.ErrNoteNotFound
+	}
+	n.UpdatedAt = time.Now()
+	m.notes[n.ID] = *n
+	return nil
+}
+
+func (m *noteRepoMock) Delete(_ context.Context, companyID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n, ok := m.notes[id]; !ok || n.CompanyID != companyID {
+		return repository.ErrNoteNotFound
+	}
+	delete(m.notes, id)
+	return nil
+}
+
+func (m *noteRepoMock) DeleteByCompany(_ context.Context, companyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, n := range m.notes {
+		if n.CompanyID == companyID {
+			delete(m.notes, id)
+		}
+	}
+	return nil
+}
+
+func (m *noteRepoMock) MoveToCompany(_ context.Context, from, to string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	moved := 0
+	for id, n := range m.notes {
+		if n.CompanyID == from {
+			n.CompanyID = to
+			m.notes[id] = n
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+// eventRepoMock: store do events.Log e fonte de eventos da linha do tempo
+type eventRepoMock struct {
+	mu     sync.Mutex
+	seq    int
+	events []models.CompanyEvent // na ordem de gravação
+}
+
+func (m *eventRepoMock) Append(_ context.Context, ev *models.CompanyEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	ev.ID = fmt.Sprintf("e%03d", m.seq)
+	m.events = append(m.events, *ev)
+	return nil
+}
+
+func (m *eventRepoMock) Before(_ context.Context, companyID string, at time.Time, id string, limit int64) ([]models.CompanyEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := []models.CompanyEvent{}
+	for i := len(m.events) - 1; i >= 0 && int64(len(list)) < limit; i-- {
+		ev := m.events[i]
+		if ev.CompanyID == companyID && (at.IsZero() || ev.Timestamp.Before(at) || (ev.Timestamp.Equal(at) && ev.ID < id)) {
+			list = append(list, ev)
+		}
+	}
+	return list, nil
+}
+
+// changeRequestRepoMock: pedidos de mudança em memória
+type changeRequestRepoMock struct {
+	mu       sync.Mutex
+	seq      int
+	requests map[string]models.ChangeRequest
+}
+
+func newChangeRequestRepoMock() *changeRequestRepoMock {
+	return &changeRequestRepoMock{requests: map[string]models.ChangeRequest{}}
+}
+
+func (m *changeRequestRepoMock) List(_ context.Context, f models.ChangeRequestFilter, limit, skip int64) ([]models.ChangeRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := []models.ChangeRequest{}
+	for _, cr := range m.requests {
+		if (f.Status == "" || cr.Status == f.Status) && (f.CompanyID == "" || cr.CompanyID == f.CompanyID) {
+			list = append(list, cr)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].RequestedAt.Equal(list[j].RequestedAt) {
+			return list[i].RequestedAt.After(list[j].RequestedAt)
+		}
+		return list[i].ID > list[j].ID
+	})
+	if skip >= int64(len(list)) {
+		return []models.ChangeRequest{}, nil
+	}
+	list = list[skip:]
+	if limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *changeRequestRepoMock) Get(_ context.Context, id string) (*models.ChangeRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cr, ok := m.requests[id]
+	if !ok {
+		return nil, repository.ErrChangeRequestNotFound
+	}
+	return &cr, nil
+}
+
+func (m *changeRequestRepoMock) Create(_ context.Context, cr *models.ChangeRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	cr.ID = fmt.Sprintf("cr%d", m.seq)
+	m.requests[cr.ID] = *cr
+	return nil
+}
+
+func (m *changeRequestRepoMock) Decide(_ context.Context, id, status, by, comment string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cr, ok := m.requests[id]
+	if !ok || cr.Status != models.ChangeStatusPending {
+		return repository.ErrChangeRequestNotPending
+	}
+	cr.Status, cr.DecidedBy, cr.DecidedAt, cr.Comment = status, by, &at, comment
+	m.requests[id] = cr
+	return nil
+}
+
+func (m *changeRequestRepoMock) Fail(_ context.Context, id, msg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cr := m.requests[id]
+	cr.Status, cr.Error = models.ChangeStatusFailed, msg
+	m.requests[id] = cr
+	return nil
+}
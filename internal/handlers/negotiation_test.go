@@ -0,0 +1,153 @@
+package handlers
+
+/*
+
+go test -run 'TestNegotiation_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+func negotiationHandler() *CompanyHandler {
+	return &CompanyHandler{Repo: &repoMock{
+		GetAllFn: func(_ context.Context, _, _ int64) ([]models.Company, error) {
+			return []models.Company{*storedCompany()}, nil
+		},
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return storedCompany(), nil },
+	}}
+}
+
+func TestNegotiation_CSVList(t *testing.T) {
+	mux := versionedMux(negotiationHandler())
+
+	cases := []struct {
+		path   string
+		header string
+		row    string
+	}{
+		{"/api/v1/companies", "id,cnpj,nome_fantasia,razao_social,endereco,numero_funcionarios,numero_minimo_pcd_exigidos,numero_pcd_contratados,cnae_principal,cnaes_secundarios,inscricao_estadual,inscricao_estadual_uf,inscricao_municipal,regime_tributario,faturamento_anual,porte,tags,custom_fields,created_at,updated_at", companyID + "," + companyID + ",ACME,,"},
+		{"/api/v2/companies", "id,cnpj,nome_fantasia,razao_social,endereco.logradouro,endereco.numero,endereco.complemento,endereco.bairro,endereco.municipio,endereco.uf,endereco.cep,numero_funcionarios", companyID + "," + validCNPJ + ",ACME,,Av. Paulista,1000,,,São Paulo,SP,01310100,150,3"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		req.Header.Set("Accept", "text/csv")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d body=%s", tc.path, rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+			t.Fatalf("%s: Content-Type = %q", tc.path, ct)
+		}
+		lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], tc.header) || !strings.HasPrefix(lines[1], tc.row) {
+			t.Fatalf("%s: csv =\n%s", tc.path, rr.Body.String())
+		}
+	}
+}
+
+func TestNegotiation_XMLAndMsgpack(t *testing.T) {
+	mux := versionedMux(negotiationHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/companies/"+companyID, nil)
+	req.Header.Set("Accept", "application/xml")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), "application/xml") {
+		t.Fatalf("xml: status=%d ct=%q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	var doc struct {
+		Data struct {
+			CNPJ     string `xml:"cnpj"`
+			Endereco struct {
+				UF string `xml:"uf"`
+			} `xml:"endereco"`
+		} `xml:"data"`
+		Meta struct {
+			APIVersion string `xml:"api_version"`
+		} `xml:"meta"`
+	}
+	if err := xml.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("xml inválido: %v\n%s", err, rr.Body.String())
+	}
+	if doc.Data.CNPJ != validCNPJ || doc.Data.Endereco.UF != "SP" || doc.Meta.APIVersion != "v2" {
+		t.Fatalf("xml = %+v", doc)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/companies/"+companyID, nil)
+	req.Header.Set("Accept", "application/msgpack")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "application/msgpack" {
+		t.Fatalf("msgpack: status=%d ct=%q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	var got map[string]any
+	if err := msgpack.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("msgpack inválido: %v", err)
+	}
+	if got["id"] != companyID || got["nome_fantasia"] != "ACME" {
+		t.Fatalf("msgpack = %v", got)
+	}
+}
+
+func TestNegotiation_NotAcceptable(t *testing.T) {
+	created := false
+	h := negotiationHandler()
+	h.Repo.(*repoMock).CreateFn = func(_ context.Context, _ *models.Company) (string, error) {
+		created = true
+		return companyID, nil
+	}
+	mux := versionedMux(h)
+
+	// CSV de um objeto: o formato existe, mas não representa o valor
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/companies/"+companyID, nil)
+	req.Header.Set("Accept", "text/csv")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotAcceptable {
+		t.Fatalf("csv objeto: status = %d", rr.Code)
+	}
+	var p utils.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil || p.Code != utils.CodeNotAcceptable {
+		t.Fatalf("problem = %+v err=%v", p, err)
+	}
+
+	// formato desconhecido: recusado antes de executar a operação
+	body := `{"cnpj":"` + validCNPJ + `","nome_fantasia":"ACME"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/companies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/html")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotAcceptable {
+		t.Fatalf("text/html: status = %d", rr.Code)
+	}
+	if created {
+		t.Fatal("Create executado apesar do 406")
+	}
+
+	// CSV num POST: a resposta seria um objeto, então recusa antes de gravar
+	req = httptest.NewRequest(http.MethodPost, "/api/v2/companies", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/csv")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotAcceptable || created {
+		t.Fatalf("POST csv: status = %d, created = %v", rr.Code, created)
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Notas do atendimento: /api/companies/{id}/notes[/{note_id}], e a linha do tempo
+// da empresa (notas + eventos publicados): /api/companies/{id}/timeline?limit=&cursor=.
+
+// Body de POST e PUT (validado por schema/note.json); igual na v1 e na v2
+type NoteDTO struct {
+	Autor string `json:"autor"`
+	Texto string `json:"texto"`
+	Tipo  string `json:"tipo"`
+}
+
+func (d NoteDTO) input() service.NoteInput {
+	return service.NoteInput{Autor: d.Autor, Texto: d.Texto, Tipo: d.Tipo}
+}
+
+// GET (lista) e POST /api/companies/{id}/notes
+func (h *CompanyHandler) CompanyNotes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listNotes(w, r)
+	case http.MethodPost:
+		schema.Validate(schema.Note, http.HandlerFunc(h.createNote)).ServeHTTP(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// GET, PUT e DELETE /api/companies/{id}/notes/{note_id}
+func (h *CompanyHandler) CompanyNoteByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getNote(w, r)
+	case http.MethodPut:
+		schema.Validate(schema.Note, http.HandlerFunc(h.replaceNote)).ServeHTTP(w, r)
+	case http.MethodDelete:
+		h.deleteNote(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (h *CompanyHandler) listNotes(w http.ResponseWriter, r *http.Request) {
+	limit, skip := pagination(r.URL.Query())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().ListNotes(ctx, r.PathValue("id"), limit, skip)
+	if err != nil {
+		writeNoteError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: list,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
+
+func (h *CompanyHandler) createNote(w http.ResponseWriter, r *http.Request) {
+	var dto NoteDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	n, err := h.service().CreateNote(ctx, r.PathValue("id"), dto.input())
+	if err != nil {
+		writeNoteError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusCreated, n)
+}
+
+func (h *CompanyHandler) getNote(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	n, err := h.service().GetNote(ctx, r.PathValue("id"), r.PathValue("note_id"))
+	if err != nil {
+		writeNoteError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, n)
+}
+
+func (h *CompanyHandler) replaceNote(w http.ResponseWriter, r *http.Request) {
+	var dto NoteDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	n, err := h.service().ReplaceNote(ctx, r.PathValue("id"), r.PathValue("note_id"), dto.input())
+	if err != nil {
+		writeNoteError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, n)
+}
+
+func (h *CompanyHandler) deleteNote(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if err := h.service().DeleteNote(ctx, r.PathValue("id"), r.PathValue("note_id")); err != nil {
+		writeNoteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/companies/{id}/timeline: notas e eventos, mais recentes primeiro.
+// ?limit (1..200, padrão 50) e ?cursor (next_cursor da página anterior).
+func (h *CompanyHandler) CompanyTimeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	limit, _ := pagination(r.URL.Query())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	page, err := h.service().Timeline(ctx, r.PathValue("id"), r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		writeNoteError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, page)
+		return
+	}
+	count := len(page.Items)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: page.Items,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Count: &count, NextCursor: page.NextCursor},
+	})
+}
+
+// Cursor inválido -> 400, empresa ou nota inexistente -> 404, o resto -> 500
+func writeNoteError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCursor):
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "cursor", Code: utils.FieldInvalidFormat}})
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, repository.ErrNoteNotFound):
+		utils.NotFound(w, r)
+	default:
+		utils.InternalError(w, r, err)
+	}
+}
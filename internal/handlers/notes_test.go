@@ -0,0 +1,159 @@
+package handlers
+
+/*
+
+go test -run 'TestNotes_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+const notesPath = "/api/companies/" + companyID + "/notes"
+
+// empresa em memória; os eventos publicados passam pelo events.Log e vão para o eventRepoMock
+func newNotesFixture() (http.Handler, *noteRepoMock, *eventRepoMock) {
+	nm, em := newNoteRepoMock(), &eventRepoMock{}
+	h := &CompanyHandler{Repo: newCompanyStore(*storedCompany()).repo(), Pub: &events.Log{Store: em}, Contacts: newContactRepoMock(), Notes: nm, Events: em}
+	return versionedMux(h), nm, em
+}
+
+func TestNotes_CRUD(t *testing.T) {
+	mux, nm, _ := newNotesFixture()
+
+	rr := doJSON(mux, http.MethodPost, notesPath, `{"autor":" Ana ","texto":"Ligou pedindo a guia da cota","tipo":"ligacao"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var n models.Note
+	_ = json.Unmarshal(rr.Body.Bytes(), &n)
+	if n.ID == "" || n.CompanyID != companyID || n.Autor != "Ana" || n.Tipo != models.NoteLigacao || n.CreatedAt.IsZero() {
+		t.Fatalf("nota = %+v", n)
+	}
+
+	rr = doJSON(mux, http.MethodPost, notesPath, `{"autor":"   ","texto":"x","tipo":"reuniao"}`)
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["autor"] != utils.FieldInvalidFormat || errs["tipo"] != utils.FieldNotInEnum {
+		t.Fatalf("inválida: status=%d errors=%v", rr.Code, errs)
+	}
+	rr = doJSON(mux, http.MethodPost, notesPath, `{"autor":"Ana","tipo":"visita"}`)
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["texto"] != utils.FieldRequired {
+		t.Fatalf("sem texto: status=%d errors=%v", rr.Code, errs)
+	}
+
+	rr = doJSON(mux, http.MethodPut, notesPath+"/"+n.ID, `{"autor":"Ana","texto":"Visita marcada","tipo":"visita"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if got := nm.notes[n.ID]; got.Texto != "Visita marcada" || got.Tipo != models.NoteVisita || !got.CreatedAt.Equal(n.CreatedAt) {
+		t.Fatalf("depois do PUT = %+v", got)
+	}
+
+	rr = doJSON(mux, http.MethodGet, "/api/v2/companies/"+companyID+"/notes", "")
+	var env struct {
+		Data []models.Note `json:"data"`
+		Meta Meta          `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 1 || *env.Meta.Count != 1 || env.Data[0].ID != n.ID {
+		t.Fatalf("lista v2 = %s", rr.Body.String())
+	}
+
+	if rr = doJSON(mux, http.MethodDelete, notesPath+"/"+n.ID, ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status=%d", rr.Code)
+	}
+	for _, m := range []string{http.MethodGet, http.MethodDelete} {
+		if rr = doJSON(mux, m, notesPath+"/"+n.ID, ""); rr.Code != http.StatusNotFound {
+			t.Fatalf("%s depois do DELETE: status=%d", m, rr.Code)
+		}
+	}
+	if rr = doJSON(mux, http.MethodGet, "/api/companies/11222333000343/notes", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("empresa inexistente: status=%d", rr.Code)
+	}
+}
+
+func TestNotes_TimelineMergesNotesAndEvents(t *testing.T) {
+	mux, _, em := newNotesFixture()
+
+	// nota, contato criado (evento), nota, contato removido (evento), nota
+	note := func(texto string) {
+		t.Helper()
+		if rr := doJSON(mux, http.MethodPost, notesPath, `{"autor":"Ana","texto":"`+texto+`","tipo":"outro"}`); rr.Code != http.StatusCreated {
+			t.Fatalf("nota %s: status=%d", texto, rr.Code)
+		}
+	}
+	note("primeira")
+	rr := doJSON(mux, http.MethodPost, "/api/companies/"+companyID+"/contacts", `{"nome":"Bia","email":"bia@acme.com"}`)
+	var contact models.Contact
+	if _ = json.Unmarshal(rr.Body.Bytes(), &contact); contact.ID == "" {
+		t.Fatalf("contato: status=%d", rr.Code)
+	}
+	note("segunda")
+	doJSON(mux, http.MethodDelete, "/api/companies/"+companyID+"/contacts/"+contact.ID, "")
+	note("terceira")
+
+	if len(em.events) != 2 {
+		t.Fatalf("eventos gravados = %+v", em.events)
+	}
+	if ev := em.events[0]; ev.CompanyID != companyID || ev.Action != events.ActionContactCreated || ev.Headers["contact_id"] != contact.ID || ev.Headers["action"] != "" {
+		t.Fatalf("evento gravado = %+v", ev)
+	}
+
+	// v1 com limit=2: três páginas
+	label := func(it models.TimelineItem) string {
+		if it.Note != nil {
+			return it.Note.Texto
+		}
+		return it.Event.Action
+	}
+	var got []string
+	cursor, pages := "", 0
+	for {
+		rr := doJSON(mux, http.MethodGet, "/api/companies/"+companyID+"/timeline?limit=2&cursor="+cursor, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+		}
+		var page models.TimelinePage
+		_ = json.Unmarshal(rr.Body.Bytes(), &page)
+		for _, it := range page.Items {
+			got = append(got, label(it))
+		}
+		pages++
+		if cursor = page.NextCursor; cursor == "" || pages > 5 {
+			break
+		}
+	}
+	want := []string{"terceira", events.ActionContactDeleted, "segunda", events.ActionContactCreated, "primeira"}
+	if pages != 3 || !slices.Equal(got, want) {
+		t.Fatalf("páginas=%d itens=%v", pages, got)
+	}
+
+	// v2: cursor no meta
+	rr = doJSON(mux, http.MethodGet, "/api/v2/companies/"+companyID+"/timeline?limit=4", "")
+	var env struct {
+		Data []models.TimelineItem `json:"data"`
+		Meta Meta                  `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 4 || env.Meta.NextCursor == "" || env.Data[1].Kind != models.TimelineEvent {
+		t.Fatalf("v2 = %s", rr.Body.String())
+	}
+	rr = doJSON(mux, http.MethodGet, "/api/v2/companies/"+companyID+"/timeline?limit=4&cursor="+env.Meta.NextCursor, "")
+	env.Meta = Meta{}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 1 || env.Meta.NextCursor != "" || env.Data[0].Note.Texto != "primeira" {
+		t.Fatalf("v2 última página = %s", rr.Body.String())
+	}
+
+	rr = doJSON(mux, http.MethodGet, "/api/companies/"+companyID+"/timeline?cursor=nao-e-cursor", "")
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["cursor"] != utils.FieldInvalidFormat {
+		t.Fatalf("cursor inválido: status=%d errors=%v", rr.Code, errs)
+	}
+}
@@ -0,0 +1,294 @@
+package handlers
+
+/*
+Garante que internal/docs/openapi.json e os handlers não divergem:
+rotas/métodos documentados existem, métodos não documentados dão 405
+e os schemas têm exatamente os campos JSON dos DTOs/modelos.
+
+go test -run 'TestOpenAPI_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/cnae"
+	"github.com/Werneck0live/cadastro-empresa/internal/docs"
+	"github.com/Werneck0live/cadastro-empresa/internal/gql"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+var specMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+type openAPIDoc struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) openAPIDoc {
+	t.Helper()
+	var doc openAPIDoc
+	if err := json.Unmarshal(docs.Spec(), &doc); err != nil {
+		t.Fatalf("openapi.json inválido: %v", err)
+	}
+	return doc
+}
+
+// mux com todas as rotas da API (mesma montagem do cmd/api, com /api/v1, /api/v2 e /graphql)
+func specTestMux() http.Handler {
+	api := http.NewServeMux()
+	h := &CompanyHandler{Repo: &repoMock{}, Pub: &pubMock{}}
+	h.Register(api)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", (&Versioning{}).Wrap(api))
+	gqlSchema, err := gql.NewSchema(&service.Companies{Repo: &repoMock{}}, nil)
+	if err != nil {
+		panic(err)
+	}
+	(&gql.Handler{Schema: gqlSchema}).Register(mux)
+	return mux
+}
+
+// substitui {param} por um valor válido
+func concretePath(p string) string {
+	return strings.NewReplacer("{id}", companyID, "{employee_id}", "e1", "{contact_id}", "c1", "{partner_id}", "p1", "{documento}", "52998224725").Replace(p)
+}
+
+func specRequest(mux http.Handler, method, path string) *httptest.ResponseRecorder {
+	var body *bytes.Buffer
+	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
+		body = bytes.NewBufferString(`{}`)
+	} else {
+		body = &bytes.Buffer{}
+	}
+	req := httptest.NewRequest(method, concretePath(path), body)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+// 404 do próprio ServeMux (rota inexistente) vem em text/plain; o dos handlers é problem+json
+func routeMissing(rr *httptest.ResponseRecorder) bool {
+	if rr.Code == http.StatusMethodNotAllowed {
+		return true
+	}
+	return rr.Code == http.StatusNotFound && !strings.HasPrefix(rr.Header().Get("Content-Type"), utils.ProblemContentType)
+}
+
+func specOperations(item map[string]json.RawMessage) map[string]bool {
+	ops := map[string]bool{}
+	for k := range item {
+		if m := strings.ToUpper(k); contains(specMethods, m) {
+			ops[m] = true
+		}
+	}
+	return ops
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOpenAPI_DocumentedRoutesExist(t *testing.T) {
+	doc := loadSpec(t)
+	mux := specTestMux()
+
+	if len(doc.Paths) == 0 {
+		t.Fatal("spec sem paths")
+	}
+	for path, item := range doc.Paths {
+		for method := range specOperations(item) {
+			rr := specRequest(mux, method, path)
+			if routeMissing(rr) {
+				t.Errorf("%s %s documentado mas não atendido (status=%d body=%s)", method, path, rr.Code, rr.Body.String())
+			}
+		}
+	}
+}
+
+func TestOpenAPI_UndocumentedMethodsRejected(t *testing.T) {
+	doc := loadSpec(t)
+	mux := specTestMux()
+
+	for path, item := range doc.Paths {
+		ops := specOperations(item)
+		for _, method := range specMethods {
+			if ops[method] {
+				continue
+			}
+			rr := specRequest(mux, method, path)
+			if !routeMissing(rr) {
+				t.Errorf("%s %s atendido (status=%d) mas não documentado no openapi.json", method, path, rr.Code)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_SchemasMatchTypes(t *testing.T) {
+	doc := loadSpec(t)
+
+	cases := map[string]any{
+		"Company":       models.Company{},
+		"CompanyCreate": CompanyCreateDTO{},
+		"CompanyPatch":  CompanyPatchDTO{},
+		"CompanyPut":    CompanyPutDTO{},
+		"Problem":       utils.Problem{},
+		"FieldError":    utils.FieldError{},
+
+		"CompanyV2":           CompanyV2{},
+		"CompanyCreateV2":     CompanyCreateV2DTO{},
+		"CompanyPatchV2":      CompanyPatchV2DTO{},
+		"CompanyPutV2":        CompanyPutV2DTO{},
+		"Address":             models.Address{},
+		"Meta":                Meta{},
+		"CompanyEnvelope":     Envelope{},
+		"CompanyListEnvelope": Envelope{},
+
+		"CompanyStats":         models.CompanyStats{},
+		"CompanyStatsEnvelope": Envelope{},
+		"StatsTotals":          models.StatsTotals{},
+		"StatsBucket":          models.StatsBucket{},
+		"GrowthPoint":          models.GrowthPoint{},
+
+		"PCDSimulate":           PCDSimulateDTO{},
+		"PCDGrowth":             PCDGrowthDTO{},
+		"PCDSimulation":         service.PCDSimulation{},
+		"PCDSimulationPoint":    service.PCDSimulationPoint{},
+		"PCDCrossing":           utils.PCDCrossing{},
+		"PCDSimulationEnvelope": Envelope{},
+
+		"Employee":               models.Employee{},
+		"EmployeeInput":          EmployeeDTO{},
+		"Headcount":              service.Headcount{},
+		"EmployeeImport":         service.EmployeeImport{},
+		"EmployeeEnvelope":       Envelope{},
+		"EmployeeListEnvelope":   Envelope{},
+		"HeadcountEnvelope":      Envelope{},
+		"EmployeeImportEnvelope": Envelope{},
+
+		"Contact":             models.Contact{},
+		"ContactInput":        ContactDTO{},
+		"ContactEnvelope":     Envelope{},
+		"ContactListEnvelope": Envelope{},
+
+		"Partner":                    models.Partner{},
+		"PartnerInput":               PartnerDTO{},
+		"PartnerCompany":             models.PartnerCompany{},
+		"PartnerEnvelope":            Envelope{},
+		"PartnerListEnvelope":        Envelope{},
+		"PartnerCompanyListEnvelope": Envelope{},
+
+		"Document":             models.Document{},
+		"DocumentEnvelope":     Envelope{},
+		"DocumentListEnvelope": Envelope{},
+
+		"CustomFieldDefinition":   models.CustomFieldDefinition{},
+		"CustomFieldInput":        CustomFieldDTO{},
+		"CustomFieldEnvelope":     Envelope{},
+		"CustomFieldListEnvelope": Envelope{},
+
+		"DuplicateCandidate":       models.DuplicateCandidate{},
+		"DuplicateCandidateV2":     DuplicateCandidateV2{},
+		"DuplicateScores":          models.DuplicateScores{},
+		"DuplicateListEnvelope":    Envelope{},
+		"CompanyMerge":             models.CompanyMerge{},
+		"CompanyMergeInput":        MergeDTO{},
+		"MergeMoved":               models.MergeMoved{},
+		"MergeDropped":             models.MergeDropped{},
+		"CompanyMergeListEnvelope": Envelope{},
+
+		"Ownership":                  models.Ownership{},
+		"OwnershipInput":             OwnershipDTO{},
+		"OwnershipUpdate":            OwnershipUpdateDTO{},
+		"OwnershipEnvelope":          Envelope{},
+		"OwnershipListEnvelope":      Envelope{},
+		"GroupMember":                models.GroupMember{},
+		"GroupMemberV2":              GroupMemberV2{},
+		"GroupMemberListEnvelope":    Envelope{},
+		"OwnershipCycle":             models.OwnershipCycle{},
+		"OwnershipCycleListEnvelope": Envelope{},
+		"GroupConsolidated":          models.GroupConsolidated{},
+		"GroupConsolidatedV2":        GroupConsolidatedV2{},
+		"GroupConsolidatedEnvelope":  Envelope{},
+
+		"Note":                 models.Note{},
+		"NoteInput":            NoteDTO{},
+		"NoteEnvelope":         Envelope{},
+		"NoteListEnvelope":     Envelope{},
+		"CompanyEvent":         models.CompanyEvent{},
+		"TimelineItem":         models.TimelineItem{},
+		"TimelinePage":         models.TimelinePage{},
+		"TimelineListEnvelope": Envelope{},
+
+		"ChangeRequest":             models.ChangeRequest{},
+		"ChangeRequestV2":           ChangeRequestV2{},
+		"ChangeDecision":            ChangeDecisionDTO{},
+		"ChangeRequestEnvelope":     Envelope{},
+		"ChangeRequestListEnvelope": Envelope{},
+
+		"CnaeEntry":        cnae.Entry{},
+		"CnaeListEnvelope": Envelope{},
+
+		"GraphQLRequest": gql.Request{},
+	}
+	for name, v := range cases {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s ausente no openapi.json", name)
+			continue
+		}
+		var documented []string
+		for p := range schema.Properties {
+			documented = append(documented, p)
+		}
+		sort.Strings(documented)
+
+		actual := jsonFieldNames(reflect.TypeOf(v))
+		if !reflect.DeepEqual(documented, actual) {
+			t.Errorf("schema %s diverge do tipo %T:\n spec=%v\n tipo=%v", name, v, documented, actual)
+		}
+	}
+}
+
+// nomes JSON serializados de uma struct (ignora json:"-"), ordenados
+func jsonFieldNames(t reflect.Type) []string {
+	var out []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Grupo econômico: /api/companies/{id}/subsidiaries[/{child_id}] (participações da
+// empresa em outras), /ancestors, /descendants, /group (consolidado) e /group/cycles.
+
+// Body de POST /api/companies/{id}/subsidiaries (validado por schema/ownership.json)
+type OwnershipDTO struct {
+	CompanyID  string  `json:"company_id"`
+	Percentual float64 `json:"percentual"`
+}
+
+// Body de PUT /api/companies/{id}/subsidiaries/{child_id} (schema/ownership_update.json)
+type OwnershipUpdateDTO struct {
+	Percentual float64 `json:"percentual"`
+}
+
+// GroupMemberV2: empresa do grupo no formato da v2
+type GroupMemberV2 struct {
+	Company    CompanyV2 `json:"company"`
+	Depth      int       `json:"depth"`
+	Percentual float64   `json:"percentual"`
+}
+
+// GroupConsolidatedV2: consolidado com as empresas no formato da v2
+type GroupConsolidatedV2 struct {
+	CompanyID               string          `json:"company_id"`
+	Companies               int             `json:"companies"`
+	NumeroFuncionarios      int             `json:"numero_funcionarios"`
+	NumeroMinimoPCDExigidos int             `json:"numero_minimo_pcd_exigidos"`
+	NumeroPCDContratados    int             `json:"numero_pcd_contratados"`
+	DeficitPCD              int             `json:"deficit_pcd"`
+	MinimoPCDConsolidado    int             `json:"minimo_pcd_consolidado"`
+	Members                 []GroupMemberV2 `json:"members"`
+}
+
+func toGroupMembersV2(list []models.GroupMember) []GroupMemberV2 {
+	out := make([]GroupMemberV2, len(list))
+	for i, m := range list {
+		out[i] = GroupMemberV2{Company: toCompanyV2(&m.Company), Depth: m.Depth, Percentual: m.Percentual}
+	}
+	return out
+}
+
+// GET (lista) e POST /api/companies/{id}/subsidiaries
+func (h *CompanyHandler) CompanySubsidiaries(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listSubsidiaries(w, r)
+	case http.MethodPost:
+		schema.Validate(schema.Ownership, http.HandlerFunc(h.createSubsidiary)).ServeHTTP(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// GET, PUT e DELETE /api/companies/{id}/subsidiaries/{child_id}
+func (h *CompanyHandler) CompanySubsidiaryByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getSubsidiary(w, r)
+	case http.MethodPut:
+		schema.Validate(schema.OwnershipUpdate, http.HandlerFunc(h.replaceSubsidiary)).ServeHTTP(w, r)
+	case http.MethodDelete:
+		h.deleteSubsidiary(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (h *CompanyHandler) listSubsidiaries(w http.ResponseWriter, r *http.Request) {
+	limit, skip := pagination(r.URL.Query())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().ListSubsidiaries(ctx, r.PathValue("id"), limit, skip)
+	if err != nil {
+		writeOwnershipError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: list,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
+
+func (h *CompanyHandler) createSubsidiary(w http.ResponseWriter, r *http.Request) {
+	var dto OwnershipDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	o, err := h.service().CreateSubsidiary(ctx, r.PathValue("id"), utils.SanitizeCNPJ(dto.CompanyID), dto.Percentual)
+	if err != nil {
+		writeOwnershipError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusCreated, o)
+}
+
+func (h *CompanyHandler) getSubsidiary(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	o, err := h.service().GetSubsidiary(ctx, r.PathValue("id"), r.PathValue("child_id"))
+	if err != nil {
+		writeOwnershipError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, o)
+}
+
+func (h *CompanyHandler) replaceSubsidiary(w http.ResponseWriter, r *http.Request) {
+	var dto OwnershipUpdateDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	o, err := h.service().ReplaceSubsidiary(ctx, r.PathValue("id"), r.PathValue("child_id"), dto.Percentual)
+	if err != nil {
+		writeOwnershipError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, o)
+}
+
+func (h *CompanyHandler) deleteSubsidiary(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if err := h.service().DeleteSubsidiary(ctx, r.PathValue("id"), r.PathValue("child_id")); err != nil {
+		writeOwnershipError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/companies/{id}/ancestors: controladoras diretas e indiretas
+func (h *CompanyHandler) CompanyAncestors(w http.ResponseWriter, r *http.Request) {
+	h.groupMembers(w, r, h.service().Ancestors)
+}
+
+// GET /api/companies/{id}/descendants: controladas diretas e indiretas
+func (h *CompanyHandler) CompanyDescendants(w http.ResponseWriter, r *http.Request) {
+	h.groupMembers(w, r, h.service().Descendants)
+}
+
+func (h *CompanyHandler) groupMembers(w http.ResponseWriter, r *http.Request, fetch func(context.Context, string) ([]models.GroupMember, error)) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	list, err := fetch(ctx, r.PathValue("id"))
+	if err != nil {
+		writeOwnershipError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: toGroupMembersV2(list),
+		Meta: Meta{APIVersion: V2, Count: &count},
+	})
+}
+
+// GET /api/companies/{id}/group: a empresa e as controladas, com funcionários e cota PCD somados
+func (h *CompanyHandler) CompanyGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	g, err := h.service().ConsolidatedGroup(ctx, r.PathValue("id"))
+	if err != nil {
+		writeOwnershipError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, g)
+		return
+	}
+	writeData(w, r, http.StatusOK, GroupConsolidatedV2{
+		CompanyID: g.CompanyID, Companies: g.Companies,
+		NumeroFuncionarios: g.NumeroFuncionarios, NumeroMinimoPCDExigidos: g.NumeroMinimoPCDExigidos,
+		NumeroPCDContratados: g.NumeroPCDContratados, DeficitPCD: g.DeficitPCD,
+		MinimoPCDConsolidado: g.MinimoPCDConsolidado,
+		Members:              toGroupMembersV2(g.Members),
+	})
+}
+
+// GET /api/companies/{id}/group/cycles: participações circulares alcançáveis a partir da empresa
+func (h *CompanyHandler) CompanyGroupCycles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	list, err := h.service().OwnershipCycles(ctx, r.PathValue("id"))
+	if err != nil {
+		writeOwnershipError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{Data: list, Meta: Meta{APIVersion: V2, Count: &count}})
+}
+
+// A própria empresa ou participação acima de 100% -> 400, empresa ou participação
+// inexistente -> 404, participação repetida ou circular -> 409, o resto -> 500
+func writeOwnershipError(w http.ResponseWriter, r *http.Request, err error) {
+	var share *service.ShareExceededError
+	switch {
+	case errors.Is(err, service.ErrOwnershipSelf):
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "company_id", Code: utils.FieldSelfReference}})
+	case errors.As(err, &share):
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "percentual", Code: utils.FieldShareExceeded, Args: []any{share.Available}}})
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, repository.ErrOwnershipNotFound):
+		utils.NotFound(w, r)
+	case errors.Is(err, repository.ErrDuplicateOwnership):
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusConflict, utils.CodeOwnershipConflict, ""))
+	case errors.Is(err, service.ErrOwnershipCycle):
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusConflict, utils.CodeOwnershipCycle, ""))
+	default:
+		utils.InternalError(w, r, err)
+	}
+}
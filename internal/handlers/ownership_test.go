@@ -0,0 +1,268 @@
+package handlers
+
+/*
+
+go test -run 'TestOwnership_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// empresas do grupo: holding = companyID
+const (
+	ownB = "11222333000262"
+	ownC = "45321456000191"
+	ownD = "98765432000198"
+)
+
+func newOwnershipFixture(edges ...models.Ownership) (http.Handler, *ownershipRepoMock) {
+	om := newOwnershipRepoMock(edges...)
+	return versionedMux(&CompanyHandler{Repo: newOwnershipStore().repo(), Ownerships: om}), om
+}
+
+func newOwnershipStore() *companyStore {
+	store := newCompanyStore()
+	for id, n := range map[string]int{companyID: 150, ownB: 250, ownC: 50, ownD: 1200} {
+		store.companies[id] = models.Company{ID: id, CNPJ: id, NomeFantasia: "Empresa " + id, NumeroFuncionarios: n, NumeroMinimoPCDExigidos: utils.ComputeMinPCD(n)}
+	}
+	pcd := 20
+	d := store.companies[ownD]
+	d.NumeroPCDContratados = &pcd
+	store.companies[ownD] = d
+	return store
+}
+
+func edge(parent, child string, pct float64) models.Ownership {
+	return models.Ownership{ID: parent + ">" + child, ParentID: parent, ChildID: child, Percentual: pct}
+}
+
+func TestOwnership_SubsidiariesCRUD(t *testing.T) {
+	mux, om := newOwnershipFixture()
+	path := "/api/companies/" + companyID + "/subsidiaries"
+
+	rr := doJSON(mux, http.MethodPost, "/api/v2/companies/"+companyID+"/subsidiaries", `{"company_id":"11.222.333/0002-62","percentual":60}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var created struct {
+		Data models.Ownership `json:"data"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &created)
+	if o := created.Data; o.ParentID != companyID || o.ChildID != ownB || o.Percentual != 60 || o.CreatedAt.IsZero() {
+		t.Fatalf("participação = %+v", o)
+	}
+
+	// repetida, a própria empresa e controlada inexistente
+	if rr = doJSON(mux, http.MethodPost, path, `{"company_id":"`+ownB+`","percentual":10}`); rr.Code != http.StatusConflict {
+		t.Fatalf("repetida: status=%d", rr.Code)
+	}
+	rr = doJSON(mux, http.MethodPost, path, `{"company_id":"`+companyID+`","percentual":10}`)
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["company_id"] != utils.FieldSelfReference {
+		t.Fatalf("própria: status=%d errors=%v", rr.Code, errs)
+	}
+	if rr = doJSON(mux, http.MethodPost, path, `{"company_id":"11222333000343","percentual":10}`); rr.Code != http.StatusNotFound {
+		t.Fatalf("controlada inexistente: status=%d", rr.Code)
+	}
+	rr = doJSON(mux, http.MethodPost, path, `{"company_id":"`+ownC+`","percentual":0}`)
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["percentual"] != utils.FieldTooSmall {
+		t.Fatalf("percentual 0: status=%d errors=%v", rr.Code, errs)
+	}
+
+	if rr = doJSON(mux, http.MethodPut, path+"/"+ownB, `{"percentual":70}`); rr.Code != http.StatusOK {
+		t.Fatalf("PUT status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if o := om.edges[[2]string{companyID, ownB}]; o.Percentual != 70 || !o.CreatedAt.Equal(created.Data.CreatedAt) {
+		t.Fatalf("depois do PUT = %+v", o)
+	}
+
+	// B já tem 70% da holding: outra controladora com 40% passaria de 100%
+	rr = doJSON(mux, http.MethodPost, "/api/companies/"+ownC+"/subsidiaries", `{"company_id":"`+ownB+`","percentual":40}`)
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["percentual"] != utils.FieldShareExceeded {
+		t.Fatalf("acima de 100%%: status=%d errors=%v", rr.Code, errs)
+	}
+
+	rr = doJSON(mux, http.MethodGet, path, "")
+	var list []models.Ownership
+	_ = json.Unmarshal(rr.Body.Bytes(), &list)
+	if len(list) != 1 || list[0].ChildID != ownB {
+		t.Fatalf("lista = %s", rr.Body.String())
+	}
+
+	if rr = doJSON(mux, http.MethodDelete, path+"/"+ownB, ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status=%d", rr.Code)
+	}
+	for _, m := range []string{http.MethodGet, http.MethodDelete} {
+		if rr = doJSON(mux, m, path+"/"+ownB, ""); rr.Code != http.StatusNotFound {
+			t.Fatalf("%s depois do DELETE: status=%d", m, rr.Code)
+		}
+	}
+}
+
+func TestOwnership_RejectsCycles(t *testing.T) {
+	mux, om := newOwnershipFixture(edge(companyID, ownB, 60), edge(ownB, ownC, 90))
+
+	for _, tc := range []struct{ parent, child string }{
+		{ownC, companyID}, // C -> A fecha A -> B -> C -> A
+		{ownC, ownB},      // C -> B fecha B -> C -> B
+	} {
+		rr := doJSON(mux, http.MethodPost, "/api/companies/"+tc.parent+"/subsidiaries", `{"company_id":"`+tc.child+`","percentual":10}`)
+		var p utils.Problem
+		_ = json.Unmarshal(rr.Body.Bytes(), &p)
+		if rr.Code != http.StatusConflict || p.Code != utils.CodeOwnershipCycle {
+			t.Fatalf("%s -> %s: status=%d body=%s", tc.parent, tc.child, rr.Code, rr.Body.String())
+		}
+	}
+	if len(om.edges) != 2 {
+		t.Fatalf("arestas = %v", om.edges)
+	}
+	// atalho sem ciclo (A -> C) é aceito
+	if rr := doJSON(mux, http.MethodPost, "/api/companies/"+companyID+"/subsidiaries", `{"company_id":"`+ownC+`","percentual":10}`); rr.Code != http.StatusCreated {
+		t.Fatalf("atalho: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestOwnership_AncestorsAndDescendants(t *testing.T) {
+	// A tem 60% de B e 100% de C; C tem 40% de B; B tem 50% de D
+	mux, _ := newOwnershipFixture(edge(companyID, ownB, 60), edge(companyID, ownC, 100), edge(ownC, ownB, 40), edge(ownB, ownD, 50))
+
+	type member struct {
+		id         string
+		depth      int
+		percentual float64
+	}
+	get := func(path string) []member {
+		t.Helper()
+		rr := doJSON(mux, http.MethodGet, path, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: status=%d body=%s", path, rr.Code, rr.Body.String())
+		}
+		var list []models.GroupMember
+		_ = json.Unmarshal(rr.Body.Bytes(), &list)
+		out := []member{}
+		for _, m := range list {
+			out = append(out, member{m.Company.ID, m.Depth, m.Percentual})
+		}
+		return out
+	}
+
+	// B: 60% direto + 100% x 40% via C
+	want := []member{{ownB, 1, 100}, {ownC, 1, 100}, {ownD, 2, 50}}
+	if got := get("/api/companies/" + companyID + "/descendants"); !slices.Equal(got, want) {
+		t.Fatalf("descendentes = %+v", got)
+	}
+	want = []member{{ownB, 1, 50}, {companyID, 2, 50}, {ownC, 2, 20}}
+	if got := get("/api/companies/" + ownD + "/ancestors"); !slices.Equal(got, want) {
+		t.Fatalf("ancestrais = %+v", got)
+	}
+	if got := get("/api/companies/" + companyID + "/ancestors"); len(got) != 0 {
+		t.Fatalf("holding sem controladoras = %+v", got)
+	}
+
+	// v2: envelope com as empresas no formato da v2
+	rr := doJSON(mux, http.MethodGet, "/api/v2/companies/"+ownD+"/ancestors", "")
+	var env struct {
+		Data []GroupMemberV2 `json:"data"`
+		Meta Meta            `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 3 || *env.Meta.Count != 3 || env.Data[0].Company.ID != ownB {
+		t.Fatalf("v2 = %s", rr.Body.String())
+	}
+
+	if rr = doJSON(mux, http.MethodGet, "/api/companies/11222333000343/descendants", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("inexistente: status=%d", rr.Code)
+	}
+}
+
+func TestOwnership_ConsolidatedGroup(t *testing.T) {
+	mux, _ := newOwnershipFixture(edge(companyID, ownB, 60), edge(ownB, ownD, 50))
+
+	rr := doJSON(mux, http.MethodGet, "/api/companies/"+companyID+"/group", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var g models.GroupConsolidated
+	_ = json.Unmarshal(rr.Body.Bytes(), &g)
+	// A 150 (mín. 3), B 250 (mín. 8), D 1200 (mín. 60, 20 contratados); C fora do grupo
+	want := models.GroupConsolidated{
+		CompanyID: companyID, Companies: 3,
+		NumeroFuncionarios: 1600, NumeroMinimoPCDExigidos: 71, NumeroPCDContratados: 20,
+		DeficitPCD: 51, MinimoPCDConsolidado: 80,
+	}
+	members := g.Members
+	g.Members = nil
+	if !reflect.DeepEqual(g, want) {
+		t.Fatalf("consolidado = %+v", g)
+	}
+	if len(members) != 3 || members[0].Company.ID != companyID || members[0].Depth != 0 || members[0].Percentual != 100 || members[2].Percentual != 30 {
+		t.Fatalf("membros = %+v", members)
+	}
+}
+
+// membros do grupo numa leitura só, qualquer que seja o tamanho do grupo
+func TestOwnership_GroupReadsMembersOnce(t *testing.T) {
+	rm := newOwnershipStore().repo()
+	get, many, gets, manys := rm.GetByIDFn, rm.GetManyFn, 0, 0
+	rm.GetByIDFn = func(ctx context.Context, id string) (*models.Company, error) {
+		gets++
+		return get(ctx, id)
+	}
+	rm.GetManyFn = func(ctx context.Context, ids []string) ([]models.Company, error) {
+		manys++
+		return many(ctx, ids)
+	}
+	om := newOwnershipRepoMock(edge(companyID, ownB, 60), edge(ownB, ownC, 100), edge(ownB, ownD, 50))
+	mux := versionedMux(&CompanyHandler{Repo: rm, Ownerships: om})
+
+	rr := doJSON(mux, http.MethodGet, "/api/companies/"+companyID+"/group", "")
+	var g models.GroupConsolidated
+	if err := json.Unmarshal(rr.Body.Bytes(), &g); err != nil || rr.Code != http.StatusOK || g.Companies != 4 {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	// GetByID: a conferência da empresa e a própria holding
+	if manys != 1 || gets != 2 {
+		t.Fatalf("GetMany = %d, GetByID = %d", manys, gets)
+	}
+}
+
+func TestOwnership_Cycles(t *testing.T) {
+	// A -> B -> C -> A, gravado direto no banco (a API recusa); D controlada por C
+	mux, _ := newOwnershipFixture(edge(companyID, ownB, 50), edge(ownB, ownC, 50), edge(ownC, companyID, 50), edge(ownC, ownD, 100))
+
+	for _, id := range []string{ownB, ownD} {
+		rr := doJSON(mux, http.MethodGet, "/api/companies/"+id+"/group/cycles", "")
+		var cycles []models.OwnershipCycle
+		_ = json.Unmarshal(rr.Body.Bytes(), &cycles)
+		if rr.Code != http.StatusOK || len(cycles) != 1 || !slices.Equal(cycles[0].Path, []string{companyID, ownB, ownC, companyID}) {
+			t.Fatalf("ciclos a partir de %s: status=%d body=%s", id, rr.Code, rr.Body.String())
+		}
+	}
+
+	// com ciclo, a busca termina e cada empresa aparece uma vez
+	rr := doJSON(mux, http.MethodGet, "/api/companies/"+ownB+"/descendants", "")
+	var list []models.GroupMember
+	_ = json.Unmarshal(rr.Body.Bytes(), &list)
+	if len(list) != 3 {
+		t.Fatalf("descendentes = %s", rr.Body.String())
+	}
+
+	mux, _ = newOwnershipFixture(edge(companyID, ownB, 50))
+	rr = doJSON(mux, http.MethodGet, "/api/v2/companies/"+companyID+"/group/cycles", "")
+	var env struct {
+		Data []models.OwnershipCycle `json:"data"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if rr.Code != http.StatusOK || env.Data == nil || len(env.Data) != 0 {
+		t.Fatalf("sem ciclos: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
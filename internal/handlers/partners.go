@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Sócios e administradores (QSA): /api/companies/{id}/partners[/{partner_id}]
+// e a consulta reversa /api/partners/{documento}/companies.
+
+// Body de POST e PUT (validado por schema/partner.json); igual na v1 e na v2
+type PartnerDTO struct {
+	Nome              string   `json:"nome"`
+	Documento         string   `json:"documento"`
+	Qualificacao      int      `json:"qualificacao"`
+	DataEntrada       *string  `json:"data_entrada"`
+	PercentualCapital *float64 `json:"percentual_capital"`
+}
+
+func (d PartnerDTO) input() service.PartnerInput {
+	return service.PartnerInput{
+		Nome: d.Nome, Documento: d.Documento, Qualificacao: d.Qualificacao,
+		DataEntrada: d.DataEntrada, PercentualCapital: d.PercentualCapital,
+	}
+}
+
+// GET (lista) e POST /api/companies/{id}/partners
+func (h *CompanyHandler) CompanyPartners(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listPartners(w, r)
+	case http.MethodPost:
+		schema.Validate(schema.Partner, http.HandlerFunc(h.createPartner)).ServeHTTP(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// GET, PUT e DELETE /api/companies/{id}/partners/{partner_id}
+func (h *CompanyHandler) CompanyPartnerByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getPartner(w, r)
+	case http.MethodPut:
+		schema.Validate(schema.Partner, http.HandlerFunc(h.replacePartner)).ServeHTTP(w, r)
+	case http.MethodDelete:
+		h.deletePartner(w, r)
+	default:
+		utils.MethodNotAllowed(w, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+// GET /api/partners/{documento}/companies: empresas em que o CPF/CNPJ é sócio
+func (h *CompanyHandler) PartnerCompanies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.MethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	doc := utils.SanitizeCNPJ(r.PathValue("documento"))
+	if utils.DocumentType(doc) == "" {
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "documento", Code: utils.FieldInvalidDoc}})
+		return
+	}
+	limit, skip := pagination(r.URL.Query())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	list, err := h.service().CompaniesOfPartner(ctx, doc, limit, skip)
+	if err != nil {
+		writePartnerError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: list,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
+
+func (h *CompanyHandler) listPartners(w http.ResponseWriter, r *http.Request) {
+	limit, skip := pagination(r.URL.Query())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := h.service().ListPartners(ctx, r.PathValue("id"), limit, skip)
+	if err != nil {
+		writePartnerError(w, r, err)
+		return
+	}
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, list)
+		return
+	}
+	count := len(list)
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{
+		Data: list,
+		Meta: Meta{APIVersion: V2, Limit: &limit, Skip: &skip, Count: &count},
+	})
+}
+
+func (h *CompanyHandler) createPartner(w http.ResponseWriter, r *http.Request) {
+	var dto PartnerDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	p, err := h.service().CreatePartner(ctx, r.PathValue("id"), dto.input())
+	if err != nil {
+		writePartnerError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusCreated, p)
+}
+
+func (h *CompanyHandler) getPartner(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	p, err := h.service().GetPartner(ctx, r.PathValue("id"), r.PathValue("partner_id"))
+	if err != nil {
+		writePartnerError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, p)
+}
+
+func (h *CompanyHandler) replacePartner(w http.ResponseWriter, r *http.Request) {
+	var dto PartnerDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	p, err := h.service().ReplacePartner(ctx, r.PathValue("id"), r.PathValue("partner_id"), dto.input())
+	if err != nil {
+		writePartnerError(w, r, err)
+		return
+	}
+	writeData(w, r, http.StatusOK, p)
+}
+
+func (h *CompanyHandler) deletePartner(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if err := h.service().DeletePartner(ctx, r.PathValue("id"), r.PathValue("partner_id")); err != nil {
+		writePartnerError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Qualificação fora da tabela ou participação acima de 100% -> 400,
+// empresa ou sócio inexistente -> 404, CPF/CNPJ repetido na empresa -> 409, o resto -> 500
+func writePartnerError(w http.ResponseWriter, r *http.Request, err error) {
+	var share *service.ShareExceededError
+	switch {
+	case errors.Is(err, service.ErrUnknownQualification):
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "qualificacao", Code: utils.FieldNotInTable, Args: []any{"QSA"}}})
+	case errors.As(err, &share):
+		utils.ValidationFailed(w, r, []utils.FieldError{{Field: "percentual_capital", Code: utils.FieldShareExceeded, Args: []any{share.Available}}})
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, repository.ErrPartnerNotFound):
+		utils.NotFound(w, r)
+	case errors.Is(err, repository.ErrDuplicatePartner):
+		utils.WriteProblem(w, r, utils.NewProblem(http.StatusConflict, utils.CodePartnerConflict, ""))
+	default:
+		utils.InternalError(w, r, err)
+	}
+}
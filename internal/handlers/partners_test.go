@@ -0,0 +1,125 @@
+package handlers
+
+/*
+
+go test -run 'TestPartners_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+const (
+	partnersPath = "/api/companies/" + companyID + "/partners"
+	otherCompany = "11444777000161"
+)
+
+// duas empresas em memória (companyID e otherCompany) + QSA em memória
+func newPartnersMux(list ...models.Partner) (http.Handler, *partnerRepoMock) {
+	pm := newPartnerRepoMock(list...)
+	store := newCompanyStore(*storedCompany(), models.Company{ID: otherCompany, CNPJ: otherCompany, NomeFantasia: "Holding"})
+	return versionedMux(&CompanyHandler{Repo: store.repo(), Partners: pm}), pm
+}
+
+func TestPartners_CRUDAndReverseLookup(t *testing.T) {
+	mux, _ := newPartnersMux(models.Partner{
+		ID: "x1", CompanyID: otherCompany, Nome: "Maria", Documento: "52998224725", TipoDocumento: utils.DocumentCPF, Qualificacao: 65,
+	})
+
+	rr := doJSON(mux, http.MethodPost, partnersPath,
+		`{"nome":"Maria","documento":"529.982.247-25","qualificacao":49,"data_entrada":"2015-03-02","percentual_capital":60}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var p models.Partner
+	_ = json.Unmarshal(rr.Body.Bytes(), &p)
+	if p.ID == "" || p.Documento != "52998224725" || p.TipoDocumento != utils.DocumentCPF || p.QualificacaoDescricao != "Sócio-Administrador" {
+		t.Fatalf("sócio = %+v", p)
+	}
+
+	// sócio pessoa jurídica (CNPJ com máscara)
+	rr = doJSON(mux, http.MethodPost, partnersPath,
+		`{"nome":"Holding","documento":"11.444.777/0001-61","qualificacao":48,"percentual_capital":40}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("PJ: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	// consulta reversa: Maria é sócia das duas empresas
+	rr = doJSON(mux, http.MethodGet, "/api/v2/partners/529.982.247-25/companies", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("reversa: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var env struct {
+		Data []models.PartnerCompany `json:"data"`
+		Meta Meta                    `json:"meta"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if len(env.Data) != 2 || *env.Meta.Count != 2 {
+		t.Fatalf("reversa = %+v", env)
+	}
+	got := map[string]int{}
+	for _, pc := range env.Data {
+		got[pc.Company.ID] = pc.Partner.Qualificacao
+	}
+	if got[companyID] != 49 || got[otherCompany] != 65 {
+		t.Fatalf("reversa = %v", got)
+	}
+
+	path := partnersPath + "/" + p.ID
+	rr = doJSON(mux, http.MethodPut, path, `{"nome":"Maria","documento":"52998224725","qualificacao":22,"percentual_capital":55.5}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(mux, http.MethodDelete, path, ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status=%d", rr.Code)
+	}
+	var list []models.Partner
+	_ = json.Unmarshal(doJSON(mux, http.MethodGet, partnersPath, "").Body.Bytes(), &list)
+	if len(list) != 1 || list[0].TipoDocumento != utils.DocumentCNPJ {
+		t.Fatalf("QSA = %+v", list)
+	}
+}
+
+func TestPartners_Invalid(t *testing.T) {
+	mux, _ := newPartnersMux(models.Partner{
+		ID: "x1", CompanyID: companyID, Nome: "Maria", Documento: "52998224725", Qualificacao: 49, PercentualCapital: ptr(70.0),
+	})
+
+	cases := []struct {
+		body  string
+		field string
+		code  string
+	}{
+		{`{"nome":"João","documento":"11222333000182","qualificacao":22}`, "documento", utils.FieldInvalidDoc},
+		{`{"nome":"João","documento":"111.444.777-35","qualificacao":99}`, "qualificacao", utils.FieldNotInTable},
+		{`{"nome":"João","documento":"111.444.777-35","qualificacao":22,"percentual_capital":30.01}`, "percentual_capital", utils.FieldShareExceeded},
+		{`{"nome":"João","documento":"111.444.777-35","qualificacao":22,"percentual_capital":101}`, "percentual_capital", utils.FieldTooLarge},
+		{`{"nome":"João","documento":"111.444.777-35","qualificacao":22,"data_entrada":"02/03/2015"}`, "data_entrada", utils.FieldInvalidDate},
+	}
+	for _, tc := range cases {
+		rr := doJSON(mux, http.MethodPost, partnersPath, tc.body)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status=%d body=%s", tc.body, rr.Code, rr.Body.String())
+		}
+		if got := problemErrors(t, rr)[tc.field]; got != tc.code {
+			t.Fatalf("%s: %s = %q, want %q", tc.body, tc.field, got, tc.code)
+		}
+	}
+
+	if rr := doJSON(mux, http.MethodPost, partnersPath, `{"nome":"Maria","documento":"52998224725","qualificacao":22}`); rr.Code != http.StatusConflict {
+		t.Fatalf("documento repetido: status=%d", rr.Code)
+	}
+	// 70 + 30 cabe
+	if rr := doJSON(mux, http.MethodPost, partnersPath, `{"nome":"João","documento":"11144477735","qualificacao":22,"percentual_capital":30}`); rr.Code != http.StatusCreated {
+		t.Fatalf("30%% restantes: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(mux, http.MethodGet, "/api/partners/12345/companies", ""); rr.Code != http.StatusBadRequest {
+		t.Fatalf("reversa com documento inválido: status=%d", rr.Code)
+	}
+}
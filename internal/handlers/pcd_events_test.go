@@ -0,0 +1,130 @@
+package handlers
+
+/*
+
+go test -run 'TestPCDThresholdEvent_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+type published struct {
+	body    string
+	headers amqp091.Table
+}
+
+// patchHeadcount: PATCH de numero_funcionarios (from -> to); devolve os eventos
+// publicados e a empresa como ficou gravada
+func patchHeadcount(t *testing.T, from, to int) ([]published, models.Company) {
+	t.Helper()
+	store := newCompanyStore(models.Company{ID: companyID, CNPJ: companyID, NomeFantasia: "ACME",
+		NumeroFuncionarios: from, NumeroMinimoPCDExigidos: utils.ComputeMinPCD(from)})
+	var got []published
+	pm := &pubMock{PublishFn: func(_ context.Context, body string, h amqp091.Table) error {
+		got = append(got, published{body, h})
+		return nil
+	}}
+	h := &CompanyHandler{Repo: store.repo(), Pub: pm}
+
+	body, _ := json.Marshal(map[string]int{"numero_funcionarios": to})
+	req := httptest.NewRequest(http.MethodPatch, "/api/companies/"+companyID, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	h.CompanyByID(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	return got, store.companies[companyID]
+}
+
+func TestPCDThresholdEvent_Crossing(t *testing.T) {
+	got, _ := patchHeadcount(t, 99, 100)
+	if len(got) != 2 || got[0].headers["action"] != events.ActionUpdated {
+		t.Fatalf("eventos = %+v", got)
+	}
+	pcd := got[1]
+	if pcd.headers["action"] != events.ActionPCDThreshold || pcd.headers["old_band"] != "0-99" || pcd.headers["new_band"] != "100-200" ||
+		pcd.headers["old_min_pcd"] != 0 || pcd.headers["new_min_pcd"] != 2 || pcd.headers["thresholds"] != "100" {
+		t.Fatalf("headers = %+v", pcd.headers)
+	}
+
+	var ev service.PCDThresholdEvent
+	if err := json.Unmarshal([]byte(pcd.body), &ev); err != nil {
+		t.Fatalf("corpo não é JSON: %v (%s)", err, pcd.body)
+	}
+	if ev.Type != events.ActionPCDThreshold || ev.CompanyID != companyID || ev.Before.MinPCD != 0 || ev.After.MinPCD != 2 ||
+		ev.After.Percent != 0.02 || len(ev.Crossings) != 1 || ev.Crossings[0].Direction != "up" {
+		t.Fatalf("evento = %+v", ev)
+	}
+	if want := "Cota PCD da EMPRESA ACME: 99 → 100 funcionários, faixa 0-99 → 100-200, mínimo de PCD 0 → 2"; ev.Message != want {
+		t.Fatalf("mensagem = %q, want %q", ev.Message, want)
+	}
+}
+
+func TestPCDThresholdEvent_MinChangeOnly(t *testing.T) {
+	// mesma faixa (2%), mas ceil(150*0.02)=3 -> ceil(160*0.02)=4
+	got, _ := patchHeadcount(t, 150, 160)
+	if len(got) != 2 || got[1].headers["thresholds"] != "" || got[1].headers["new_min_pcd"] != 4 {
+		t.Fatalf("eventos = %+v", got)
+	}
+}
+
+func TestPCDThresholdEvent_NoChange(t *testing.T) {
+	// 110 e 120: mínimo 3 nos dois
+	if got, _ := patchHeadcount(t, 110, 120); len(got) != 1 {
+		t.Fatalf("esperava só o evento de edição; got %+v", got)
+	}
+}
+
+// abaixo de 100 a cota é 0: o 0 precisa ser gravado (o evento diz que ela caiu)
+func TestPCDThresholdEvent_DropBelowThreshold(t *testing.T) {
+	got, stored := patchHeadcount(t, 120, 50)
+	if stored.NumeroFuncionarios != 50 || stored.NumeroMinimoPCDExigidos != 0 {
+		t.Fatalf("gravado: funcionários=%d mínimo=%d", stored.NumeroFuncionarios, stored.NumeroMinimoPCDExigidos)
+	}
+	if len(got) != 2 || got[1].headers["new_band"] != "0-99" || got[1].headers["new_min_pcd"] != 0 {
+		t.Fatalf("eventos = %+v", got)
+	}
+
+	if _, stored = patchHeadcount(t, 120, 0); stored.NumeroFuncionarios != 0 || stored.NumeroMinimoPCDExigidos != 0 {
+		t.Fatalf("zerada: funcionários=%d mínimo=%d", stored.NumeroFuncionarios, stored.NumeroMinimoPCDExigidos)
+	}
+}
+
+func TestPCDThresholdEvent_CreateEn(t *testing.T) {
+	var got []published
+	rm := &repoMock{CreateFn: func(_ context.Context, c *models.Company) (string, error) { return c.CNPJ, nil }}
+	pm := &pubMock{PublishFn: func(_ context.Context, body string, h amqp091.Table) error {
+		got = append(got, published{body, h})
+		return nil
+	}}
+	h := &CompanyHandler{Repo: rm, Pub: pm, EventLang: i18n.En}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/companies",
+		bytes.NewBufferString(`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","numero_funcionarios":600}`))
+	h.Companies(httptest.NewRecorder(), req)
+
+	if len(got) != 2 || got[1].headers["thresholds"] != "100,201,501" || got[1].headers["lang"] != "en" {
+		t.Fatalf("eventos = %+v", got)
+	}
+	var ev service.PCDThresholdEvent
+	_ = json.Unmarshal([]byte(got[1].body), &ev)
+	if want := "PCD quota of company ACME: 0 → 600 employees, band 0-99 → 501-1000, minimum PCD 0 → 24"; ev.Message != want {
+		t.Fatalf("mensagem = %q, want %q", ev.Message, want)
+	}
+}
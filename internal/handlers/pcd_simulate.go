@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Body de POST /api/pcd/simulate (validado por schema/pcd_simulate.json)
+type PCDSimulateDTO struct {
+	Headcounts  []int         `json:"headcounts,omitempty"`
+	Growth      *PCDGrowthDTO `json:"growth,omitempty"`
+	RuleVersion string        `json:"rule_version,omitempty"`
+	CurrentPCD  int           `json:"current_pcd,omitempty"`
+}
+
+type PCDGrowthDTO struct {
+	Start       int     `json:"start"`
+	Periods     int     `json:"periods"`
+	RatePercent float64 `json:"rate_percent,omitempty"` // crescimento composto por período
+	Increment   int     `json:"increment,omitempty"`    // funcionários somados por período
+}
+
+// POST /api/pcd/simulate: obrigação PCD para uma série de números de funcionários
+// (nada é lido ou gravado no cadastro).
+func (h *CompanyHandler) SimulatePCD(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.MethodNotAllowed(w, r, http.MethodPost)
+		return
+	}
+	schema.Validate(schema.PCDSimulate, http.HandlerFunc(h.simulatePCD)).ServeHTTP(w, r)
+}
+
+func (h *CompanyHandler) simulatePCD(w http.ResponseWriter, r *http.Request) {
+	var dto PCDSimulateDTO
+	if err := utils.DecodeStrict(r.Body, &dto); err != nil {
+		utils.InvalidJSON(w, r, err)
+		return
+	}
+
+	in := service.PCDSimulationInput{
+		Headcounts:  dto.Headcounts,
+		RuleVersion: dto.RuleVersion,
+		CurrentPCD:  dto.CurrentPCD,
+	}
+	if g := dto.Growth; g != nil {
+		in.Growth = &service.PCDGrowth{Start: g.Start, Periods: g.Periods, RatePercent: g.RatePercent, Increment: g.Increment}
+	}
+	sim, err := service.SimulatePCD(in)
+	if errors.Is(err, service.ErrUnknownPCDRule) {
+		versions := make([]string, 0, len(utils.PCDRules))
+		for v := range utils.PCDRules {
+			versions = append(versions, v)
+		}
+		sort.Strings(versions)
+		utils.ValidationFailed(w, r, []utils.FieldError{{
+			Field: "rule_version", Code: utils.FieldNotInEnum, Args: []any{strings.Join(versions, ", ")},
+		}})
+		return
+	}
+	if err != nil {
+		utils.InternalError(w, r, err)
+		return
+	}
+
+	if APIVersionFrom(r.Context()) != V2 {
+		utils.WriteResponse(w, r, http.StatusOK, sim)
+		return
+	}
+	utils.WriteResponse(w, r, http.StatusOK, Envelope{Data: sim, Meta: Meta{APIVersion: V2}})
+}
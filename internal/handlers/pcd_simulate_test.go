@@ -0,0 +1,144 @@
+package handlers
+
+/*
+
+go test -run 'TestSimulatePCD_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+func simulate(t *testing.T, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	// sem repositório: a simulação não pode tocar no cadastro
+	mux := versionedMux(&CompanyHandler{Repo: &repoMock{}})
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSimulatePCD_Headcounts(t *testing.T) {
+	rr := simulate(t, "/api/pcd/simulate", `{"headcounts":[90,100,600,180],"current_pcd":2}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rr.Code, rr.Body.String())
+	}
+	var sim service.PCDSimulation
+	if err := json.Unmarshal(rr.Body.Bytes(), &sim); err != nil {
+		t.Fatal(err)
+	}
+	if sim.RuleVersion != utils.PCDRuleDefault || len(sim.Points) != 4 {
+		t.Fatalf("sim = %+v", sim)
+	}
+
+	p := sim.Points
+	if p[0].MinPCD != 0 || p[0].Crossings != nil || p[0].AdditionalHires != 0 {
+		t.Fatalf("90: %+v", p[0])
+	}
+	if p[1].MinPCD != 2 || p[1].DeltaMinPCD != 2 || len(p[1].Crossings) != 1 || p[1].Crossings[0].Threshold != 100 {
+		t.Fatalf("100: %+v", p[1])
+	}
+	// 600 * 4% = 24; cruza 201 e 501
+	if p[2].Band != "501-1000" || p[2].MinPCD != 24 || p[2].AdditionalHires != 22 || len(p[2].Crossings) != 2 {
+		t.Fatalf("600: %+v", p[2])
+	}
+	if c := p[3].Crossings; len(c) != 2 || c[0].Direction != utils.PCDCrossingDown || c[0].Threshold != 501 || p[3].DeltaMinPCD != -20 {
+		t.Fatalf("180: %+v", p[3])
+	}
+	if sim.PeakMinPCD != 24 || sim.AdditionalHires != 22 {
+		t.Fatalf("pico = %d, contratações = %d", sim.PeakMinPCD, sim.AdditionalHires)
+	}
+}
+
+func TestSimulatePCD_GrowthV2(t *testing.T) {
+	rr := simulate(t, "/api/v2/pcd/simulate", `{"growth":{"start":90,"periods":3,"rate_percent":10,"increment":1}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rr.Code, rr.Body.String())
+	}
+	var env struct {
+		Data service.PCDSimulation `json:"data"`
+		Meta Meta                  `json:"meta"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &env); err != nil {
+		t.Fatal(err)
+	}
+	// round(90 * 1.1^k) + k
+	want := []int{90, 100, 111, 123}
+	if env.Meta.APIVersion != V2 || len(env.Data.Points) != len(want) {
+		t.Fatalf("env = %+v", env)
+	}
+	for i, n := range want {
+		if env.Data.Points[i].Headcount != n {
+			t.Fatalf("ponto %d = %d, want %d", i, env.Data.Points[i].Headcount, n)
+		}
+	}
+	if env.Data.Points[1].Crossings[0].ToBand != "100-200" {
+		t.Fatalf("cruzamento = %+v", env.Data.Points[1].Crossings)
+	}
+}
+
+// limites do schema: a curva passa do int, os pontos saturam no teto
+func TestSimulatePCD_GrowthLimits(t *testing.T) {
+	rr := simulate(t, "/api/pcd/simulate", `{"growth":{"start":10000000,"periods":120,"rate_percent":1000,"increment":100000}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rr.Code, rr.Body.String())
+	}
+	var sim service.PCDSimulation
+	if err := json.Unmarshal(rr.Body.Bytes(), &sim); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range sim.Points {
+		if p.Headcount != service.MaxSimulatedHeadcount || p.MinPCD <= 0 {
+			t.Fatalf("ponto %d = %+v", p.Period, p)
+		}
+	}
+
+	rr = simulate(t, "/api/pcd/simulate", `{"growth":{"start":0,"periods":120,"rate_percent":-100,"increment":-100000}}`)
+	if err := json.Unmarshal(rr.Body.Bytes(), &sim); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("status = %d body=%s", rr.Code, rr.Body.String())
+	}
+	if last := sim.Points[len(sim.Points)-1]; last.Headcount != 0 || sim.PeakMinPCD != 0 {
+		t.Fatalf("queda: último = %+v, pico = %d", last, sim.PeakMinPCD)
+	}
+}
+
+func TestSimulatePCD_Invalid(t *testing.T) {
+	cases := []struct {
+		body  string
+		field string
+		code  string
+	}{
+		{`{}`, "headcounts", utils.FieldRequiredOneOf},
+		{`{"headcounts":[100],"growth":{"start":1,"periods":1}}`, "growth", utils.FieldMutuallyExclusive},
+		{`{"headcounts":[100,-1]}`, "headcounts[1]", utils.FieldMustBeNonNeg},
+		{`{"headcounts":[100],"rule_version":"lei-2030"}`, "rule_version", utils.FieldNotInEnum},
+		{`{"growth":{"start":10,"periods":500}}`, "growth.periods", utils.FieldTooLarge},
+	}
+	for _, tc := range cases {
+		rr := simulate(t, "/api/pcd/simulate", tc.body)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d", tc.body, rr.Code)
+		}
+		var p utils.Problem
+		if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, e := range p.Errors {
+			found = found || (e.Field == tc.field && e.Code == tc.code && e.Message != "")
+		}
+		if !found {
+			t.Fatalf("%s: errors = %+v", tc.body, p.Errors)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+/*
+
+go test -run 'TestPorte_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+func TestPorte_Create(t *testing.T) {
+	var created *models.Company
+	rm := &repoMock{CreateFn: func(_ context.Context, c *models.Company) (string, error) {
+		created = c
+		return c.ID, nil
+	}}
+	h := &CompanyHandler{Repo: rm, Pub: &pubMock{}}
+	mux := versionedMux(h)
+
+	// faturamento de EPP e poucos funcionários: vale o maior (EPP)
+	rr := doJSON(mux, http.MethodPost, "/api/v2/companies",
+		`{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","numero_funcionarios":5,"regime_tributario":"simples_nacional","faturamento_anual":1200000.50}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if created.Porte != utils.PorteEPP || created.RegimeTributario != models.RegimeSimplesNacional || *created.FaturamentoAnual != 1200000.50 {
+		t.Fatalf("gravado = %q %q %v", created.Porte, created.RegimeTributario, created.FaturamentoAnual)
+	}
+	var env struct {
+		Data CompanyV2 `json:"data"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &env)
+	if env.Data.Porte != utils.PorteEPP {
+		t.Fatalf("v2 = %+v", env.Data)
+	}
+
+	// sem faturamento: só os funcionários; limites configurados no handler
+	h.PorteThresholds = utils.DefaultPorteThresholds
+	h.PorteThresholds.MEMaxFuncionarios = 19
+	rr = doJSON(mux, http.MethodPost, "/api/companies", `{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","numero_funcionarios":15}`)
+	if rr.Code != http.StatusCreated || created.Porte != utils.PorteME || created.FaturamentoAnual != nil {
+		t.Fatalf("status=%d porte=%q", rr.Code, created.Porte)
+	}
+
+	// porte não vem do cliente
+	rr = doJSON(mux, http.MethodPost, "/api/companies", `{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","porte":"grande"}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("porte no body: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rr = doJSON(mux, http.MethodPost, "/api/companies", `{"cnpj":"`+validCNPJ+`","nome_fantasia":"ACME","regime_tributario":"lucro","faturamento_anual":-1}`)
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest || errs["regime_tributario"] == "" || errs["faturamento_anual"] == "" {
+		t.Fatalf("inválidos: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestPorte_PatchRecomputes(t *testing.T) {
+	stored := storedCompany() // 150 funcionários
+	faturamento := 100_000.0
+	stored.FaturamentoAnual, stored.Porte = &faturamento, utils.PorteGrande
+	var upd *models.Company
+	rm := &repoMock{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return stored, nil },
+		UpdateFn: func(_ context.Context, _ string, u *models.Company, _ []string) error {
+			upd = u
+			return nil
+		},
+	}
+	mux := versionedMux(&CompanyHandler{Repo: rm, Pub: &pubMock{}})
+
+	// funcionários mudam: o faturamento gravado entra no cálculo
+	rr := doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"numero_funcionarios":30}`)
+	if rr.Code != http.StatusOK || upd.Porte != utils.PorteEPP || upd.FaturamentoAnual != nil {
+		t.Fatalf("status=%d porte=%q", rr.Code, upd.Porte)
+	}
+
+	// faturamento muda: os funcionários gravados entram no cálculo
+	rr = doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"faturamento_anual":400000000}`)
+	if rr.Code != http.StatusOK || upd.Porte != utils.PorteGrande || *upd.FaturamentoAnual != 400000000 {
+		t.Fatalf("status=%d porte=%q", rr.Code, upd.Porte)
+	}
+
+	// só o regime: porte não é regravado
+	rr = doJSON(mux, http.MethodPatch, "/api/companies/"+companyID, `{"regime_tributario":"lucro_presumido"}`)
+	if rr.Code != http.StatusOK || upd.Porte != "" || upd.RegimeTributario != models.RegimeLucroPresumido {
+		t.Fatalf("status=%d update=%q %q", rr.Code, upd.Porte, upd.RegimeTributario)
+	}
+}
+
+func TestPorte_FiltersAndStats(t *testing.T) {
+	var gotFilter models.CompanyFilter
+	var gotGroupBy []string
+	rm := &repoMock{
+		FindFn: func(_ context.Context, f models.CompanyFilter, _, _ int64) ([]models.Company, error) {
+			gotFilter = f
+			return []models.Company{}, nil
+		},
+		StatsFn: func(_ context.Context, f models.CompanyFilter, groupBy []string) (*models.CompanyStats, error) {
+			gotFilter, gotGroupBy = f, groupBy
+			return &models.CompanyStats{}, nil
+		},
+	}
+	mux := versionedMux(&CompanyHandler{Repo: rm})
+
+	rr := doJSON(mux, http.MethodGet, "/api/companies?regime_tributario=Lucro_Real&porte=medio", "")
+	if rr.Code != http.StatusOK || gotFilter.RegimeTributario != models.RegimeLucroReal || gotFilter.Porte != utils.PorteMedio {
+		t.Fatalf("status=%d filtro=%+v", rr.Code, gotFilter)
+	}
+
+	rr = doJSON(mux, http.MethodGet, "/api/companies/stats?porte=epp&group_by=regime_tributario,porte", "")
+	if rr.Code != http.StatusOK || gotFilter.Porte != utils.PorteEPP ||
+		!slices.Equal(gotGroupBy, []string{models.StatsByRegime, models.StatsByPorte}) {
+		t.Fatalf("status=%d filtro=%+v group_by=%v", rr.Code, gotFilter, gotGroupBy)
+	}
+
+	rr = doJSON(mux, http.MethodGet, "/api/companies?porte=pequeno&regime_tributario=x", "")
+	if errs := problemErrors(t, rr); rr.Code != http.StatusBadRequest ||
+		errs["porte"] != utils.FieldNotInEnum || errs["regime_tributario"] != utils.FieldNotInEnum {
+		t.Fatalf("inválidos: status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
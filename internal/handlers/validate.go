@@ -1,31 +1,51 @@
 package handlers
 
-import "errors"
+import "github.com/Werneck0live/cadastro-empresa/internal/utils"
 
-func validateCreateDTO(d CompanyCreateDTO) error {
-	if d.CNPJ == "" {
-		return errors.New("cnpj is required")
-	}
-	if d.NomeFantasia == "" && d.RazaoSocial == "" {
-		return errors.New("either nome_fantasia or razao_social is required")
+// As regras de formato dos payloads ficam nos JSON Schemas (internal/schema/schemas).
+// Aqui só o que depende da rota e não cabe num schema.
+
+// id = {id} da rota; no PUT o cnpj do body (se vier) deve ser igual a ele.
+func validatePutCNPJ(d CompanyPutDTO, id string) []utils.FieldError {
+	if d.CNPJ != nil && utils.SanitizeCNPJ(*d.CNPJ) != id {
+		return []utils.FieldError{{Field: "cnpj", Code: utils.FieldMismatch}}
 	}
-	if d.NumeroFuncionarios < 0 {
-		return errors.New("numero_funcionarios must be >= 0")
+	if !utils.ValidateCNPJ(id) {
+		return []utils.FieldError{{Field: "cnpj", Code: utils.FieldInvalidCNPJ}}
 	}
 	return nil
 }
 
-func validateUpdateDTO(d CompanyPatchDTO) error {
-	if d.NumeroFuncionarios != nil && *d.NumeroFuncionarios < 0 {
-		return errors.New("numero_funcionarios must be >= 0")
+// inscrição estadual e UF vêm juntas ("" = não informada); a IE é conferida
+// pelo algoritmo da UF (o formato de cada campo já passou pelo schema)
+func validateIE(ie, uf string) []utils.FieldError {
+	switch {
+	case ie == "" && uf == "":
+		return nil
+	case uf == "":
+		return []utils.FieldError{{Field: "inscricao_estadual_uf", Code: utils.FieldRequired}}
+	case ie == "":
+		return []utils.FieldError{{Field: "inscricao_estadual", Code: utils.FieldRequired}}
+	case !utils.ValidateIE(uf, utils.SanitizeIE(ie)):
+		return []utils.FieldError{{Field: "inscricao_estadual", Code: utils.FieldInvalidIE, Args: []any{uf}}}
 	}
-
 	return nil
 }
 
-func validatePutDTO(d CompanyPutDTO) error {
-	if d.NumeroFuncionarios < 0 {
-		return errors.New("numero_funcionarios must be >= 0")
+// desligamento (se vier) não pode ser antes da admissão; prefix = caminho do
+// funcionário no payload (ex.: "line[3]." na importação CSV)
+func validateEmployeeDates(admissao string, desligamento *string, prefix string) []utils.FieldError {
+	if desligamento != nil && *desligamento < admissao {
+		return []utils.FieldError{{Field: prefix + "desligamento", Code: utils.FieldTooSmall, Args: []any{admissao}}}
 	}
 	return nil
 }
+
+// deref: valor apontado ou zero (campos opcionais do PATCH)
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
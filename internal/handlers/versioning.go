@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Versões da API. As rotas são registradas uma vez só (/api/companies...);
+// Versioning reescreve /api/v1/... e /api/v2/... para elas e guarda a versão
+// no contexto, e os handlers escolhem o formato de entrada/saída por ela.
+type APIVersion string
+
+const (
+	V1 APIVersion = "v1" // contrato original (também servido sem prefixo em /api/companies)
+	V2 APIVersion = "v2" // envelope {data, meta}, CNPJ formatado, endereço estruturado
+)
+
+type apiVersionKey struct{}
+
+func WithAPIVersion(ctx context.Context, v APIVersion) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, v)
+}
+
+// APIVersionFrom: versão da requisição (sem versão no contexto = v1)
+func APIVersionFrom(ctx context.Context) APIVersion {
+	if v, ok := ctx.Value(apiVersionKey{}).(APIVersion); ok {
+		return v
+	}
+	return V1
+}
+
+// Versioning identifica a versão pelo prefixo do path.
+// Respostas da v1 (com ou sem prefixo) levam Deprecation/Sunset (RFC 9745 / RFC 8594)
+// e um Link para a versão sucessora.
+type Versioning struct {
+	V1DeprecatedAt time.Time // zero = v1 ainda não depreciada
+	V1Sunset       time.Time // zero = sem data de desligamento
+}
+
+var versionPrefixes = map[string]APIVersion{
+	"/api/v1/": V1,
+	"/api/v2/": V2,
+}
+
+func (m *Versioning) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		version := V1
+		path := r.URL.Path
+		for prefix, v := range versionPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				version = v
+				path = "/api/" + strings.TrimPrefix(path, prefix)
+				break
+			}
+		}
+
+		if version == V1 {
+			m.deprecationHeaders(w.Header(), path)
+		}
+
+		// mesmo esquema do http.StripPrefix: copia a requisição e troca só o path
+		r2 := r.WithContext(WithAPIVersion(r.Context(), version))
+		if path != r.URL.Path {
+			u := *r.URL
+			u.Path = path
+			u.RawPath = ""
+			r2.URL = &u
+		}
+		next.ServeHTTP(w, r2)
+	})
+}
+
+func (m *Versioning) deprecationHeaders(h http.Header, path string) {
+	if m.V1DeprecatedAt.IsZero() {
+		return
+	}
+	h.Set("Deprecation", "@"+strconv.FormatInt(m.V1DeprecatedAt.Unix(), 10))
+	if !m.V1Sunset.IsZero() {
+		h.Set("Sunset", m.V1Sunset.UTC().Format(http.TimeFormat))
+	}
+	successor := "/api/v2/" + strings.TrimPrefix(path, "/api/")
+	h.Add("Link", "<"+successor+`>; rel="successor-version"`)
+	h.Add("Link", `</docs>; rel="deprecation"; type="text/html"`)
+}
+
+// versionedPath: path canônico com a versão (v1 e sem prefixo são o mesmo contrato)
+func versionedPath(r *http.Request) string {
+	if v := APIVersionFrom(r.Context()); v != V1 {
+		return "/api/" + string(v) + "/" + strings.TrimPrefix(r.URL.Path, "/api/")
+	}
+	return r.URL.Path
+}
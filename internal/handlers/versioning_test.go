@@ -0,0 +1,229 @@
+package handlers
+
+/*
+
+go test -run 'TestVersioning_' -v ./internal/handlers -count=1
+
+*/
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+var (
+	testDeprecatedAt = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	testSunset       = time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func versionedMux(h *CompanyHandler) http.Handler {
+	api := http.NewServeMux()
+	h.Register(api)
+	return (&Versioning{V1DeprecatedAt: testDeprecatedAt, V1Sunset: testSunset}).Wrap(api)
+}
+
+func storedCompany() *models.Company {
+	return &models.Company{
+		ID: companyID, CNPJ: companyID, NomeFantasia: "ACME",
+		Endereco:           "Av. Paulista, 1000 - São Paulo/SP, CEP 01310-100",
+		NumeroFuncionarios: 150, NumeroMinimoPCDExigidos: 3,
+		EnderecoEstruturado: &models.Address{
+			Logradouro: "Av. Paulista", Numero: "1000", Municipio: "São Paulo", UF: "SP", CEP: "01310100",
+		},
+	}
+}
+
+func TestVersioning_DeprecationHeaders(t *testing.T) {
+	h := &CompanyHandler{Repo: &repoMock{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return storedCompany(), nil },
+	}}
+	mux := versionedMux(h)
+
+	cases := []struct {
+		path       string
+		deprecated bool
+	}{
+		{"/api/companies/" + companyID, true},
+		{"/api/v1/companies/" + companyID, true},
+		{"/api/v2/companies/" + companyID, false},
+	}
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: status=%d body=%s", tc.path, rr.Code, rr.Body.String())
+		}
+
+		dep := rr.Header().Get("Deprecation")
+		if !tc.deprecated {
+			if dep != "" || rr.Header().Get("Sunset") != "" {
+				t.Fatalf("%s: v2 não deveria ter Deprecation/Sunset", tc.path)
+			}
+			continue
+		}
+		if dep != "@1790812800" {
+			t.Fatalf("%s: Deprecation=%q", tc.path, dep)
+		}
+		if got := rr.Header().Get("Sunset"); got != "Thu, 01 Apr 2027 00:00:00 GMT" {
+			t.Fatalf("%s: Sunset=%q", tc.path, got)
+		}
+		links := strings.Join(rr.Header().Values("Link"), ", ")
+		if !strings.Contains(links, "</api/v2/companies/"+companyID+`>; rel="successor-version"`) {
+			t.Fatalf("%s: Link=%q", tc.path, links)
+		}
+	}
+}
+
+func TestVersioning_V1KeepsShape(t *testing.T) {
+	h := &CompanyHandler{Repo: &repoMock{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return storedCompany(), nil },
+	}}
+	rr := httptest.NewRecorder()
+	versionedMux(h).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/companies/"+companyID, nil))
+
+	var body map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if body["cnpj"] != companyID || body["endereco"] != storedCompany().Endereco {
+		t.Fatalf("v1 mudou de formato: %v", body)
+	}
+	if _, ok := body["data"]; ok {
+		t.Fatal("v1 não usa envelope")
+	}
+}
+
+func TestVersioning_V2Get_Envelope(t *testing.T) {
+	h := &CompanyHandler{Repo: &repoMock{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return storedCompany(), nil },
+	}}
+	rr := httptest.NewRecorder()
+	versionedMux(h).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v2/companies/"+companyID, nil))
+
+	var env struct {
+		Data CompanyV2 `json:"data"`
+		Meta Meta      `json:"meta"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if env.Meta.APIVersion != V2 {
+		t.Fatalf("meta=%+v", env.Meta)
+	}
+	if env.Data.CNPJ != validCNPJ {
+		t.Fatalf("cnpj formatado: got %q want %q", env.Data.CNPJ, validCNPJ)
+	}
+	if env.Data.Endereco == nil || env.Data.Endereco.UF != "SP" || env.Data.Endereco.CEP != "01310100" {
+		t.Fatalf("endereco=%+v", env.Data.Endereco)
+	}
+}
+
+func TestVersioning_V2Get_LegacyAddress(t *testing.T) {
+	legacy := storedCompany()
+	legacy.EnderecoEstruturado = nil
+	legacy.Endereco = "Rua X, 10"
+
+	h := &CompanyHandler{Repo: &repoMock{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Company, error) { return legacy, nil },
+	}}
+	rr := httptest.NewRecorder()
+	versionedMux(h).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v2/companies/"+companyID, nil))
+
+	var env Envelope
+	env.Data = &CompanyV2{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if got := env.Data.(*CompanyV2).Endereco; got == nil || got.Logradouro != "Rua X, 10" {
+		t.Fatalf("endereço da v1 deveria vir em logradouro: %+v", got)
+	}
+}
+
+func TestVersioning_V2List_Meta(t *testing.T) {
+	h := &CompanyHandler{Repo: &repoMock{
+		GetAllFn: func(_ context.Context, limit, skip int64) ([]models.Company, error) {
+			return []models.Company{*storedCompany(), *storedCompany()}, nil
+		},
+	}}
+	rr := httptest.NewRecorder()
+	versionedMux(h).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v2/companies?limit=10&skip=5", nil))
+
+	var env struct {
+		Data []CompanyV2 `json:"data"`
+		Meta Meta        `json:"meta"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if len(env.Data) != 2 || *env.Meta.Limit != 10 || *env.Meta.Skip != 5 || *env.Meta.Count != 2 {
+		t.Fatalf("envelope inesperado: %s", rr.Body.String())
+	}
+}
+
+func TestVersioning_V2Create_StructuredAddress(t *testing.T) {
+	var saved *models.Company
+	h := &CompanyHandler{
+		Repo: &repoMock{CreateFn: func(_ context.Context, c *models.Company) (string, error) {
+			saved = c
+			return c.ID, nil
+		}},
+		Pub: &pubMock{},
+	}
+
+	body := `{"cnpj":"` + validCNPJ + `","nome_fantasia":"ACME","numero_funcionarios":150,
+		"endereco":{"logradouro":"Av. Paulista","numero":"1000","municipio":"São Paulo","uf":"SP","cep":"01310-100"}}`
+	rr := httptest.NewRecorder()
+	versionedMux(h).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v2/companies", bytes.NewBufferString(body)))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if saved == nil || saved.EnderecoEstruturado == nil || saved.EnderecoEstruturado.CEP != "01310100" {
+		t.Fatalf("endereço estruturado não gravado: %+v", saved)
+	}
+	// a v1 continua enxergando o endereço como texto
+	if want := "Av. Paulista, 1000, São Paulo/SP, CEP 01310-100"; saved.Endereco != want {
+		t.Fatalf("endereco texto: got %q want %q", saved.Endereco, want)
+	}
+	if !strings.Contains(rr.Body.String(), `"data"`) {
+		t.Fatalf("resposta sem envelope: %s", rr.Body.String())
+	}
+}
+
+func TestVersioning_V2Create_RejectsTextAddress(t *testing.T) {
+	h := &CompanyHandler{Repo: &repoMock{}, Pub: &pubMock{}}
+
+	body := `{"cnpj":"` + validCNPJ + `","nome_fantasia":"ACME","endereco":"Rua X, 10"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/companies", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	versionedMux(h).ServeHTTP(rr, req)
+
+	var p utils.Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if rr.Code != http.StatusBadRequest || len(p.Errors) != 1 || p.Errors[0].Field != "endereco" || p.Errors[0].Code != utils.FieldInvalidType {
+		t.Fatalf("problem inesperado: %d %#v", rr.Code, p)
+	}
+	// instance mostra o path pedido, não o reescrito
+	if p.Instance != "/api/v2/companies" {
+		t.Fatalf("instance=%q", p.Instance)
+	}
+}
+
+func TestVersioning_IdempotencyKeyIsPerVersion(t *testing.T) {
+	v1 := httptest.NewRequest(http.MethodPost, "/api/companies", nil)
+	v2 := v1.WithContext(WithAPIVersion(v1.Context(), V2))
+	if versionedPath(v1) == versionedPath(v2) {
+		t.Fatal("v1 e v2 com a mesma Idempotency-Key não podem compartilhar a resposta guardada")
+	}
+}
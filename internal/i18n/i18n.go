@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Catálogo de mensagens (pt-BR / en).
+// Os textos ficam em locales/<lang>.json, embutidos no binário como as seeds.
+
+type Lang string
+
+const (
+	PtBR Lang = "pt-BR"
+	En   Lang = "en"
+)
+
+// Idioma das respostas HTTP quando o cliente não manda Accept-Language
+// (a API sempre respondeu em inglês).
+const DefaultHTTPLang = En
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+var catalog = map[Lang]map[string]string{}
+
+func init() {
+	for _, lang := range []Lang{PtBR, En} {
+		b, err := localesFS.ReadFile("locales/" + string(lang) + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog %s: %v", lang, err))
+		}
+		msgs := map[string]string{}
+		if err := json.Unmarshal(b, &msgs); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", lang, err))
+		}
+		catalog[lang] = msgs
+	}
+}
+
+// T devolve a mensagem da chave no idioma pedido.
+// Fallback: inglês e, por último, a própria chave (nunca devolve vazio).
+func T(lang Lang, key string, args ...any) string {
+	msg, ok := catalog[lang][key]
+	if !ok {
+		if msg, ok = catalog[En][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Parse converte um código de idioma (config, ex.: "pt-BR", "pt", "en-US").
+// Retorna ok=false se não for suportado.
+func Parse(s string) (Lang, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case s == "pt" || strings.HasPrefix(s, "pt-") || strings.HasPrefix(s, "pt_"):
+		return PtBR, true
+	case s == "en" || strings.HasPrefix(s, "en-") || strings.HasPrefix(s, "en_"):
+		return En, true
+	}
+	return "", false
+}
+
+// FromAcceptLanguage escolhe o idioma suportado de maior "q" no header Accept-Language.
+// Ex.: "en-US;q=0.5, pt-BR" -> pt-BR
+func FromAcceptLanguage(header string, def Lang) Lang {
+	type cand struct {
+		lang Lang
+		q    float64
+	}
+	var cands []cand
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if lang, ok := Parse(tag); ok && q > 0 {
+			cands = append(cands, cand{lang: lang, q: q})
+		}
+	}
+	if len(cands) == 0 {
+		return def
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].q > cands[j].q })
+	return cands[0].lang
+}
+
+// FromRequest: idioma das respostas HTTP (Accept-Language, padrão DefaultHTTPLang).
+func FromRequest(r *http.Request) Lang {
+	if r == nil {
+		return DefaultHTTPLang
+	}
+	return FromAcceptLanguage(r.Header.Get("Accept-Language"), DefaultHTTPLang)
+}
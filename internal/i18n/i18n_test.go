@@ -0,0 +1,59 @@
+package i18n
+
+/*
+
+go test -run 'TestI18n_' -v ./internal/i18n -count=1
+
+*/
+
+import "testing"
+
+func TestI18n_FromAcceptLanguage(t *testing.T) {
+	cases := []struct {
+		header string
+		want   Lang
+	}{
+		{"", En},
+		{"pt-BR", PtBR},
+		{"pt", PtBR},
+		{"pt-PT,pt;q=0.9", PtBR},
+		{"en-US,en;q=0.9", En},
+		{"en-US;q=0.5, pt-BR", PtBR},
+		{"pt-BR;q=0.4, en;q=0.8", En},
+		{"fr-FR, de;q=0.9", En},
+		{"fr-FR, pt;q=0.1", PtBR},
+		{"pt-BR;q=0", En},
+		{"pt-BR;q=abc, en", En},
+	}
+	for _, tc := range cases {
+		if got := FromAcceptLanguage(tc.header, En); got != tc.want {
+			t.Fatalf("header=%q want=%s got=%s", tc.header, tc.want, got)
+		}
+	}
+}
+
+func TestI18n_T(t *testing.T) {
+	if got := T(PtBR, "event.created", "ACME"); got != "Cadastro de EMPRESA ACME" {
+		t.Fatalf("pt-BR: %q", got)
+	}
+	if got := T(En, "event.created", "ACME"); got != "Company ACME created" {
+		t.Fatalf("en: %q", got)
+	}
+	if got := T(PtBR, "no.such.key"); got != "no.such.key" {
+		t.Fatalf("fallback: %q", got)
+	}
+}
+
+// Todo texto precisa existir nos dois idiomas
+func TestI18n_CatalogsHaveSameKeys(t *testing.T) {
+	for k := range catalog[En] {
+		if _, ok := catalog[PtBR][k]; !ok {
+			t.Errorf("chave %q sem tradução pt-BR", k)
+		}
+	}
+	for k := range catalog[PtBR] {
+		if _, ok := catalog[En][k]; !ok {
+			t.Errorf("chave %q sem tradução en", k)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import "strings"
+
+// Endereço estruturado (contrato da /api/v2).
+// Na v1 o endereço continua um texto livre: Line() gera esse texto.
+type Address struct {
+	Logradouro  string `bson:"logradouro" json:"logradouro"`
+	Numero      string `bson:"numero,omitempty" json:"numero,omitempty"`
+	Complemento string `bson:"complemento,omitempty" json:"complemento,omitempty"`
+	Bairro      string `bson:"bairro,omitempty" json:"bairro,omitempty"`
+	Municipio   string `bson:"municipio,omitempty" json:"municipio,omitempty"`
+	UF          string `bson:"uf,omitempty" json:"uf,omitempty"`
+	CEP         string `bson:"cep,omitempty" json:"cep,omitempty"` // apenas dígitos
+}
+
+// Line: "Logradouro, Numero - Complemento - Bairro, Municipio/UF, CEP 00000-000"
+func (a *Address) Line() string {
+	if a == nil {
+		return ""
+	}
+	street := a.Logradouro
+	if a.Numero != "" {
+		street += ", " + a.Numero
+	}
+	parts := []string{street}
+	if a.Complemento != "" {
+		parts = append(parts, a.Complemento)
+	}
+	if a.Bairro != "" {
+		parts = append(parts, a.Bairro)
+	}
+	line := strings.Join(parts, " - ")
+
+	city := a.Municipio
+	if a.UF != "" {
+		if city != "" {
+			city += "/"
+		}
+		city += a.UF
+	}
+	if city != "" {
+		line += ", " + city
+	}
+	if len(a.CEP) == 8 {
+		line += ", CEP " + a.CEP[:5] + "-" + a.CEP[5:]
+	}
+	return line
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Pedido de mudança sensível (coleção change_requests): a operação não é aplicada
+// na hora e fica aguardando a aprovação de outro usuário (maker-checker).
+type ChangeRequest struct {
+	ID          string     `bson:"_id" json:"id"`
+	CompanyID   string     `bson:"company_id" json:"company_id"`
+	Action      string     `bson:"action" json:"action"`                             // ChangeAction*
+	Reasons     []string   `bson:"reasons" json:"reasons"`                           // Approval*: regras que exigiram a aprovação
+	Before      Company    `bson:"before" json:"before"`                             // empresa quando o pedido foi feito
+	After       *Company   `bson:"after,omitempty" json:"after,omitempty"`           // como a empresa fica (nil na exclusão e na fusão)
+	MergeInto   string     `bson:"merge_into,omitempty" json:"merge_into,omitempty"` // fusão: empresa que absorve a do pedido
+	Status      string     `bson:"status" json:"status"`                             // ChangeStatus*
+	RequestedBy string     `bson:"requested_by" json:"requested_by"`
+	RequestedAt time.Time  `bson:"requested_at" json:"requested_at"`
+	DecidedBy   string     `bson:"decided_by,omitempty" json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `bson:"decided_at,omitempty" json:"decided_at,omitempty"`
+	Comment     string     `bson:"comment,omitempty" json:"comment,omitempty"`
+	Error       string     `bson:"error,omitempty" json:"error,omitempty"` // aprovado, mas a gravação falhou
+}
+
+// Como o pedido é aplicado
+const (
+	ChangeActionDelete = "delete" // exclusão da empresa
+	ChangeActionUpdate = "update" // PATCH/PUT: a empresa passa a ser After
+	ChangeActionMerge  = "merge"  // fusão: a empresa é absorvida por MergeInto (e removida)
+)
+
+// Situação do pedido
+const (
+	ChangeStatusPending  = "pending"
+	ChangeStatusApproved = "approved"
+	ChangeStatusRejected = "rejected"
+	ChangeStatusFailed   = "failed" // aprovado, mas a mudança não pôde ser gravada (Error)
+)
+
+var ChangeStatuses = []string{ChangeStatusPending, ChangeStatusApproved, ChangeStatusRejected, ChangeStatusFailed}
+
+// Operações que podem exigir aprovação (APPROVAL_REQUIRED)
+const (
+	ApprovalDelete        = "delete"
+	ApprovalCNPJChange    = "cnpj_change"
+	ApprovalHeadcountDrop = "headcount_drop"
+)
+
+// ApprovalPolicy: quais operações viram pedido de mudança
+type ApprovalPolicy struct {
+	Delete     bool
+	CNPJChange bool
+	// queda de funcionários (em % do quadro atual) a partir da qual exige aprovação; 0 = nunca
+	HeadcountDropPercent float64
+}
+
+// Filtros de GET /api/change-requests (vazio = todos)
+type ChangeRequestFilter struct {
+	Status    string
+	CompanyID string
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Filtros da listagem de empresas. Campos vazios/nil não filtram.
+type CompanyFilter struct {
+	Nome            string // trecho de nome_fantasia ou razao_social (sem diferenciar maiúsculas)
+	CNPJPrefix      string // início do CNPJ (apenas dígitos)
+	UF              string // endereco_estruturado.uf (sem ele, a UF do fim do endereço em texto)
+	MinFuncionarios *int
+	MaxFuncionarios *int
+	CreatedFrom     *time.Time // created_at >= CreatedFrom
+	CreatedTo       *time.Time // created_at < CreatedTo
+
+	// CNAE 2.3 da atividade principal (com CNAESecundarios, também das secundárias)
+	CNAESecao       string // letra (A..U)
+	CNAEDivisao     string // 2 dígitos
+	CNAEClasse      string // 5 dígitos
+	CNAESecundarios bool
+
+	RegimeTributario string // models.Regime*
+	Porte            string // utils.Porte*
+
+	Tags []string // a empresa tem de ter todas
+}
+
+func (f CompanyFilter) IsZero() bool {
+	return f.Nome == "" && f.CNPJPrefix == "" && f.UF == "" && f.MinFuncionarios == nil && f.MaxFuncionarios == nil &&
+		f.CreatedFrom == nil && f.CreatedTo == nil && f.CNAESecao == "" && f.CNAEDivisao == "" && f.CNAEClasse == "" &&
+		f.RegimeTributario == "" && f.Porte == "" && len(f.Tags) == 0
+}
@@ -8,8 +8,26 @@ type Company struct {
 	NomeFantasia                string    `bson:"nome_fantasia" json:"nome_fantasia"`
 	RazaoSocial                 string    `bson:"razao_social" json:"razao_social"`
 	Endereco                    string    `bson:"endereco" json:"endereco"`
+	EnderecoEstruturado         *Address  `bson:"endereco_estruturado,omitempty" json:"-"` // só exposto na v2
 	NumeroFuncionarios      	int       `json:"numero_funcionarios" bson:"numero_funcionarios"`
 	NumeroMinimoPCDExigidos 	int       `json:"numero_minimo_pcd_exigidos" bson:"numero_minimo_pcd_exigidos"`
+	NumeroPCDContratados        *int      `json:"numero_pcd_contratados,omitempty" bson:"numero_pcd_contratados,omitempty"` // nil = não informado
+	CNAEPrincipal               string    `bson:"cnae_principal,omitempty" json:"cnae_principal,omitempty"` // subclasse CNAE 2.3, só dígitos
+	CNAESecundarios             []string  `bson:"cnaes_secundarios,omitempty" json:"cnaes_secundarios,omitempty"`
+	InscricaoEstadual           string    `bson:"inscricao_estadual,omitempty" json:"inscricao_estadual,omitempty"` // só dígitos ("P..." no produtor rural de SP) ou ISENTO
+	InscricaoEstadualUF         string    `bson:"inscricao_estadual_uf,omitempty" json:"inscricao_estadual_uf,omitempty"`
+	InscricaoMunicipal          string    `bson:"inscricao_municipal,omitempty" json:"inscricao_municipal,omitempty"`
+	RegimeTributario            string    `bson:"regime_tributario,omitempty" json:"regime_tributario,omitempty"` // models.Regime*
+	FaturamentoAnual            *float64  `bson:"faturamento_anual,omitempty" json:"faturamento_anual,omitempty"` // R$; nil = não informado
+	Porte                       string    `bson:"porte,omitempty" json:"porte,omitempty"` // utils.Porte*, calculado a cada gravação
+	Tags                        []string  `bson:"tags,omitempty" json:"tags,omitempty"` // minúsculas, sem repetidas
+	CustomFields                map[string]any `bson:"custom_fields,omitempty" json:"custom_fields,omitempty"` // chaves de CustomFieldDefinition
 	CreatedAt                   time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt                   time.Time `bson:"updated_at" json:"updated_at"`
 }
+
+// Campos numéricos que o Update parcial grava mesmo com 0 quando o PATCH os informa
+const (
+	FieldNumeroFuncionarios = "numero_funcionarios"
+	FieldNumeroMinimoPCD    = "numero_minimo_pcd_exigidos"
+)
@@ -0,0 +1,65 @@
+package models
+
+// Agrupamentos aceitos pelas estatísticas (parâmetro group_by)
+const (
+	StatsByUF            = "uf"
+	StatsByHeadcountBand = "headcount_band"
+	StatsByPCDBand       = "pcd_band"
+	StatsByMonth         = "month" // crescimento por mês de cadastro
+	StatsByYear          = "year"  // crescimento por ano de cadastro
+	StatsByRegime        = "regime_tributario"
+	StatsByPorte         = "porte"
+)
+
+var StatsGroupings = []string{StatsByUF, StatsByHeadcountBand, StatsByPCDBand, StatsByMonth, StatsByYear, StatsByRegime, StatsByPorte}
+
+// Faixas de porte por número de funcionários usadas nas estatísticas.
+// Max == 0: sem limite superior.
+type HeadcountBand struct {
+	Code string
+	Min  int
+	Max  int
+}
+
+var HeadcountBands = []HeadcountBand{
+	{Code: "0-9", Min: 0, Max: 9},
+	{Code: "10-49", Min: 10, Max: 49},
+	{Code: "50-99", Min: 50, Max: 99},
+	{Code: "100-499", Min: 100, Max: 499},
+	{Code: "500-999", Min: 500, Max: 999},
+	{Code: "1000+", Min: 1000},
+}
+
+// Estatísticas da base (GET /api/companies/stats). Só os grupos pedidos vêm preenchidos.
+// pcd_required é calculado pela regra atual da lei a partir de numero_funcionarios.
+type CompanyStats struct {
+	Totals          StatsTotals   `json:"totals"`
+	ByUF            []StatsBucket `json:"by_uf,omitempty"`
+	ByHeadcountBand []StatsBucket `json:"by_headcount_band,omitempty"`
+	ByPCDBand       []StatsBucket `json:"by_pcd_band,omitempty"`
+	ByMonth         []GrowthPoint `json:"by_month,omitempty"`
+	ByYear          []GrowthPoint `json:"by_year,omitempty"`
+	ByRegime        []StatsBucket `json:"by_regime_tributario,omitempty"`
+	ByPorte         []StatsBucket `json:"by_porte,omitempty"`
+}
+
+type StatsTotals struct {
+	Companies    int64 `json:"companies" bson:"companies"`
+	Funcionarios int64 `json:"funcionarios" bson:"funcionarios"`
+	PCDRequired  int64 `json:"pcd_required" bson:"pcd_required"`
+}
+
+// Key: UF ("" = sem endereço estruturado), código da faixa, regime ou porte ("" = não informado)
+type StatsBucket struct {
+	Key          string `json:"key" bson:"_id"`
+	Companies    int64  `json:"companies" bson:"companies"`
+	Funcionarios int64  `json:"funcionarios" bson:"funcionarios"`
+	PCDRequired  int64  `json:"pcd_required" bson:"pcd_required"`
+}
+
+// Crescimento: empresas cadastradas no período (YYYY-MM ou YYYY) e o acumulado dentro do filtro
+type GrowthPoint struct {
+	Period string `json:"period" bson:"_id"`
+	New    int64  `json:"new" bson:"new"`
+	Total  int64  `json:"total" bson:"-"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Contato de uma empresa (coleção contacts): com quem falar sobre a cota PCD,
+// cadastro etc. No máximo um contato principal por empresa.
+type Contact struct {
+	ID                 string    `bson:"_id" json:"id"`
+	CompanyID          string    `bson:"company_id" json:"company_id"`
+	Nome               string    `bson:"nome" json:"nome"`
+	Cargo              string    `bson:"cargo,omitempty" json:"cargo,omitempty"`
+	Email              string    `bson:"email,omitempty" json:"email,omitempty"`       // minúsculas
+	Telefone           string    `bson:"telefone,omitempty" json:"telefone,omitempty"` // DDD + número, só dígitos
+	Principal          bool      `bson:"principal" json:"principal"`
+	RepresentanteLegal bool      `bson:"representante_legal" json:"representante_legal"`
+	CreatedAt          time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt          time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Definição de um campo personalizado das empresas (coleção custom_field_definitions).
+// Os valores ficam em Company.CustomFields[Key] e são conferidos com o Type da definição.
+type CustomFieldDefinition struct {
+	Key       string    `bson:"_id" json:"key"` // ^[a-z][a-z0-9_]*$, imutável
+	Label     string    `bson:"label" json:"label"`
+	Type      string    `bson:"type" json:"type"`                           // CustomField*
+	Options   []string  `bson:"options,omitempty" json:"options,omitempty"` // só no tipo enum
+	Required  bool      `bson:"required" json:"required"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Tipos aceitos em CustomFieldDefinition.Type
+const (
+	CustomFieldString  = "string"
+	CustomFieldNumber  = "number"
+	CustomFieldInteger = "integer"
+	CustomFieldBoolean = "boolean"
+	CustomFieldDate    = "date" // YYYY-MM-DD
+	CustomFieldEnum    = "enum" // um dos Options
+)
+
+var CustomFieldTypes = []string{CustomFieldString, CustomFieldNumber, CustomFieldInteger, CustomFieldBoolean, CustomFieldDate, CustomFieldEnum}
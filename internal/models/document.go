@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Documento anexado a uma empresa (contrato, cartão CNPJ, relatório PCD...).
+// O conteúdo e estes dados ficam no GridFS (bucket documents); ver repository.DocumentRepository.
+type Document struct {
+	ID          string    `json:"id"`
+	CompanyID   string    `json:"company_id"`
+	NomeArquivo string    `json:"nome_arquivo"`
+	ContentType string    `json:"content_type"`
+	Tamanho     int64     `json:"tamanho"` // bytes
+	SHA256      string    `json:"sha256"`  // hex do conteúdo
+	Categoria   string    `json:"categoria,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Categorias aceitas em categoria (opcional)
+const (
+	DocumentContrato     = "contrato"
+	DocumentCartaoCNPJ   = "cartao_cnpj"
+	DocumentRelatorioPCD = "relatorio_pcd"
+	DocumentOutro        = "outro"
+)
+
+var DocumentCategorias = []string{DocumentContrato, DocumentCartaoCNPJ, DocumentRelatorioPCD, DocumentOutro}
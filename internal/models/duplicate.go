@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Possível duplicata de uma empresa (GET /api/companies/{id}/duplicates)
+type DuplicateCandidate struct {
+	Company Company         `json:"company"`
+	Score   float64         `json:"score"` // 0 a 1: média ponderada das notas abaixo
+	Scores  DuplicateScores `json:"scores"`
+}
+
+// Pré-filtro das candidatas a duplicata, feito no banco: basta um dos critérios.
+// A nota é calculada depois, só sobre as empresas que passarem.
+type DuplicateQuery struct {
+	ExcludeID string   // a própria empresa
+	CNPJ      string   // só dígitos: CNPJs com algum trecho igual, na mesma posição
+	NameWords []string // sem acento, minúsculas: contidas no nome fantasia ou na razão social
+}
+
+// Notas de 0 a 1 por critério
+type DuplicateScores struct {
+	CNPJ     float64 `json:"cnpj"`     // pela distância de edição entre os CNPJs
+	Nome     float64 `json:"nome"`     // nome fantasia ou razão social, sem acentos nem sufixos (LTDA, ME...)
+	Endereco float64 `json:"endereco"` // 0 se uma das duas não tem endereço
+}
+
+// Fusão de duas empresas (coleção company_merges): a absorvida é removida e os
+// dados das duas, como estavam antes da fusão, ficam registrados aqui.
+type CompanyMerge struct {
+	ID       string       `bson:"_id" json:"id"`
+	TargetID string       `bson:"target_id" json:"target_id"` // empresa que ficou
+	SourceID string       `bson:"source_id" json:"source_id"` // empresa absorvida
+	Target   Company      `bson:"target" json:"target"`
+	Source   Company      `bson:"source" json:"source"`
+	Moved    MergeMoved   `bson:"moved" json:"moved"`
+	Dropped  MergeDropped `bson:"dropped" json:"dropped"`
+	MergedAt time.Time    `bson:"merged_at" json:"merged_at"`
+}
+
+// Registros dos sub-recursos passados da absorvida para a que ficou
+// (repetidos, como o mesmo CPF nas duas, não contam: ficam os da que ficou)
+type MergeMoved struct {
+	Employees  int `bson:"employees" json:"employees"`
+	Contacts   int `bson:"contacts" json:"contacts"`
+	Partners   int `bson:"partners" json:"partners"`
+	Documents  int `bson:"documents" json:"documents"`
+	Ownerships int `bson:"ownerships" json:"ownerships"` // participações do grupo econômico (como controladora ou controlada)
+	Notes      int `bson:"notes" json:"notes"`
+	Merges     int `bson:"merges" json:"merges"` // fusões anteriores da absorvida
+}
+
+// Registros da absorvida descartados na fusão por já existirem na que ficou
+// (mesmo CPF, mesmo sócio, mesma participação), guardados como estavam
+type MergeDropped struct {
+	Employees  []Employee  `bson:"employees,omitempty" json:"employees,omitempty"`
+	Partners   []Partner   `bson:"partners,omitempty" json:"partners,omitempty"`
+	Ownerships []Ownership `bson:"ownerships,omitempty" json:"ownerships,omitempty"` // inclui a participação entre as duas, que viraria da empresa nela mesma
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Funcionário de uma empresa (coleção employees). A partir deles são derivados
+// numero_funcionarios, numero_pcd_contratados e numero_minimo_pcd_exigidos da empresa.
+// Datas em "YYYY-MM-DD" (comparáveis como texto).
+type Employee struct {
+	ID           string    `bson:"_id" json:"id"`
+	CompanyID    string    `bson:"company_id" json:"company_id"`
+	Nome         string    `bson:"nome" json:"nome"`
+	CPF          string    `bson:"cpf" json:"cpf"` // apenas dígitos; único por empresa
+	Admissao     string    `bson:"admissao" json:"admissao"`
+	Desligamento *string   `bson:"desligamento,omitempty" json:"desligamento,omitempty"` // nil = ativo
+	PCD          bool      `bson:"pcd" json:"pcd"`                                       // PCD ou beneficiário reabilitado (conta para a cota)
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Ativo na data (YYYY-MM-DD): admitido até ela e não desligado até ela
+func (e Employee) ActiveOn(date string) bool {
+	return e.Admissao <= date && (e.Desligamento == nil || *e.Desligamento > date)
+}
+
+type EmployeeFilter struct {
+	ActiveOn string // "" = todos
+	PCD      *bool
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Registro de uma requisição com Idempotency-Key.
+// Enquanto Completed == false a requisição original ainda está em processamento.
+type IdempotencyRecord struct {
+	Key         string    `bson:"_id" json:"key"`
+	Fingerprint string    `bson:"fingerprint" json:"fingerprint"` // sha256 de método + path + body
+	Method      string    `bson:"method" json:"method"`
+	Path        string    `bson:"path" json:"path"`
+	Completed   bool      `bson:"completed" json:"completed"`
+	Status      int       `bson:"status,omitempty" json:"status,omitempty"`
+	ContentType string    `bson:"content_type,omitempty" json:"content_type,omitempty"`
+	Body        []byte    `bson:"body,omitempty" json:"-"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"` // base do índice TTL
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Anotação do atendimento sobre uma empresa (coleção notes): ligação, visita,
+// constatação etc., com quem registrou.
+type Note struct {
+	ID        string    `bson:"_id" json:"id"`
+	CompanyID string    `bson:"company_id" json:"company_id"`
+	Autor     string    `bson:"autor" json:"autor"`
+	Texto     string    `bson:"texto" json:"texto"`
+	Tipo      string    `bson:"tipo" json:"tipo"` // Note*
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Tipos aceitos em Note.Tipo
+const (
+	NoteLigacao     = "ligacao"
+	NoteVisita      = "visita"
+	NoteConstatacao = "constatacao"
+	NoteOutro       = "outro"
+)
+
+var NoteTypes = []string{NoteLigacao, NoteVisita, NoteConstatacao, NoteOutro}
+
+// Evento publicado sobre uma empresa, gravado para a linha do tempo (coleção company_events)
+type CompanyEvent struct {
+	ID        string            `bson:"_id" json:"id"`
+	CompanyID string            `bson:"company_id" json:"company_id"`
+	Action    string            `bson:"action" json:"action"`
+	Message   string            `bson:"message" json:"message"`                     // corpo publicado (JSON no cota_pcd)
+	Headers   map[string]string `bson:"headers,omitempty" json:"headers,omitempty"` // demais headers (cnpj, nome, contact_id...)
+	Timestamp time.Time         `bson:"timestamp" json:"timestamp"`
+}
+
+// Item da linha do tempo: uma nota ou um evento
+type TimelineItem struct {
+	Kind  string        `json:"kind"` // Timeline*
+	ID    string        `json:"id"`
+	At    time.Time     `json:"at"` // created_at da nota ou timestamp do evento
+	Note  *Note         `json:"note,omitempty"`
+	Event *CompanyEvent `json:"event,omitempty"`
+}
+
+const (
+	TimelineNote  = "note"
+	TimelineEvent = "event"
+)
+
+// Página da linha do tempo (mais recentes primeiro)
+type TimelinePage struct {
+	Items      []TimelineItem `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"` // vazio = não há mais itens
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Participação de uma empresa no capital de outra (grupo econômico; coleção
+// company_ownerships, uma aresta controladora -> controlada).
+type Ownership struct {
+	ID         string    `bson:"_id" json:"id"`
+	ParentID   string    `bson:"parent_id" json:"parent_id"`   // controladora (holding)
+	ChildID    string    `bson:"child_id" json:"child_id"`     // controlada
+	Percentual float64   `bson:"percentual" json:"percentual"` // 0 < p <= 100
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Empresa do grupo vista a partir de outra (ancestrais ou descendentes)
+type GroupMember struct {
+	Company    Company `json:"company"`
+	Depth      int     `json:"depth"`      // 1 = participação direta
+	Percentual float64 `json:"percentual"` // participação efetiva: produto dos percentuais, somado pelos caminhos
+}
+
+// Ciclo de participações: path começa e termina na mesma empresa (A -> B -> A)
+type OwnershipCycle struct {
+	Path []string `json:"path"`
+}
+
+// Visão consolidada: a empresa e as controladas (diretas e indiretas)
+type GroupConsolidated struct {
+	CompanyID               string        `json:"company_id"`
+	Companies               int           `json:"companies"`
+	NumeroFuncionarios      int           `json:"numero_funcionarios"`
+	NumeroMinimoPCDExigidos int           `json:"numero_minimo_pcd_exigidos"` // soma das cotas: a cota é de cada empresa
+	NumeroPCDContratados    int           `json:"numero_pcd_contratados"`     // soma das que informaram
+	DeficitPCD              int           `json:"deficit_pcd"`                // soma do que falta em cada empresa (não informado = 0 contratados)
+	MinimoPCDConsolidado    int           `json:"minimo_pcd_consolidado"`     // a cota se o grupo fosse uma empresa só
+	Members                 []GroupMember `json:"members"`                    // a da rota primeiro (depth 0, 100%)
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Sócio ou administrador de uma empresa (QSA da Receita Federal; coleção partners).
+// Documento é o CPF (pessoa física) ou o CNPJ (pessoa jurídica), só dígitos.
+type Partner struct {
+	ID                    string    `bson:"_id" json:"id"`
+	CompanyID             string    `bson:"company_id" json:"company_id"`
+	Nome                  string    `bson:"nome" json:"nome"`
+	Documento             string    `bson:"documento" json:"documento"`
+	TipoDocumento         string    `bson:"tipo_documento" json:"tipo_documento"` // cpf|cnpj
+	Qualificacao          int       `bson:"qualificacao" json:"qualificacao"`     // código da tabela da Receita
+	QualificacaoDescricao string    `bson:"qualificacao_descricao" json:"qualificacao_descricao"`
+	DataEntrada           *string   `bson:"data_entrada,omitempty" json:"data_entrada,omitempty"`             // YYYY-MM-DD
+	PercentualCapital     *float64  `bson:"percentual_capital,omitempty" json:"percentual_capital,omitempty"` // 0-100
+	CreatedAt             time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt             time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Qualificações de sócio/administrador (tabela da Receita Federal usada no QSA)
+var PartnerQualifications = map[int]string{
+	5:  "Administrador",
+	8:  "Conselheiro de Administração",
+	10: "Diretor",
+	16: "Presidente",
+	17: "Procurador",
+	22: "Sócio",
+	23: "Sócio Comanditado",
+	24: "Sócio Comanditário",
+	28: "Sócio-Gerente",
+	29: "Sócio Incapaz ou Relativamente Incapaz (exceto menor)",
+	30: "Sócio Menor (Assistido/Representado)",
+	31: "Sócio Ostensivo",
+	37: "Sócio Pessoa Jurídica Domiciliado no Exterior",
+	38: "Sócio Pessoa Física Residente no Exterior",
+	47: "Sócio Pessoa Física Residente no Brasil",
+	48: "Sócio Pessoa Jurídica Domiciliado no Brasil",
+	49: "Sócio-Administrador",
+	54: "Fundador",
+	63: "Cotas em Tesouraria",
+	65: "Titular Pessoa Física Residente ou Domiciliado no Brasil",
+	66: "Titular Pessoa Física Residente ou Domiciliado no Exterior",
+	78: "Titular Pessoa Jurídica Domiciliada no Brasil",
+	79: "Titular Pessoa Jurídica Domiciliada no Exterior",
+}
+
+// Empresa em que um CPF/CNPJ é sócio (consulta reversa do QSA)
+type PartnerCompany struct {
+	Partner Partner `json:"partner"`
+	Company Company `json:"company"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// Regimes tributários aceitos em regime_tributario
+const (
+	RegimeSimplesNacional = "simples_nacional"
+	RegimeMEI             = "mei"
+	RegimeLucroPresumido  = "lucro_presumido"
+	RegimeLucroReal       = "lucro_real"
+)
+
+var RegimesTributarios = []string{RegimeSimplesNacional, RegimeMEI, RegimeLucroPresumido, RegimeLucroReal}
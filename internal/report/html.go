@@ -0,0 +1,46 @@
+package report
+
+import (
+	"embed"
+	"html/template"
+	"io"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+var (
+	companyTmpl   = template.Must(template.ParseFS(templatesFS, "templates/layout.html", "templates/company.html"))
+	portfolioTmpl = template.Must(template.ParseFS(templatesFS, "templates/layout.html", "templates/portfolio.html"))
+)
+
+// dados dos templates: o relatório + os formatadores do idioma (.T, .Int, .Date...)
+type companyView struct {
+	text
+	CompanyReport
+	Lang  i18n.Lang
+	Title string
+}
+
+type portfolioView struct {
+	text
+	PortfolioReport
+	Lang  i18n.Lang
+	Title string
+}
+
+func (r CompanyReport) HTML(w io.Writer, lang i18n.Lang) error {
+	t := text{lang: lang}
+	return companyTmpl.ExecuteTemplate(w, "company.html", companyView{
+		text: t, CompanyReport: r, Lang: lang, Title: t.T("report.company.title"),
+	})
+}
+
+func (r PortfolioReport) HTML(w io.Writer, lang i18n.Lang) error {
+	t := text{lang: lang}
+	return portfolioTmpl.ExecuteTemplate(w, "portfolio.html", portfolioView{
+		text: t, PortfolioReport: r, Lang: lang, Title: t.T("report.portfolio.title"),
+	})
+}
@@ -0,0 +1,184 @@
+package report
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+)
+
+// PDF gerado em Go puro (go-pdf/fpdf), sem binários externos.
+// Fonte padrão Helvetica com a codificação cp1252, que cobre a acentuação do português.
+
+const (
+	pdfLine   = 7.0  // altura de linha (mm)
+	pdfLabelW = 70.0 // coluna de rótulos das tabelas chave/valor
+)
+
+type pdfDoc struct {
+	*fpdf.Fpdf
+	t  text
+	tr func(string) string
+}
+
+// newPDF: página com título; footer = texto do rodapé (data de geração)
+func newPDF(lang i18n.Lang, orientation, title, footer string) *pdfDoc {
+	f := fpdf.New(orientation, "mm", "A4", "")
+	d := &pdfDoc{Fpdf: f, t: text{lang: lang}, tr: f.UnicodeTranslatorFromDescriptor("cp1252")}
+	f.SetTitle(title, true)
+	f.SetCreator("cadastro-empresa", true)
+	f.SetAutoPageBreak(true, 15)
+	f.AliasNbPages("")
+	f.SetFooterFunc(func() {
+		f.SetY(-12)
+		f.SetFont("Helvetica", "I", 8)
+		f.SetTextColor(120, 120, 120)
+		f.CellFormat(0, 5, d.tr(footer), "", 0, "L", false, 0, "")
+		f.CellFormat(0, 5, strconv.Itoa(f.PageNo())+"/{nb}", "", 0, "R", false, 0, "")
+	})
+	f.AddPage()
+
+	f.SetFont("Helvetica", "B", 16)
+	f.SetTextColor(43, 87, 151)
+	f.MultiCell(0, 9, d.tr(title), "B", "L", false)
+	f.SetTextColor(34, 34, 34)
+	f.Ln(4)
+	return d
+}
+
+func (d *pdfDoc) heading(s string) {
+	d.Ln(3)
+	d.SetFont("Helvetica", "B", 12)
+	d.SetTextColor(43, 87, 151)
+	d.CellFormat(0, pdfLine+1, d.tr(s), "", 1, "L", false, 0, "")
+	d.SetTextColor(34, 34, 34)
+}
+
+// row: linha "rótulo | valor" das tabelas chave/valor
+func (d *pdfDoc) row(label, value string) {
+	d.SetFont("Helvetica", "B", 10)
+	d.SetFillColor(240, 243, 248)
+	d.CellFormat(pdfLabelW, pdfLine, d.tr(label), "1", 0, "L", true, 0, "")
+	d.SetFont("Helvetica", "", 10)
+	d.CellFormat(0, pdfLine, d.tr(value), "1", 1, "L", false, 0, "")
+}
+
+func (d *pdfDoc) paragraph(s string) {
+	d.SetFont("Helvetica", "", 10)
+	d.MultiCell(0, 5, d.tr(s), "", "L", false)
+	d.Ln(2)
+}
+
+// table: cabeçalho + linhas, com larguras fixas; colunas em right[i] alinhadas à direita
+func (d *pdfDoc) table(widths []float64, right []bool, header []string, rows [][]string) {
+	d.SetFont("Helvetica", "B", 9)
+	d.SetFillColor(240, 243, 248)
+	for i, h := range header {
+		d.CellFormat(widths[i], pdfLine, d.tr(fitText(d.Fpdf, d.tr, h, widths[i]-2)), "1", 0, "L", true, 0, "")
+	}
+	d.Ln(-1)
+	d.SetFont("Helvetica", "", 9)
+	for _, row := range rows {
+		for i, v := range row {
+			align := "L"
+			if right[i] {
+				align = "R"
+			}
+			d.CellFormat(widths[i], pdfLine-1, d.tr(fitText(d.Fpdf, d.tr, v, widths[i]-2)), "1", 0, align, false, 0, "")
+		}
+		d.Ln(-1)
+	}
+}
+
+// fitText corta o texto (com "...") para caber na largura da célula
+func fitText(f *fpdf.Fpdf, tr func(string) string, s string, width float64) string {
+	if f.GetStringWidth(tr(s)) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && f.GetStringWidth(tr(string(r)+"...")) > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "..."
+}
+
+func (d *pdfDoc) method(extra string) {
+	d.heading(d.t.T("report.method.title"))
+	d.paragraph(d.t.T("report.method.law"))
+	d.paragraph(d.t.T("report.method.bands"))
+	if extra != "" {
+		d.paragraph(extra)
+	}
+}
+
+func (r CompanyReport) PDF(w io.Writer, lang i18n.Lang) error {
+	t := text{lang: lang}
+	d := newPDF(lang, "P", t.T("report.company.title"), t.T("report.generated_at", t.Date(r.GeneratedAt)))
+	d.SetCreationDate(r.GeneratedAt)
+	c := r.Company
+
+	d.heading(t.T("report.company"))
+	d.row(t.T("report.cnpj"), c.CNPJ)
+	d.row(t.T("report.razao_social"), c.RazaoSocial)
+	d.row(t.T("report.nome_fantasia"), c.NomeFantasia)
+	d.row(t.T("report.endereco"), c.Endereco)
+
+	d.heading(t.T("report.quota"))
+	d.row(t.T("report.headcount"), t.Int(c.Funcionarios))
+	d.row(t.T("report.band"), t.Band(c.Band))
+	d.row(t.T("report.band_percent"), t.Percent(c.Band.Percent))
+	d.row(t.T("report.min_pcd"), t.Int(c.MinPCD))
+	d.row(t.T("report.actual_pcd"), t.PCDAtual(c))
+	if c.Status == StatusNonCompliant {
+		d.row(t.T("report.gap"), t.Int(c.Gap))
+	}
+	d.row(t.T("report.status"), t.Status(c.Status))
+
+	d.method(t.Method(c))
+	return d.Output(w)
+}
+
+func (r PortfolioReport) PDF(w io.Writer, lang i18n.Lang) error {
+	t := text{lang: lang}
+	// paisagem: a tabela de empresas tem 7 colunas
+	d := newPDF(lang, "L", t.T("report.portfolio.title"), t.T("report.generated_at", t.Date(r.GeneratedAt)))
+	d.SetCreationDate(r.GeneratedAt)
+
+	d.heading(t.T("report.portfolio.summary"))
+	d.row(t.T("report.portfolio.companies"), t.Int(r.Totals.Companies))
+	d.row(t.T("report.headcount"), t.Int(r.Totals.Funcionarios))
+	d.row(t.T("report.min_pcd"), t.Int(r.Totals.MinPCD))
+	d.row(t.T("report.portfolio.known"), t.Int(r.Totals.Known))
+	d.row(t.T("report.portfolio.non_compliant"), t.Int(r.Totals.NonCompliant))
+	d.row(t.T("report.gap"), t.Int(r.Totals.Gap))
+
+	d.heading(t.T("report.portfolio.by_band"))
+	bands := make([][]string, len(r.ByBand))
+	for i, b := range r.ByBand {
+		bands[i] = []string{t.Band(b.Band), t.Percent(b.Band.Percent), t.Int(b.Companies), t.Int(b.Funcionarios), t.Int(b.MinPCD), t.Int(b.Gap)}
+	}
+	d.table([]float64{45, 45, 40, 45, 50, 52}, []bool{false, true, true, true, true, true},
+		[]string{t.T("report.band"), t.T("report.band_percent"), t.T("report.portfolio.companies"), t.T("report.headcount"), t.T("report.min_pcd"), t.T("report.gap")},
+		bands)
+
+	d.heading(t.T("report.portfolio.details"))
+	if r.Truncated > 0 {
+		d.paragraph(t.T("report.portfolio.truncated", t.Int(r.Truncated)))
+	}
+	if len(r.Companies) == 0 {
+		d.paragraph(t.T("report.portfolio.empty"))
+	} else {
+		rows := make([][]string, len(r.Companies))
+		for i, c := range r.Companies {
+			rows[i] = []string{c.Name(), c.CNPJ, t.Int(c.Funcionarios), t.Band(c.Band), t.Int(c.MinPCD), t.PCDAtual(c), t.Status(c.Status)}
+		}
+		d.table([]float64{66, 36, 24, 26, 40, 30, 55}, []bool{false, false, true, false, true, true, false},
+			[]string{t.T("report.company"), t.T("report.cnpj"), t.T("report.headcount"), t.T("report.band"), t.T("report.min_pcd"), t.T("report.actual_pcd"), t.T("report.status")},
+			rows)
+	}
+
+	d.method("")
+	return d.Output(w)
+}
@@ -0,0 +1,137 @@
+package report
+
+import (
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Relatórios de cumprimento da cota PCD (Lei 8.213/91, art. 93) entregues aos clientes.
+// Os dados são montados aqui; HTML (html.go) e PDF (pdf.go) só formatam.
+
+// Situação da empresa frente à cota
+const (
+	StatusExempt       = "exempt"        // menos de 100 funcionários
+	StatusCompliant    = "compliant"     // PCD contratadas >= mínimo
+	StatusNonCompliant = "non_compliant" // PCD contratadas < mínimo
+	StatusUnknown      = "unknown"       // PCD contratadas não informado
+)
+
+type Company struct {
+	ID           string
+	CNPJ         string // com máscara
+	NomeFantasia string
+	RazaoSocial  string
+	Endereco     string
+
+	Funcionarios int
+	Band         utils.PCDBand
+	MinPCD       int  // utils.ComputeMinPCD(Funcionarios)
+	PCDAtual     *int // nil = não informado
+	Gap          int  // vagas a preencher (só em StatusNonCompliant)
+	Status       string
+}
+
+// Nome para exibição: fantasia, senão razão social
+func (c Company) Name() string {
+	if c.NomeFantasia != "" {
+		return c.NomeFantasia
+	}
+	return c.RazaoSocial
+}
+
+func ForCompany(c *models.Company) Company {
+	rc := Company{
+		ID:           c.ID,
+		CNPJ:         utils.FormatCNPJ(c.CNPJ),
+		NomeFantasia: c.NomeFantasia,
+		RazaoSocial:  c.RazaoSocial,
+		Endereco:     c.Endereco,
+		Funcionarios: c.NumeroFuncionarios,
+		Band:         utils.PCDBandFor(c.NumeroFuncionarios),
+		MinPCD:       utils.ComputeMinPCD(c.NumeroFuncionarios),
+		PCDAtual:     c.NumeroPCDContratados,
+	}
+	switch {
+	case rc.Band.Percent == 0:
+		rc.Status = StatusExempt
+	case rc.PCDAtual == nil:
+		rc.Status = StatusUnknown
+	case *rc.PCDAtual >= rc.MinPCD:
+		rc.Status = StatusCompliant
+	default:
+		rc.Status = StatusNonCompliant
+		rc.Gap = rc.MinPCD - *rc.PCDAtual
+	}
+	return rc
+}
+
+// Relatório de uma empresa
+type CompanyReport struct {
+	Company     Company
+	GeneratedAt time.Time
+}
+
+func NewCompanyReport(c *models.Company, now time.Time) CompanyReport {
+	return CompanyReport{Company: ForCompany(c), GeneratedAt: now.UTC()}
+}
+
+// Relatório da carteira (todas as empresas do filtro)
+type PortfolioReport struct {
+	Companies   []Company
+	Totals      Totals
+	ByBand      []BandTotals // todas as faixas legais, na ordem de utils.PCDBands
+	Truncated   int          // > 0: só as primeiras Truncated empresas entraram
+	GeneratedAt time.Time
+}
+
+type Totals struct {
+	Companies    int
+	Funcionarios int
+	MinPCD       int
+	Known        int // empresas com PCD contratadas informado (e obrigadas)
+	NonCompliant int
+	Gap          int
+}
+
+type BandTotals struct {
+	Band utils.PCDBand
+	Totals
+}
+
+func NewPortfolioReport(list []models.Company, now time.Time) PortfolioReport {
+	p := PortfolioReport{
+		Companies:   make([]Company, len(list)),
+		ByBand:      make([]BandTotals, len(utils.PCDBands)),
+		GeneratedAt: now.UTC(),
+	}
+	for i, b := range utils.PCDBands {
+		p.ByBand[i].Band = b
+	}
+	for i := range list {
+		c := ForCompany(&list[i])
+		p.Companies[i] = c
+		p.Totals.add(c)
+		for j := range p.ByBand {
+			if p.ByBand[j].Band.Code == c.Band.Code {
+				p.ByBand[j].add(c)
+			}
+		}
+	}
+	return p
+}
+
+func (t *Totals) add(c Company) {
+	t.Companies++
+	t.Funcionarios += c.Funcionarios
+	t.MinPCD += c.MinPCD
+	switch c.Status {
+	case StatusCompliant:
+		t.Known++
+	case StatusNonCompliant:
+		t.Known++
+		t.NonCompliant++
+		t.Gap += c.Gap
+	}
+}
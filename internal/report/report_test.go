@@ -0,0 +1,124 @@
+package report
+
+/*
+
+go test -run 'TestReport_' -v ./internal/report -count=1
+
+*/
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+)
+
+func intPtr(n int) *int { return &n }
+
+var reportTime = time.Date(2025, 3, 4, 15, 30, 0, 0, time.UTC)
+
+func TestReport_ForCompany(t *testing.T) {
+	cases := []struct {
+		funcionarios int
+		atual        *int
+		band         string
+		min          int
+		status       string
+		gap          int
+	}{
+		{50, intPtr(0), "0-99", 0, StatusExempt, 0},
+		{150, nil, "100-200", 3, StatusUnknown, 0},
+		{150, intPtr(3), "100-200", 3, StatusCompliant, 0},
+		{1001, intPtr(40), "1001+", 51, StatusNonCompliant, 11},
+	}
+	for _, tc := range cases {
+		c := ForCompany(&models.Company{CNPJ: "11222333000181", NumeroFuncionarios: tc.funcionarios, NumeroPCDContratados: tc.atual})
+		if c.Band.Code != tc.band || c.MinPCD != tc.min || c.Status != tc.status || c.Gap != tc.gap {
+			t.Errorf("%d funcionários: %+v", tc.funcionarios, c)
+		}
+		if c.CNPJ != "11.222.333/0001-81" {
+			t.Errorf("CNPJ = %q", c.CNPJ)
+		}
+	}
+}
+
+func TestReport_Portfolio(t *testing.T) {
+	p := NewPortfolioReport([]models.Company{
+		{NumeroFuncionarios: 10},
+		{NumeroFuncionarios: 150, NumeroPCDContratados: intPtr(1)},
+		{NumeroFuncionarios: 180},
+		{NumeroFuncionarios: 1001, NumeroPCDContratados: intPtr(60)},
+	}, reportTime)
+
+	want := Totals{Companies: 4, Funcionarios: 1341, MinPCD: 3 + 4 + 51, Known: 2, NonCompliant: 1, Gap: 2}
+	if p.Totals != want {
+		t.Fatalf("totals = %+v, want %+v", p.Totals, want)
+	}
+	if len(p.ByBand) != 5 || p.ByBand[1].Companies != 2 || p.ByBand[1].Gap != 2 || p.ByBand[2].Companies != 0 {
+		t.Fatalf("by band = %+v", p.ByBand)
+	}
+}
+
+func TestReport_HTML(t *testing.T) {
+	r := NewCompanyReport(&models.Company{
+		CNPJ: "11222333000181", NomeFantasia: "ACME <Ltda>", NumeroFuncionarios: 1500, NumeroPCDContratados: intPtr(70),
+	}, reportTime)
+
+	var buf bytes.Buffer
+	if err := r.HTML(&buf, i18n.PtBR); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`<html lang="pt-BR">`, "Relatório de cumprimento da cota PCD", "04/03/2025 15:30 UTC",
+		"ACME &lt;Ltda&gt;", "1.500", "5%", "75", "Não cumpre a cota",
+		"1.500 funcionários × 5% = 75,00; arredondado para cima: 75 PCD.",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("html sem %q", want)
+		}
+	}
+
+	buf.Reset()
+	if err := r.HTML(&buf, i18n.En); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "1,500 employees × 5% = 75.00; rounded up: 75 PCD.") {
+		t.Errorf("html en:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	p := NewPortfolioReport(nil, reportTime)
+	if err := p.HTML(&buf, i18n.PtBR); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Nenhuma empresa encontrada.") {
+		t.Errorf("carteira vazia:\n%s", buf.String())
+	}
+}
+
+func TestReport_PDF(t *testing.T) {
+	r := NewCompanyReport(&models.Company{CNPJ: "11222333000181", RazaoSocial: "Indústria São João", NumeroFuncionarios: 150}, reportTime)
+	var buf bytes.Buffer
+	if err := r.PDF(&buf, i18n.PtBR); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) || !bytes.Contains(buf.Bytes(), []byte("%%EOF")) {
+		t.Fatalf("pdf inválido: %q", buf.Bytes()[:min(buf.Len(), 20)])
+	}
+
+	list := make([]models.Company, 120) // mais de uma página
+	for i := range list {
+		list[i] = models.Company{CNPJ: "11222333000181", NomeFantasia: strings.Repeat("Empresa muito comprida ", 5), NumeroFuncionarios: i * 10}
+	}
+	buf.Reset()
+	if err := NewPortfolioReport(list, reportTime).PDF(&buf, i18n.En); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) || bytes.Count(buf.Bytes(), []byte("/Type /Page\n")) < 2 {
+		t.Fatalf("pdf da carteira: %d bytes", buf.Len())
+	}
+}
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// text formata números, datas e rótulos no idioma do relatório (chaves "report.*" do catálogo)
+type text struct {
+	lang i18n.Lang
+}
+
+func (t text) T(key string, args ...any) string { return i18n.T(t.lang, key, args...) }
+
+func (t text) separators() (thousands, decimal string) {
+	if t.lang == i18n.PtBR {
+		return ".", ","
+	}
+	return ",", "."
+}
+
+// Int: 1001 -> "1.001" (pt-BR) / "1,001" (en)
+func (t text) Int(n int) string {
+	s := strconv.Itoa(n)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	th, _ := t.separators()
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteString(th)
+		}
+		b.WriteRune(r)
+	}
+	if neg {
+		return "-" + b.String()
+	}
+	return b.String()
+}
+
+// Decimal: duas casas; 3.5 -> "3,50" (pt-BR) / "3.50" (en)
+func (t text) Decimal(f float64) string {
+	_, dec := t.separators()
+	whole, frac, _ := strings.Cut(strconv.FormatFloat(f, 'f', 2, 64), ".")
+	n, _ := strconv.Atoi(whole)
+	return t.Int(n) + dec + frac
+}
+
+func (t text) Percent(p float64) string {
+	_, dec := t.separators()
+	return strings.Replace(strconv.FormatFloat(p*100, 'f', -1, 64), ".", dec, 1) + "%"
+}
+
+func (t text) Date(tm time.Time) string {
+	return tm.UTC().Format(t.T("report.date_layout"))
+}
+
+// Band: "100 – 200" / "1.001+"
+func (t text) Band(b utils.PCDBand) string {
+	if b.Max == 0 {
+		return t.Int(b.Min) + "+"
+	}
+	return t.Int(b.Min) + " – " + t.Int(b.Max)
+}
+
+func (t text) Status(status string) string { return t.T("report.status." + status) }
+
+func (t text) PCDAtual(c Company) string {
+	if c.PCDAtual == nil {
+		return t.T("report.not_reported")
+	}
+	return t.Int(*c.PCDAtual)
+}
+
+// Method: a conta que levou ao mínimo exigido
+func (t text) Method(c Company) string {
+	if c.Band.Percent == 0 {
+		return t.T("report.method.exempt", t.Int(c.Funcionarios))
+	}
+	exact := float64(c.Funcionarios) * c.Band.Percent
+	return t.T("report.method.calc", t.Int(c.Funcionarios), t.Percent(c.Band.Percent), t.Decimal(exact), t.Int(c.MinPCD))
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrChangeRequestNotFound   = errors.New("change request not found")
+	ErrChangeRequestNotPending = errors.New("change request already decided")
+)
+
+// Pedidos de mudança que aguardam aprovação (coleção change_requests).
+type ChangeRequestRepository struct {
+	coll *mongo.Collection
+}
+
+func NewChangeRequestRepository(db *mongo.Database) *ChangeRequestRepository {
+	return &ChangeRequestRepository{coll: db.Collection("change_requests")}
+}
+
+func (r *ChangeRequestRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "requested_at", Value: -1}}, Options: options.Index().SetName("status_requested_at")},
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "requested_at", Value: -1}}, Options: options.Index().SetName("company_requested_at")},
+	})
+	if err != nil {
+		return fmt.Errorf("change_requests indexes: %w", err)
+	}
+	return nil
+}
+
+// List: mais recentes primeiro
+func (r *ChangeRequestRepository) List(ctx context.Context, f models.ChangeRequestFilter, limit, skip int64) ([]models.ChangeRequest, error) {
+	q := bson.M{}
+	if f.Status != "" {
+		q["status"] = f.Status
+	}
+	if f.CompanyID != "" {
+		q["company_id"] = f.CompanyID
+	}
+	opts := options.Find().SetLimit(limit).SetSkip(skip).
+		SetSort(bson.D{{Key: "requested_at", Value: -1}, {Key: "_id", Value: -1}})
+	cur, err := r.coll.Find(ctx, q, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []models.ChangeRequest{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *ChangeRequestRepository) Get(ctx context.Context, id string) (*models.ChangeRequest, error) {
+	var cr models.ChangeRequest
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&cr)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrChangeRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
+
+func (r *ChangeRequestRepository) Create(ctx context.Context, cr *models.ChangeRequest) error {
+	cr.ID = primitive.NewObjectID().Hex()
+	_, err := r.coll.InsertOne(ctx, cr)
+	return err
+}
+
+// Decide grava a decisão só se o pedido ainda estiver pendente (dois aprovadores
+// ao mesmo tempo: um deles recebe ErrChangeRequestNotPending)
+func (r *ChangeRequestRepository) Decide(ctx context.Context, id, status, by, comment string, at time.Time) error {
+	set := bson.M{"status": status, "decided_by": by, "decided_at": at}
+	if comment != "" {
+		set["comment"] = comment
+	}
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "status": models.ChangeStatusPending}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrChangeRequestNotPending
+	}
+	return nil
+}
+
+// Fail marca o pedido aprovado cuja mudança não pôde ser gravada
+func (r *ChangeRequestRepository) Fail(ctx context.Context, id, msg string) error {
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": models.ChangeStatusFailed, "error": msg}})
+	return err
+}
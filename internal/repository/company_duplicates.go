@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Trechos do CNPJ comparados na mesma posição: com até 3 dígitos trocados
+// (o limite da nota de CNPJ), pelo menos um dos 4 fica intacto.
+var duplicateCNPJBlocks = [][2]int{{0, 4}, {4, 8}, {8, 11}, {11, 14}}
+
+// letras que o FoldText tira o acento (ou a cedilha): casam com as acentuadas gravadas
+var foldedLetters = map[rune]string{
+	'a': "aáàâãäAÁÀÂÃÄ", 'e': "eéèêëEÉÈÊË", 'i': "iíìîïIÍÌÎÏ", 'o': "oóòôõöOÓÒÔÕÖ",
+	'u': "uúùûüUÚÙÛÜ", 'c': "cçCÇ", 'n': "nñNÑ",
+}
+
+// FindDuplicateCandidates: empresas que passam no pré-filtro da busca de duplicatas
+// (qualquer critério de q), as mais recentes primeiro, até limit
+func (r *CompanyRepository) FindDuplicateCandidates(ctx context.Context, q models.DuplicateQuery, limit int64) ([]models.Company, error) {
+	or := bson.A{}
+	if len(q.CNPJ) == 14 {
+		for _, b := range duplicateCNPJBlocks {
+			re := "^" + strings.Repeat(".", b[0]) + q.CNPJ[b[0]:b[1]]
+			or = append(or, bson.M{"cnpj": bson.M{"$regex": re}})
+		}
+	}
+	for _, w := range q.NameWords {
+		re := bson.M{"$regex": foldedRegex(w), "$options": "i"}
+		or = append(or, bson.M{"nome_fantasia": re}, bson.M{"razao_social": re})
+	}
+	if len(or) == 0 {
+		return []models.Company{}, nil
+	}
+	return r.find(ctx, bson.M{"_id": bson.M{"$ne": q.ExcludeID}, "$or": or}, limit, 0)
+}
+
+// foldedRegex: a palavra sem acento vira um regex que casa com ela acentuada
+func foldedRegex(word string) string {
+	var b strings.Builder
+	for _, c := range word {
+		if set, ok := foldedLetters[c]; ok {
+			b.WriteString("[" + set + "]")
+			continue
+		}
+		b.WriteString(regexp.QuoteMeta(string(c)))
+	}
+	return b.String()
+}
@@ -4,15 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/Werneck0live/cadastro-empresa/internal/cnae"
 	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var ErrDuplicateCNPJ = errors.New("cnpj already exists")
+var (
+	ErrDuplicateCNPJ   = errors.New("cnpj already exists")
+	ErrCompanyNotFound = errors.New("company not found")
+)
 
 type CompanyRepository struct {
 	coll *mongo.Collection
@@ -23,6 +31,23 @@ func NewCompanyRepository(db *mongo.Database) *CompanyRepository {
 }
 
 func (r *CompanyRepository) EnsureIndexes(ctx context.Context) error {
+	if err := r.ensureUniqueCNPJ(ctx); err != nil {
+		return err
+	}
+	// filtros por seção/divisão/classe CNAE (regex por prefixo usa o índice)
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "cnae_principal", Value: 1}}, Options: options.Index().SetName("cnae_principal")},
+		{Keys: bson.D{{Key: "cnaes_secundarios", Value: 1}}, Options: options.Index().SetName("cnaes_secundarios")},
+		// filtros e estatísticas por perfil tributário
+		{Keys: bson.D{{Key: "regime_tributario", Value: 1}, {Key: "porte", Value: 1}}, Options: options.Index().SetName("regime_tributario_porte")},
+		{Keys: bson.D{{Key: "porte", Value: 1}}, Options: options.Index().SetName("porte")},
+		// filtro por tag (multikey)
+		{Keys: bson.D{{Key: "tags", Value: 1}}, Options: options.Index().SetName("tags")},
+	})
+	return err
+}
+
+func (r *CompanyRepository) ensureUniqueCNPJ(ctx context.Context) error {
 	model := mongo.IndexModel{
 		Keys: bson.D{{Key: "cnpj", Value: 1}},
 		Options: options.Index().
@@ -44,6 +69,29 @@ func (r *CompanyRepository) EnsureIndexes(ctx context.Context) error {
 	return err
 }
 
+// EnsureValidator aplica o $jsonSchema na coleção (collMod; cria a coleção se ainda não existir).
+// validationLevel "moderate": documentos antigos fora do schema continuam editáveis.
+func (r *CompanyRepository) EnsureValidator(ctx context.Context, jsonSchema map[string]any) error {
+	db := r.coll.Database()
+	validator := bson.M{"$jsonSchema": jsonSchema}
+
+	cmd := bson.D{
+		{Key: "collMod", Value: r.coll.Name()},
+		{Key: "validator", Value: validator},
+		{Key: "validationLevel", Value: "moderate"},
+		{Key: "validationAction", Value: "error"},
+	}
+	err := db.RunCommand(ctx, cmd).Err()
+	if ce, ok := err.(mongo.CommandError); ok && ce.Code == 26 { // NamespaceNotFound
+		opts := options.CreateCollection().
+			SetValidator(validator).
+			SetValidationLevel("moderate").
+			SetValidationAction("error")
+		return db.CreateCollection(ctx, r.coll.Name(), opts)
+	}
+	return err
+}
+
 func (r *CompanyRepository) Create(ctx context.Context, c *models.Company) (string, error) {
 	c.CreatedAt = time.Now()
 	c.UpdatedAt = c.CreatedAt
@@ -65,15 +113,128 @@ func (r *CompanyRepository) Create(ctx context.Context, c *models.Company) (stri
 func (r *CompanyRepository) GetByID(ctx context.Context, id string) (*models.Company, error) {
 	var c models.Company
 	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&c)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrCompanyNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &c, nil
 }
 
+// GetMany: as empresas dos ids numa consulta só ($in); ids inexistentes ficam de fora
+func (r *CompanyRepository) GetMany(ctx context.Context, ids []string) ([]models.Company, error) {
+	return r.find(ctx, bson.M{"_id": bson.M{"$in": ids}}, 0, 0)
+}
+
 func (r *CompanyRepository) GetAll(ctx context.Context, limit int64, skip int64) ([]models.Company, error) {
+	return r.find(ctx, bson.M{}, limit, skip)
+}
+
+// Find lista com filtros (ver models.CompanyFilter); mesma ordenação do GetAll
+func (r *CompanyRepository) Find(ctx context.Context, f models.CompanyFilter, limit, skip int64) ([]models.Company, error) {
+	return r.find(ctx, companyFilterQuery(f), limit, skip)
+}
+
+func companyFilterQuery(f models.CompanyFilter) bson.M {
+	q := bson.M{}
+	if f.Nome != "" {
+		re := bson.M{"$regex": regexp.QuoteMeta(f.Nome), "$options": "i"}
+		q["$or"] = bson.A{bson.M{"nome_fantasia": re}, bson.M{"razao_social": re}}
+	}
+	if f.CNPJPrefix != "" {
+		q["cnpj"] = bson.M{"$regex": "^" + regexp.QuoteMeta(f.CNPJPrefix)}
+	}
+	var and bson.A
+	if f.UF != "" {
+		// sem endereço estruturado (v1, cadastros antigos): a UF do fim do endereço em texto
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"endereco_estruturado.uf": f.UF},
+			bson.M{"endereco_estruturado.uf": bson.M{"$exists": false}, "endereco": bson.M{"$regex": ufTextRegex(regexp.QuoteMeta(f.UF))}},
+		}})
+	}
+	if f.MinFuncionarios != nil || f.MaxFuncionarios != nil {
+		rng := bson.M{}
+		if f.MinFuncionarios != nil {
+			rng["$gte"] = *f.MinFuncionarios
+		}
+		if f.MaxFuncionarios != nil {
+			rng["$lte"] = *f.MaxFuncionarios
+		}
+		q["numero_funcionarios"] = rng
+	}
+	if f.CreatedFrom != nil || f.CreatedTo != nil {
+		rng := bson.M{}
+		if f.CreatedFrom != nil {
+			rng["$gte"] = *f.CreatedFrom
+		}
+		if f.CreatedTo != nil {
+			rng["$lt"] = *f.CreatedTo
+		}
+		q["created_at"] = rng
+	}
+	if f.RegimeTributario != "" {
+		q["regime_tributario"] = f.RegimeTributario
+	}
+	if f.Porte != "" {
+		q["porte"] = f.Porte
+	}
+	if len(f.Tags) > 0 {
+		q["tags"] = bson.M{"$all": f.Tags}
+	}
+	and = append(and, cnaeFilterQuery(f)...)
+	if len(and) > 0 {
+		q["$and"] = and
+	}
+	return q
+}
+
+// UF no fim do endereço em texto: "... - Recife/PE", "... Rio de Janeiro - RJ",
+// "... SP, 01310-100". %s é a UF (ou o grupo que a captura).
+const ufTextPattern = `(?:^|[\s/,-])%s(?:\s*[,-]?\s*(?:CEP:?\s*)?\d{5}-?\d{3})?\s*\.?$`
+
+func ufTextRegex(uf string) string {
+	return fmt.Sprintf(ufTextPattern, uf)
+}
+
+// ufExpr: UF do endereço estruturado ou, sem ele, a do endereço em texto ("" se nenhuma)
+func ufExpr() bson.M {
+	found := bson.M{"$regexFind": bson.M{"input": bson.M{"$ifNull": bson.A{"$endereco", ""}}, "regex": ufTextRegex("([A-Z]{2})")}}
+	uf := bson.M{"$arrayElemAt": bson.A{"$$m.captures", 0}}
+	return bson.M{"$ifNull": bson.A{"$endereco_estruturado.uf", bson.M{"$let": bson.M{
+		"vars": bson.M{"m": found},
+		"in":   bson.M{"$cond": bson.A{bson.M{"$in": bson.A{uf, utils.UFs}}, uf, ""}},
+	}}}}
+}
+
+// cnaeFilterQuery: uma condição por nível informado (seção, divisão, classe),
+// todas por prefixo dos dígitos gravados (a seção vira a lista das suas divisões)
+func cnaeFilterQuery(f models.CompanyFilter) bson.A {
+	var prefixes []string
+	if f.CNAESecao != "" {
+		prefixes = append(prefixes, "^("+strings.Join(cnae.DivisoesDaSecao(f.CNAESecao), "|")+")")
+	}
+	if f.CNAEDivisao != "" {
+		prefixes = append(prefixes, "^"+regexp.QuoteMeta(f.CNAEDivisao))
+	}
+	if f.CNAEClasse != "" {
+		prefixes = append(prefixes, "^"+regexp.QuoteMeta(f.CNAEClasse))
+	}
+	conds := bson.A{}
+	for _, p := range prefixes {
+		cond := bson.M{"cnae_principal": bson.M{"$regex": p}}
+		if f.CNAESecundarios {
+			// regex em campo array casa com qualquer elemento
+			cond = bson.M{"$or": bson.A{cond, bson.M{"cnaes_secundarios": bson.M{"$regex": p}}}}
+		}
+		conds = append(conds, cond)
+	}
+	return conds
+}
+
+func (r *CompanyRepository) find(ctx context.Context, query bson.M, limit, skip int64) ([]models.Company, error) {
 	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cur, err := r.coll.Find(ctx, bson.M{}, opts)
+	cur, err := r.coll.Find(ctx, query, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -90,32 +251,77 @@ func (r *CompanyRepository) GetAll(ctx context.Context, limit int64, skip int64)
 	return list, cur.Err()
 }
 
-func (r *CompanyRepository) Update(ctx context.Context, id string, c *models.Company) error {
+// Update parcial: campo vazio (string "" ou número 0) não muda, exceto os listados em
+// always, gravados mesmo zerados (ex.: PATCH com numero_funcionarios: 0).
+func (r *CompanyRepository) Update(ctx context.Context, id string, c *models.Company, always ...string) error {
 	now := time.Now()
 	set := bson.M{
 		"updated_at": now,
 	}
-
-	if c.NomeFantasia != "" {
-		set["nome_fantasia"] = c.NomeFantasia
-	}
-	if c.RazaoSocial != "" {
-		set["razao_social"] = c.RazaoSocial
+	put := func(field string, v any, empty bool) {
+		if !empty || slices.Contains(always, field) {
+			set[field] = v
+		}
 	}
+
+	put("nome_fantasia", c.NomeFantasia, c.NomeFantasia == "")
+	put("razao_social", c.RazaoSocial, c.RazaoSocial == "")
+	unset := bson.M{}
 	if c.Endereco != "" {
 		set["endereco"] = c.Endereco
+		// endereço em texto (v1) sem o estruturado: o estruturado antigo deixaria de bater
+		if c.EnderecoEstruturado != nil {
+			set["endereco_estruturado"] = c.EnderecoEstruturado
+		} else {
+			unset["endereco_estruturado"] = ""
+		}
 	}
-	if c.NumeroFuncionarios != 0 {
-		set["numero_funcionarios"] = c.NumeroFuncionarios
+	put(models.FieldNumeroFuncionarios, c.NumeroFuncionarios, c.NumeroFuncionarios == 0)
+	put(models.FieldNumeroMinimoPCD, c.NumeroMinimoPCDExigidos, c.NumeroMinimoPCDExigidos == 0)
+	if c.NumeroPCDContratados != nil {
+		set["numero_pcd_contratados"] = *c.NumeroPCDContratados
 	}
-	if c.NumeroMinimoPCDExigidos != 0 {
-		set["numero_minimo_pcd_exigidos"] = c.NumeroMinimoPCDExigidos
+	put("cnpj", c.CNPJ, c.CNPJ == "")
+	put("cnae_principal", c.CNAEPrincipal, c.CNAEPrincipal == "")
+	if c.InscricaoEstadual != "" {
+		set["inscricao_estadual"] = c.InscricaoEstadual
+		set["inscricao_estadual_uf"] = c.InscricaoEstadualUF
 	}
-	if c.CNPJ != "" {
-		set["cnpj"] = c.CNPJ
+	put("inscricao_municipal", c.InscricaoMunicipal, c.InscricaoMunicipal == "")
+	put("regime_tributario", c.RegimeTributario, c.RegimeTributario == "")
+	if c.FaturamentoAnual != nil {
+		set["faturamento_anual"] = *c.FaturamentoAnual
+	}
+	put("porte", c.Porte, c.Porte == "")
+	// nil = não muda; lista vazia remove as secundárias
+	if c.CNAESecundarios != nil {
+		if len(c.CNAESecundarios) > 0 {
+			set["cnaes_secundarios"] = c.CNAESecundarios
+		} else {
+			unset["cnaes_secundarios"] = ""
+		}
+	}
+	if c.Tags != nil {
+		if len(c.Tags) > 0 {
+			set["tags"] = c.Tags
+		} else {
+			unset["tags"] = ""
+		}
+	}
+	// campos personalizados um a um (os outros ficam); valor nil remove o campo
+	for k, v := range c.CustomFields {
+		if v == nil {
+			unset["custom_fields."+k] = ""
+		} else {
+			set["custom_fields."+k] = v
+		}
 	}
 
-	_, err := r.coll.UpdateByID(ctx, id, bson.M{"$set": set})
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	_, err := r.coll.UpdateByID(ctx, id, update)
 	if err != nil {
 		if we, ok := err.(mongo.WriteException); ok {
 			for _, e := range we.WriteErrors {
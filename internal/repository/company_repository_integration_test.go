@@ -77,6 +77,32 @@ func TestCompanyRepository_Integration_CreateGetUpdateReplaceDelete(t *testing.T
 		t.Fatalf("get mismatch: %#v", got)
 	}
 
+	// GetMany: ids inexistentes ficam de fora
+	many, err := repo.GetMany(ctx, []string{id, "00000000000000"})
+	if err != nil || len(many) != 1 || many[0].ID != id {
+		t.Fatalf("get many = %+v err=%v", many, err)
+	}
+
+	// FindDuplicateCandidates: casa por trecho do CNPJ na mesma posição ou por palavra
+	// do nome sem acento; a própria empresa fica de fora
+	acai := models.Company{ID: "99887766000155", CNPJ: "99887766000155", NomeFantasia: "Açaí da Praça", CreatedAt: now, UpdatedAt: now}
+	if _, err := repo.Create(ctx, &acai); err != nil {
+		t.Fatalf("create candidate: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.Delete(ctx, acai.ID) })
+	cands, err := repo.FindDuplicateCandidates(ctx, models.DuplicateQuery{ExcludeID: id, NameWords: []string{"acai"}}, 10)
+	if err != nil || len(cands) != 1 || cands[0].ID != acai.ID {
+		t.Fatalf("candidates by name = %+v err=%v", cands, err)
+	}
+	cands, err = repo.FindDuplicateCandidates(ctx, models.DuplicateQuery{ExcludeID: id, CNPJ: "00000000000155"}, 10)
+	if err != nil || len(cands) != 1 || cands[0].ID != acai.ID {
+		t.Fatalf("candidates by cnpj = %+v err=%v", cands, err)
+	}
+	cands, err = repo.FindDuplicateCandidates(ctx, models.DuplicateQuery{ExcludeID: acai.ID, NameWords: []string{"acai"}}, 10)
+	if err != nil || len(cands) != 0 {
+		t.Fatalf("candidates excluding self = %+v err=%v", cands, err)
+	}
+
 	if got.NumeroMinimoPCDExigidos != utils.ComputeMinPCD(got.NumeroMinimoPCDExigidos) {
 		t.Fatalf("fail calc pcd (create-method): got=%d", got.NumeroMinimoPCDExigidos)
 	}
@@ -101,6 +127,16 @@ func TestCompanyRepository_Integration_CreateGetUpdateReplaceDelete(t *testing.T
 		t.Fatalf("fail calc pcd (update-method): got=%d", got3.NumeroMinimoPCDExigidos)
 	}
 
+	// PATCH para menos de 100: a cota 0 é gravada (always), não ignorada como campo vazio
+	err = repo.Update(ctx, id, &models.Company{NumeroFuncionarios: 50, NumeroMinimoPCDExigidos: utils.ComputeMinPCD(50)},
+		models.FieldNumeroFuncionarios, models.FieldNumeroMinimoPCD)
+	if err != nil {
+		t.Fatalf("update 120 -> 50: %v", err)
+	}
+	if got5, err := repo.GetByID(ctx, id); err != nil || got5.NumeroFuncionarios != 50 || got5.NumeroMinimoPCDExigidos != 0 {
+		t.Fatalf("after update 120 -> 50: %#v err=%v", got5, err)
+	}
+
 	// 4) Replace (PUT)
 	newDoc := models.Company{
 		ID:                 id,
@@ -133,3 +169,94 @@ func TestCompanyRepository_Integration_CreateGetUpdateReplaceDelete(t *testing.T
 		t.Fatalf("expected not found after delete")
 	}
 }
+
+// Testa o pipeline de Stats: totais, UF, faixas, crescimento e filtros
+func TestCompanyRepository_Integration_Stats(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	mongoC, err := mongodb.RunContainer(ctx, tc.WithImage("mongo:7"))
+	if err != nil {
+		t.Fatalf("start mongo: %v", err)
+	}
+	t.Cleanup(func() { _ = mongoC.Terminate(ctx) })
+
+	uri, err := mongoC.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("conn string: %v", err)
+	}
+	client, err := db.NewMongoClient(uri)
+	if err != nil {
+		t.Fatalf("mongo client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Disconnect(ctx) })
+
+	repo := NewCompanyRepository(client.Database("testdb"))
+
+	jan := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+	// created_at controlado: insere direto (o Create grava time.Now())
+	docs := []any{
+		models.Company{ID: "1", CNPJ: "1", NumeroFuncionarios: 150, EnderecoEstruturado: &models.Address{UF: "SP"}, CreatedAt: jan,
+			RegimeTributario: models.RegimeLucroReal, Porte: utils.PorteGrande},
+		models.Company{ID: "2", CNPJ: "2", NumeroFuncionarios: 1001, EnderecoEstruturado: &models.Address{UF: "SP"}, CreatedAt: mar,
+			RegimeTributario: models.RegimeLucroReal, Porte: utils.PorteGrande},
+		models.Company{ID: "3", CNPJ: "3", NumeroFuncionarios: 20, EnderecoEstruturado: &models.Address{UF: "RJ"}, CreatedAt: mar,
+			RegimeTributario: models.RegimeSimplesNacional, Porte: utils.PorteEPP},
+		// só o endereço em texto (v1): a UF sai do fim dele
+		models.Company{ID: "4", CNPJ: "4", NumeroFuncionarios: 5, Endereco: "Av. Atlântica, 500 - Rio de Janeiro - RJ", CreatedAt: mar},
+	}
+	if _, err := repo.coll.InsertMany(ctx, docs); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	st, err := repo.Stats(ctx, models.CompanyFilter{}, models.StatsGroupings)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	// 150 -> 3 (2%); 1001 -> 51 (5%, ceil)
+	if st.Totals != (models.StatsTotals{Companies: 4, Funcionarios: 1176, PCDRequired: 54}) {
+		t.Fatalf("totals = %+v", st.Totals)
+	}
+	if len(st.ByUF) != 2 || st.ByUF[0].Key != "RJ" || st.ByUF[0].Companies != 2 || st.ByUF[1].Key != "SP" || st.ByUF[1].Companies != 2 {
+		t.Fatalf("by_uf = %+v", st.ByUF)
+	}
+	if len(st.ByHeadcountBand) != len(models.HeadcountBands) || st.ByHeadcountBand[0].Companies != 1 || st.ByHeadcountBand[5].Key != "1000+" {
+		t.Fatalf("by_headcount_band = %+v", st.ByHeadcountBand)
+	}
+	if len(st.ByPCDBand) != len(utils.PCDBands) || st.ByPCDBand[0].Companies != 2 || st.ByPCDBand[1].PCDRequired != 3 || st.ByPCDBand[4].PCDRequired != 51 {
+		t.Fatalf("by_pcd_band = %+v", st.ByPCDBand)
+	}
+	if len(st.ByMonth) != 2 || st.ByMonth[0] != (models.GrowthPoint{Period: "2025-01", New: 1, Total: 1}) ||
+		st.ByMonth[1] != (models.GrowthPoint{Period: "2025-03", New: 3, Total: 4}) {
+		t.Fatalf("by_month = %+v", st.ByMonth)
+	}
+	if len(st.ByYear) != 1 || st.ByYear[0].Total != 4 {
+		t.Fatalf("by_year = %+v", st.ByYear)
+	}
+	// todos os regimes/portes, na ordem, e o "" (não informado) no fim
+	if len(st.ByRegime) != 5 || st.ByRegime[0].Companies != 1 || st.ByRegime[3].Companies != 2 || st.ByRegime[4].Key != "" {
+		t.Fatalf("by_regime_tributario = %+v", st.ByRegime)
+	}
+	if len(st.ByPorte) != 5 || st.ByPorte[1].Key != utils.PorteEPP || st.ByPorte[3].Funcionarios != 1151 || st.ByPorte[4].Companies != 1 {
+		t.Fatalf("by_porte = %+v", st.ByPorte)
+	}
+
+	// filtros + só totais
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	st, err = repo.Stats(ctx, models.CompanyFilter{UF: "SP", CreatedFrom: &from}, nil)
+	if err != nil {
+		t.Fatalf("stats filtrado: %v", err)
+	}
+	if st.Totals.Companies != 1 || st.Totals.PCDRequired != 51 || st.ByUF != nil || st.ByMonth != nil {
+		t.Fatalf("stats filtrado = %+v", st)
+	}
+	if st, err = repo.Stats(ctx, models.CompanyFilter{UF: "RJ"}, nil); err != nil || st.Totals.Companies != 2 {
+		t.Fatalf("stats RJ = %+v err=%v", st, err)
+	}
+
+	st, err = repo.Stats(ctx, models.CompanyFilter{RegimeTributario: models.RegimeLucroReal, Porte: utils.PorteGrande}, nil)
+	if err != nil || st.Totals.Companies != 2 {
+		t.Fatalf("stats por perfil = %+v err=%v", st, err)
+	}
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Stats agrega as empresas que passam no filtro num único pipeline ($match + $facet).
+// groupBy: models.StatsBy*; os totais vêm sempre.
+func (r *CompanyRepository) Stats(ctx context.Context, f models.CompanyFilter, groupBy []string) (*models.CompanyStats, error) {
+	cur, err := r.coll.Aggregate(ctx, statsPipeline(f, groupBy))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var res []struct {
+		Totals          []models.StatsTotals `bson:"totals"`
+		ByUF            []models.StatsBucket `bson:"by_uf"`
+		ByHeadcountBand []models.StatsBucket `bson:"by_headcount_band"`
+		ByPCDBand       []models.StatsBucket `bson:"by_pcd_band"`
+		ByMonth         []models.GrowthPoint `bson:"by_month"`
+		ByYear          []models.GrowthPoint `bson:"by_year"`
+		ByRegime        []models.StatsBucket `bson:"by_regime_tributario"`
+		ByPorte         []models.StatsBucket `bson:"by_porte"`
+	}
+	if err := cur.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	st := &models.CompanyStats{}
+	if len(res) == 0 {
+		return st, nil
+	}
+	out := res[0]
+	if len(out.Totals) > 0 {
+		st.Totals = out.Totals[0]
+	}
+	if slices.Contains(groupBy, models.StatsByUF) {
+		st.ByUF = out.ByUF
+	}
+	if slices.Contains(groupBy, models.StatsByHeadcountBand) {
+		codes := make([]string, len(models.HeadcountBands))
+		for i, b := range models.HeadcountBands {
+			codes[i] = b.Code
+		}
+		st.ByHeadcountBand = allBands(codes, out.ByHeadcountBand)
+	}
+	if slices.Contains(groupBy, models.StatsByPCDBand) {
+		codes := make([]string, len(utils.PCDBands))
+		for i, b := range utils.PCDBands {
+			codes[i] = b.Code
+		}
+		st.ByPCDBand = allBands(codes, out.ByPCDBand)
+	}
+	if slices.Contains(groupBy, models.StatsByMonth) {
+		st.ByMonth = cumulative(out.ByMonth)
+	}
+	if slices.Contains(groupBy, models.StatsByYear) {
+		st.ByYear = cumulative(out.ByYear)
+	}
+	if slices.Contains(groupBy, models.StatsByRegime) {
+		st.ByRegime = withUnset(allBands(models.RegimesTributarios, out.ByRegime), out.ByRegime)
+	}
+	if slices.Contains(groupBy, models.StatsByPorte) {
+		st.ByPorte = withUnset(allBands(utils.Portes, out.ByPorte), out.ByPorte)
+	}
+	return st, nil
+}
+
+func statsPipeline(f models.CompanyFilter, groupBy []string) bson.A {
+	sums := func(key any) bson.M {
+		return bson.M{
+			"_id":          key,
+			"companies":    bson.M{"$sum": 1},
+			"funcionarios": bson.M{"$sum": "$_nf"},
+			"pcd_required": bson.M{"$sum": "$_pcd"},
+		}
+	}
+	growth := func(format string) bson.A {
+		return bson.A{
+			bson.M{"$match": bson.M{"created_at": bson.M{"$type": "date"}}},
+			bson.M{"$group": bson.M{
+				"_id": bson.M{"$dateToString": bson.M{"format": format, "date": "$created_at"}},
+				"new": bson.M{"$sum": 1},
+			}},
+			bson.M{"$sort": bson.M{"_id": 1}},
+		}
+	}
+
+	facets := bson.M{
+		"totals": bson.A{bson.M{"$group": sums(nil)}},
+	}
+	for _, g := range groupBy {
+		switch g {
+		case models.StatsByUF:
+			facets["by_uf"] = bson.A{
+				bson.M{"$group": sums(ufExpr())},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			}
+		case models.StatsByHeadcountBand:
+			facets["by_headcount_band"] = bson.A{bson.M{"$group": sums(headcountBandExpr())}}
+		case models.StatsByPCDBand:
+			facets["by_pcd_band"] = bson.A{bson.M{"$group": sums(pcdBandExpr(func(b utils.PCDBand) any { return b.Code }))}}
+		case models.StatsByMonth:
+			facets["by_month"] = growth("%Y-%m")
+		case models.StatsByYear:
+			facets["by_year"] = growth("%Y")
+		case models.StatsByRegime:
+			facets["by_regime_tributario"] = bson.A{bson.M{"$group": sums(bson.M{"$ifNull": bson.A{"$regime_tributario", ""}})}}
+		case models.StatsByPorte:
+			facets["by_porte"] = bson.A{bson.M{"$group": sums(bson.M{"$ifNull": bson.A{"$porte", ""}})}}
+		}
+	}
+
+	return bson.A{
+		bson.M{"$match": companyFilterQuery(f)},
+		bson.M{"$addFields": bson.M{"_nf": bson.M{"$ifNull": bson.A{"$numero_funcionarios", 0}}}},
+		// PCD exigido recalculado pela faixa (o campo gravado pode estar desatualizado).
+		// Percentual inteiro e divisão por 100 para o ceil não sofrer com ponto flutuante.
+		bson.M{"$addFields": bson.M{"_pcd": pcdBandExpr(func(b utils.PCDBand) any {
+			pct := int(math.Round(b.Percent * 100))
+			if pct == 0 {
+				return 0
+			}
+			return bson.M{"$ceil": bson.M{"$divide": bson.A{bson.M{"$multiply": bson.A{"$_nf", pct}}, 100}}}
+		})}},
+		bson.M{"$facet": facets},
+	}
+}
+
+// pcdBandExpr: $switch sobre as faixas legais (da maior para a menor), com then(faixa)
+func pcdBandExpr(then func(utils.PCDBand) any) bson.M {
+	var branches bson.A
+	for i := len(utils.PCDBands) - 1; i > 0; i-- {
+		b := utils.PCDBands[i]
+		branches = append(branches, bson.M{"case": bson.M{"$gte": bson.A{"$_nf", b.Min}}, "then": then(b)})
+	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": then(utils.PCDBands[0])}}
+}
+
+func headcountBandExpr() bson.M {
+	bands := models.HeadcountBands
+	var branches bson.A
+	for i := len(bands) - 1; i > 0; i-- {
+		branches = append(branches, bson.M{"case": bson.M{"$gte": bson.A{"$_nf", bands[i].Min}}, "then": bands[i].Code})
+	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": bands[0].Code}}
+}
+
+// allBands devolve todas as faixas, na ordem de codes, com zero nas que não têm empresas
+func allBands(codes []string, got []models.StatsBucket) []models.StatsBucket {
+	out := make([]models.StatsBucket, len(codes))
+	for i, code := range codes {
+		out[i].Key = code
+		for _, b := range got {
+			if b.Key == code {
+				out[i] = b
+			}
+		}
+	}
+	return out
+}
+
+// withUnset acrescenta ao fim o grupo "" (campo não informado), se houver empresas nele
+func withUnset(buckets, got []models.StatsBucket) []models.StatsBucket {
+	for _, b := range got {
+		if b.Key == "" {
+			return append(buckets, b)
+		}
+	}
+	return buckets
+}
+
+func cumulative(points []models.GrowthPoint) []models.GrowthPoint {
+	var total int64
+	for i := range points {
+		total += points[i].New
+		points[i].Total = total
+	}
+	return points
+}
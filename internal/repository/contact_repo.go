@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrContactNotFound = errors.New("contact not found")
+
+// Contatos das empresas (coleção contacts, um documento por contato).
+type ContactRepository struct {
+	coll *mongo.Collection
+}
+
+func NewContactRepository(db *mongo.Database) *ContactRepository {
+	return &ContactRepository{coll: db.Collection("contacts")}
+}
+
+func (r *ContactRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "principal", Value: -1}, {Key: "nome", Value: 1}},
+		Options: options.Index().SetName("company_principal_nome"),
+	})
+	if err != nil {
+		return fmt.Errorf("contacts indexes: %w", err)
+	}
+	return nil
+}
+
+// List: principal primeiro, depois por nome
+func (r *ContactRepository) List(ctx context.Context, companyID string, limit, skip int64) ([]models.Contact, error) {
+	opts := options.Find().SetLimit(limit).SetSkip(skip).
+		SetSort(bson.D{{Key: "principal", Value: -1}, {Key: "nome", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := r.coll.Find(ctx, bson.M{"company_id": companyID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []models.Contact{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *ContactRepository) Get(ctx context.Context, companyID, id string) (*models.Contact, error) {
+	var c models.Contact
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "company_id": companyID}).Decode(&c)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrContactNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *ContactRepository) Create(ctx context.Context, c *models.Contact) error {
+	c.ID = primitive.NewObjectID().Hex()
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = c.CreatedAt
+	_, err := r.coll.InsertOne(ctx, c)
+	return err
+}
+
+// Replace grava o contato inteiro (created_at preservado por quem chama)
+func (r *ContactRepository) Replace(ctx context.Context, c *models.Contact) error {
+	c.UpdatedAt = time.Now()
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": c.ID, "company_id": c.CompanyID}, c)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrContactNotFound
+	}
+	return nil
+}
+
+// ClearPrimary desmarca o principal dos demais contatos da empresa
+func (r *ContactRepository) ClearPrimary(ctx context.Context, companyID, exceptID string) error {
+	_, err := r.coll.UpdateMany(ctx,
+		bson.M{"company_id": companyID, "principal": true, "_id": bson.M{"$ne": exceptID}},
+		bson.M{"$set": bson.M{"principal": false, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+func (r *ContactRepository) Delete(ctx context.Context, companyID, id string) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id, "company_id": companyID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrContactNotFound
+	}
+	return nil
+}
+
+// DeleteByCompany remove todos os contatos (exclusão da empresa)
+func (r *ContactRepository) DeleteByCompany(ctx context.Context, companyID string) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"company_id": companyID})
+	return err
+}
+
+// MoveToCompany passa os contatos de from para to (fusão de empresas). Se to já
+// tem contato principal, os que chegam deixam de ser principais.
+func (r *ContactRepository) MoveToCompany(ctx context.Context, from, to string) (int, error) {
+	set := bson.M{"company_id": to, "updated_at": time.Now()}
+	n, err := r.coll.CountDocuments(ctx, bson.M{"company_id": to, "principal": true})
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		set["principal"] = false
+	}
+	res, err := r.coll.UpdateMany(ctx, bson.M{"company_id": from}, bson.M{"$set": set})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.ModifiedCount), nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrCustomFieldNotFound  = errors.New("custom field definition not found")
+	ErrDuplicateCustomField = errors.New("custom field definition already exists")
+)
+
+// Definições dos campos personalizados (coleção custom_field_definitions, _id = key).
+type CustomFieldRepository struct {
+	coll *mongo.Collection
+}
+
+func NewCustomFieldRepository(db *mongo.Database) *CustomFieldRepository {
+	return &CustomFieldRepository{coll: db.Collection("custom_field_definitions")}
+}
+
+// List: todas as definições, por key (são poucas; sem paginação)
+func (r *CustomFieldRepository) List(ctx context.Context) ([]models.CustomFieldDefinition, error) {
+	cur, err := r.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []models.CustomFieldDefinition{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *CustomFieldRepository) Get(ctx context.Context, key string) (*models.CustomFieldDefinition, error) {
+	var d models.CustomFieldDefinition
+	err := r.coll.FindOne(ctx, bson.M{"_id": key}).Decode(&d)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrCustomFieldNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *CustomFieldRepository) Create(ctx context.Context, d *models.CustomFieldDefinition) error {
+	d.CreatedAt = time.Now()
+	d.UpdatedAt = d.CreatedAt
+	_, err := r.coll.InsertOne(ctx, d)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateCustomField
+	}
+	return err
+}
+
+// Replace grava a definição inteira (created_at preservado por quem chama)
+func (r *CustomFieldRepository) Replace(ctx context.Context, d *models.CustomFieldDefinition) error {
+	d.UpdatedAt = time.Now()
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": d.Key}, d)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrCustomFieldNotFound
+	}
+	return nil
+}
+
+func (r *CustomFieldRepository) Delete(ctx context.Context, key string) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": key})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrCustomFieldNotFound
+	}
+	return nil
+}
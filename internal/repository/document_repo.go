@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrDocumentNotFound = errors.New("document not found")
+
+const documentsBucket = "documents"
+
+// Documentos das empresas no GridFS (coleções documents.files e documents.chunks).
+// Empresa, content-type, SHA-256 e categoria ficam no metadata do arquivo.
+type DocumentRepository struct {
+	db    *mongo.Database
+	files *mongo.Collection
+}
+
+func NewDocumentRepository(db *mongo.Database) *DocumentRepository {
+	return &DocumentRepository{db: db, files: db.Collection(documentsBucket + ".files")}
+}
+
+// documento em documents.files (campos do GridFS + metadata)
+type documentFile struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	Length     int64              `bson:"length"`
+	UploadDate time.Time          `bson:"uploadDate"`
+	Filename   string             `bson:"filename"`
+	Metadata   documentMetadata   `bson:"metadata"`
+}
+
+type documentMetadata struct {
+	CompanyID   string `bson:"company_id"`
+	ContentType string `bson:"content_type"`
+	SHA256      string `bson:"sha256"`
+	Categoria   string `bson:"categoria,omitempty"`
+}
+
+func (f documentFile) document() models.Document {
+	return models.Document{
+		ID:          f.ID.Hex(),
+		CompanyID:   f.Metadata.CompanyID,
+		NomeArquivo: f.Filename,
+		ContentType: f.Metadata.ContentType,
+		Tamanho:     f.Length,
+		SHA256:      f.Metadata.SHA256,
+		Categoria:   f.Metadata.Categoria,
+		CreatedAt:   f.UploadDate,
+	}
+}
+
+// os índices do próprio GridFS são criados pelo driver na primeira gravação
+func (r *DocumentRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.files.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "metadata.company_id", Value: 1}, {Key: "uploadDate", Value: -1}},
+		Options: options.Index().SetName("company_upload_date"),
+	})
+	if err != nil {
+		return fmt.Errorf("documents indexes: %w", err)
+	}
+	return nil
+}
+
+// bucket por operação: os prazos do GridFS (v1 do driver) são do bucket, não do context
+func (r *DocumentRepository) bucket(ctx context.Context) (*gridfs.Bucket, error) {
+	b, err := gridfs.NewBucket(r.db, options.GridFSBucket().SetName(documentsBucket))
+	if err != nil {
+		return nil, err
+	}
+	if dl, ok := ctx.Deadline(); ok {
+		_ = b.SetReadDeadline(dl)
+		_ = b.SetWriteDeadline(dl)
+	}
+	return b, nil
+}
+
+// List: mais recentes primeiro
+func (r *DocumentRepository) List(ctx context.Context, companyID string, limit, skip int64) ([]models.Document, error) {
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.D{{Key: "uploadDate", Value: -1}, {Key: "_id", Value: -1}})
+	cur, err := r.files.Find(ctx, bson.M{"metadata.company_id": companyID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var files []documentFile
+	if err := cur.All(ctx, &files); err != nil {
+		return nil, err
+	}
+	list := make([]models.Document, len(files))
+	for i, f := range files {
+		list[i] = f.document()
+	}
+	return list, nil
+}
+
+func (r *DocumentRepository) Get(ctx context.Context, companyID, id string) (*models.Document, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrDocumentNotFound
+	}
+	var f documentFile
+	err = r.files.FindOne(ctx, bson.M{"_id": oid, "metadata.company_id": companyID}).Decode(&f)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := f.document()
+	return &d, nil
+}
+
+// Upload grava o conteúdo e preenche ID, Tamanho e CreatedAt com o que o GridFS gravou
+func (r *DocumentRepository) Upload(ctx context.Context, d *models.Document, content io.Reader) error {
+	b, err := r.bucket(ctx)
+	if err != nil {
+		return err
+	}
+	oid := primitive.NewObjectID()
+	meta := documentMetadata{CompanyID: d.CompanyID, ContentType: d.ContentType, SHA256: d.SHA256, Categoria: d.Categoria}
+	if err := b.UploadFromStreamWithID(oid, d.NomeArquivo, content, options.GridFSUpload().SetMetadata(meta)); err != nil {
+		return err
+	}
+	saved, err := r.Get(ctx, d.CompanyID, oid.Hex())
+	if err != nil {
+		return err
+	}
+	*d = *saved
+	return nil
+}
+
+// Open devolve o conteúdo com Seek (http.ServeContent atende Range com ele)
+func (r *DocumentRepository) Open(ctx context.Context, companyID, id string) (io.ReadSeekCloser, error) {
+	d, err := r.Get(ctx, companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.bucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	oid, _ := primitive.ObjectIDFromHex(d.ID)
+	dl, _ := ctx.Deadline()
+	return &gridfsReader{bucket: b, id: oid, size: d.Tamanho, deadline: dl}, nil
+}
+
+func (r *DocumentRepository) Delete(ctx context.Context, companyID, id string) error {
+	if _, err := r.Get(ctx, companyID, id); err != nil {
+		return err
+	}
+	b, err := r.bucket(ctx)
+	if err != nil {
+		return err
+	}
+	oid, _ := primitive.ObjectIDFromHex(id)
+	if err := b.DeleteContext(ctx, oid); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return ErrDocumentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteByCompany remove todos os documentos (exclusão da empresa)
+func (r *DocumentRepository) DeleteByCompany(ctx context.Context, companyID string) error {
+	cur, err := r.files.Find(ctx, bson.M{"metadata.company_id": companyID}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	var files []documentFile
+	if err := cur.All(ctx, &files); err != nil {
+		return err
+	}
+	b, err := r.bucket(ctx)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := b.DeleteContext(ctx, f.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// MoveToCompany passa os documentos de from para to (fusão de empresas); o conteúdo
+// no GridFS não muda, só o metadata.company_id.
+func (r *DocumentRepository) MoveToCompany(ctx context.Context, from, to string) (int, error) {
+	res, err := r.files.UpdateMany(ctx, bson.M{"metadata.company_id": from}, bson.M{"$set": bson.M{"metadata.company_id": to}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.ModifiedCount), nil
+}
+
+// gridfsReader: io.ReadSeeker sobre o GridFS. Seek só guarda a posição; a leitura
+// (re)abre o stream a partir dela, pulando os chunks anteriores.
+type gridfsReader struct {
+	bucket   *gridfs.Bucket
+	id       primitive.ObjectID
+	size     int64
+	deadline time.Time // zero = sem prazo
+	pos      int64
+	stream   *gridfs.DownloadStream
+}
+
+func (g *gridfsReader) Read(p []byte) (int, error) {
+	if g.pos >= g.size {
+		return 0, io.EOF
+	}
+	if g.stream == nil {
+		ds, err := g.bucket.OpenDownloadStream(g.id)
+		if err != nil {
+			return 0, err
+		}
+		_ = ds.SetReadDeadline(g.deadline)
+		if _, err := ds.Skip(g.pos); err != nil {
+			_ = ds.Close()
+			return 0, err
+		}
+		g.stream = ds
+	}
+	n, err := g.stream.Read(p)
+	g.pos += int64(n)
+	return n, err
+}
+
+func (g *gridfsReader) Seek(offset int64, whence int) (int64, error) {
+	pos := offset
+	switch whence {
+	case io.SeekCurrent:
+		pos += g.pos
+	case io.SeekEnd:
+		pos += g.size
+	}
+	if pos < 0 {
+		return 0, errors.New("gridfs: negative position")
+	}
+	if pos != g.pos && g.stream != nil {
+		_ = g.stream.Close()
+		g.stream = nil
+	}
+	g.pos = pos
+	return pos, nil
+}
+
+func (g *gridfsReader) Close() error {
+	if g.stream == nil {
+		return nil
+	}
+	return g.stream.Close()
+}
@@ -0,0 +1,97 @@
+//go:build integration
+// +build integration
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	/*
+		Para Rodar: go test -tags=integration -v ./internal/repository -run TestDocumentRepository_Integration -count=1
+	*/
+
+	"github.com/Werneck0live/cadastro-empresa/internal/db"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	tc "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+)
+
+// Upload -> List -> Open (Seek no meio de um chunk) -> Delete no GridFS real
+func TestDocumentRepository_Integration_UploadSeekDelete(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	mongoC, err := mongodb.RunContainer(ctx, tc.WithImage("mongo:7"))
+	if err != nil {
+		t.Fatalf("start mongo: %v", err)
+	}
+	t.Cleanup(func() { _ = mongoC.Terminate(ctx) })
+
+	uri, err := mongoC.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("conn string: %v", err)
+	}
+	client, err := db.NewMongoClient(uri)
+	if err != nil {
+		t.Fatalf("mongo client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Disconnect(ctx) })
+
+	repo := NewDocumentRepository(client.Database("testdb"))
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("indexes: %v", err)
+	}
+
+	// 600 KB: mais de dois chunks de 255 KB
+	content := make([]byte, 600<<10)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	d := models.Document{CompanyID: "11222333000181", NomeArquivo: "relatorio.pdf", ContentType: "application/pdf", SHA256: "abc", Categoria: models.DocumentRelatorioPCD}
+	if err := repo.Upload(ctx, &d, bytes.NewReader(content)); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if d.ID == "" || d.Tamanho != int64(len(content)) || d.CreatedAt.IsZero() || d.Categoria != models.DocumentRelatorioPCD {
+		t.Fatalf("upload = %+v", d)
+	}
+
+	list, err := repo.List(ctx, d.CompanyID, 10, 0)
+	if err != nil || len(list) != 1 || list[0].ID != d.ID {
+		t.Fatalf("list = %v, %v", list, err)
+	}
+	if _, err := repo.Get(ctx, "99999999000191", d.ID); !errors.Is(err, ErrDocumentNotFound) {
+		t.Fatalf("outra empresa: %v", err)
+	}
+
+	rc, err := repo.Open(ctx, d.CompanyID, d.ID)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer rc.Close()
+	off := int64(300 << 10)
+	if _, err := rc.Seek(off, io.SeekStart); err != nil {
+		t.Fatalf("seek: %v", err)
+	}
+	part := make([]byte, 1000)
+	if _, err := io.ReadFull(rc, part); err != nil || !bytes.Equal(part, content[off:off+1000]) {
+		t.Fatalf("leitura após seek: %v", err)
+	}
+	if _, err := rc.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("seek início: %v", err)
+	}
+	all, err := io.ReadAll(rc)
+	if err != nil || !bytes.Equal(all, content) {
+		t.Fatalf("leitura completa: %d bytes, %v", len(all), err)
+	}
+
+	if err := repo.Delete(ctx, d.CompanyID, d.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repo.Delete(ctx, d.CompanyID, d.ID); !errors.Is(err, ErrDocumentNotFound) {
+		t.Fatalf("delete de novo: %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrEmployeeNotFound = errors.New("employee not found")
+	ErrDuplicateCPF     = errors.New("cpf already exists in company")
+)
+
+// Funcionários das empresas (coleção employees, um documento por funcionário).
+type EmployeeRepository struct {
+	coll *mongo.Collection
+}
+
+func NewEmployeeRepository(db *mongo.Database) *EmployeeRepository {
+	return &EmployeeRepository{coll: db.Collection("employees")}
+}
+
+func (r *EmployeeRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "cpf", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("uniq_company_cpf"),
+		},
+		{
+			Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "admissao", Value: 1}},
+			Options: options.Index().SetName("company_admissao"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("employees indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *EmployeeRepository) List(ctx context.Context, companyID string, f models.EmployeeFilter, limit, skip int64) ([]models.Employee, error) {
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.D{{Key: "nome", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := r.coll.Find(ctx, employeeQuery(companyID, f), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []models.Employee{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func employeeQuery(companyID string, f models.EmployeeFilter) bson.M {
+	q := bson.M{"company_id": companyID}
+	if f.ActiveOn != "" {
+		q["admissao"] = bson.M{"$lte": f.ActiveOn}
+		q["$or"] = bson.A{
+			bson.M{"desligamento": bson.M{"$exists": false}},
+			bson.M{"desligamento": nil},
+			bson.M{"desligamento": bson.M{"$gt": f.ActiveOn}},
+		}
+	}
+	if f.PCD != nil {
+		q["pcd"] = *f.PCD
+	}
+	return q
+}
+
+// Count: ativos na data e, desses, quantos PCD/reabilitados
+func (r *EmployeeRepository) Count(ctx context.Context, companyID, activeOn string) (total, pcd int, err error) {
+	q := employeeQuery(companyID, models.EmployeeFilter{ActiveOn: activeOn})
+	n, err := r.coll.CountDocuments(ctx, q)
+	if err != nil {
+		return 0, 0, err
+	}
+	q["pcd"] = true
+	p, err := r.coll.CountDocuments(ctx, q)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(n), int(p), nil
+}
+
+func (r *EmployeeRepository) Get(ctx context.Context, companyID, id string) (*models.Employee, error) {
+	var e models.Employee
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "company_id": companyID}).Decode(&e)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrEmployeeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *EmployeeRepository) Create(ctx context.Context, e *models.Employee) error {
+	e.ID = primitive.NewObjectID().Hex()
+	e.CreatedAt = time.Now()
+	e.UpdatedAt = e.CreatedAt
+	_, err := r.coll.InsertOne(ctx, e)
+	return duplicateCPF(err)
+}
+
+// Replace grava o funcionário inteiro (created_at preservado por quem chama)
+func (r *EmployeeRepository) Replace(ctx context.Context, e *models.Employee) error {
+	e.UpdatedAt = time.Now()
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": e.ID, "company_id": e.CompanyID}, e)
+	if err != nil {
+		return duplicateCPF(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrEmployeeNotFound
+	}
+	return nil
+}
+
+func (r *EmployeeRepository) Delete(ctx context.Context, companyID, id string) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id, "company_id": companyID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrEmployeeNotFound
+	}
+	return nil
+}
+
+// DeleteByCompany remove todos os funcionários (exclusão da empresa)
+func (r *EmployeeRepository) DeleteByCompany(ctx context.Context, companyID string) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"company_id": companyID})
+	return err
+}
+
+// MoveToCompany passa os funcionários de from para to (fusão de empresas). CPF que
+// já consta em to é o mesmo funcionário: o registro de from é descartado (e devolvido).
+func (r *EmployeeRepository) MoveToCompany(ctx context.Context, from, to string) (int, []models.Employee, error) {
+	return moveByKey[models.Employee](ctx, r.coll, "cpf", from, to)
+}
+
+// Upsert grava a lista pela chave (company_id, cpf): CPF novo cria, CPF existente
+// é substituído (mantendo _id e created_at). Devolve quantos foram criados e atualizados.
+func (r *EmployeeRepository) Upsert(ctx context.Context, companyID string, list []models.Employee) (created, updated int, err error) {
+	if len(list) == 0 {
+		return 0, 0, nil
+	}
+	now := time.Now()
+	writes := make([]mongo.WriteModel, len(list))
+	for i, e := range list {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"company_id": companyID, "cpf": e.CPF}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"nome":         e.Nome,
+					"admissao":     e.Admissao,
+					"desligamento": e.Desligamento,
+					"pcd":          e.PCD,
+					"updated_at":   now,
+				},
+				"$setOnInsert": bson.M{"_id": primitive.NewObjectID().Hex(), "created_at": now},
+			}).
+			SetUpsert(true)
+	}
+	res, err := r.coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(res.UpsertedCount), int(res.MatchedCount), nil
+}
+
+func duplicateCPF(err error) error {
+	if we, ok := err.(mongo.WriteException); ok {
+		for _, e := range we.WriteErrors {
+			if e.Code == 11000 {
+				return ErrDuplicateCPF
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Eventos publicados sobre as empresas (coleção company_events), gravados pelo
+// events.Log para a linha do tempo. Só recebe inserções.
+type EventRepository struct {
+	coll *mongo.Collection
+}
+
+func NewEventRepository(db *mongo.Database) *EventRepository {
+	return &EventRepository{coll: db.Collection("company_events")}
+}
+
+func (r *EventRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName("company_timestamp"),
+	})
+	if err != nil {
+		return fmt.Errorf("company_events indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *EventRepository) Append(ctx context.Context, ev *models.CompanyEvent) error {
+	ev.ID = primitive.NewObjectID().Hex()
+	_, err := r.coll.InsertOne(ctx, ev)
+	return err
+}
+
+// Before: os limit eventos mais recentes anteriores ao cursor (at, id) da linha do tempo;
+// at zero = a partir do mais recente
+func (r *EventRepository) Before(ctx context.Context, companyID string, at time.Time, id string, limit int64) ([]models.CompanyEvent, error) {
+	opts := options.Find().SetLimit(limit).
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	cur, err := r.coll.Find(ctx, beforeCursor(companyID, "timestamp", at, id), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []models.CompanyEvent{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// beforeCursor: documentos da empresa depois do cursor na ordem (field desc, _id desc)
+func beforeCursor(companyID, field string, at time.Time, id string) bson.M {
+	q := bson.M{"company_id": companyID}
+	if !at.IsZero() {
+		q["$or"] = bson.A{
+			bson.M{field: bson.M{"$lt": at}},
+			bson.M{field: at, "_id": bson.M{"$lt": id}},
+		}
+	}
+	return q
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrIdempotencyKeyExists   = errors.New("idempotency key already exists")
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+)
+
+// Guarda as respostas das requisições com Idempotency-Key.
+// Os documentos expiram sozinhos pelo índice TTL em created_at.
+type IdempotencyRepository struct {
+	coll *mongo.Collection
+	ttl  time.Duration
+}
+
+func NewIdempotencyRepository(db *mongo.Database, ttl time.Duration) *IdempotencyRepository {
+	return &IdempotencyRepository{coll: db.Collection("idempotency_keys"), ttl: ttl}
+}
+
+func (r *IdempotencyRepository) EnsureIndexes(ctx context.Context) error {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().
+			SetExpireAfterSeconds(int32(r.ttl.Seconds())).
+			SetName("ttl_created_at"),
+	}
+	_, err := r.coll.Indexes().CreateOne(ctx, model)
+	if err == nil {
+		return nil
+	}
+	// TTL alterado na config -> recria o índice com o novo expireAfterSeconds
+	if ce, ok := err.(mongo.CommandError); ok && ce.Code == 85 { // IndexOptionsConflict
+		if _, dropErr := r.coll.Indexes().DropOne(ctx, "ttl_created_at"); dropErr != nil {
+			return fmt.Errorf("drop index ttl_created_at: %w", dropErr)
+		}
+		_, createErr := r.coll.Indexes().CreateOne(ctx, model)
+		return createErr
+	}
+	return err
+}
+
+// Reserve grava a chave como "em processamento".
+// Se a chave já existir, retorna ErrIdempotencyKeyExists.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, rec *models.IdempotencyRecord) error {
+	rec.Completed = false
+	rec.CreatedAt = time.Now()
+	_, err := r.coll.InsertOne(ctx, rec)
+	if err != nil {
+		if we, ok := err.(mongo.WriteException); ok {
+			for _, e := range we.WriteErrors {
+				if e.Code == 11000 {
+					return ErrIdempotencyKeyExists
+				}
+			}
+		}
+	}
+	return err
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	var rec models.IdempotencyRecord
+	err := r.coll.FindOne(ctx, bson.M{"_id": key}).Decode(&rec)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrIdempotencyKeyNotFound
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// Complete salva a resposta final da requisição original (usada nos replays).
+func (r *IdempotencyRepository) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	_, err := r.coll.UpdateByID(ctx, key, bson.M{"$set": bson.M{
+		"completed":    true,
+		"status":       status,
+		"content_type": contentType,
+		"body":         body,
+	}})
+	return err
+}
+
+// Release remove a reserva (ex.: a requisição falhou com 5xx e o cliente pode tentar de novo).
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Histórico das fusões de empresas (coleção company_merges, um documento por fusão).
+type MergeRepository struct {
+	coll *mongo.Collection
+}
+
+func NewMergeRepository(db *mongo.Database) *MergeRepository {
+	return &MergeRepository{coll: db.Collection("company_merges")}
+}
+
+func (r *MergeRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "target_id", Value: 1}, {Key: "merged_at", Value: -1}},
+		Options: options.Index().SetName("target_merged_at"),
+	})
+	if err != nil {
+		return fmt.Errorf("company_merges indexes: %w", err)
+	}
+	return nil
+}
+
+// List: fusões em que a empresa ficou, mais recentes primeiro
+func (r *MergeRepository) List(ctx context.Context, targetID string, limit, skip int64) ([]models.CompanyMerge, error) {
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.D{{Key: "merged_at", Value: -1}})
+	cur, err := r.coll.Find(ctx, bson.M{"target_id": targetID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []models.CompanyMerge{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *MergeRepository) Create(ctx context.Context, m *models.CompanyMerge) error {
+	m.ID = primitive.NewObjectID().Hex()
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Update regrava a fusão (os contadores e descartados saem depois do Create)
+func (r *MergeRepository) Update(ctx context.Context, m *models.CompanyMerge) error {
+	_, err := r.coll.ReplaceOne(ctx, bson.M{"_id": m.ID}, m)
+	return err
+}
+
+// MoveToCompany: as fusões em que from ficou passam a constar no histórico de to
+// (from foi absorvida por to). Os dados gravados em cada fusão não mudam.
+func (r *MergeRepository) MoveToCompany(ctx context.Context, from, to string) (int, error) {
+	res, err := r.coll.UpdateMany(ctx, bson.M{"target_id": from}, bson.M{"$set": bson.M{"target_id": to}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.ModifiedCount), nil
+}
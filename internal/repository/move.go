@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// moveByKey passa os documentos de uma empresa para outra (fusão), respeitando o
+// índice único (company_id, key): os que já existem em to são removidos de from e
+// devolvidos em dropped (decodificados em T), para ficarem no histórico da fusão.
+// Devolve também quantos foram transferidos.
+func moveByKey[T any](ctx context.Context, coll *mongo.Collection, key, from, to string) (int, []T, error) {
+	existing, err := coll.Distinct(ctx, key, bson.M{"company_id": to})
+	if err != nil {
+		return 0, nil, err
+	}
+	var dropped []T
+	if len(existing) > 0 {
+		cur, err := coll.Find(ctx, bson.M{"company_id": from, key: bson.M{"$in": existing}})
+		if err != nil {
+			return 0, nil, err
+		}
+		if err := cur.All(ctx, &dropped); err != nil {
+			return 0, nil, err
+		}
+	}
+	res, err := coll.UpdateMany(ctx,
+		bson.M{"company_id": from, key: bson.M{"$nin": existing}},
+		bson.M{"$set": bson.M{"company_id": to, "updated_at": time.Now()}})
+	if err != nil {
+		return 0, nil, err
+	}
+	if _, err := coll.DeleteMany(ctx, bson.M{"company_id": from}); err != nil {
+		return int(res.ModifiedCount), nil, err
+	}
+	return int(res.ModifiedCount), dropped, nil
+}
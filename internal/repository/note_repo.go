@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrNoteNotFound = errors.New("note not found")
+
+// Anotações das empresas (coleção notes, um documento por nota).
+type NoteRepository struct {
+	coll *mongo.Collection
+}
+
+func NewNoteRepository(db *mongo.Database) *NoteRepository {
+	return &NoteRepository{coll: db.Collection("notes")}
+}
+
+func (r *NoteRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName("company_created_at"),
+	})
+	if err != nil {
+		return fmt.Errorf("notes indexes: %w", err)
+	}
+	return nil
+}
+
+// List: mais recentes primeiro
+func (r *NoteRepository) List(ctx context.Context, companyID string, limit, skip int64) ([]models.Note, error) {
+	opts := options.Find().SetLimit(limit).SetSkip(skip).
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	return r.find(ctx, bson.M{"company_id": companyID}, opts)
+}
+
+// Before: as limit notas mais recentes anteriores ao cursor (at, id) da linha do tempo;
+// at zero = a partir da mais recente
+func (r *NoteRepository) Before(ctx context.Context, companyID string, at time.Time, id string, limit int64) ([]models.Note, error) {
+	opts := options.Find().SetLimit(limit).
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	return r.find(ctx, beforeCursor(companyID, "created_at", at, id), opts)
+}
+
+func (r *NoteRepository) find(ctx context.Context, q bson.M, opts *options.FindOptions) ([]models.Note, error) {
+	cur, err := r.coll.Find(ctx, q, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []models.Note{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *NoteRepository) Get(ctx context.Context, companyID, id string) (*models.Note, error) {
+	var n models.Note
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "company_id": companyID}).Decode(&n)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNoteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (r *NoteRepository) Create(ctx context.Context, n *models.Note) error {
+	n.ID = primitive.NewObjectID().Hex()
+	n.CreatedAt = time.Now()
+	n.UpdatedAt = n.CreatedAt
+	_, err := r.coll.InsertOne(ctx, n)
+	return err
+}
+
+// Replace grava a nota inteira (created_at preservado por quem chama)
+func (r *NoteRepository) Replace(ctx context.Context, n *models.Note) error {
+	n.UpdatedAt = time.Now()
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": n.ID, "company_id": n.CompanyID}, n)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
+
+func (r *NoteRepository) Delete(ctx context.Context, companyID, id string) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id, "company_id": companyID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
+
+// DeleteByCompany remove as notas (exclusão da empresa)
+func (r *NoteRepository) DeleteByCompany(ctx context.Context, companyID string) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"company_id": companyID})
+	return err
+}
+
+// MoveToCompany passa as notas de from para to (fusão de empresas)
+func (r *NoteRepository) MoveToCompany(ctx context.Context, from, to string) (int, error) {
+	res, err := r.coll.UpdateMany(ctx, bson.M{"company_id": from},
+		bson.M{"$set": bson.M{"company_id": to, "updated_at": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.ModifiedCount), nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrOwnershipNotFound  = errors.New("ownership not found")
+	ErrDuplicateOwnership = errors.New("ownership already exists")
+)
+
+// Participações entre empresas (coleção company_ownerships, uma aresta por par controladora/controlada).
+type OwnershipRepository struct {
+	coll *mongo.Collection
+}
+
+func NewOwnershipRepository(db *mongo.Database) *OwnershipRepository {
+	return &OwnershipRepository{coll: db.Collection("company_ownerships")}
+}
+
+func (r *OwnershipRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "parent_id", Value: 1}, {Key: "child_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("uniq_parent_child"),
+		},
+		{
+			// subida no grafo: controladoras de uma empresa
+			Keys:    bson.D{{Key: "child_id", Value: 1}},
+			Options: options.Index().SetName("child_id"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("company_ownerships indexes: %w", err)
+	}
+	return nil
+}
+
+// List: controladas diretas da empresa
+func (r *OwnershipRepository) List(ctx context.Context, parentID string, limit, skip int64) ([]models.Ownership, error) {
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.D{{Key: "child_id", Value: 1}})
+	return r.find(ctx, bson.M{"parent_id": parentID}, opts)
+}
+
+// ByParents: arestas que saem das empresas (um nível para baixo no grafo)
+func (r *OwnershipRepository) ByParents(ctx context.Context, ids []string) ([]models.Ownership, error) {
+	return r.find(ctx, bson.M{"parent_id": bson.M{"$in": ids}}, options.Find())
+}
+
+// ByChildren: arestas que chegam nas empresas (um nível para cima no grafo)
+func (r *OwnershipRepository) ByChildren(ctx context.Context, ids []string) ([]models.Ownership, error) {
+	return r.find(ctx, bson.M{"child_id": bson.M{"$in": ids}}, options.Find())
+}
+
+func (r *OwnershipRepository) find(ctx context.Context, q bson.M, opts *options.FindOptions) ([]models.Ownership, error) {
+	cur, err := r.coll.Find(ctx, q, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []models.Ownership{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ShareTotal: soma dos percentuais das controladoras de childID, exceto exceptParentID
+func (r *OwnershipRepository) ShareTotal(ctx context.Context, childID, exceptParentID string) (float64, error) {
+	cur, err := r.coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"child_id": childID, "parent_id": bson.M{"$ne": exceptParentID}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$percentual"}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close(ctx)
+
+	var out []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := cur.All(ctx, &out); err != nil || len(out) == 0 {
+		return 0, err
+	}
+	return out[0].Total, nil
+}
+
+func (r *OwnershipRepository) Get(ctx context.Context, parentID, childID string) (*models.Ownership, error) {
+	var o models.Ownership
+	err := r.coll.FindOne(ctx, bson.M{"parent_id": parentID, "child_id": childID}).Decode(&o)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrOwnershipNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *OwnershipRepository) Create(ctx context.Context, o *models.Ownership) error {
+	o.ID = primitive.NewObjectID().Hex()
+	o.CreatedAt = time.Now()
+	o.UpdatedAt = o.CreatedAt
+	_, err := r.coll.InsertOne(ctx, o)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateOwnership
+	}
+	return err
+}
+
+// Replace grava a aresta inteira (created_at preservado por quem chama)
+func (r *OwnershipRepository) Replace(ctx context.Context, o *models.Ownership) error {
+	o.UpdatedAt = time.Now()
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"parent_id": o.ParentID, "child_id": o.ChildID}, o)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrOwnershipNotFound
+	}
+	return nil
+}
+
+func (r *OwnershipRepository) Delete(ctx context.Context, parentID, childID string) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"parent_id": parentID, "child_id": childID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrOwnershipNotFound
+	}
+	return nil
+}
+
+// DeleteByCompany remove as arestas em que a empresa é controladora ou controlada (exclusão da empresa)
+func (r *OwnershipRepository) DeleteByCompany(ctx context.Context, companyID string) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"$or": bson.A{bson.M{"parent_id": companyID}, bson.M{"child_id": companyID}}})
+	return err
+}
+
+// MoveToCompany passa as arestas de from para to (fusão de empresas). Aresta que
+// já existe em to ou que viraria participação de to nela mesma é descartada (e
+// devolvida). A colisão é conferida antes de gravar: dentro de uma transação, o
+// erro de chave duplicada abortaria a fusão inteira.
+func (r *OwnershipRepository) MoveToCompany(ctx context.Context, from, to string) (int, []models.Ownership, error) {
+	list, err := r.find(ctx, bson.M{"$or": bson.A{bson.M{"parent_id": from}, bson.M{"child_id": from}}}, options.Find())
+	if err != nil {
+		return 0, nil, err
+	}
+	moved := 0
+	var dropped []models.Ownership
+	for _, o := range list {
+		parent, child := o.ParentID, o.ChildID
+		if parent == from {
+			parent = to
+		}
+		if child == from {
+			child = to
+		}
+		if parent != child {
+			n, err := r.coll.CountDocuments(ctx, bson.M{"parent_id": parent, "child_id": child})
+			if err != nil {
+				return moved, dropped, err
+			}
+			if n == 0 {
+				_, err := r.coll.UpdateOne(ctx, bson.M{"_id": o.ID},
+					bson.M{"$set": bson.M{"parent_id": parent, "child_id": child, "updated_at": time.Now()}})
+				if err != nil {
+					return moved, dropped, err
+				}
+				moved++
+				continue
+			}
+		}
+		if _, err := r.coll.DeleteOne(ctx, bson.M{"_id": o.ID}); err != nil {
+			return moved, dropped, err
+		}
+		dropped = append(dropped, o)
+	}
+	return moved, dropped, nil
+}
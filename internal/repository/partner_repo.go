@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrPartnerNotFound  = errors.New("partner not found")
+	ErrDuplicatePartner = errors.New("partner document already exists in company")
+)
+
+// Sócios das empresas (coleção partners, um documento por sócio de cada empresa).
+type PartnerRepository struct {
+	coll *mongo.Collection
+}
+
+func NewPartnerRepository(db *mongo.Database) *PartnerRepository {
+	return &PartnerRepository{coll: db.Collection("partners")}
+}
+
+func (r *PartnerRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "documento", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("uniq_company_documento"),
+		},
+		{
+			// consulta reversa: empresas de um CPF/CNPJ
+			Keys:    bson.D{{Key: "documento", Value: 1}, {Key: "company_id", Value: 1}},
+			Options: options.Index().SetName("documento_company"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("partners indexes: %w", err)
+	}
+	return nil
+}
+
+func (r *PartnerRepository) List(ctx context.Context, companyID string, limit, skip int64) ([]models.Partner, error) {
+	return r.find(ctx, bson.M{"company_id": companyID}, limit, skip,
+		bson.D{{Key: "nome", Value: 1}, {Key: "_id", Value: 1}})
+}
+
+// ByDocument: participações de um CPF/CNPJ, em todas as empresas
+func (r *PartnerRepository) ByDocument(ctx context.Context, documento string, limit, skip int64) ([]models.Partner, error) {
+	return r.find(ctx, bson.M{"documento": documento}, limit, skip,
+		bson.D{{Key: "company_id", Value: 1}})
+}
+
+func (r *PartnerRepository) find(ctx context.Context, q bson.M, limit, skip int64, sort bson.D) ([]models.Partner, error) {
+	cur, err := r.coll.Find(ctx, q, options.Find().SetLimit(limit).SetSkip(skip).SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []models.Partner{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ShareTotal: soma de percentual_capital dos sócios da empresa, exceto exceptID
+func (r *PartnerRepository) ShareTotal(ctx context.Context, companyID, exceptID string) (float64, error) {
+	cur, err := r.coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"company_id": companyID, "_id": bson.M{"$ne": exceptID}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$percentual_capital"}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close(ctx)
+
+	var out []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := cur.All(ctx, &out); err != nil || len(out) == 0 {
+		return 0, err
+	}
+	return out[0].Total, nil
+}
+
+func (r *PartnerRepository) Get(ctx context.Context, companyID, id string) (*models.Partner, error) {
+	var p models.Partner
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "company_id": companyID}).Decode(&p)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrPartnerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PartnerRepository) Create(ctx context.Context, p *models.Partner) error {
+	p.ID = primitive.NewObjectID().Hex()
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = p.CreatedAt
+	_, err := r.coll.InsertOne(ctx, p)
+	return duplicatePartner(err)
+}
+
+// Replace grava o sócio inteiro (created_at preservado por quem chama)
+func (r *PartnerRepository) Replace(ctx context.Context, p *models.Partner) error {
+	p.UpdatedAt = time.Now()
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": p.ID, "company_id": p.CompanyID}, p)
+	if err != nil {
+		return duplicatePartner(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrPartnerNotFound
+	}
+	return nil
+}
+
+func (r *PartnerRepository) Delete(ctx context.Context, companyID, id string) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id, "company_id": companyID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrPartnerNotFound
+	}
+	return nil
+}
+
+// DeleteByCompany remove o QSA da empresa (exclusão da empresa)
+func (r *PartnerRepository) DeleteByCompany(ctx context.Context, companyID string) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"company_id": companyID})
+	return err
+}
+
+// MoveToCompany passa o QSA de from para to (fusão de empresas). Sócio (documento)
+// que já consta em to fica como está; o registro de from é descartado (e devolvido).
+func (r *PartnerRepository) MoveToCompany(ctx context.Context, from, to string) (int, []models.Partner, error) {
+	return moveByKey[models.Partner](ctx, r.coll, "documento", from, to)
+}
+
+func duplicatePartner(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicatePartner
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor roda operações de vários repositórios numa transação do Mongo.
+// Transação exige replica set ou mongos; num standalone (ex.: o docker-compose
+// de desenvolvimento) as operações rodam sem ela.
+type Transactor struct {
+	client *mongo.Client
+
+	mu        sync.Mutex
+	checked   bool
+	supported bool
+}
+
+func NewTransactor(client *mongo.Client) *Transactor {
+	return &Transactor{client: client}
+}
+
+// WithTransaction chama fn com um ctx que carrega a sessão: os repositórios que
+// usam esse ctx entram na transação. fn pode ser chamada de novo em erro transitório.
+func (t *Transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !t.isSupported(ctx) {
+		return fn(ctx)
+	}
+	sess, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// isSupported consulta o hello até a primeira resposta: replica set (setName)
+// ou mongos (isdbgrid). Com o hello falhando, roda sem transação e tenta de novo depois.
+func (t *Transactor) isSupported(ctx context.Context) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.checked {
+		var hello struct {
+			SetName string `bson:"setName"`
+			Msg     string `bson:"msg"`
+		}
+		if err := t.client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+			return false
+		}
+		t.checked, t.supported = true, hello.SetName != "" || hello.Msg == "isdbgrid"
+	}
+	return t.supported
+}
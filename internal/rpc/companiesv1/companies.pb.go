@@ -0,0 +1,1092 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.28.3
+// source: companies/v1/companies.proto
+
+// Cadastro de empresas via gRPC (uso interno entre serviços Go).
+// Mesmas regras da API REST: validação pelos JSON Schemas da /api/v2,
+// cálculo de PCD e eventos publicados no RabbitMQ.
+//
+// Para regenerar o código Go (internal/rpc/companiesv1):
+//
+//	protoc -I proto \
+//	  --go_out=. --go_opt=module=github.com/Werneck0live/cadastro-empresa \
+//	  --go-grpc_out=. --go-grpc_opt=module=github.com/Werneck0live/cadastro-empresa \
+//	  proto/companies/v1/companies.proto
+
+package companiesv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Action int32
+
+const (
+	Action_ACTION_UNSPECIFIED Action = 0
+	Action_ACTION_CREATED     Action = 1
+	Action_ACTION_UPDATED     Action = 2
+	Action_ACTION_DELETED     Action = 3
+)
+
+// Enum value maps for Action.
+var (
+	Action_name = map[int32]string{
+		0: "ACTION_UNSPECIFIED",
+		1: "ACTION_CREATED",
+		2: "ACTION_UPDATED",
+		3: "ACTION_DELETED",
+	}
+	Action_value = map[string]int32{
+		"ACTION_UNSPECIFIED": 0,
+		"ACTION_CREATED":     1,
+		"ACTION_UPDATED":     2,
+		"ACTION_DELETED":     3,
+	}
+)
+
+func (x Action) Enum() *Action {
+	p := new(Action)
+	*p = x
+	return p
+}
+
+func (x Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_companies_v1_companies_proto_enumTypes[0].Descriptor()
+}
+
+func (Action) Type() protoreflect.EnumType {
+	return &file_companies_v1_companies_proto_enumTypes[0]
+}
+
+func (x Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Action.Descriptor instead.
+func (Action) EnumDescriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{0}
+}
+
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Logradouro    string                 `protobuf:"bytes,1,opt,name=logradouro,proto3" json:"logradouro,omitempty"`
+	Numero        string                 `protobuf:"bytes,2,opt,name=numero,proto3" json:"numero,omitempty"`
+	Complemento   string                 `protobuf:"bytes,3,opt,name=complemento,proto3" json:"complemento,omitempty"`
+	Bairro        string                 `protobuf:"bytes,4,opt,name=bairro,proto3" json:"bairro,omitempty"`
+	Municipio     string                 `protobuf:"bytes,5,opt,name=municipio,proto3" json:"municipio,omitempty"`
+	Uf            string                 `protobuf:"bytes,6,opt,name=uf,proto3" json:"uf,omitempty"`
+	Cep           string                 `protobuf:"bytes,7,opt,name=cep,proto3" json:"cep,omitempty"` // na resposta: só dígitos
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_companies_v1_companies_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Address) GetLogradouro() string {
+	if x != nil {
+		return x.Logradouro
+	}
+	return ""
+}
+
+func (x *Address) GetNumero() string {
+	if x != nil {
+		return x.Numero
+	}
+	return ""
+}
+
+func (x *Address) GetComplemento() string {
+	if x != nil {
+		return x.Complemento
+	}
+	return ""
+}
+
+func (x *Address) GetBairro() string {
+	if x != nil {
+		return x.Bairro
+	}
+	return ""
+}
+
+func (x *Address) GetMunicipio() string {
+	if x != nil {
+		return x.Municipio
+	}
+	return ""
+}
+
+func (x *Address) GetUf() string {
+	if x != nil {
+		return x.Uf
+	}
+	return ""
+}
+
+func (x *Address) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+type Company struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Id                      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Cnpj                    string                 `protobuf:"bytes,2,opt,name=cnpj,proto3" json:"cnpj,omitempty"` // só dígitos
+	NomeFantasia            string                 `protobuf:"bytes,3,opt,name=nome_fantasia,json=nomeFantasia,proto3" json:"nome_fantasia,omitempty"`
+	RazaoSocial             string                 `protobuf:"bytes,4,opt,name=razao_social,json=razaoSocial,proto3" json:"razao_social,omitempty"`
+	Endereco                string                 `protobuf:"bytes,5,opt,name=endereco,proto3" json:"endereco,omitempty"` // texto livre (o mesmo da API v1)
+	EnderecoEstruturado     *Address               `protobuf:"bytes,6,opt,name=endereco_estruturado,json=enderecoEstruturado,proto3" json:"endereco_estruturado,omitempty"`
+	NumeroFuncionarios      int32                  `protobuf:"varint,7,opt,name=numero_funcionarios,json=numeroFuncionarios,proto3" json:"numero_funcionarios,omitempty"`
+	NumeroMinimoPcdExigidos int32                  `protobuf:"varint,8,opt,name=numero_minimo_pcd_exigidos,json=numeroMinimoPcdExigidos,proto3" json:"numero_minimo_pcd_exigidos,omitempty"`
+	CreatedAt               *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt               *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *Company) Reset() {
+	*x = Company{}
+	mi := &file_companies_v1_companies_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Company) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Company) ProtoMessage() {}
+
+func (x *Company) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Company.ProtoReflect.Descriptor instead.
+func (*Company) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Company) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Company) GetCnpj() string {
+	if x != nil {
+		return x.Cnpj
+	}
+	return ""
+}
+
+func (x *Company) GetNomeFantasia() string {
+	if x != nil {
+		return x.NomeFantasia
+	}
+	return ""
+}
+
+func (x *Company) GetRazaoSocial() string {
+	if x != nil {
+		return x.RazaoSocial
+	}
+	return ""
+}
+
+func (x *Company) GetEndereco() string {
+	if x != nil {
+		return x.Endereco
+	}
+	return ""
+}
+
+func (x *Company) GetEnderecoEstruturado() *Address {
+	if x != nil {
+		return x.EnderecoEstruturado
+	}
+	return nil
+}
+
+func (x *Company) GetNumeroFuncionarios() int32 {
+	if x != nil {
+		return x.NumeroFuncionarios
+	}
+	return 0
+}
+
+func (x *Company) GetNumeroMinimoPcdExigidos() int32 {
+	if x != nil {
+		return x.NumeroMinimoPcdExigidos
+	}
+	return 0
+}
+
+func (x *Company) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Company) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Cnpj               string                 `protobuf:"bytes,1,opt,name=cnpj,proto3" json:"cnpj,omitempty"` // com ou sem máscara
+	NomeFantasia       string                 `protobuf:"bytes,2,opt,name=nome_fantasia,json=nomeFantasia,proto3" json:"nome_fantasia,omitempty"`
+	RazaoSocial        string                 `protobuf:"bytes,3,opt,name=razao_social,json=razaoSocial,proto3" json:"razao_social,omitempty"`
+	Endereco           *Address               `protobuf:"bytes,4,opt,name=endereco,proto3" json:"endereco,omitempty"`
+	NumeroFuncionarios int32                  `protobuf:"varint,5,opt,name=numero_funcionarios,json=numeroFuncionarios,proto3" json:"numero_funcionarios,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	mi := &file_companies_v1_companies_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateRequest) GetCnpj() string {
+	if x != nil {
+		return x.Cnpj
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetNomeFantasia() string {
+	if x != nil {
+		return x.NomeFantasia
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetRazaoSocial() string {
+	if x != nil {
+		return x.RazaoSocial
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetEndereco() *Address {
+	if x != nil {
+		return x.Endereco
+	}
+	return nil
+}
+
+func (x *CreateRequest) GetNumeroFuncionarios() int32 {
+	if x != nil {
+		return x.NumeroFuncionarios
+	}
+	return 0
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_companies_v1_companies_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CompanyFilter struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Nome            string                 `protobuf:"bytes,1,opt,name=nome,proto3" json:"nome,omitempty"` // trecho do nome fantasia ou da razão social
+	CnpjPrefix      string                 `protobuf:"bytes,2,opt,name=cnpj_prefix,json=cnpjPrefix,proto3" json:"cnpj_prefix,omitempty"`
+	Uf              string                 `protobuf:"bytes,3,opt,name=uf,proto3" json:"uf,omitempty"`
+	MinFuncionarios *int32                 `protobuf:"varint,4,opt,name=min_funcionarios,json=minFuncionarios,proto3,oneof" json:"min_funcionarios,omitempty"`
+	MaxFuncionarios *int32                 `protobuf:"varint,5,opt,name=max_funcionarios,json=maxFuncionarios,proto3,oneof" json:"max_funcionarios,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CompanyFilter) Reset() {
+	*x = CompanyFilter{}
+	mi := &file_companies_v1_companies_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompanyFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompanyFilter) ProtoMessage() {}
+
+func (x *CompanyFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompanyFilter.ProtoReflect.Descriptor instead.
+func (*CompanyFilter) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CompanyFilter) GetNome() string {
+	if x != nil {
+		return x.Nome
+	}
+	return ""
+}
+
+func (x *CompanyFilter) GetCnpjPrefix() string {
+	if x != nil {
+		return x.CnpjPrefix
+	}
+	return ""
+}
+
+func (x *CompanyFilter) GetUf() string {
+	if x != nil {
+		return x.Uf
+	}
+	return ""
+}
+
+func (x *CompanyFilter) GetMinFuncionarios() int32 {
+	if x != nil && x.MinFuncionarios != nil {
+		return *x.MinFuncionarios
+	}
+	return 0
+}
+
+func (x *CompanyFilter) GetMaxFuncionarios() int32 {
+	if x != nil && x.MaxFuncionarios != nil {
+		return *x.MaxFuncionarios
+	}
+	return 0
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *CompanyFilter         `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Skip          int64                  `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_companies_v1_companies_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListRequest) GetFilter() *CompanyFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRequest) GetSkip() int64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+type UpdateRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Cnpj               *string                `protobuf:"bytes,2,opt,name=cnpj,proto3,oneof" json:"cnpj,omitempty"`
+	NomeFantasia       *string                `protobuf:"bytes,3,opt,name=nome_fantasia,json=nomeFantasia,proto3,oneof" json:"nome_fantasia,omitempty"`
+	RazaoSocial        *string                `protobuf:"bytes,4,opt,name=razao_social,json=razaoSocial,proto3,oneof" json:"razao_social,omitempty"`
+	Endereco           *Address               `protobuf:"bytes,5,opt,name=endereco,proto3" json:"endereco,omitempty"`
+	NumeroFuncionarios *int32                 `protobuf:"varint,6,opt,name=numero_funcionarios,json=numeroFuncionarios,proto3,oneof" json:"numero_funcionarios,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_companies_v1_companies_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetCnpj() string {
+	if x != nil && x.Cnpj != nil {
+		return *x.Cnpj
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetNomeFantasia() string {
+	if x != nil && x.NomeFantasia != nil {
+		return *x.NomeFantasia
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetRazaoSocial() string {
+	if x != nil && x.RazaoSocial != nil {
+		return *x.RazaoSocial
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetEndereco() *Address {
+	if x != nil {
+		return x.Endereco
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetNumeroFuncionarios() int32 {
+	if x != nil && x.NumeroFuncionarios != nil {
+		return *x.NumeroFuncionarios
+	}
+	return 0
+}
+
+type ReplaceRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Cnpj               string                 `protobuf:"bytes,2,opt,name=cnpj,proto3" json:"cnpj,omitempty"` // opcional; se vier, deve ser o mesmo do id
+	NomeFantasia       string                 `protobuf:"bytes,3,opt,name=nome_fantasia,json=nomeFantasia,proto3" json:"nome_fantasia,omitempty"`
+	RazaoSocial        string                 `protobuf:"bytes,4,opt,name=razao_social,json=razaoSocial,proto3" json:"razao_social,omitempty"`
+	Endereco           *Address               `protobuf:"bytes,5,opt,name=endereco,proto3" json:"endereco,omitempty"`
+	NumeroFuncionarios int32                  `protobuf:"varint,6,opt,name=numero_funcionarios,json=numeroFuncionarios,proto3" json:"numero_funcionarios,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ReplaceRequest) Reset() {
+	*x = ReplaceRequest{}
+	mi := &file_companies_v1_companies_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceRequest) ProtoMessage() {}
+
+func (x *ReplaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceRequest.ProtoReflect.Descriptor instead.
+func (*ReplaceRequest) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReplaceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReplaceRequest) GetCnpj() string {
+	if x != nil {
+		return x.Cnpj
+	}
+	return ""
+}
+
+func (x *ReplaceRequest) GetNomeFantasia() string {
+	if x != nil {
+		return x.NomeFantasia
+	}
+	return ""
+}
+
+func (x *ReplaceRequest) GetRazaoSocial() string {
+	if x != nil {
+		return x.RazaoSocial
+	}
+	return ""
+}
+
+func (x *ReplaceRequest) GetEndereco() *Address {
+	if x != nil {
+		return x.Endereco
+	}
+	return nil
+}
+
+func (x *ReplaceRequest) GetNumeroFuncionarios() int32 {
+	if x != nil {
+		return x.NumeroFuncionarios
+	}
+	return 0
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_companies_v1_companies_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WatchEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Actions       []Action               `protobuf:"varint,1,rep,packed,name=actions,proto3,enum=cadastro.companies.v1.Action" json:"actions,omitempty"` // vazio = todas
+	CompanyId     string                 `protobuf:"bytes,2,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`                      // vazio = todas as empresas
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_companies_v1_companies_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchEventsRequest) GetActions() []Action {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *WatchEventsRequest) GetCompanyId() string {
+	if x != nil {
+		return x.CompanyId
+	}
+	return ""
+}
+
+type CompanyEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        Action                 `protobuf:"varint,1,opt,name=action,proto3,enum=cadastro.companies.v1.Action" json:"action,omitempty"`
+	CompanyId     string                 `protobuf:"bytes,2,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	Cnpj          string                 `protobuf:"bytes,3,opt,name=cnpj,proto3" json:"cnpj,omitempty"`
+	Nome          string                 `protobuf:"bytes,4,opt,name=nome,proto3" json:"nome,omitempty"`
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"` // texto do evento (o mesmo publicado no RabbitMQ)
+	Lang          string                 `protobuf:"bytes,6,opt,name=lang,proto3" json:"lang,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompanyEvent) Reset() {
+	*x = CompanyEvent{}
+	mi := &file_companies_v1_companies_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompanyEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompanyEvent) ProtoMessage() {}
+
+func (x *CompanyEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_companies_v1_companies_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompanyEvent.ProtoReflect.Descriptor instead.
+func (*CompanyEvent) Descriptor() ([]byte, []int) {
+	return file_companies_v1_companies_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CompanyEvent) GetAction() Action {
+	if x != nil {
+		return x.Action
+	}
+	return Action_ACTION_UNSPECIFIED
+}
+
+func (x *CompanyEvent) GetCompanyId() string {
+	if x != nil {
+		return x.CompanyId
+	}
+	return ""
+}
+
+func (x *CompanyEvent) GetCnpj() string {
+	if x != nil {
+		return x.Cnpj
+	}
+	return ""
+}
+
+func (x *CompanyEvent) GetNome() string {
+	if x != nil {
+		return x.Nome
+	}
+	return ""
+}
+
+func (x *CompanyEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CompanyEvent) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+func (x *CompanyEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+var File_companies_v1_companies_proto protoreflect.FileDescriptor
+
+const file_companies_v1_companies_proto_rawDesc = "" +
+	"\n" +
+	"\x1ccompanies/v1/companies.proto\x12\x15cadastro.companies.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbb\x01\n" +
+	"\aAddress\x12\x1e\n" +
+	"\n" +
+	"logradouro\x18\x01 \x01(\tR\n" +
+	"logradouro\x12\x16\n" +
+	"\x06numero\x18\x02 \x01(\tR\x06numero\x12 \n" +
+	"\vcomplemento\x18\x03 \x01(\tR\vcomplemento\x12\x16\n" +
+	"\x06bairro\x18\x04 \x01(\tR\x06bairro\x12\x1c\n" +
+	"\tmunicipio\x18\x05 \x01(\tR\tmunicipio\x12\x0e\n" +
+	"\x02uf\x18\x06 \x01(\tR\x02uf\x12\x10\n" +
+	"\x03cep\x18\a \x01(\tR\x03cep\"\xc8\x03\n" +
+	"\aCompany\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04cnpj\x18\x02 \x01(\tR\x04cnpj\x12#\n" +
+	"\rnome_fantasia\x18\x03 \x01(\tR\fnomeFantasia\x12!\n" +
+	"\frazao_social\x18\x04 \x01(\tR\vrazaoSocial\x12\x1a\n" +
+	"\bendereco\x18\x05 \x01(\tR\bendereco\x12Q\n" +
+	"\x14endereco_estruturado\x18\x06 \x01(\v2\x1e.cadastro.companies.v1.AddressR\x13enderecoEstruturado\x12/\n" +
+	"\x13numero_funcionarios\x18\a \x01(\x05R\x12numeroFuncionarios\x12;\n" +
+	"\x1anumero_minimo_pcd_exigidos\x18\b \x01(\x05R\x17numeroMinimoPcdExigidos\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xd8\x01\n" +
+	"\rCreateRequest\x12\x12\n" +
+	"\x04cnpj\x18\x01 \x01(\tR\x04cnpj\x12#\n" +
+	"\rnome_fantasia\x18\x02 \x01(\tR\fnomeFantasia\x12!\n" +
+	"\frazao_social\x18\x03 \x01(\tR\vrazaoSocial\x12:\n" +
+	"\bendereco\x18\x04 \x01(\v2\x1e.cadastro.companies.v1.AddressR\bendereco\x12/\n" +
+	"\x13numero_funcionarios\x18\x05 \x01(\x05R\x12numeroFuncionarios\"\x1c\n" +
+	"\n" +
+	"GetRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xde\x01\n" +
+	"\rCompanyFilter\x12\x12\n" +
+	"\x04nome\x18\x01 \x01(\tR\x04nome\x12\x1f\n" +
+	"\vcnpj_prefix\x18\x02 \x01(\tR\n" +
+	"cnpjPrefix\x12\x0e\n" +
+	"\x02uf\x18\x03 \x01(\tR\x02uf\x12.\n" +
+	"\x10min_funcionarios\x18\x04 \x01(\x05H\x00R\x0fminFuncionarios\x88\x01\x01\x12.\n" +
+	"\x10max_funcionarios\x18\x05 \x01(\x05H\x01R\x0fmaxFuncionarios\x88\x01\x01B\x13\n" +
+	"\x11_min_funcionariosB\x13\n" +
+	"\x11_max_funcionarios\"u\n" +
+	"\vListRequest\x12<\n" +
+	"\x06filter\x18\x01 \x01(\v2$.cadastro.companies.v1.CompanyFilterR\x06filter\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x12\n" +
+	"\x04skip\x18\x03 \x01(\x03R\x04skip\"\xc0\x02\n" +
+	"\rUpdateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\x04cnpj\x18\x02 \x01(\tH\x00R\x04cnpj\x88\x01\x01\x12(\n" +
+	"\rnome_fantasia\x18\x03 \x01(\tH\x01R\fnomeFantasia\x88\x01\x01\x12&\n" +
+	"\frazao_social\x18\x04 \x01(\tH\x02R\vrazaoSocial\x88\x01\x01\x12:\n" +
+	"\bendereco\x18\x05 \x01(\v2\x1e.cadastro.companies.v1.AddressR\bendereco\x124\n" +
+	"\x13numero_funcionarios\x18\x06 \x01(\x05H\x03R\x12numeroFuncionarios\x88\x01\x01B\a\n" +
+	"\x05_cnpjB\x10\n" +
+	"\x0e_nome_fantasiaB\x0f\n" +
+	"\r_razao_socialB\x16\n" +
+	"\x14_numero_funcionarios\"\xe9\x01\n" +
+	"\x0eReplaceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04cnpj\x18\x02 \x01(\tR\x04cnpj\x12#\n" +
+	"\rnome_fantasia\x18\x03 \x01(\tR\fnomeFantasia\x12!\n" +
+	"\frazao_social\x18\x04 \x01(\tR\vrazaoSocial\x12:\n" +
+	"\bendereco\x18\x05 \x01(\v2\x1e.cadastro.companies.v1.AddressR\bendereco\x12/\n" +
+	"\x13numero_funcionarios\x18\x06 \x01(\x05R\x12numeroFuncionarios\"\x1f\n" +
+	"\rDeleteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"l\n" +
+	"\x12WatchEventsRequest\x127\n" +
+	"\aactions\x18\x01 \x03(\x0e2\x1d.cadastro.companies.v1.ActionR\aactions\x12\x1d\n" +
+	"\n" +
+	"company_id\x18\x02 \x01(\tR\tcompanyId\"\xf4\x01\n" +
+	"\fCompanyEvent\x125\n" +
+	"\x06action\x18\x01 \x01(\x0e2\x1d.cadastro.companies.v1.ActionR\x06action\x12\x1d\n" +
+	"\n" +
+	"company_id\x18\x02 \x01(\tR\tcompanyId\x12\x12\n" +
+	"\x04cnpj\x18\x03 \x01(\tR\x04cnpj\x12\x12\n" +
+	"\x04nome\x18\x04 \x01(\tR\x04nome\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12\x12\n" +
+	"\x04lang\x18\x06 \x01(\tR\x04lang\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp*\\\n" +
+	"\x06Action\x12\x16\n" +
+	"\x12ACTION_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eACTION_CREATED\x10\x01\x12\x12\n" +
+	"\x0eACTION_UPDATED\x10\x02\x12\x12\n" +
+	"\x0eACTION_DELETED\x10\x032\xc6\x04\n" +
+	"\tCompanies\x12N\n" +
+	"\x06Create\x12$.cadastro.companies.v1.CreateRequest\x1a\x1e.cadastro.companies.v1.Company\x12H\n" +
+	"\x03Get\x12!.cadastro.companies.v1.GetRequest\x1a\x1e.cadastro.companies.v1.Company\x12L\n" +
+	"\x04List\x12\".cadastro.companies.v1.ListRequest\x1a\x1e.cadastro.companies.v1.Company0\x01\x12N\n" +
+	"\x06Update\x12$.cadastro.companies.v1.UpdateRequest\x1a\x1e.cadastro.companies.v1.Company\x12P\n" +
+	"\aReplace\x12%.cadastro.companies.v1.ReplaceRequest\x1a\x1e.cadastro.companies.v1.Company\x12N\n" +
+	"\x06Delete\x12$.cadastro.companies.v1.DeleteRequest\x1a\x1e.cadastro.companies.v1.Company\x12_\n" +
+	"\vWatchEvents\x12).cadastro.companies.v1.WatchEventsRequest\x1a#.cadastro.companies.v1.CompanyEvent0\x01BOZMgithub.com/Werneck0live/cadastro-empresa/internal/rpc/companiesv1;companiesv1b\x06proto3"
+
+var (
+	file_companies_v1_companies_proto_rawDescOnce sync.Once
+	file_companies_v1_companies_proto_rawDescData []byte
+)
+
+func file_companies_v1_companies_proto_rawDescGZIP() []byte {
+	file_companies_v1_companies_proto_rawDescOnce.Do(func() {
+		file_companies_v1_companies_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_companies_v1_companies_proto_rawDesc), len(file_companies_v1_companies_proto_rawDesc)))
+	})
+	return file_companies_v1_companies_proto_rawDescData
+}
+
+var file_companies_v1_companies_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_companies_v1_companies_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_companies_v1_companies_proto_goTypes = []any{
+	(Action)(0),                   // 0: cadastro.companies.v1.Action
+	(*Address)(nil),               // 1: cadastro.companies.v1.Address
+	(*Company)(nil),               // 2: cadastro.companies.v1.Company
+	(*CreateRequest)(nil),         // 3: cadastro.companies.v1.CreateRequest
+	(*GetRequest)(nil),            // 4: cadastro.companies.v1.GetRequest
+	(*CompanyFilter)(nil),         // 5: cadastro.companies.v1.CompanyFilter
+	(*ListRequest)(nil),           // 6: cadastro.companies.v1.ListRequest
+	(*UpdateRequest)(nil),         // 7: cadastro.companies.v1.UpdateRequest
+	(*ReplaceRequest)(nil),        // 8: cadastro.companies.v1.ReplaceRequest
+	(*DeleteRequest)(nil),         // 9: cadastro.companies.v1.DeleteRequest
+	(*WatchEventsRequest)(nil),    // 10: cadastro.companies.v1.WatchEventsRequest
+	(*CompanyEvent)(nil),          // 11: cadastro.companies.v1.CompanyEvent
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
+}
+var file_companies_v1_companies_proto_depIdxs = []int32{
+	1,  // 0: cadastro.companies.v1.Company.endereco_estruturado:type_name -> cadastro.companies.v1.Address
+	12, // 1: cadastro.companies.v1.Company.created_at:type_name -> google.protobuf.Timestamp
+	12, // 2: cadastro.companies.v1.Company.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: cadastro.companies.v1.CreateRequest.endereco:type_name -> cadastro.companies.v1.Address
+	5,  // 4: cadastro.companies.v1.ListRequest.filter:type_name -> cadastro.companies.v1.CompanyFilter
+	1,  // 5: cadastro.companies.v1.UpdateRequest.endereco:type_name -> cadastro.companies.v1.Address
+	1,  // 6: cadastro.companies.v1.ReplaceRequest.endereco:type_name -> cadastro.companies.v1.Address
+	0,  // 7: cadastro.companies.v1.WatchEventsRequest.actions:type_name -> cadastro.companies.v1.Action
+	0,  // 8: cadastro.companies.v1.CompanyEvent.action:type_name -> cadastro.companies.v1.Action
+	12, // 9: cadastro.companies.v1.CompanyEvent.timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 10: cadastro.companies.v1.Companies.Create:input_type -> cadastro.companies.v1.CreateRequest
+	4,  // 11: cadastro.companies.v1.Companies.Get:input_type -> cadastro.companies.v1.GetRequest
+	6,  // 12: cadastro.companies.v1.Companies.List:input_type -> cadastro.companies.v1.ListRequest
+	7,  // 13: cadastro.companies.v1.Companies.Update:input_type -> cadastro.companies.v1.UpdateRequest
+	8,  // 14: cadastro.companies.v1.Companies.Replace:input_type -> cadastro.companies.v1.ReplaceRequest
+	9,  // 15: cadastro.companies.v1.Companies.Delete:input_type -> cadastro.companies.v1.DeleteRequest
+	10, // 16: cadastro.companies.v1.Companies.WatchEvents:input_type -> cadastro.companies.v1.WatchEventsRequest
+	2,  // 17: cadastro.companies.v1.Companies.Create:output_type -> cadastro.companies.v1.Company
+	2,  // 18: cadastro.companies.v1.Companies.Get:output_type -> cadastro.companies.v1.Company
+	2,  // 19: cadastro.companies.v1.Companies.List:output_type -> cadastro.companies.v1.Company
+	2,  // 20: cadastro.companies.v1.Companies.Update:output_type -> cadastro.companies.v1.Company
+	2,  // 21: cadastro.companies.v1.Companies.Replace:output_type -> cadastro.companies.v1.Company
+	2,  // 22: cadastro.companies.v1.Companies.Delete:output_type -> cadastro.companies.v1.Company
+	11, // 23: cadastro.companies.v1.Companies.WatchEvents:output_type -> cadastro.companies.v1.CompanyEvent
+	17, // [17:24] is the sub-list for method output_type
+	10, // [10:17] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_companies_v1_companies_proto_init() }
+func file_companies_v1_companies_proto_init() {
+	if File_companies_v1_companies_proto != nil {
+		return
+	}
+	file_companies_v1_companies_proto_msgTypes[4].OneofWrappers = []any{}
+	file_companies_v1_companies_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_companies_v1_companies_proto_rawDesc), len(file_companies_v1_companies_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_companies_v1_companies_proto_goTypes,
+		DependencyIndexes: file_companies_v1_companies_proto_depIdxs,
+		EnumInfos:         file_companies_v1_companies_proto_enumTypes,
+		MessageInfos:      file_companies_v1_companies_proto_msgTypes,
+	}.Build()
+	File_companies_v1_companies_proto = out.File
+	file_companies_v1_companies_proto_goTypes = nil
+	file_companies_v1_companies_proto_depIdxs = nil
+}
@@ -0,0 +1,377 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: companies/v1/companies.proto
+
+// Cadastro de empresas via gRPC (uso interno entre serviços Go).
+// Mesmas regras da API REST: validação pelos JSON Schemas da /api/v2,
+// cálculo de PCD e eventos publicados no RabbitMQ.
+//
+// Para regenerar o código Go (internal/rpc/companiesv1):
+//
+//	protoc -I proto \
+//	  --go_out=. --go_opt=module=github.com/Werneck0live/cadastro-empresa \
+//	  --go-grpc_out=. --go-grpc_opt=module=github.com/Werneck0live/cadastro-empresa \
+//	  proto/companies/v1/companies.proto
+
+package companiesv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Companies_Create_FullMethodName      = "/cadastro.companies.v1.Companies/Create"
+	Companies_Get_FullMethodName         = "/cadastro.companies.v1.Companies/Get"
+	Companies_List_FullMethodName        = "/cadastro.companies.v1.Companies/List"
+	Companies_Update_FullMethodName      = "/cadastro.companies.v1.Companies/Update"
+	Companies_Replace_FullMethodName     = "/cadastro.companies.v1.Companies/Replace"
+	Companies_Delete_FullMethodName      = "/cadastro.companies.v1.Companies/Delete"
+	Companies_WatchEvents_FullMethodName = "/cadastro.companies.v1.Companies/WatchEvents"
+)
+
+// CompaniesClient is the client API for Companies service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CompaniesClient interface {
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Company, error)
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Company, error)
+	// Envia uma empresa por mensagem (mesma paginação da API: limit 1-200, padrão 50)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Company], error)
+	// Update parcial (PATCH): só os campos presentes mudam
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Company, error)
+	// Substitui o documento inteiro (PUT)
+	Replace(ctx context.Context, in *ReplaceRequest, opts ...grpc.CallOption) (*Company, error)
+	// Devolve a empresa removida
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Company, error)
+	// Eventos de cadastro/edição/exclusão feitos nesta instância da API
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CompanyEvent], error)
+}
+
+type companiesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCompaniesClient(cc grpc.ClientConnInterface) CompaniesClient {
+	return &companiesClient{cc}
+}
+
+func (c *companiesClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Company, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Company)
+	err := c.cc.Invoke(ctx, Companies_Create_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companiesClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Company, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Company)
+	err := c.cc.Invoke(ctx, Companies_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companiesClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Company], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Companies_ServiceDesc.Streams[0], Companies_List_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListRequest, Company]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Companies_ListClient = grpc.ServerStreamingClient[Company]
+
+func (c *companiesClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Company, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Company)
+	err := c.cc.Invoke(ctx, Companies_Update_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companiesClient) Replace(ctx context.Context, in *ReplaceRequest, opts ...grpc.CallOption) (*Company, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Company)
+	err := c.cc.Invoke(ctx, Companies_Replace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companiesClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Company, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Company)
+	err := c.cc.Invoke(ctx, Companies_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companiesClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CompanyEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Companies_ServiceDesc.Streams[1], Companies_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, CompanyEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Companies_WatchEventsClient = grpc.ServerStreamingClient[CompanyEvent]
+
+// CompaniesServer is the server API for Companies service.
+// All implementations must embed UnimplementedCompaniesServer
+// for forward compatibility.
+type CompaniesServer interface {
+	Create(context.Context, *CreateRequest) (*Company, error)
+	Get(context.Context, *GetRequest) (*Company, error)
+	// Envia uma empresa por mensagem (mesma paginação da API: limit 1-200, padrão 50)
+	List(*ListRequest, grpc.ServerStreamingServer[Company]) error
+	// Update parcial (PATCH): só os campos presentes mudam
+	Update(context.Context, *UpdateRequest) (*Company, error)
+	// Substitui o documento inteiro (PUT)
+	Replace(context.Context, *ReplaceRequest) (*Company, error)
+	// Devolve a empresa removida
+	Delete(context.Context, *DeleteRequest) (*Company, error)
+	// Eventos de cadastro/edição/exclusão feitos nesta instância da API
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[CompanyEvent]) error
+	mustEmbedUnimplementedCompaniesServer()
+}
+
+// UnimplementedCompaniesServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCompaniesServer struct{}
+
+func (UnimplementedCompaniesServer) Create(context.Context, *CreateRequest) (*Company, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedCompaniesServer) Get(context.Context, *GetRequest) (*Company, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedCompaniesServer) List(*ListRequest, grpc.ServerStreamingServer[Company]) error {
+	return status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedCompaniesServer) Update(context.Context, *UpdateRequest) (*Company, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedCompaniesServer) Replace(context.Context, *ReplaceRequest) (*Company, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Replace not implemented")
+}
+func (UnimplementedCompaniesServer) Delete(context.Context, *DeleteRequest) (*Company, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedCompaniesServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[CompanyEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedCompaniesServer) mustEmbedUnimplementedCompaniesServer() {}
+func (UnimplementedCompaniesServer) testEmbeddedByValue()                   {}
+
+// UnsafeCompaniesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CompaniesServer will
+// result in compilation errors.
+type UnsafeCompaniesServer interface {
+	mustEmbedUnimplementedCompaniesServer()
+}
+
+func RegisterCompaniesServer(s grpc.ServiceRegistrar, srv CompaniesServer) {
+	// If the following call pancis, it indicates UnimplementedCompaniesServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Companies_ServiceDesc, srv)
+}
+
+func _Companies_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompaniesServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Companies_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompaniesServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Companies_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompaniesServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Companies_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompaniesServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Companies_List_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CompaniesServer).List(m, &grpc.GenericServerStream[ListRequest, Company]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Companies_ListServer = grpc.ServerStreamingServer[Company]
+
+func _Companies_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompaniesServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Companies_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompaniesServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Companies_Replace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompaniesServer).Replace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Companies_Replace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompaniesServer).Replace(ctx, req.(*ReplaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Companies_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompaniesServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Companies_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompaniesServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Companies_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CompaniesServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, CompanyEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Companies_WatchEventsServer = grpc.ServerStreamingServer[CompanyEvent]
+
+// Companies_ServiceDesc is the grpc.ServiceDesc for Companies service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Companies_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cadastro.companies.v1.Companies",
+	HandlerType: (*CompaniesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _Companies_Create_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _Companies_Get_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Companies_Update_Handler,
+		},
+		{
+			MethodName: "Replace",
+			Handler:    _Companies_Replace_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Companies_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "List",
+			Handler:       _Companies_List_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _Companies_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "companies/v1/companies.proto",
+}
@@ -0,0 +1,125 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Erros no formato gRPC: status code + detalhes
+//   - ErrorInfo.Reason: o mesmo code do problem+json da API REST (validation_failed, not_found...)
+//   - BadRequest.FieldViolations: os mesmos errors[] (field, message) da REST
+//
+// Os textos seguem o metadata "accept-language".
+
+const errorDomain = "cadastro-empresa"
+
+var grpcCodes = map[string]codes.Code{
+	utils.CodeValidationFailed: codes.InvalidArgument,
+	utils.CodeBadRequest:       codes.InvalidArgument,
+	utils.CodeNotFound:         codes.NotFound,
+	utils.CodeCNPJConflict:     codes.AlreadyExists,
+	utils.CodeApprovalRequired: codes.FailedPrecondition,
+	utils.CodeInternalError:    codes.Internal,
+}
+
+var errEventsDisabled = errors.New("event stream disabled")
+
+func langFrom(ctx context.Context) i18n.Lang {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("accept-language"); len(v) > 0 {
+			return i18n.FromAcceptLanguage(strings.Join(v, ","), i18n.DefaultHTTPLang)
+		}
+	}
+	return i18n.DefaultHTTPLang
+}
+
+// withUser: usuário do metadata "x-user" (operações que podem exigir aprovação)
+func withUser(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("x-user"); len(v) > 0 {
+			return service.WithUser(ctx, strings.TrimSpace(v[0]))
+		}
+	}
+	return ctx
+}
+
+// texto padrão do code (detail do catálogo; sem ele, o title); cause != nil usa a mensagem dele
+func newStatus(ctx context.Context, code string, cause error) *status.Status {
+	msg := ""
+	if cause != nil {
+		msg = cause.Error()
+	} else {
+		lang := langFrom(ctx)
+		msg = i18n.T(lang, "problem."+code+".detail")
+		if msg == "problem."+code+".detail" {
+			msg = i18n.T(lang, "problem."+code)
+		}
+	}
+	c, ok := grpcCodes[code]
+	if !ok {
+		c = codes.Unknown
+	}
+	st := status.New(c, msg)
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: code, Domain: errorDomain}); err == nil {
+		st = withInfo
+	}
+	return st
+}
+
+func validationError(ctx context.Context, errs []utils.FieldError) error {
+	lang := langFrom(ctx)
+	br := &errdetails.BadRequest{}
+	for _, e := range errs {
+		msg := e.Message
+		if msg == "" {
+			msg = i18n.T(lang, "field."+e.Code, append([]any{e.Field}, e.Args...)...)
+		}
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       e.Field,
+			Description: msg,
+			Reason:      e.Code,
+		})
+	}
+	st := newStatus(ctx, utils.CodeValidationFailed, nil)
+	if withFields, err := st.WithDetails(br); err == nil {
+		st = withFields
+	}
+	return st.Err()
+}
+
+func serviceError(ctx context.Context, err error) error {
+	var cf *service.CustomFieldsError
+	var pending *service.ApprovalRequiredError
+	switch {
+	case errors.As(err, &pending):
+		// a mudança não foi aplicada: virou um pedido de aprovação (/api/change-requests)
+		st := status.New(grpcCodes[utils.CodeApprovalRequired], newStatus(ctx, utils.CodeApprovalRequired, nil).Message())
+		info := &errdetails.ErrorInfo{Reason: utils.CodeApprovalRequired, Domain: errorDomain,
+			Metadata: map[string]string{"change_request_id": pending.Request.ID}}
+		if withInfo, err := st.WithDetails(info); err == nil {
+			st = withInfo
+		}
+		return st.Err()
+	case errors.Is(err, service.ErrUserRequired):
+		return validationError(ctx, []utils.FieldError{{Field: "x-user", Code: utils.FieldRequired}})
+	case errors.Is(err, service.ErrNotFound):
+		return newStatus(ctx, utils.CodeNotFound, nil).Err()
+	case errors.Is(err, repository.ErrDuplicateCNPJ):
+		return newStatus(ctx, utils.CodeCNPJConflict, nil).Err()
+	case errors.As(err, &cf):
+		return validationError(ctx, cf.Errors)
+	default:
+		return newStatus(ctx, utils.CodeInternalError, err).Err()
+	}
+}
@@ -0,0 +1,319 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	pb "github.com/Werneck0live/cadastro-empresa/internal/rpc/companiesv1"
+	"github.com/Werneck0live/cadastro-empresa/internal/schema"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Server implementa o serviço gRPC Companies (proto/companies/v1/companies.proto).
+// Mesmas regras da API REST (service.Companies) e mesmos JSON Schemas da /api/v2
+// para validar as requisições.
+type Server struct {
+	pb.UnimplementedCompaniesServer
+
+	Svc *service.Companies
+	Bus *events.Bus // nil = WatchEvents indisponível
+}
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+func (s *Server) Register(gs *grpc.Server) {
+	pb.RegisterCompaniesServer(gs, s)
+}
+
+func (s *Server) Create(ctx context.Context, req *pb.CreateRequest) (*pb.Company, error) {
+	in, err := decodeInput(ctx, schema.CompanyCreateV2, payload{
+		CNPJ:               optString(req.GetCnpj()),
+		NomeFantasia:       optString(req.GetNomeFantasia()),
+		RazaoSocial:        optString(req.GetRazaoSocial()),
+		Endereco:           addressFromPB(req.GetEndereco()),
+		NumeroFuncionarios: &req.NumeroFuncionarios,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.Svc.Create(ctx, service.CompanyInput{
+		CNPJ:                deref(in.CNPJ),
+		NomeFantasia:        deref(in.NomeFantasia),
+		RazaoSocial:         deref(in.RazaoSocial),
+		EnderecoEstruturado: in.Endereco,
+		NumeroFuncionarios:  int(deref(in.NumeroFuncionarios)),
+	})
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+	return toPB(c), nil
+}
+
+func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (*pb.Company, error) {
+	c, err := s.Svc.Get(ctx, utils.SanitizeCNPJ(req.GetId()))
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+	return toPB(c), nil
+}
+
+func (s *Server) List(req *pb.ListRequest, stream grpc.ServerStreamingServer[pb.Company]) error {
+	ctx := stream.Context()
+	limit, skip := req.GetLimit(), req.GetSkip()
+	if limit <= 0 || limit > maxLimit {
+		limit = defaultLimit
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	var f models.CompanyFilter
+	if pf := req.GetFilter(); pf != nil {
+		f.Nome = pf.GetNome()
+		f.CNPJPrefix = utils.SanitizeCNPJ(pf.GetCnpjPrefix())
+		f.UF = pf.GetUf()
+		if pf.MinFuncionarios != nil {
+			v := int(pf.GetMinFuncionarios())
+			f.MinFuncionarios = &v
+		}
+		if pf.MaxFuncionarios != nil {
+			v := int(pf.GetMaxFuncionarios())
+			f.MaxFuncionarios = &v
+		}
+	}
+
+	list, err := s.Svc.List(ctx, f, limit, skip)
+	if err != nil {
+		return newStatus(ctx, utils.CodeInternalError, err).Err()
+	}
+	for i := range list {
+		if err := stream.Send(toPB(&list[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.Company, error) {
+	in, err := decodeInput(ctx, schema.CompanyPatchV2, payload{
+		CNPJ:               req.Cnpj,
+		NomeFantasia:       req.NomeFantasia,
+		RazaoSocial:        req.RazaoSocial,
+		Endereco:           addressFromPB(req.GetEndereco()),
+		NumeroFuncionarios: req.NumeroFuncionarios,
+	})
+	if err != nil {
+		return nil, err
+	}
+	p := service.CompanyPatch{
+		CNPJ:                in.CNPJ,
+		NomeFantasia:        in.NomeFantasia,
+		RazaoSocial:         in.RazaoSocial,
+		EnderecoEstruturado: in.Endereco,
+	}
+	if in.NumeroFuncionarios != nil {
+		n := int(*in.NumeroFuncionarios)
+		p.NumeroFuncionarios = &n
+	}
+	c, err := s.Svc.Patch(withUser(ctx), utils.SanitizeCNPJ(req.GetId()), p)
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+	if c == nil {
+		return nil, newStatus(ctx, utils.CodeNotFound, nil).Err()
+	}
+	return toPB(c), nil
+}
+
+func (s *Server) Replace(ctx context.Context, req *pb.ReplaceRequest) (*pb.Company, error) {
+	id := utils.SanitizeCNPJ(req.GetId())
+	in, err := decodeInput(ctx, schema.CompanyPutV2, payload{
+		CNPJ:               optString(req.GetCnpj()),
+		NomeFantasia:       optString(req.GetNomeFantasia()),
+		RazaoSocial:        optString(req.GetRazaoSocial()),
+		Endereco:           addressFromPB(req.GetEndereco()),
+		NumeroFuncionarios: &req.NumeroFuncionarios,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// mesma regra do PUT: cnpj, se vier, deve ser o id
+	if in.CNPJ != nil && utils.SanitizeCNPJ(*in.CNPJ) != id {
+		return nil, validationError(ctx, []utils.FieldError{{Field: "cnpj", Code: utils.FieldMismatch}})
+	}
+	c, err := s.Svc.Replace(withUser(ctx), id, service.CompanyInput{
+		NomeFantasia:        deref(in.NomeFantasia),
+		RazaoSocial:         deref(in.RazaoSocial),
+		EnderecoEstruturado: in.Endereco,
+		NumeroFuncionarios:  int(deref(in.NumeroFuncionarios)),
+		KeepExtras:          true, // CNAEs, IE/IM, tags etc. não estão no ReplaceRequest
+	})
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+	return toPB(c), nil
+}
+
+func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.Company, error) {
+	c, err := s.Svc.Delete(withUser(ctx), utils.SanitizeCNPJ(req.GetId()))
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+	return toPB(c), nil
+}
+
+// ---------- eventos
+
+var actionsToPB = map[string]pb.Action{
+	events.ActionCreated: pb.Action_ACTION_CREATED,
+	events.ActionUpdated: pb.Action_ACTION_UPDATED,
+	events.ActionDeleted: pb.Action_ACTION_DELETED,
+}
+
+func (s *Server) WatchEvents(req *pb.WatchEventsRequest, stream grpc.ServerStreamingServer[pb.CompanyEvent]) error {
+	ctx := stream.Context()
+	if s.Bus == nil {
+		return status.Error(codes.Unavailable, errEventsDisabled.Error())
+	}
+
+	actions := map[pb.Action]bool{}
+	for _, a := range req.GetActions() {
+		if a != pb.Action_ACTION_UNSPECIFIED {
+			actions[a] = true
+		}
+	}
+	companyID := utils.SanitizeCNPJ(req.GetCompanyId())
+
+	evs, cancel := s.Bus.Subscribe(64)
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-evs:
+			if !ok {
+				return nil // bus fechado (shutdown)
+			}
+			action, ok := actionsToPB[ev.Action]
+			if !ok {
+				continue // ex.: cota_pcd, sem valor no enum do proto
+			}
+			if len(actions) > 0 && !actions[action] {
+				continue
+			}
+			if companyID != "" && ev.CompanyID != companyID {
+				continue
+			}
+			err := stream.Send(&pb.CompanyEvent{
+				Action:    action,
+				CompanyId: ev.CompanyID,
+				Cnpj:      ev.CNPJ,
+				Nome:      ev.Nome,
+				Message:   ev.Message,
+				Lang:      ev.Lang,
+				Timestamp: timestamppb.New(ev.Timestamp),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ---------- conversões
+
+// payload no formato JSON da /api/v2 (snake_case), para reaproveitar os JSON Schemas
+type payload struct {
+	CNPJ               *string         `json:"cnpj,omitempty"`
+	NomeFantasia       *string         `json:"nome_fantasia,omitempty"`
+	RazaoSocial        *string         `json:"razao_social,omitempty"`
+	Endereco           *models.Address `json:"endereco,omitempty"`
+	NumeroFuncionarios *int32          `json:"numero_funcionarios,omitempty"`
+}
+
+// No proto3 string vazia = campo ausente
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func decodeInput(ctx context.Context, s *schema.Schema, in payload) (*payload, error) {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return nil, newStatus(ctx, utils.CodeInternalError, err).Err()
+	}
+	errs, err := s.ValidateJSON(b)
+	if err != nil {
+		return nil, newStatus(ctx, utils.CodeInternalError, err).Err()
+	}
+	if len(errs) > 0 {
+		return nil, validationError(ctx, errs)
+	}
+	return &in, nil
+}
+
+func addressFromPB(a *pb.Address) *models.Address {
+	if a == nil {
+		return nil
+	}
+	return &models.Address{
+		Logradouro:  a.GetLogradouro(),
+		Numero:      a.GetNumero(),
+		Complemento: a.GetComplemento(),
+		Bairro:      a.GetBairro(),
+		Municipio:   a.GetMunicipio(),
+		UF:          a.GetUf(),
+		CEP:         a.GetCep(),
+	}
+}
+
+func toPB(c *models.Company) *pb.Company {
+	out := &pb.Company{
+		Id:                      c.ID,
+		Cnpj:                    c.CNPJ,
+		NomeFantasia:            c.NomeFantasia,
+		RazaoSocial:             c.RazaoSocial,
+		Endereco:                c.Endereco,
+		NumeroFuncionarios:      int32(c.NumeroFuncionarios),
+		NumeroMinimoPcdExigidos: int32(c.NumeroMinimoPCDExigidos),
+	}
+	if a := c.EnderecoEstruturado; a != nil {
+		out.EnderecoEstruturado = &pb.Address{
+			Logradouro:  a.Logradouro,
+			Numero:      a.Numero,
+			Complemento: a.Complemento,
+			Bairro:      a.Bairro,
+			Municipio:   a.Municipio,
+			Uf:          a.UF,
+			Cep:         a.CEP,
+		}
+	}
+	if !c.CreatedAt.IsZero() {
+		out.CreatedAt = timestamppb.New(c.CreatedAt)
+	}
+	if !c.UpdatedAt.IsZero() {
+		out.UpdatedAt = timestamppb.New(c.UpdatedAt)
+	}
+	return out
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,367 @@
+package rpc
+
+/*
+
+go test -v ./internal/rpc -count=1
+
+*/
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	pb "github.com/Werneck0live/cadastro-empresa/internal/rpc/companiesv1"
+	"github.com/Werneck0live/cadastro-empresa/internal/service"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+const (
+	validCNPJ = "11.222.333/0001-81"
+	companyID = "11222333000181"
+)
+
+// repositório em memória (só o necessário para os testes)
+type memRepo struct {
+	mu       sync.Mutex
+	docs     map[string]*models.Company
+	lastFind *models.CompanyFilter
+}
+
+func newMemRepo(cs ...models.Company) *memRepo {
+	r := &memRepo{docs: map[string]*models.Company{}}
+	for i := range cs {
+		c := cs[i]
+		r.docs[c.ID] = &c
+	}
+	return r
+}
+
+func (r *memRepo) GetAll(ctx context.Context, limit, skip int64) ([]models.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.Company
+	for _, c := range r.docs {
+		out = append(out, *c)
+	}
+	return out, nil
+}
+
+func (r *memRepo) Find(ctx context.Context, f models.CompanyFilter, limit, skip int64) ([]models.Company, error) {
+	r.mu.Lock()
+	r.lastFind = &f
+	r.mu.Unlock()
+	return r.GetAll(ctx, limit, skip)
+}
+
+func (r *memRepo) Create(ctx context.Context, c *models.Company) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.docs[c.ID]; ok {
+		return "", repository.ErrDuplicateCNPJ
+	}
+	cp := *c
+	r.docs[c.ID] = &cp
+	return c.ID, nil
+}
+
+func (r *memRepo) GetByID(ctx context.Context, id string) (*models.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.docs[id]
+	if !ok {
+		return nil, repository.ErrCompanyNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+// FindDuplicateCandidates: sem o pré-filtro (todas menos a própria)
+func (r *memRepo) FindDuplicateCandidates(ctx context.Context, q models.DuplicateQuery, limit int64) ([]models.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.Company{}
+	for id, c := range r.docs {
+		if id != q.ExcludeID && int64(len(out)) < limit {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) GetMany(ctx context.Context, ids []string) ([]models.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.Company{}
+	for _, id := range ids {
+		if c, ok := r.docs[id]; ok {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) Update(ctx context.Context, id string, upd *models.Company, always ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.docs[id] = service.PatchedCompany(r.docs[id], upd, always...)
+	return nil
+}
+
+func (r *memRepo) Replace(ctx context.Context, id string, doc *models.Company) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *doc
+	r.docs[id] = &cp
+	return nil
+}
+
+func (r *memRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.docs, id)
+	return nil
+}
+
+func (r *memRepo) Stats(ctx context.Context, f models.CompanyFilter, groupBy []string) (*models.CompanyStats, error) {
+	return nil, errors.New("not implemented")
+}
+
+func seedCompany() models.Company {
+	return models.Company{
+		ID: companyID, CNPJ: companyID, NomeFantasia: "ACME", RazaoSocial: "ACME LTDA",
+		Endereco: "Rua A, 1", NumeroFuncionarios: 10,
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+// cliente ligado a um servidor em memória (bufconn)
+func newTestClient(t *testing.T, repo *memRepo) (pb.CompaniesClient, *events.Bus) {
+	t.Helper()
+	bus := events.NewBus(nil)
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	(&Server{Svc: &service.Companies{Repo: repo, Pub: bus}, Bus: bus}).Register(gs)
+	go func() { _ = gs.Serve(lis) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		gs.Stop()
+	})
+	return pb.NewCompaniesClient(conn), bus
+}
+
+func reason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+func TestRPC_CreateAndGet(t *testing.T) {
+	repo := newMemRepo()
+	client, bus := newTestClient(t, repo)
+	evs, cancel := bus.Subscribe(1)
+	defer cancel()
+	ctx := context.Background()
+
+	c, err := client.Create(ctx, &pb.CreateRequest{
+		Cnpj: validCNPJ, NomeFantasia: "ACME", RazaoSocial: "ACME LTDA", NumeroFuncionarios: 120,
+		Endereco: &pb.Address{Logradouro: "Rua A", Numero: "1", Uf: "SP", Cep: "01000-000"},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if c.GetId() != companyID || c.GetEnderecoEstruturado().GetCep() != "01000000" || c.GetEndereco() == "" {
+		t.Fatalf("create = %+v", c)
+	}
+
+	select {
+	case ev := <-evs:
+		if ev.Action != events.ActionCreated || ev.CompanyID != companyID {
+			t.Fatalf("event = %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("evento não publicado")
+	}
+
+	got, err := client.Get(ctx, &pb.GetRequest{Id: validCNPJ})
+	if err != nil || got.GetNomeFantasia() != "ACME" {
+		t.Fatalf("get = %+v, err=%v", got, err)
+	}
+
+	_, err = client.Create(ctx, &pb.CreateRequest{Cnpj: validCNPJ, NomeFantasia: "X"})
+	if status.Code(err) != codes.AlreadyExists || reason(err) != utils.CodeCNPJConflict {
+		t.Fatalf("duplicate err = %v", err)
+	}
+}
+
+func TestRPC_Create_ValidationError(t *testing.T) {
+	client, _ := newTestClient(t, newMemRepo())
+
+	_, err := client.Create(context.Background(), &pb.CreateRequest{Cnpj: "123", Endereco: &pb.Address{Uf: "sp"}})
+	if status.Code(err) != codes.InvalidArgument || reason(err) != utils.CodeValidationFailed {
+		t.Fatalf("err = %v", err)
+	}
+	fields := map[string]bool{}
+	for _, d := range status.Convert(err).Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				if v.GetDescription() == "" {
+					t.Errorf("violação sem descrição: %+v", v)
+				}
+				fields[v.GetField()] = true
+			}
+		}
+	}
+	for _, f := range []string{"cnpj", "endereco.logradouro", "endereco.uf"} {
+		if !fields[f] {
+			t.Errorf("violação de %q ausente (got %v)", f, fields)
+		}
+	}
+}
+
+func TestRPC_NotFound(t *testing.T) {
+	client, _ := newTestClient(t, newMemRepo())
+	ctx := context.Background()
+
+	if _, err := client.Get(ctx, &pb.GetRequest{Id: companyID}); status.Code(err) != codes.NotFound {
+		t.Fatalf("get err = %v", err)
+	}
+	if _, err := client.Delete(ctx, &pb.DeleteRequest{Id: companyID}); status.Code(err) != codes.NotFound {
+		t.Fatalf("delete err = %v", err)
+	}
+}
+
+func TestRPC_UpdateAndReplace(t *testing.T) {
+	seed := seedCompany()
+	seed.CNAEPrincipal, seed.InscricaoMunicipal, seed.Tags = "6201501", "12345678", []string{"cliente"}
+	repo := newMemRepo(seed)
+	client, _ := newTestClient(t, repo)
+	ctx := context.Background()
+
+	n := int32(250)
+	c, err := client.Update(ctx, &pb.UpdateRequest{Id: companyID, NumeroFuncionarios: &n})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if c.GetNumeroFuncionarios() != 250 || c.GetNumeroMinimoPcdExigidos() != int32(utils.ComputeMinPCD(250)) {
+		t.Fatalf("update = %+v", c)
+	}
+
+	_, err = client.Replace(ctx, &pb.ReplaceRequest{
+		Id: companyID, Cnpj: "00.000.000/0001-91", NomeFantasia: "X",
+		Endereco: &pb.Address{Logradouro: "Rua B"},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("replace mismatch err = %v", err)
+	}
+
+	c, err = client.Replace(ctx, &pb.ReplaceRequest{
+		Id: companyID, NomeFantasia: "Nova", RazaoSocial: "Nova LTDA", NumeroFuncionarios: 10,
+		Endereco: &pb.Address{Logradouro: "Rua B"},
+	})
+	if err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if c.GetCnpj() != companyID || c.GetNomeFantasia() != "Nova" || !c.GetCreatedAt().AsTime().Equal(seedCompany().CreatedAt) {
+		t.Fatalf("replace = %+v", c)
+	}
+	// o ReplaceRequest não tem CNAE, IM nem tags: ficam como estavam
+	if got := repo.docs[companyID]; got.CNAEPrincipal != "6201501" || got.InscricaoMunicipal != "12345678" || len(got.Tags) != 1 {
+		t.Fatalf("gravada = %+v", got)
+	}
+}
+
+func TestRPC_List_Stream(t *testing.T) {
+	repo := newMemRepo(seedCompany())
+	client, _ := newTestClient(t, repo)
+
+	minFunc := int32(5)
+	stream, err := client.List(context.Background(), &pb.ListRequest{
+		Filter: &pb.CompanyFilter{Nome: "acme", CnpjPrefix: "11.222", MinFuncionarios: &minFunc},
+	})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var got []*pb.Company
+	for {
+		c, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("recv: %v", err)
+		}
+		got = append(got, c)
+	}
+	if len(got) != 1 || got[0].GetId() != companyID {
+		t.Fatalf("list = %+v", got)
+	}
+	f := repo.lastFind
+	if f == nil || f.Nome != "acme" || f.CNPJPrefix != "11222" || f.MinFuncionarios == nil || *f.MinFuncionarios != 5 {
+		t.Fatalf("filter = %+v", f)
+	}
+}
+
+func TestRPC_WatchEvents(t *testing.T) {
+	client, _ := newTestClient(t, newMemRepo(seedCompany()))
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchEvents(ctx, &pb.WatchEventsRequest{Actions: []pb.Action{pb.Action_ACTION_DELETED}})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	// a assinatura é registrada em background: repete até o evento chegar
+	got := make(chan *pb.CompanyEvent, 1)
+	go func() {
+		ev, err := stream.Recv()
+		if err == nil {
+			got <- ev
+		}
+	}()
+
+	nome := "ACME"
+	for i := 0; ; i++ {
+		if i == 50 {
+			t.Fatal("evento não recebido")
+		}
+		// edição não passa no filtro; exclusão sim
+		_, _ = client.Update(ctx, &pb.UpdateRequest{Id: companyID, NomeFantasia: &nome})
+		if _, err := client.Delete(ctx, &pb.DeleteRequest{Id: companyID}); err == nil {
+			_, _ = client.Create(ctx, &pb.CreateRequest{Cnpj: validCNPJ, NomeFantasia: nome})
+		}
+		select {
+		case ev := <-got:
+			if ev.GetAction() != pb.Action_ACTION_DELETED || ev.GetCompanyId() != companyID || ev.GetTimestamp() == nil {
+				t.Fatalf("event = %+v", ev)
+			}
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"embed"
+	"fmt"
+)
+
+//go:embed schemas/*.json
+var schemasFS embed.FS
+
+// Schemas dos payloads de /api/companies (v1) e /api/v2/companies
+var (
+	CompanyCreate = mustLoad("company_create.json")
+	CompanyPut    = mustLoad("company_put.json")
+	CompanyPatch  = mustLoad("company_patch.json")
+
+	// /api/v2: mesmos campos, endereco estruturado (objeto)
+	CompanyCreateV2 = mustLoad("company_create_v2.json")
+	CompanyPutV2    = mustLoad("company_put_v2.json")
+	CompanyPatchV2  = mustLoad("company_patch_v2.json")
+
+	companyDocument = mustLoad("company_document.json")
+
+	// POST /api/pcd/simulate
+	PCDSimulate = mustLoad("pcd_simulate.json")
+
+	// funcionários (POST/PUT e cada linha da importação CSV)
+	Employee = mustLoad("employee.json")
+
+	// contatos (POST/PUT)
+	Contact = mustLoad("contact.json")
+
+	// sócios / QSA (POST/PUT)
+	Partner = mustLoad("partner.json")
+
+	// definições dos campos personalizados (POST/PUT)
+	CustomField = mustLoad("custom_field.json")
+
+	// fusão de duplicatas (POST /api/companies/{id}/merge)
+	CompanyMerge = mustLoad("company_merge.json")
+
+	// grupo econômico (POST e PUT /api/companies/{id}/subsidiaries)
+	Ownership       = mustLoad("ownership.json")
+	OwnershipUpdate = mustLoad("ownership_update.json")
+
+	// notas do atendimento (POST/PUT)
+	Note = mustLoad("note.json")
+
+	// decisão dos pedidos de mudança (POST .../approve e .../reject)
+	ChangeDecision = mustLoad("change_decision.json")
+)
+
+func mustLoad(name string) *Schema {
+	b, err := schemasFS.ReadFile("schemas/" + name)
+	if err != nil {
+		panic(fmt.Sprintf("schema: %s: %v", name, err))
+	}
+	s, err := Parse(b)
+	if err != nil {
+		panic(fmt.Sprintf("schema: %s: %v", name, err))
+	}
+	return s
+}
+
+// CompanyMongoValidator: $jsonSchema da coleção companies.
+// Regras do payload de criação + campos gravados pelo servidor (company_document.json).
+func CompanyMongoValidator() map[string]any {
+	return MongoJSONSchema(CompanyCreate.extend(companyDocument))
+}
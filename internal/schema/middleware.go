@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+const maxBodyBytes = 1 << 20
+
+// Validate valida o body JSON contra o schema antes de chamar next.
+// JSON malformado -> 400 invalid_json; violações -> 400 validation_failed com TODAS em errors[].
+// O body é restaurado para o handler decodificar normalmente.
+func Validate(s *Schema, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+		if err != nil {
+			utils.BadRequest(w, r, "detail.body_unreadable")
+			return
+		}
+		errs, err := s.ValidateJSON(body)
+		if err != nil {
+			utils.InvalidJSON(w, r, err)
+			return
+		}
+		if len(errs) > 0 {
+			utils.ValidationFailed(w, r, errs)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
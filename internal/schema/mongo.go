@@ -0,0 +1,124 @@
+package schema
+
+// Conversão para o dialeto $jsonSchema do MongoDB:
+// - "type" vira "bsonType" ("integer" -> int/long etc.)
+// - "format" e "$schema" não existem no Mongo e são descartados
+// - additionalProperties só é mantido se vier explicitamente no schema do documento
+
+var bsonTypes = map[string][]string{
+	"string":  {"string"},
+	"integer": {"int", "long"},
+	"number":  {"int", "long", "double", "decimal"},
+	"boolean": {"bool"},
+	"object":  {"object"},
+	"array":   {"array"},
+	"null":    {"null"},
+}
+
+func MongoJSONSchema(s *Schema) map[string]any {
+	out := map[string]any{}
+	if s.Title != "" {
+		out["title"] = s.Title
+	}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+
+	var bt []string
+	if len(s.BSONType) > 0 {
+		bt = append(bt, s.BSONType...)
+	} else {
+		for _, t := range s.Type {
+			bt = append(bt, bsonTypes[t]...)
+		}
+	}
+	switch len(bt) {
+	case 0:
+	case 1:
+		out["bsonType"] = bt[0]
+	default:
+		out["bsonType"] = bt
+	}
+
+	if len(s.Properties) > 0 {
+		props := map[string]any{}
+		for name, p := range s.Properties {
+			props[name] = MongoJSONSchema(p)
+		}
+		out["properties"] = props
+	}
+	if len(s.Required) > 0 {
+		out["required"] = s.Required
+	}
+	if s.AdditionalProperties != nil {
+		out["additionalProperties"] = *s.AdditionalProperties
+	}
+	if s.MinLength != nil {
+		out["minLength"] = *s.MinLength
+	}
+	if s.MaxLength != nil {
+		out["maxLength"] = *s.MaxLength
+	}
+	if s.Minimum != nil {
+		out["minimum"] = *s.Minimum
+	}
+	if s.Maximum != nil {
+		out["maximum"] = *s.Maximum
+	}
+	if s.Pattern != "" {
+		out["pattern"] = s.Pattern
+	}
+	if len(s.Enum) > 0 {
+		out["enum"] = s.Enum
+	}
+	if s.Items != nil {
+		out["items"] = MongoJSONSchema(s.Items)
+	}
+	if s.MinItems != nil {
+		out["minItems"] = *s.MinItems
+	}
+	if s.MaxItems != nil {
+		out["maxItems"] = *s.MaxItems
+	}
+	if len(s.AnyOf) > 0 {
+		branches := make([]any, len(s.AnyOf))
+		for i, a := range s.AnyOf {
+			branches[i] = MongoJSONSchema(a)
+		}
+		out["anyOf"] = branches
+	}
+	if len(s.OneOf) > 0 {
+		branches := make([]any, len(s.OneOf))
+		for i, a := range s.OneOf {
+			branches[i] = MongoJSONSchema(a)
+		}
+		out["oneOf"] = branches
+	}
+	return out
+}
+
+// extend devolve uma cópia de s com as propriedades/required de doc somadas
+// (doc sobrescreve propriedades de mesmo nome). additionalProperties vem de doc.
+func (s *Schema) extend(doc *Schema) *Schema {
+	out := *s
+	out.Title, out.Description = doc.Title, doc.Description
+	out.AdditionalProperties = doc.AdditionalProperties
+
+	out.Properties = map[string]*Schema{}
+	for k, v := range s.Properties {
+		out.Properties[k] = v
+	}
+	for k, v := range doc.Properties {
+		out.Properties[k] = v
+	}
+
+	seen := map[string]bool{}
+	out.Required = nil
+	for _, r := range append(append([]string{}, s.Required...), doc.Required...) {
+		if !seen[r] {
+			seen[r] = true
+			out.Required = append(out.Required, r)
+		}
+	}
+	return &out
+}
@@ -0,0 +1,360 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/cnae"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Subconjunto do JSON Schema (draft 2020-12) usado nos payloads da API.
+// Palavras-chave suportadas: type, properties, required, additionalProperties,
+// minLength, maxLength, minimum, maximum, pattern, enum, format, items,
+// minItems, maxItems, anyOf, oneOf.
+// Os mesmos schemas viram o $jsonSchema da coleção no Mongo (ver MongoJSONSchema).
+type Schema struct {
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+
+	// Só para o $jsonSchema do Mongo (ex.: "date"); ignorado na validação da API.
+	BSONType Types `json:"bsonType,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// "type" aceita string ou lista de strings
+type Types []string
+
+func (t *Types) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// Validadores de "format" (não expressáveis em pattern)
+var formats = map[string]func(string) bool{
+	"cnpj":     func(s string) bool { return utils.ValidateCNPJ(utils.SanitizeCNPJ(s)) },
+	"cnae":     cnae.ValidSubclasse, // subclasse CNAE 2.3 (tabela embutida)
+	"cpf":      func(s string) bool { return utils.ValidateCPF(utils.SanitizeCNPJ(s)) },
+	"document": func(s string) bool { return utils.DocumentType(utils.SanitizeCNPJ(s)) != "" }, // CPF ou CNPJ
+	"email":    utils.ValidateEmail,
+	"phone":    func(s string) bool { return utils.ValidatePhone(utils.SanitizePhone(s)) },
+	"date": func(s string) bool { // YYYY-MM-DD
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	},
+}
+
+// Parse lê um schema e compila os patterns.
+func Parse(b []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *Schema) compile() error {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %q: %w", s.Pattern, err)
+		}
+		s.re = re
+	}
+	if s.Format != "" {
+		if _, ok := formats[s.Format]; !ok {
+			return fmt.Errorf("unknown format %q", s.Format)
+		}
+	}
+	for name, p := range s.Properties {
+		if err := p.compile(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if s.Items != nil {
+		if err := s.Items.compile(); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+	}
+	for _, a := range append(append([]*Schema{}, s.AnyOf...), s.OneOf...) {
+		if err := a.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateJSON decodifica e valida o body. err != nil = JSON malformado;
+// caso contrário devolve TODAS as violações (vazio = válido).
+func (s *Schema) ValidateJSON(b []byte) ([]utils.FieldError, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected additional JSON content")
+	}
+	return s.Validate(v), nil
+}
+
+// Validate valida um valor já decodificado (números como json.Number).
+func (s *Schema) Validate(v any) []utils.FieldError {
+	var errs []utils.FieldError
+	s.validate("", v, &errs)
+	return errs
+}
+
+func (s *Schema) validate(path string, v any, errs *[]utils.FieldError) {
+	add := func(code string, args ...any) {
+		*errs = append(*errs, utils.FieldError{Field: path, Code: code, Args: args})
+	}
+
+	if len(s.Type) > 0 && !s.Type.matches(v) {
+		add(utils.FieldInvalidType, strings.Join(s.Type, "|"))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		add(utils.FieldNotInEnum, enumList(s.Enum))
+	}
+
+	switch val := v.(type) {
+	case string:
+		n := len([]rune(val))
+		if s.MinLength != nil && n < *s.MinLength {
+			if *s.MinLength == 1 {
+				add(utils.FieldRequired)
+			} else {
+				add(utils.FieldTooShort, *s.MinLength)
+			}
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			add(utils.FieldTooLong, *s.MaxLength)
+		}
+		if s.re != nil && !s.re.MatchString(val) {
+			add(utils.FieldInvalidFormat)
+		}
+		// string vazia com minLength já saiu como "required" acima
+		if s.Format != "" && !(val == "" && s.MinLength != nil) && !formats[s.Format](val) {
+			add("invalid_" + s.Format)
+		}
+
+	case json.Number:
+		f, _ := val.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			if *s.Minimum == 0 {
+				add(utils.FieldMustBeNonNeg)
+			} else {
+				add(utils.FieldTooSmall, *s.Minimum)
+			}
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			add(utils.FieldTooLarge, *s.Maximum)
+		}
+
+	case map[string]any:
+		for _, req := range s.Required {
+			if _, ok := val[req]; !ok {
+				*errs = append(*errs, utils.FieldError{Field: join(path, req), Code: utils.FieldRequired})
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys) // ordem estável nos errors[]
+		for _, k := range keys {
+			if p, ok := s.Properties[k]; ok {
+				p.validate(join(path, k), val[k], errs)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				*errs = append(*errs, utils.FieldError{Field: join(path, k), Code: utils.FieldUnknown})
+			}
+		}
+
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			add(utils.FieldTooFewItems, *s.MinItems)
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			add(utils.FieldTooManyItems, *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range val {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	}
+
+	if len(s.AnyOf) > 0 {
+		s.validateAnyOf(path, v, errs)
+	}
+	if len(s.OneOf) > 0 {
+		s.validateOneOf(path, v, errs)
+	}
+}
+
+// anyOf: basta um ramo válido. Se todos falharem, cada campo exigido pelos ramos
+// recebe "required_one_of" apontando para os demais (ex.: nome_fantasia | razao_social).
+func (s *Schema) validateAnyOf(path string, v any, errs *[]utils.FieldError) {
+	var fields []string
+	for _, branch := range s.AnyOf {
+		if len(branch.Validate(v)) == 0 {
+			return
+		}
+		fields = append(fields, branch.Required...)
+	}
+	if len(fields) == 0 {
+		*errs = append(*errs, utils.FieldError{Field: path, Code: utils.FieldInvalidFormat})
+		return
+	}
+	for i, f := range fields {
+		*errs = append(*errs, utils.FieldError{
+			Field: join(path, f),
+			Code:  utils.FieldRequiredOneOf,
+			Args:  []any{strings.Join(othersOf(fields, i), " / ")},
+		})
+	}
+}
+
+// oneOf: exatamente um ramo válido. Nenhum: mesmas mensagens do anyOf;
+// mais de um: "mutually_exclusive" nos campos exigidos pelos ramos que casaram.
+func (s *Schema) validateOneOf(path string, v any, errs *[]utils.FieldError) {
+	matched := 0
+	var fields []string
+	for _, branch := range s.OneOf {
+		if len(branch.Validate(v)) == 0 {
+			matched++
+			fields = append(fields, branch.Required...)
+		}
+	}
+	switch {
+	case matched == 0:
+		(&Schema{AnyOf: s.OneOf}).validateAnyOf(path, v, errs)
+	case matched > 1 && len(fields) < 2:
+		*errs = append(*errs, utils.FieldError{Field: path, Code: utils.FieldInvalidFormat})
+	case matched > 1:
+		for i, f := range fields {
+			*errs = append(*errs, utils.FieldError{
+				Field: join(path, f),
+				Code:  utils.FieldMutuallyExclusive,
+				Args:  []any{strings.Join(othersOf(fields, i), " / ")},
+			})
+		}
+	}
+}
+
+// othersOf: fields sem o i-ésimo
+func othersOf(fields []string, i int) []string {
+	others := make([]string, 0, len(fields)-1)
+	others = append(others, fields[:i]...)
+	return append(others, fields[i+1:]...)
+}
+
+func (t Types) matches(v any) bool {
+	for _, typ := range t {
+		switch typ {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]any); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]any); ok {
+				return true
+			}
+		case "number":
+			if _, ok := v.(json.Number); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := v.(json.Number); ok {
+				if f, err := n.Float64(); err == nil && f == math.Trunc(f) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		if e == nil {
+			parts[i] = "null"
+			continue
+		}
+		parts[i] = fmt.Sprint(e)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,194 @@
+package schema
+
+/*
+
+go test -v ./internal/schema -count=1
+
+*/
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// field -> code, para comparar sem depender de Args/ordem
+func codes(errs []utils.FieldError) map[string]string {
+	out := map[string]string{}
+	for _, e := range errs {
+		out[e.Field] = e.Code
+	}
+	return out
+}
+
+func TestCompanyCreate(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want map[string]string
+	}{
+		{"válido", `{"cnpj":"11.222.333/0001-81","nome_fantasia":"ACME"}`, map[string]string{}},
+		{"só razão social", `{"cnpj":"11222333000181","razao_social":"ACME LTDA"}`, map[string]string{}},
+		{"cnpj ausente", `{"nome_fantasia":"ACME"}`, map[string]string{"cnpj": utils.FieldRequired}},
+		{"cnpj vazio", `{"cnpj":"","nome_fantasia":"ACME"}`, map[string]string{"cnpj": utils.FieldRequired}},
+		{"cnpj inválido", `{"cnpj":"123","nome_fantasia":"ACME"}`, map[string]string{"cnpj": utils.FieldInvalidCNPJ}},
+		{"sem nomes", `{"cnpj":"11222333000181"}`, map[string]string{
+			"nome_fantasia": utils.FieldRequiredOneOf,
+			"razao_social":  utils.FieldRequiredOneOf,
+		}},
+		{"nome vazio", `{"cnpj":"11222333000181","nome_fantasia":""}`, map[string]string{
+			"nome_fantasia": utils.FieldRequiredOneOf,
+			"razao_social":  utils.FieldRequiredOneOf,
+		}},
+		{"funcionários negativo", `{"cnpj":"11222333000181","nome_fantasia":"A","numero_funcionarios":-1}`,
+			map[string]string{"numero_funcionarios": utils.FieldMustBeNonNeg}},
+		{"funcionários fracionário", `{"cnpj":"11222333000181","nome_fantasia":"A","numero_funcionarios":1.5}`,
+			map[string]string{"numero_funcionarios": utils.FieldInvalidType}},
+		{"campo desconhecido", `{"cnpj":"11222333000181","nome_fantasia":"A","foo":1}`,
+			map[string]string{"foo": utils.FieldUnknown}},
+		{"tudo errado de uma vez", `{"cnpj":1,"numero_funcionarios":"10","foo":true}`, map[string]string{
+			"cnpj":                utils.FieldInvalidType,
+			"numero_funcionarios": utils.FieldInvalidType,
+			"foo":                 utils.FieldUnknown,
+			"nome_fantasia":       utils.FieldRequiredOneOf,
+			"razao_social":        utils.FieldRequiredOneOf,
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs, err := CompanyCreate.ValidateJSON([]byte(tc.body))
+			if err != nil {
+				t.Fatalf("json: %v", err)
+			}
+			if got := codes(errs); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("errors=%v want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCompanyPatch_Nullable(t *testing.T) {
+	errs, err := CompanyPatch.ValidateJSON([]byte(`{"nome_fantasia":null,"endereco":"Rua X"}`))
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("patch com null deveria ser válido: err=%v errs=%v", err, errs)
+	}
+	errs, _ = CompanyPatch.ValidateJSON([]byte(`{"numero_funcionarios":-3}`))
+	if codes(errs)["numero_funcionarios"] != utils.FieldMustBeNonNeg {
+		t.Fatalf("errs=%v", errs)
+	}
+}
+
+func TestValidateJSON_Malformed(t *testing.T) {
+	for _, body := range []string{`{`, `{"cnpj":"1"} {}`, ``} {
+		if _, err := CompanyCreate.ValidateJSON([]byte(body)); err == nil {
+			t.Fatalf("body %q deveria falhar no decode", body)
+		}
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	s, err := Parse([]byte(`{
+		"type": "object",
+		"properties": {
+			"uf":   { "type": "string", "enum": ["SP", "RJ"] },
+			"cep":  { "type": "string", "pattern": "^[0-9]{8}$" },
+			"nome": { "type": "string", "minLength": 3, "maxLength": 5 },
+			"n":    { "type": "number", "minimum": 1, "maximum": 10 }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, _ := s.ValidateJSON([]byte(`{"uf":"XX","cep":"1234","nome":"ab","n":11}`))
+	want := map[string]string{
+		"uf":   utils.FieldNotInEnum,
+		"cep":  utils.FieldInvalidFormat,
+		"nome": utils.FieldTooShort,
+		"n":    utils.FieldTooLarge,
+	}
+	if got := codes(errs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("errors=%v want=%v", got, want)
+	}
+}
+
+func TestKeywords_ItemsAndOneOf(t *testing.T) {
+	s, err := Parse([]byte(`{
+		"type": "object",
+		"properties": {
+			"a": { "type": "array", "minItems": 1, "maxItems": 2, "items": { "type": "integer", "minimum": 0 } },
+			"b": { "type": "object" }
+		},
+		"oneOf": [ { "required": ["a"] }, { "required": ["b"] } ]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		body string
+		want map[string]string
+	}{
+		{`{"a":[1,2]}`, map[string]string{}},
+		{`{"a":[1,-1,"x"]}`, map[string]string{"a": utils.FieldTooManyItems, "a[1]": utils.FieldMustBeNonNeg, "a[2]": utils.FieldInvalidType}},
+		{`{"a":[]}`, map[string]string{"a": utils.FieldTooFewItems}},
+		{`{}`, map[string]string{"a": utils.FieldRequiredOneOf, "b": utils.FieldRequiredOneOf}},
+		{`{"a":[1],"b":{}}`, map[string]string{"a": utils.FieldMutuallyExclusive, "b": utils.FieldMutuallyExclusive}},
+	}
+	for _, tc := range cases {
+		errs, _ := s.ValidateJSON([]byte(tc.body))
+		if got := codes(errs); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: errors=%v want=%v", tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, b := range []string{
+		`{"pattern": "("}`,
+		`{"properties": {"x": {"format": "nao-existe"}}}`,
+		`{"type": 1}`,
+	} {
+		if _, err := Parse([]byte(b)); err == nil {
+			t.Fatalf("schema %s deveria ser rejeitado", b)
+		}
+	}
+}
+
+func TestCompanyMongoValidator(t *testing.T) {
+	v := CompanyMongoValidator()
+
+	if v["bsonType"] != "object" {
+		t.Fatalf("bsonType=%v", v["bsonType"])
+	}
+	if _, ok := v["additionalProperties"]; ok {
+		t.Fatal("documento do Mongo tem _id/created_at: additionalProperties não pode ser false")
+	}
+
+	props := v["properties"].(map[string]any)
+	cnpj := props["cnpj"].(map[string]any)
+	if _, ok := cnpj["format"]; ok {
+		t.Fatal("format não existe no $jsonSchema do Mongo")
+	}
+	if cnpj["pattern"] != "^[0-9]{14}$" {
+		t.Fatalf("cnpj deveria vir do company_document.json: %v", cnpj)
+	}
+	if got := props["numero_funcionarios"].(map[string]any)["bsonType"]; !reflect.DeepEqual(got, []string{"int", "long"}) {
+		t.Fatalf("integer -> %v", got)
+	}
+	if got := props["created_at"].(map[string]any)["bsonType"]; got != "date" {
+		t.Fatalf("created_at -> %v", got)
+	}
+
+	required := map[string]bool{}
+	for _, r := range v["required"].([]string) {
+		required[r] = true
+	}
+	for _, f := range []string{"_id", "cnpj", "created_at", "updated_at"} {
+		if !required[f] {
+			t.Fatalf("%s deveria ser required (required=%v)", f, v["required"])
+		}
+	}
+	if branches, ok := v["anyOf"].([]any); !ok || len(branches) != 2 {
+		t.Fatalf("anyOf nome_fantasia/razao_social ausente: %v", v["anyOf"])
+	}
+}
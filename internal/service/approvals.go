@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+)
+
+// Maker-checker: as operações configuradas em Companies.Approvals (exclusão, troca de
+// CNPJ, queda grande de funcionários) não são aplicadas na hora. Viram um pedido de
+// mudança que outro usuário aprova ou rejeita; a aprovação grava pelo repositório da
+// empresa e publica os eventos de sempre.
+
+type ChangeRequestRepository interface {
+	List(ctx context.Context, f models.ChangeRequestFilter, limit, skip int64) ([]models.ChangeRequest, error)
+	Get(ctx context.Context, id string) (*models.ChangeRequest, error)
+	Create(ctx context.Context, cr *models.ChangeRequest) error
+	Decide(ctx context.Context, id, status, by, comment string, at time.Time) error
+	Fail(ctx context.Context, id, msg string) error
+}
+
+var (
+	errChangeRequestsDisabled = errors.New("change request repository not configured")
+
+	// operação que exige aprovação (ou decisão de um pedido) sem o usuário identificado
+	ErrUserRequired = errors.New("user required")
+	// quem pediu a mudança não pode decidir o pedido
+	ErrSameUser = errors.New("change request must be decided by another user")
+	// pedido já aprovado ou rejeitado
+	ErrChangeDecided = errors.New("change request already decided")
+	// a empresa mudou (ou foi removida) depois do pedido: ele não pode mais ser aplicado
+	ErrChangeStale = errors.New("company changed since the change request was made")
+)
+
+// ApprovalRequiredError: a mudança virou o pedido Request, pendente de aprovação
+type ApprovalRequiredError struct {
+	Request *models.ChangeRequest
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return fmt.Sprintf("change request %s pending approval", e.Request.ID)
+}
+
+type userKey struct{}
+
+// WithUser: usuário que faz a operação (header X-User na REST e no GraphQL, metadata x-user no gRPC)
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+func UserFrom(ctx context.Context) string {
+	u, _ := ctx.Value(userKey{}).(string)
+	return u
+}
+
+// approvalReasons: regras da política que a mudança before -> after atinge (after nil = exclusão)
+func (s *Companies) approvalReasons(before, after *models.Company) []string {
+	p := s.Approvals
+	reasons := []string{}
+	if after == nil {
+		if p.Delete {
+			reasons = append(reasons, models.ApprovalDelete)
+		}
+		return reasons
+	}
+	if p.CNPJChange && after.CNPJ != before.CNPJ {
+		reasons = append(reasons, models.ApprovalCNPJChange)
+	}
+	if p.HeadcountDropPercent > 0 && before.NumeroFuncionarios > 0 && after.NumeroFuncionarios < before.NumeroFuncionarios {
+		drop := float64(before.NumeroFuncionarios-after.NumeroFuncionarios) * 100 / float64(before.NumeroFuncionarios)
+		if drop >= p.HeadcountDropPercent {
+			reasons = append(reasons, models.ApprovalHeadcountDrop)
+		}
+	}
+	return reasons
+}
+
+// requireApproval: nil se a mudança pode ser aplicada agora; senão grava o pedido
+// e devolve *ApprovalRequiredError. Sem o repositório de pedidos, nada exige aprovação.
+func (s *Companies) requireApproval(ctx context.Context, before, after *models.Company) error {
+	if s.ChangeRequests == nil {
+		return nil
+	}
+	reasons := s.approvalReasons(before, after)
+	if len(reasons) == 0 {
+		return nil
+	}
+	cr := models.ChangeRequest{CompanyID: before.ID, Action: models.ChangeActionUpdate, Reasons: reasons, Before: *before, After: after}
+	if after == nil {
+		cr.Action = models.ChangeActionDelete
+	}
+	return s.requestChange(ctx, &cr)
+}
+
+// requireMergeApproval: a fusão remove a absorvida, então segue a regra da exclusão;
+// o pedido (ChangeActionMerge) guarda a absorvida e a empresa que fica
+func (s *Companies) requireMergeApproval(ctx context.Context, target, source *models.Company) error {
+	if s.ChangeRequests == nil {
+		return nil
+	}
+	reasons := s.approvalReasons(source, nil)
+	if len(reasons) == 0 {
+		return nil
+	}
+	return s.requestChange(ctx, &models.ChangeRequest{
+		CompanyID: source.ID, Action: models.ChangeActionMerge, Reasons: reasons, Before: *source, MergeInto: target.ID,
+	})
+}
+
+// requestChange grava o pedido como pendente, em nome do usuário do ctx
+func (s *Companies) requestChange(ctx context.Context, cr *models.ChangeRequest) error {
+	user := UserFrom(ctx)
+	if user == "" {
+		return ErrUserRequired
+	}
+	cr.Status, cr.RequestedBy, cr.RequestedAt = models.ChangeStatusPending, user, time.Now()
+	if err := s.ChangeRequests.Create(ctx, cr); err != nil {
+		return err
+	}
+	return &ApprovalRequiredError{Request: cr}
+}
+
+func (s *Companies) ListChangeRequests(ctx context.Context, f models.ChangeRequestFilter, limit, skip int64) ([]models.ChangeRequest, error) {
+	if s.ChangeRequests == nil {
+		return nil, errChangeRequestsDisabled
+	}
+	return s.ChangeRequests.List(ctx, f, limit, skip)
+}
+
+func (s *Companies) GetChangeRequest(ctx context.Context, id string) (*models.ChangeRequest, error) {
+	if s.ChangeRequests == nil {
+		return nil, errChangeRequestsDisabled
+	}
+	return s.ChangeRequests.Get(ctx, id)
+}
+
+// decidable: o pedido, se ainda pendente e decidido por outro usuário
+func (s *Companies) decidable(ctx context.Context, id string) (*models.ChangeRequest, string, error) {
+	cr, err := s.GetChangeRequest(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	user := UserFrom(ctx)
+	if user == "" {
+		return nil, "", ErrUserRequired
+	}
+	if cr.Status != models.ChangeStatusPending {
+		return nil, "", ErrChangeDecided
+	}
+	if user == cr.RequestedBy {
+		return nil, "", ErrSameUser
+	}
+	return cr, user, nil
+}
+
+// ApproveChange aplica a mudança do pedido. A empresa precisa estar como no pedido
+// (updated_at igual ao de Before); senão ErrChangeStale e o pedido continua pendente.
+// Falha ao gravar deixa o pedido como failed.
+func (s *Companies) ApproveChange(ctx context.Context, id, comment string) (*models.ChangeRequest, error) {
+	cr, user, err := s.decidable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.Get(ctx, cr.CompanyID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrChangeStale
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !current.UpdatedAt.Equal(cr.Before.UpdatedAt) {
+		return nil, ErrChangeStale
+	}
+
+	// marca antes de aplicar: de dois aprovadores ao mesmo tempo, só um grava
+	now := time.Now()
+	if err := s.ChangeRequests.Decide(ctx, cr.ID, models.ChangeStatusApproved, user, comment, now); err != nil {
+		return nil, err
+	}
+	cr.Status, cr.DecidedBy, cr.DecidedAt, cr.Comment = models.ChangeStatusApproved, user, &now, comment
+
+	if err := s.applyChange(ctx, cr, current); err != nil {
+		cr.Status, cr.Error = models.ChangeStatusFailed, err.Error()
+		_ = s.ChangeRequests.Fail(ctx, cr.ID, cr.Error)
+		return cr, err
+	}
+	return cr, nil
+}
+
+func (s *Companies) applyChange(ctx context.Context, cr *models.ChangeRequest, current *models.Company) error {
+	switch cr.Action {
+	case models.ChangeActionDelete:
+		return s.deleteCompany(ctx, current)
+	case models.ChangeActionMerge:
+		target, err := s.Get(ctx, cr.MergeInto)
+		if err != nil {
+			return err
+		}
+		_, _, err = s.merge(ctx, target, current)
+		return err
+	}
+	after := *cr.After
+	after.ID, after.CreatedAt, after.UpdatedAt = current.ID, current.CreatedAt, time.Now()
+	if err := s.Repo.Replace(ctx, current.ID, &after); err != nil {
+		return err
+	}
+	cr.After = &after
+	s.publishEvent("Edição", &after)
+	s.publishPCDChange(current, &after)
+	return nil
+}
+
+func (s *Companies) RejectChange(ctx context.Context, id, comment string) (*models.ChangeRequest, error) {
+	cr, user, err := s.decidable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.ChangeRequests.Decide(ctx, cr.ID, models.ChangeStatusRejected, user, comment, now); err != nil {
+		return nil, err
+	}
+	cr.Status, cr.DecidedBy, cr.DecidedAt, cr.Comment = models.ChangeStatusRejected, user, &now, comment
+	return cr, nil
+}
+
+// PatchedCompany: como a empresa fica depois do Repository.Update com upd e always
+// (mesmas regras: campo vazio não muda, salvo os de always; lista vazia remove;
+// custom field nil remove)
+func PatchedCompany(c, upd *models.Company, always ...string) *models.Company {
+	out := *c
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&out.CNPJ, upd.CNPJ)
+	set(&out.NomeFantasia, upd.NomeFantasia)
+	set(&out.RazaoSocial, upd.RazaoSocial)
+	if upd.Endereco != "" {
+		out.Endereco, out.EnderecoEstruturado = upd.Endereco, upd.EnderecoEstruturado
+	}
+	if upd.NumeroFuncionarios != 0 || slices.Contains(always, models.FieldNumeroFuncionarios) {
+		out.NumeroFuncionarios = upd.NumeroFuncionarios
+	}
+	if upd.NumeroMinimoPCDExigidos != 0 || slices.Contains(always, models.FieldNumeroMinimoPCD) {
+		out.NumeroMinimoPCDExigidos = upd.NumeroMinimoPCDExigidos
+	}
+	if upd.NumeroPCDContratados != nil {
+		out.NumeroPCDContratados = upd.NumeroPCDContratados
+	}
+	set(&out.CNAEPrincipal, upd.CNAEPrincipal)
+	if upd.CNAESecundarios != nil {
+		out.CNAESecundarios = nilIfEmpty(upd.CNAESecundarios)
+	}
+	if upd.InscricaoEstadual != "" {
+		out.InscricaoEstadual, out.InscricaoEstadualUF = upd.InscricaoEstadual, upd.InscricaoEstadualUF
+	}
+	set(&out.InscricaoMunicipal, upd.InscricaoMunicipal)
+	set(&out.RegimeTributario, upd.RegimeTributario)
+	if upd.FaturamentoAnual != nil {
+		out.FaturamentoAnual = upd.FaturamentoAnual
+	}
+	set(&out.Porte, upd.Porte)
+	if upd.Tags != nil {
+		out.Tags = nilIfEmpty(upd.Tags)
+	}
+	if len(upd.CustomFields) > 0 {
+		fields := map[string]any{}
+		for k, v := range c.CustomFields {
+			fields[k] = v
+		}
+		for k, v := range upd.CustomFields {
+			if v == nil {
+				delete(fields, k)
+			} else {
+				fields[k] = v
+			}
+		}
+		out.CustomFields = fields
+		if len(fields) == 0 {
+			out.CustomFields = nil
+		}
+	}
+	return &out
+}
@@ -0,0 +1,500 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/cnae"
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/repository"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Regras do cadastro de empresas, compartilhadas pelas portas de entrada
+// (REST v1/v2, GraphQL...). Cada porta valida o formato do payload (JSON Schema)
+// e traduz os erros daqui para o seu protocolo.
+
+type Repository interface {
+	GetAll(ctx context.Context, limit, skip int64) ([]models.Company, error)
+	Find(ctx context.Context, f models.CompanyFilter, limit, skip int64) ([]models.Company, error)
+	Create(ctx context.Context, c *models.Company) (string, error)
+	GetByID(ctx context.Context, id string) (*models.Company, error)
+	GetMany(ctx context.Context, ids []string) ([]models.Company, error)                // ids inexistentes ficam de fora
+	Update(ctx context.Context, id string, upd *models.Company, always ...string) error // always: campos gravados mesmo zerados
+	Replace(ctx context.Context, id string, doc *models.Company) error
+	Delete(ctx context.Context, id string) error
+	Stats(ctx context.Context, f models.CompanyFilter, groupBy []string) (*models.CompanyStats, error)
+	FindDuplicateCandidates(ctx context.Context, q models.DuplicateQuery, limit int64) ([]models.Company, error)
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, body string, headers amqp.Table) error
+	Close() error
+}
+
+var ErrNotFound = errors.New("company not found")
+
+type Companies struct {
+	Repo       Repository
+	Pub        Publisher
+	Employees  EmployeeRepository  // nil = sem o sub-recurso de funcionários
+	Contacts   ContactRepository   // nil = sem o sub-recurso de contatos
+	Partners   PartnerRepository   // nil = sem o QSA
+	Documents  DocumentRepository  // nil = sem os documentos anexados
+	Merges     MergeRepository     // nil = sem a fusão de duplicatas
+	Ownerships OwnershipRepository // nil = sem o grupo econômico
+	Notes      NoteRepository      // nil = sem as notas e a linha do tempo
+	Events     EventRepository     // nil = linha do tempo só com as notas
+
+	// Pedidos de mudança (nil = nada exige aprovação) e quais operações viram pedido
+	ChangeRequests ChangeRequestRepository
+	Approvals      models.ApprovalPolicy
+
+	// Transações do Mongo na fusão de empresas (nil = sem transação)
+	Tx Transactor
+
+	// Definições dos campos personalizados (nil = nenhum custom_fields aceito)
+	CustomFields CustomFieldRepository
+
+	// Idioma do texto dos eventos publicados (padrão pt-BR)
+	EventLang i18n.Lang
+	// Limites do porte (zero = utils.DefaultPorteThresholds)
+	PorteThresholds utils.PorteThresholds
+}
+
+// Dados de criação/substituição (já validados pelo schema da porta de entrada)
+type CompanyInput struct {
+	CNPJ                 string
+	NomeFantasia         string
+	RazaoSocial          string
+	Endereco             string
+	EnderecoEstruturado  *models.Address // só v2/GraphQL; Endereco traz o texto equivalente
+	NumeroFuncionarios   int
+	NumeroPCDContratados *int   // nil = não informado
+	CNAEPrincipal        string // subclasse CNAE 2.3 (com ou sem máscara; validada pelo schema)
+	CNAESecundarios      []string
+	InscricaoEstadual    string // com ou sem máscara; conferida com a UF pela porta de entrada
+	InscricaoEstadualUF  string
+	InscricaoMunicipal   string
+	RegimeTributario     string   // models.Regime*
+	FaturamentoAnual     *float64 // nil = não informado (o porte sai só dos funcionários)
+	Tags                 []string
+	CustomFields         map[string]any // conferidos com as definições (CustomFieldRepository)
+
+	// Replace pelo GraphQL/gRPC, que só trazem cnpj, nomes, endereço e funcionários:
+	// os demais campos (PCD contratados, CNAEs, IE/IM, regime, faturamento, tags e
+	// custom_fields) são mantidos como estão gravados em vez de apagados
+	KeepExtras bool
+}
+
+// keepExtras copia da empresa gravada os campos fora do input básico
+func (in *CompanyInput) keepExtras(c *models.Company) {
+	in.NumeroPCDContratados = c.NumeroPCDContratados
+	in.CNAEPrincipal = c.CNAEPrincipal
+	in.CNAESecundarios = c.CNAESecundarios
+	in.InscricaoEstadual = c.InscricaoEstadual
+	in.InscricaoEstadualUF = c.InscricaoEstadualUF
+	in.InscricaoMunicipal = c.InscricaoMunicipal
+	in.RegimeTributario = c.RegimeTributario
+	in.FaturamentoAnual = c.FaturamentoAnual
+	in.Tags = c.Tags
+}
+
+// Update parcial; nil = não muda
+type CompanyPatch struct {
+	CNPJ                 *string
+	NomeFantasia         *string
+	RazaoSocial          *string
+	Endereco             *string
+	EnderecoEstruturado  *models.Address // nil com Endereco != nil: o estruturado antigo é removido
+	NumeroFuncionarios   *int
+	NumeroPCDContratados *int
+	CNAEPrincipal        *string
+	CNAESecundarios      *[]string // lista vazia remove as secundárias
+	InscricaoEstadual    *string   // sempre junto com InscricaoEstadualUF
+	InscricaoEstadualUF  *string
+	InscricaoMunicipal   *string
+	RegimeTributario     *string
+	FaturamentoAnual     *float64
+	Tags                 *[]string      // substitui a lista inteira ([] remove)
+	CustomFields         map[string]any // só as chaves informadas mudam; nil remove o campo
+}
+
+// Com endereço estruturado, o texto (lido pela v1) é gerado a partir dele
+func (in *CompanyInput) normalizeAddress() {
+	if in.EnderecoEstruturado != nil {
+		in.EnderecoEstruturado = normalizeAddress(in.EnderecoEstruturado)
+		in.Endereco = in.EnderecoEstruturado.Line()
+	}
+}
+
+func (p *CompanyPatch) normalizeAddress() {
+	if p.EnderecoEstruturado != nil {
+		p.EnderecoEstruturado = normalizeAddress(p.EnderecoEstruturado)
+		line := p.EnderecoEstruturado.Line()
+		p.Endereco = &line
+	}
+}
+
+// normalizeCNAEs: só dígitos, sem repetidas e sem a principal entre as secundárias.
+// Devolve nil se secundarias for nil (Patch: não muda).
+func normalizeCNAEs(principal string, secundarias []string) (string, []string) {
+	principal = cnae.Sanitize(principal)
+	if secundarias == nil {
+		return principal, nil
+	}
+	out := []string{}
+	for _, c := range secundarias {
+		c = cnae.Sanitize(c)
+		if c != principal && !slices.Contains(out, c) {
+			out = append(out, c)
+		}
+	}
+	return principal, out
+}
+
+func normalizeAddress(a *models.Address) *models.Address {
+	out := *a
+	out.CEP = utils.SanitizeCNPJ(a.CEP) // só dígitos
+	return &out
+}
+
+func (s *Companies) List(ctx context.Context, f models.CompanyFilter, limit, skip int64) ([]models.Company, error) {
+	if f.IsZero() {
+		return s.Repo.GetAll(ctx, limit, skip)
+	}
+	return s.Repo.Find(ctx, f, limit, skip)
+}
+
+// Stats: agregados das empresas que passam no filtro (ver models.CompanyStats)
+func (s *Companies) Stats(ctx context.Context, f models.CompanyFilter, groupBy []string) (*models.CompanyStats, error) {
+	return s.Repo.Stats(ctx, f, groupBy)
+}
+
+// Get: empresa inexistente -> ErrNotFound; outras falhas (Mongo fora, timeout) seguem como estão
+func (s *Companies) Get(ctx context.Context, id string) (*models.Company, error) {
+	c, err := s.Repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrCompanyNotFound) || (err == nil && c == nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Companies) Create(ctx context.Context, in CompanyInput) (*models.Company, error) {
+	customFields, err := s.checkCustomFields(ctx, in.CustomFields, false)
+	if err != nil {
+		return nil, err
+	}
+	in.normalizeAddress()
+	principal, secundarias := normalizeCNAEs(in.CNAEPrincipal, in.CNAESecundarios)
+	c := models.Company{
+		CNPJ:               utils.SanitizeCNPJ(in.CNPJ),
+		NomeFantasia:       in.NomeFantasia,
+		RazaoSocial:        in.RazaoSocial,
+		Endereco:           in.Endereco,
+		NumeroFuncionarios: in.NumeroFuncionarios,
+
+		EnderecoEstruturado:  in.EnderecoEstruturado,
+		NumeroPCDContratados: in.NumeroPCDContratados,
+		CNAEPrincipal:        principal,
+		CNAESecundarios:      nilIfEmpty(secundarias),
+		InscricaoEstadual:    utils.SanitizeIE(in.InscricaoEstadual),
+		InscricaoEstadualUF:  strings.ToUpper(in.InscricaoEstadualUF),
+		InscricaoMunicipal:   utils.SanitizeIM(in.InscricaoMunicipal),
+		RegimeTributario:     in.RegimeTributario,
+		FaturamentoAnual:     in.FaturamentoAnual,
+		Porte:                s.porte(in.FaturamentoAnual, in.NumeroFuncionarios),
+		Tags:                 nilIfEmpty(normalizeTags(in.Tags)),
+		CustomFields:         customFields,
+	}
+	c.ID = c.CNPJ
+
+	if _, err := s.Repo.Create(ctx, &c); err != nil {
+		return nil, err
+	}
+
+	s.publishEvent("Cadastro", &c)
+	s.publishPCDChange(nil, &c)
+	return &c, nil
+}
+
+// Patch devolve o documento atualizado; (nil, nil) se ele não puder ser relido.
+func (s *Companies) Patch(ctx context.Context, id string, p CompanyPatch) (*models.Company, error) {
+	// Buscar atual para comparar CNPJ e (opcional) recalcular PCD
+	existing, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	customFields, err := s.checkCustomFields(ctx, p.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Monta o modelo para update apenas com campos presentes
+	upd := models.Company{}
+	p.normalizeAddress()
+
+	if p.CNPJ != nil {
+		cnpj := utils.SanitizeCNPJ(*p.CNPJ) // já validado pelo schema (format: cnpj)
+		// Só tente mudar se for diferente do atual
+		if cnpj != existing.CNPJ {
+			upd.CNPJ = cnpj
+		}
+	}
+	if p.NomeFantasia != nil {
+		upd.NomeFantasia = *p.NomeFantasia
+	}
+	if p.RazaoSocial != nil {
+		upd.RazaoSocial = *p.RazaoSocial
+	}
+	if p.Endereco != nil {
+		upd.Endereco = *p.Endereco
+		upd.EnderecoEstruturado = p.EnderecoEstruturado // nil na v1: o repositório remove o estruturado antigo
+	}
+
+	var always []string
+	if p.NumeroFuncionarios != nil {
+		upd.NumeroFuncionarios = *p.NumeroFuncionarios
+
+		upd.NumeroMinimoPCDExigidos = utils.ComputeMinPCD(upd.NumeroFuncionarios)
+		// abaixo de 100 funcionários a cota é 0: os dois são gravados mesmo zerados
+		always = append(always, models.FieldNumeroFuncionarios, models.FieldNumeroMinimoPCD)
+	}
+	upd.NumeroPCDContratados = p.NumeroPCDContratados
+	p.applyCNAEs(existing, &upd)
+	if p.InscricaoEstadual != nil && p.InscricaoEstadualUF != nil {
+		upd.InscricaoEstadual = utils.SanitizeIE(*p.InscricaoEstadual)
+		upd.InscricaoEstadualUF = strings.ToUpper(*p.InscricaoEstadualUF)
+	}
+	if p.InscricaoMunicipal != nil {
+		upd.InscricaoMunicipal = utils.SanitizeIM(*p.InscricaoMunicipal)
+	}
+	if p.RegimeTributario != nil {
+		upd.RegimeTributario = *p.RegimeTributario
+	}
+	// porte recalculado se faturamento ou funcionários mudarem (o outro vem do atual)
+	if p.FaturamentoAnual != nil || p.NumeroFuncionarios != nil {
+		faturamento, funcionarios := existing.FaturamentoAnual, existing.NumeroFuncionarios
+		if p.FaturamentoAnual != nil {
+			faturamento = p.FaturamentoAnual
+		}
+		if p.NumeroFuncionarios != nil {
+			funcionarios = *p.NumeroFuncionarios
+		}
+		upd.FaturamentoAnual = p.FaturamentoAnual
+		upd.Porte = s.porte(faturamento, funcionarios)
+	}
+	if p.Tags != nil {
+		upd.Tags = normalizeTags(*p.Tags)
+	}
+	upd.CustomFields = customFields
+
+	if s.ChangeRequests != nil {
+		if err := s.requireApproval(ctx, existing, PatchedCompany(existing, &upd, always...)); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Repo.Update(ctx, id, &upd, always...); err != nil {
+		return nil, err
+	}
+
+	// Retorna o doc atualizado
+	c2, _ := s.Repo.GetByID(ctx, id)
+	if c2 == nil {
+		return nil, nil
+	}
+	s.publishEvent("Edição", c2)
+	s.publishPCDChange(existing, c2)
+	return c2, nil
+}
+
+// applyCNAEs: principal e/ou secundárias informadas no patch. Uma nova principal
+// que já constava entre as secundárias sai delas (mesmo se a lista não veio no patch).
+func (p *CompanyPatch) applyCNAEs(existing *models.Company, upd *models.Company) {
+	if p.CNAEPrincipal == nil && p.CNAESecundarios == nil {
+		return
+	}
+	principal := existing.CNAEPrincipal
+	if p.CNAEPrincipal != nil {
+		principal = *p.CNAEPrincipal
+	}
+	secundarias := existing.CNAESecundarios
+	if p.CNAESecundarios != nil {
+		secundarias = *p.CNAESecundarios
+	}
+	if secundarias == nil {
+		secundarias = []string{}
+	}
+	upd.CNAEPrincipal, upd.CNAESecundarios = normalizeCNAEs(principal, secundarias)
+}
+
+// porte pelos limites configurados (ver utils.PorteThresholds.Porte)
+func (s *Companies) porte(faturamento *float64, funcionarios int) string {
+	t := s.PorteThresholds
+	if t.IsZero() {
+		t = utils.DefaultPorteThresholds
+	}
+	return t.Porte(faturamento, funcionarios)
+}
+
+// nilIfEmpty: documentos sem secundárias (ou sem tags) não gravam o campo
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+// Replace substitui o documento inteiro (PUT). O CNPJ é sempre o {id}:
+// a checagem de divergência com o body fica na porta de entrada.
+func (s *Companies) Replace(ctx context.Context, id string, in CompanyInput) (*models.Company, error) {
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	customFields := current.CustomFields // já conferidos quando foram gravados
+	if in.KeepExtras {
+		in.keepExtras(current)
+	} else if customFields, err = s.checkCustomFields(ctx, in.CustomFields, false); err != nil {
+		return nil, err
+	}
+	in.normalizeAddress()
+	principal, secundarias := normalizeCNAEs(in.CNAEPrincipal, in.CNAESecundarios)
+
+	// monta o documento COMPLETO que substituirá o atual (PUT = replace)
+	newDoc := models.Company{
+		ID:                      id, // preserva o mesmo _id
+		CNPJ:                    id,
+		NomeFantasia:            in.NomeFantasia,
+		RazaoSocial:             in.RazaoSocial,
+		Endereco:                in.Endereco,
+		EnderecoEstruturado:     in.EnderecoEstruturado,
+		NumeroFuncionarios:      in.NumeroFuncionarios,
+		NumeroMinimoPCDExigidos: utils.ComputeMinPCD(in.NumeroFuncionarios),
+		NumeroPCDContratados:    in.NumeroPCDContratados,
+		CNAEPrincipal:           principal,
+		CNAESecundarios:         nilIfEmpty(secundarias),
+		InscricaoEstadual:       utils.SanitizeIE(in.InscricaoEstadual),
+		InscricaoEstadualUF:     strings.ToUpper(in.InscricaoEstadualUF),
+		InscricaoMunicipal:      utils.SanitizeIM(in.InscricaoMunicipal),
+		RegimeTributario:        in.RegimeTributario,
+		FaturamentoAnual:        in.FaturamentoAnual,
+		Porte:                   s.porte(in.FaturamentoAnual, in.NumeroFuncionarios),
+		Tags:                    nilIfEmpty(normalizeTags(in.Tags)),
+		CustomFields:            customFields,
+		CreatedAt:               current.CreatedAt, // preserva criação
+		UpdatedAt:               time.Now(),
+	}
+
+	if err := s.requireApproval(ctx, current, &newDoc); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Replace(ctx, id, &newDoc); err != nil {
+		return nil, err
+	}
+
+	s.publishEvent("Edição", &newDoc)
+	s.publishPCDChange(current, &newDoc)
+	return &newDoc, nil
+}
+
+// Delete devolve a empresa removida (para o evento e para quem chamou)
+func (s *Companies) Delete(ctx context.Context, id string) (*models.Company, error) {
+	// Busca antes de deletar para logar o nome
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireApproval(ctx, c, nil); err != nil {
+		return nil, err
+	}
+	if err := s.deleteCompany(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// deleteCompany remove a empresa e os sub-recursos e publica a exclusão
+func (s *Companies) deleteCompany(ctx context.Context, c *models.Company) error {
+	id := c.ID
+	if err := s.Repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if s.Employees != nil {
+		// a empresa já foi removida: funcionários que sobrarem não são mais alcançáveis pela API
+		_ = s.Employees.DeleteByCompany(ctx, id)
+	}
+	if s.Contacts != nil {
+		_ = s.Contacts.DeleteByCompany(ctx, id)
+	}
+	if s.Partners != nil {
+		_ = s.Partners.DeleteByCompany(ctx, id)
+	}
+	if s.Documents != nil {
+		_ = s.Documents.DeleteByCompany(ctx, id)
+	}
+	if s.Ownerships != nil {
+		_ = s.Ownerships.DeleteByCompany(ctx, id)
+	}
+	if s.Notes != nil {
+		_ = s.Notes.DeleteByCompany(ctx, id)
+	}
+
+	s.publishEvent("Exclusão", c)
+	return nil
+}
+
+// texto do evento por ação (o header "action" continua cadastro|edição|exclusão)
+var eventMessageKeys = map[string]string{
+	"Cadastro": "event.created",
+	"Edição":   "event.updated",
+	"Exclusão": "event.deleted",
+}
+
+func (s *Companies) publishEvent(acao string, c *models.Company) {
+	if s.Pub == nil || c == nil {
+		return
+	}
+	lang := s.eventLang()
+	empresa := displayName(c)
+	msg := i18n.T(lang, eventMessageKeys[acao], empresa)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_ = s.Pub.Publish(ctx, msg, amqp.Table{
+		"action":     strings.ToLower(acao), // cadastro|edição|exclusão
+		"company_id": c.ID,
+		"cnpj":       c.CNPJ,
+		"nome":       empresa,
+		"lang":       string(lang),
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (s *Companies) eventLang() i18n.Lang {
+	if s.EventLang == "" {
+		return i18n.PtBR
+	}
+	return s.EventLang
+}
+
+// Escolhe o nome a exibir nos eventos
+func displayName(c *models.Company) string {
+	if c.NomeFantasia != "" {
+		return c.NomeFantasia
+	}
+	if c.RazaoSocial != "" {
+		return c.RazaoSocial
+	}
+	return c.CNPJ
+}
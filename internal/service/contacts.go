@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Contatos da empresa (com quem falar sobre a cota PCD, cadastro etc.).
+// Marcar um contato como principal desmarca o anterior.
+
+type ContactRepository interface {
+	List(ctx context.Context, companyID string, limit, skip int64) ([]models.Contact, error)
+	Get(ctx context.Context, companyID, id string) (*models.Contact, error)
+	Create(ctx context.Context, c *models.Contact) error
+	Replace(ctx context.Context, c *models.Contact) error
+	ClearPrimary(ctx context.Context, companyID, exceptID string) error
+	Delete(ctx context.Context, companyID, id string) error
+	DeleteByCompany(ctx context.Context, companyID string) error
+	MoveToCompany(ctx context.Context, from, to string) (int, error)
+}
+
+var errContactsDisabled = errors.New("contact repository not configured")
+
+// Dados de um contato (já validados pela porta de entrada)
+type ContactInput struct {
+	Nome               string
+	Cargo              string
+	Email              string
+	Telefone           string
+	Principal          bool
+	RepresentanteLegal bool
+}
+
+func (in ContactInput) contact(companyID string) models.Contact {
+	return models.Contact{
+		CompanyID:          companyID,
+		Nome:               in.Nome,
+		Cargo:              in.Cargo,
+		Email:              utils.NormalizeEmail(in.Email),
+		Telefone:           utils.SanitizePhone(in.Telefone),
+		Principal:          in.Principal,
+		RepresentanteLegal: in.RepresentanteLegal,
+	}
+}
+
+// contacts confere se o sub-recurso existe e devolve a empresa (usada nos eventos)
+func (s *Companies) contacts(ctx context.Context, companyID string) (ContactRepository, *models.Company, error) {
+	if s.Contacts == nil {
+		return nil, nil, errContactsDisabled
+	}
+	c, err := s.Get(ctx, companyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.Contacts, c, nil
+}
+
+func (s *Companies) ListContacts(ctx context.Context, companyID string, limit, skip int64) ([]models.Contact, error) {
+	repo, _, err := s.contacts(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx, companyID, limit, skip)
+}
+
+func (s *Companies) GetContact(ctx context.Context, companyID, id string) (*models.Contact, error) {
+	repo, _, err := s.contacts(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.Get(ctx, companyID, id)
+}
+
+func (s *Companies) CreateContact(ctx context.Context, companyID string, in ContactInput) (*models.Contact, error) {
+	repo, company, err := s.contacts(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	ct := in.contact(companyID)
+	if err := repo.Create(ctx, &ct); err != nil {
+		return nil, err
+	}
+	if ct.Principal {
+		if err := repo.ClearPrimary(ctx, companyID, ct.ID); err != nil {
+			return nil, err
+		}
+	}
+	s.publishContactEvent(events.ActionContactCreated, company, &ct)
+	return &ct, nil
+}
+
+func (s *Companies) ReplaceContact(ctx context.Context, companyID, id string, in ContactInput) (*models.Contact, error) {
+	repo, company, err := s.contacts(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := repo.Get(ctx, companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	ct := in.contact(companyID)
+	ct.ID, ct.CreatedAt = current.ID, current.CreatedAt
+	if err := repo.Replace(ctx, &ct); err != nil {
+		return nil, err
+	}
+	if ct.Principal {
+		if err := repo.ClearPrimary(ctx, companyID, ct.ID); err != nil {
+			return nil, err
+		}
+	}
+	s.publishContactEvent(events.ActionContactUpdated, company, &ct)
+	return &ct, nil
+}
+
+func (s *Companies) DeleteContact(ctx context.Context, companyID, id string) error {
+	repo, company, err := s.contacts(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	ct, err := repo.Get(ctx, companyID, id)
+	if err != nil {
+		return err
+	}
+	if err := repo.Delete(ctx, companyID, id); err != nil {
+		return err
+	}
+	s.publishContactEvent(events.ActionContactDeleted, company, ct)
+	return nil
+}
+
+// texto do evento de contato por ação
+var contactEventKeys = map[string]string{
+	events.ActionContactCreated: "event.contact_created",
+	events.ActionContactUpdated: "event.contact_updated",
+	events.ActionContactDeleted: "event.contact_deleted",
+}
+
+// publishContactEvent: mesmos headers do evento da empresa, mais contact_id e contact_nome
+func (s *Companies) publishContactEvent(action string, company *models.Company, ct *models.Contact) {
+	if s.Pub == nil || company == nil || ct == nil {
+		return
+	}
+	lang := s.eventLang()
+	empresa := displayName(company)
+	msg := i18n.T(lang, contactEventKeys[action], ct.Nome, empresa)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_ = s.Pub.Publish(ctx, msg, amqp.Table{
+		"action":       action,
+		"company_id":   company.ID,
+		"cnpj":         company.CNPJ,
+		"nome":         empresa,
+		"contact_id":   ct.ID,
+		"contact_nome": ct.Nome,
+		"lang":         string(lang),
+		"timestamp":    time.Now().UTC().Format(time.RFC3339),
+	})
+}
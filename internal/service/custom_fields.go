@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Tags e campos personalizados das empresas. As definições dos campos
+// (tipo, opções, obrigatoriedade) são cadastradas pelos administradores;
+// os valores enviados nas empresas são conferidos com elas a cada gravação.
+
+type CustomFieldRepository interface {
+	List(ctx context.Context) ([]models.CustomFieldDefinition, error)
+	Get(ctx context.Context, key string) (*models.CustomFieldDefinition, error)
+	Create(ctx context.Context, d *models.CustomFieldDefinition) error
+	Replace(ctx context.Context, d *models.CustomFieldDefinition) error
+	Delete(ctx context.Context, key string) error
+}
+
+// tamanho máximo de um valor do tipo string
+const customFieldMaxLen = 500
+
+var errCustomFieldsDisabled = errors.New("custom field repository not configured")
+
+// CustomFieldsError: valores de custom_fields que não conferem com as definições
+type CustomFieldsError struct {
+	Errors []utils.FieldError
+}
+
+func (e *CustomFieldsError) Error() string {
+	return fmt.Sprintf("invalid custom fields (%d errors)", len(e.Errors))
+}
+
+// Definição de campo (formato já validado pela porta de entrada)
+type CustomFieldInput struct {
+	Label    string
+	Type     string // models.CustomField*
+	Options  []string
+	Required bool
+}
+
+func (s *Companies) customFields() (CustomFieldRepository, error) {
+	if s.CustomFields == nil {
+		return nil, errCustomFieldsDisabled
+	}
+	return s.CustomFields, nil
+}
+
+func (s *Companies) ListCustomFields(ctx context.Context) ([]models.CustomFieldDefinition, error) {
+	repo, err := s.customFields()
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx)
+}
+
+func (s *Companies) GetCustomField(ctx context.Context, key string) (*models.CustomFieldDefinition, error) {
+	repo, err := s.customFields()
+	if err != nil {
+		return nil, err
+	}
+	return repo.Get(ctx, key)
+}
+
+func (s *Companies) CreateCustomField(ctx context.Context, key string, in CustomFieldInput) (*models.CustomFieldDefinition, error) {
+	repo, err := s.customFields()
+	if err != nil {
+		return nil, err
+	}
+	d := in.definition(key)
+	if err := repo.Create(ctx, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ReplaceCustomField: a key não muda. Valores já gravados nas empresas não são
+// reconferidos; passam pela nova definição na próxima gravação de cada empresa.
+func (s *Companies) ReplaceCustomField(ctx context.Context, key string, in CustomFieldInput) (*models.CustomFieldDefinition, error) {
+	repo, err := s.customFields()
+	if err != nil {
+		return nil, err
+	}
+	current, err := repo.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	d := in.definition(key)
+	d.CreatedAt = current.CreatedAt
+	if err := repo.Replace(ctx, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// DeleteCustomField: os valores gravados ficam nas empresas até serem
+// removidos (null no PATCH) ou a empresa ser substituída (PUT).
+func (s *Companies) DeleteCustomField(ctx context.Context, key string) error {
+	repo, err := s.customFields()
+	if err != nil {
+		return err
+	}
+	return repo.Delete(ctx, key)
+}
+
+func (in CustomFieldInput) definition(key string) models.CustomFieldDefinition {
+	d := models.CustomFieldDefinition{Key: key, Label: in.Label, Type: in.Type, Required: in.Required}
+	if in.Type == models.CustomFieldEnum {
+		for _, o := range in.Options {
+			if !slices.Contains(d.Options, o) {
+				d.Options = append(d.Options, o)
+			}
+		}
+	}
+	return d
+}
+
+// normalizeTags: minúsculas, sem espaços nas pontas e sem repetidas.
+// Devolve nil se tags for nil (Patch: não muda).
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	out := []string{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// checkCustomFields confere os valores com as definições e devolve-os no tipo
+// gravado (number -> float64, integer -> int64). No PATCH (partial), nil remove o
+// campo e os obrigatórios ausentes não são cobrados (só a remoção deles).
+func (s *Companies) checkCustomFields(ctx context.Context, values map[string]any, partial bool) (map[string]any, error) {
+	if len(values) == 0 && (partial || s.CustomFields == nil) {
+		return nil, nil
+	}
+	defs := map[string]models.CustomFieldDefinition{}
+	if s.CustomFields != nil {
+		list, err := s.CustomFields.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range list {
+			defs[d.Key] = d
+		}
+	}
+
+	var errs []utils.FieldError
+	out := make(map[string]any, len(values))
+	for _, key := range sortedKeys(values) {
+		field := "custom_fields." + key
+		v := values[key]
+		d, ok := defs[key]
+		switch {
+		case v == nil && partial:
+			// remoção: também vale para campos cuja definição já foi excluída
+			if ok && d.Required {
+				errs = append(errs, utils.FieldError{Field: field, Code: utils.FieldRequired})
+				continue
+			}
+			out[key] = nil
+		case !ok:
+			errs = append(errs, utils.FieldError{Field: field, Code: utils.FieldUnknown})
+		case v == nil:
+			if d.Required {
+				errs = append(errs, utils.FieldError{Field: field, Code: utils.FieldRequired})
+			}
+		default:
+			nv, fe := customFieldValue(d, v)
+			if fe != nil {
+				fe.Field = field
+				errs = append(errs, *fe)
+				continue
+			}
+			out[key] = nv
+		}
+	}
+	if !partial {
+		for _, key := range sortedKeys(defs) {
+			if _, ok := values[key]; !ok && defs[key].Required {
+				errs = append(errs, utils.FieldError{Field: "custom_fields." + key, Code: utils.FieldRequired})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &CustomFieldsError{Errors: errs}
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// customFieldValue: o valor no tipo da definição (Field do erro fica com quem chama)
+func customFieldValue(d models.CustomFieldDefinition, v any) (any, *utils.FieldError) {
+	switch d.Type {
+	case models.CustomFieldString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, &utils.FieldError{Code: utils.FieldInvalidType, Args: []any{d.Type}}
+		}
+		if len([]rune(s)) > customFieldMaxLen {
+			return nil, &utils.FieldError{Code: utils.FieldTooLong, Args: []any{customFieldMaxLen}}
+		}
+		return s, nil
+	case models.CustomFieldNumber:
+		f, ok := toFloat(v)
+		if !ok {
+			return nil, &utils.FieldError{Code: utils.FieldInvalidType, Args: []any{d.Type}}
+		}
+		return f, nil
+	case models.CustomFieldInteger:
+		f, ok := toFloat(v)
+		if !ok || f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+			return nil, &utils.FieldError{Code: utils.FieldInvalidType, Args: []any{d.Type}}
+		}
+		return int64(f), nil
+	case models.CustomFieldBoolean:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, &utils.FieldError{Code: utils.FieldInvalidType, Args: []any{d.Type}}
+		}
+		return b, nil
+	case models.CustomFieldDate:
+		s, ok := v.(string)
+		if !ok {
+			return nil, &utils.FieldError{Code: utils.FieldInvalidType, Args: []any{"string"}}
+		}
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return nil, &utils.FieldError{Code: utils.FieldInvalidDate}
+		}
+		return s, nil
+	case models.CustomFieldEnum:
+		s, ok := v.(string)
+		if !ok {
+			return nil, &utils.FieldError{Code: utils.FieldInvalidType, Args: []any{"string"}}
+		}
+		if !slices.Contains(d.Options, s) {
+			return nil, &utils.FieldError{Code: utils.FieldNotInEnum, Args: []any{strings.Join(d.Options, ", ")}}
+		}
+		return s, nil
+	}
+	return nil, &utils.FieldError{Code: utils.FieldInvalidType, Args: []any{d.Type}}
+}
+
+// números vindos do JSON (float64) ou de outras portas (inteiros)
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, !math.IsNaN(n) && !math.IsInf(n, 0)
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
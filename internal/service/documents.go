@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+)
+
+// Documentos anexados à empresa (contratos, cartão CNPJ, relatórios PCD...).
+// Não são editáveis: para trocar um arquivo, envie o novo e remova o antigo.
+
+type DocumentRepository interface {
+	List(ctx context.Context, companyID string, limit, skip int64) ([]models.Document, error)
+	Get(ctx context.Context, companyID, id string) (*models.Document, error)
+	Upload(ctx context.Context, d *models.Document, content io.Reader) error
+	Open(ctx context.Context, companyID, id string) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, companyID, id string) error
+	DeleteByCompany(ctx context.Context, companyID string) error
+	MoveToCompany(ctx context.Context, from, to string) (int, error)
+}
+
+var errDocumentsDisabled = errors.New("document repository not configured")
+
+// Arquivo enviado (content-type e tamanho já conferidos pela porta de entrada)
+type DocumentInput struct {
+	NomeArquivo string
+	ContentType string
+	Categoria   string // models.Document*; "" = sem categoria
+	Content     []byte
+}
+
+// documents confere se o sub-recurso existe e devolve a empresa (usada nos eventos)
+func (s *Companies) documents(ctx context.Context, companyID string) (DocumentRepository, *models.Company, error) {
+	if s.Documents == nil {
+		return nil, nil, errDocumentsDisabled
+	}
+	c, err := s.Get(ctx, companyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.Documents, c, nil
+}
+
+func (s *Companies) ListDocuments(ctx context.Context, companyID string, limit, skip int64) ([]models.Document, error) {
+	repo, _, err := s.documents(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx, companyID, limit, skip)
+}
+
+func (s *Companies) GetDocument(ctx context.Context, companyID, id string) (*models.Document, error) {
+	repo, _, err := s.documents(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.Get(ctx, companyID, id)
+}
+
+// UploadDocument grava o arquivo com o SHA-256 do conteúdo
+func (s *Companies) UploadDocument(ctx context.Context, companyID string, in DocumentInput) (*models.Document, error) {
+	repo, company, err := s.documents(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(in.Content)
+	d := models.Document{
+		CompanyID:   companyID,
+		NomeArquivo: in.NomeArquivo,
+		ContentType: in.ContentType,
+		Tamanho:     int64(len(in.Content)),
+		SHA256:      hex.EncodeToString(sum[:]),
+		Categoria:   in.Categoria,
+	}
+	if err := repo.Upload(ctx, &d, bytes.NewReader(in.Content)); err != nil {
+		return nil, err
+	}
+	s.publishDocumentEvent(events.ActionDocumentCreated, company, &d)
+	return &d, nil
+}
+
+// OpenDocument devolve os dados do documento e o conteúdo (quem chama fecha)
+func (s *Companies) OpenDocument(ctx context.Context, companyID, id string) (*models.Document, io.ReadSeekCloser, error) {
+	repo, _, err := s.documents(ctx, companyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	d, err := repo.Get(ctx, companyID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := repo.Open(ctx, companyID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, content, nil
+}
+
+func (s *Companies) DeleteDocument(ctx context.Context, companyID, id string) error {
+	repo, company, err := s.documents(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	d, err := repo.Get(ctx, companyID, id)
+	if err != nil {
+		return err
+	}
+	if err := repo.Delete(ctx, companyID, id); err != nil {
+		return err
+	}
+	s.publishDocumentEvent(events.ActionDocumentDeleted, company, d)
+	return nil
+}
+
+// texto do evento de documento por ação
+var documentEventKeys = map[string]string{
+	events.ActionDocumentCreated: "event.document_created",
+	events.ActionDocumentDeleted: "event.document_deleted",
+}
+
+// publishDocumentEvent: mesmos headers do evento da empresa, mais document_id, document_nome e sha256
+func (s *Companies) publishDocumentEvent(action string, company *models.Company, d *models.Document) {
+	if s.Pub == nil || company == nil || d == nil {
+		return
+	}
+	lang := s.eventLang()
+	empresa := displayName(company)
+	msg := i18n.T(lang, documentEventKeys[action], d.NomeArquivo, empresa)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_ = s.Pub.Publish(ctx, msg, amqp.Table{
+		"action":        action,
+		"company_id":    company.ID,
+		"cnpj":          company.CNPJ,
+		"nome":          empresa,
+		"document_id":   d.ID,
+		"document_nome": d.NomeArquivo,
+		"sha256":        d.SHA256,
+		"lang":          string(lang),
+		"timestamp":     time.Now().UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Empresas cadastradas em dobro (nomes parecidos, CNPJ digitado errado):
+// busca de candidatas por semelhança e fusão de duas empresas em uma.
+
+type MergeRepository interface {
+	List(ctx context.Context, targetID string, limit, skip int64) ([]models.CompanyMerge, error)
+	Create(ctx context.Context, m *models.CompanyMerge) error
+	Update(ctx context.Context, m *models.CompanyMerge) error
+	MoveToCompany(ctx context.Context, from, to string) (int, error)
+}
+
+// Transactor roda fn numa transação (o ctx passado a fn leva a sessão); sem
+// suporte no banco (Mongo standalone), roda fn direto
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Pesos da nota final; sem endereço numa das duas, o peso dele sai da conta
+const (
+	duplicateWeightCNPJ     = 0.4
+	duplicateWeightNome     = 0.4
+	duplicateWeightEndereco = 0.2
+
+	// CNPJs a partir desta distância de edição não contam como parecidos
+	duplicateMaxCNPJEdits = 4
+
+	// candidatas comparadas por busca (as mais recentes, se o pré-filtro trouxer mais)
+	duplicateCandidatesMax = 2_000
+
+	DefaultDuplicateMinScore = 0.6
+)
+
+var (
+	ErrMergeSelf        = errors.New("cannot merge a company into itself")
+	errMergesDisabled   = errors.New("merge repository not configured")
+	duplicateNameSuffix = []string{"ltda", "me", "epp", "eireli", "sa", "cia", "mei", "ss", "slu"}
+	// palavras comuns demais para pré-filtrar candidatas pelo nome
+	duplicateCommonWords = []string{"comercio", "servicos", "industria", "grupo", "brasil", "empresa", "companhia"}
+	duplicateAddressAbbr = strings.NewReplacer(" avenida ", " av ", " rua ", " r ", " alameda ", " al ", " praca ", " pc ", " rodovia ", " rod ", " estrada ", " est ")
+)
+
+// FindDuplicates: empresas com nota >= minScore, da maior para a menor nota.
+// Só as pré-filtradas no banco são comparadas (CNPJ com um trecho igual ou uma palavra
+// do nome em comum): sem nenhum dos dois, a nota não passa de 0.2 (só o endereço).
+// truncated: o pré-filtro trouxe mais que duplicateCandidatesMax e só as mais
+// recentes foram comparadas.
+func (s *Companies) FindDuplicates(ctx context.Context, id string, minScore float64, limit, skip int64) (list []models.DuplicateCandidate, truncated bool, err error) {
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	ref := newDuplicateKey(c)
+
+	cands, err := s.Repo.FindDuplicateCandidates(ctx, ref.query(c.ID), duplicateCandidatesMax+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if truncated = len(cands) > duplicateCandidatesMax; truncated {
+		cands = cands[:duplicateCandidatesMax]
+	}
+	out := []models.DuplicateCandidate{}
+	for i := range cands {
+		if cands[i].ID == c.ID {
+			continue
+		}
+		cand := duplicateScore(ref, newDuplicateKey(&cands[i]))
+		if cand.Score >= minScore {
+			cand.Company = cands[i]
+			out = append(out, cand)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Company.ID < out[j].Company.ID
+	})
+	if skip >= int64(len(out)) {
+		return []models.DuplicateCandidate{}, truncated, nil
+	}
+	out = out[skip:]
+	if limit < int64(len(out)) {
+		out = out[:limit]
+	}
+	return out, truncated, nil
+}
+
+// campos já normalizados para a comparação
+type duplicateKey struct {
+	cnpj     string
+	nomes    []string // fantasia e razão social, sem sufixos societários
+	endereco string
+	cep      string
+	numero   string
+}
+
+func newDuplicateKey(c *models.Company) duplicateKey {
+	k := duplicateKey{cnpj: c.CNPJ, endereco: duplicateAddress(c.Endereco)}
+	for _, n := range []string{c.NomeFantasia, c.RazaoSocial} {
+		if n = duplicateName(n); n != "" && !slices.Contains(k.nomes, n) {
+			k.nomes = append(k.nomes, n)
+		}
+	}
+	if a := c.EnderecoEstruturado; a != nil {
+		k.cep, k.numero = a.CEP, utils.FoldText(a.Numero)
+	}
+	return k
+}
+
+// query: pré-filtro do banco. Palavras do nome com 3 letras ou mais, tirando as
+// comuns demais (se o nome só tiver delas, ficam todas).
+func (k duplicateKey) query(excludeID string) models.DuplicateQuery {
+	q := models.DuplicateQuery{ExcludeID: excludeID, CNPJ: k.cnpj}
+	var common []string
+	for _, n := range k.nomes {
+		for _, w := range strings.Fields(n) {
+			if len([]rune(w)) < 3 || strings.IndexFunc(w, unicode.IsDigit) >= 0 ||
+				slices.Contains(duplicateNameSuffix, w) || slices.Contains(q.NameWords, w) || slices.Contains(common, w) {
+				continue
+			}
+			if slices.Contains(duplicateCommonWords, w) {
+				common = append(common, w)
+				continue
+			}
+			q.NameWords = append(q.NameWords, w)
+		}
+	}
+	if len(q.NameWords) == 0 {
+		q.NameWords = common
+	}
+	return q
+}
+
+// duplicateName: sem acentos e sem os sufixos societários do fim ("Acme Ltda - ME" -> "acme")
+func duplicateName(s string) string {
+	words := strings.Fields(utils.FoldText(s))
+	for len(words) > 1 && slices.Contains(duplicateNameSuffix, words[len(words)-1]) {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+func duplicateAddress(s string) string {
+	return strings.TrimSpace(duplicateAddressAbbr.Replace(" " + utils.FoldText(s) + " "))
+}
+
+func duplicateScore(a, b duplicateKey) models.DuplicateCandidate {
+	var sc models.DuplicateScores
+	if d := utils.EditDistance(a.cnpj, b.cnpj); d < duplicateMaxCNPJEdits {
+		sc.CNPJ = 1 - float64(d)/duplicateMaxCNPJEdits
+	}
+	for _, x := range a.nomes {
+		for _, y := range b.nomes {
+			sc.Nome = max(sc.Nome, utils.Similarity(x, y), utils.TokenSimilarity(x, y))
+		}
+	}
+	hasAddress := a.endereco != "" && b.endereco != ""
+	if hasAddress {
+		sc.Endereco = max(utils.Similarity(a.endereco, b.endereco), utils.TokenSimilarity(a.endereco, b.endereco))
+		if a.cep != "" && a.cep == b.cep && a.numero != "" && a.numero == b.numero {
+			sc.Endereco = 1 // mesmo CEP e número: mesmo lugar, escrito de outro jeito
+		}
+	}
+
+	total := duplicateWeightCNPJ*sc.CNPJ + duplicateWeightNome*sc.Nome
+	weights := duplicateWeightCNPJ + duplicateWeightNome
+	if hasAddress {
+		total += duplicateWeightEndereco * sc.Endereco
+		weights += duplicateWeightEndereco
+	}
+	return models.DuplicateCandidate{Score: round3(total / weights), Scores: models.DuplicateScores{
+		CNPJ: round3(sc.CNPJ), Nome: round3(sc.Nome), Endereco: round3(sc.Endereco),
+	}}
+}
+
+func round3(f float64) float64 {
+	return float64(int(f*1000+0.5)) / 1000
+}
+
+func (s *Companies) ListMerges(ctx context.Context, id string, limit, skip int64) ([]models.CompanyMerge, error) {
+	if s.Merges == nil {
+		return nil, errMergesDisabled
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.Merges.List(ctx, id, limit, skip)
+}
+
+// MergeCompanies absorve sourceID em targetID: os campos vazios da que fica são
+// preenchidos com os da absorvida (tags, secundárias e campos personalizados somam),
+// os sub-recursos passam para a que fica e a absorvida é removida. As duas, como
+// estavam, ficam no histórico (company_merges), gravado antes de qualquer mudança,
+// junto com os registros descartados por repetição. Com s.Tx, tudo numa transação.
+// Publica o evento fusão. Com a aprovação de exclusão ligada, a fusão vira pedido
+// (*ApprovalRequiredError), aplicado pelo ApproveChange.
+func (s *Companies) MergeCompanies(ctx context.Context, targetID, sourceID string) (*models.Company, *models.CompanyMerge, error) {
+	if s.Merges == nil {
+		return nil, nil, errMergesDisabled
+	}
+	if targetID == sourceID {
+		return nil, nil, ErrMergeSelf
+	}
+	target, err := s.Get(ctx, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	source, err := s.Get(ctx, sourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.requireMergeApproval(ctx, target, source); err != nil {
+		return nil, nil, err
+	}
+	return s.merge(ctx, target, source)
+}
+
+func (s *Companies) merge(ctx context.Context, target, source *models.Company) (*models.Company, *models.CompanyMerge, error) {
+	if s.Merges == nil {
+		return nil, nil, errMergesDisabled
+	}
+	var (
+		m      models.CompanyMerge
+		merged *models.Company
+	)
+	err := s.inTransaction(ctx, func(ctx context.Context) error {
+		// a transação pode ser repetida: tudo é refeito a partir das duas lidas acima
+		m = models.CompanyMerge{TargetID: target.ID, SourceID: source.ID, Target: *target, Source: *source, MergedAt: time.Now().UTC()}
+		// histórico antes de mexer em qualquer coisa: os dados originais ficam guardados
+		if err := s.Merges.Create(ctx, &m); err != nil {
+			return err
+		}
+		if err := s.moveSubResources(ctx, source.ID, target.ID, &m); err != nil {
+			return err
+		}
+		if err := s.Merges.Update(ctx, &m); err != nil {
+			return err
+		}
+
+		merged = mergedCompany(target, source)
+		if s.Employees != nil && m.Moved.Employees > 0 {
+			// com funcionários transferidos, o quadro sai do cadastro de funcionários
+			total, pcd, err := s.Employees.Count(ctx, target.ID, time.Now().Format(time.DateOnly))
+			if err != nil {
+				return err
+			}
+			merged.NumeroFuncionarios, merged.NumeroPCDContratados = total, &pcd
+		}
+		merged.NumeroMinimoPCDExigidos = utils.ComputeMinPCD(merged.NumeroFuncionarios)
+		merged.Porte = s.porte(merged.FaturamentoAnual, merged.NumeroFuncionarios)
+		merged.UpdatedAt = time.Now()
+
+		if err := s.Repo.Replace(ctx, target.ID, merged); err != nil {
+			return err
+		}
+		return s.Repo.Delete(ctx, source.ID)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.publishMergeEvent(merged, source)
+	s.publishPCDChange(target, merged)
+	return merged, &m, nil
+}
+
+func (s *Companies) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.Tx == nil {
+		return fn(ctx)
+	}
+	return s.Tx.WithTransaction(ctx, fn)
+}
+
+// moveSubResources preenche m.Moved e m.Dropped
+func (s *Companies) moveSubResources(ctx context.Context, from, to string, m *models.CompanyMerge) error {
+	var err error
+	moved, dropped := &m.Moved, &m.Dropped
+	if s.Employees != nil {
+		if moved.Employees, dropped.Employees, err = s.Employees.MoveToCompany(ctx, from, to); err != nil {
+			return err
+		}
+	}
+	if s.Contacts != nil {
+		if moved.Contacts, err = s.Contacts.MoveToCompany(ctx, from, to); err != nil {
+			return err
+		}
+	}
+	if s.Partners != nil {
+		if moved.Partners, dropped.Partners, err = s.Partners.MoveToCompany(ctx, from, to); err != nil {
+			return err
+		}
+	}
+	if s.Documents != nil {
+		if moved.Documents, err = s.Documents.MoveToCompany(ctx, from, to); err != nil {
+			return err
+		}
+	}
+	if s.Ownerships != nil {
+		if moved.Ownerships, dropped.Ownerships, err = s.Ownerships.MoveToCompany(ctx, from, to); err != nil {
+			return err
+		}
+	}
+	if s.Notes != nil {
+		if moved.Notes, err = s.Notes.MoveToCompany(ctx, from, to); err != nil {
+			return err
+		}
+	}
+	moved.Merges, err = s.Merges.MoveToCompany(ctx, from, to)
+	return err
+}
+
+// mergedCompany: a que fica, com os campos vazios preenchidos pela absorvida
+func mergedCompany(target, source *models.Company) *models.Company {
+	out := *target
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&out.NomeFantasia, source.NomeFantasia)
+	fill(&out.RazaoSocial, source.RazaoSocial)
+	if out.Endereco == "" {
+		out.Endereco, out.EnderecoEstruturado = source.Endereco, source.EnderecoEstruturado
+	}
+	if out.InscricaoEstadual == "" {
+		out.InscricaoEstadual, out.InscricaoEstadualUF = source.InscricaoEstadual, source.InscricaoEstadualUF
+	}
+	fill(&out.InscricaoMunicipal, source.InscricaoMunicipal)
+	fill(&out.RegimeTributario, source.RegimeTributario)
+	if out.FaturamentoAnual == nil {
+		out.FaturamentoAnual = source.FaturamentoAnual
+	}
+	if out.NumeroFuncionarios == 0 {
+		out.NumeroFuncionarios = source.NumeroFuncionarios
+	}
+	if out.NumeroPCDContratados == nil {
+		out.NumeroPCDContratados = source.NumeroPCDContratados
+	}
+
+	secundarias := slices.Clone(target.CNAESecundarios)
+	if out.CNAEPrincipal == "" {
+		out.CNAEPrincipal = source.CNAEPrincipal
+	} else if source.CNAEPrincipal != "" {
+		secundarias = append(secundarias, source.CNAEPrincipal)
+	}
+	_, secundarias = normalizeCNAEs(out.CNAEPrincipal, append(secundarias, source.CNAESecundarios...))
+	out.CNAESecundarios = nilIfEmpty(secundarias)
+
+	out.Tags = nilIfEmpty(normalizeTags(append(slices.Clone(target.Tags), source.Tags...)))
+	if len(source.CustomFields) > 0 {
+		out.CustomFields = map[string]any{}
+		for k, v := range source.CustomFields {
+			out.CustomFields[k] = v
+		}
+		for k, v := range target.CustomFields {
+			out.CustomFields[k] = v
+		}
+	}
+	if !source.CreatedAt.IsZero() && source.CreatedAt.Before(out.CreatedAt) {
+		out.CreatedAt = source.CreatedAt // a mais antiga das duas
+	}
+	return &out
+}
+
+// publishMergeEvent: headers do evento da empresa que ficou, mais merged_id e merged_cnpj
+func (s *Companies) publishMergeEvent(target, source *models.Company) {
+	if s.Pub == nil {
+		return
+	}
+	lang := s.eventLang()
+	empresa := displayName(target)
+	msg := i18n.T(lang, "event.merged", displayName(source), empresa)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_ = s.Pub.Publish(ctx, msg, amqp.Table{
+		"action":      events.ActionMerged,
+		"company_id":  target.ID,
+		"cnpj":        target.CNPJ,
+		"nome":        empresa,
+		"merged_id":   source.ID,
+		"merged_cnpj": source.CNPJ,
+		"merged_nome": displayName(source),
+		"lang":        string(lang),
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Funcionários da empresa. Toda mudança recalcula, a partir dos ativos hoje,
+// numero_funcionarios, numero_pcd_contratados e numero_minimo_pcd_exigidos
+// (valores digitados à mão na empresa são sobrescritos). A queda de funcionários
+// segue a mesma aprovação do PATCH: o funcionário é gravado e os números da
+// empresa viram pedido de mudança (*ApprovalRequiredError).
+
+type EmployeeRepository interface {
+	List(ctx context.Context, companyID string, f models.EmployeeFilter, limit, skip int64) ([]models.Employee, error)
+	Count(ctx context.Context, companyID, activeOn string) (total, pcd int, err error)
+	Get(ctx context.Context, companyID, id string) (*models.Employee, error)
+	Create(ctx context.Context, e *models.Employee) error
+	Replace(ctx context.Context, e *models.Employee) error
+	Delete(ctx context.Context, companyID, id string) error
+	DeleteByCompany(ctx context.Context, companyID string) error
+	MoveToCompany(ctx context.Context, from, to string) (moved int, dropped []models.Employee, err error)
+	Upsert(ctx context.Context, companyID string, list []models.Employee) (created, updated int, err error)
+}
+
+var errEmployeesDisabled = errors.New("employee repository not configured")
+
+// Dados de um funcionário (já validados pela porta de entrada)
+type EmployeeInput struct {
+	Nome         string
+	CPF          string
+	Admissao     string  // YYYY-MM-DD
+	Desligamento *string // nil = ativo
+	PCD          bool
+}
+
+func (in EmployeeInput) employee(companyID string) models.Employee {
+	return models.Employee{
+		CompanyID:    companyID,
+		Nome:         in.Nome,
+		CPF:          utils.SanitizeCNPJ(in.CPF), // só dígitos
+		Admissao:     in.Admissao,
+		Desligamento: in.Desligamento,
+		PCD:          in.PCD,
+	}
+}
+
+// Números da empresa derivados dos funcionários ativos em AsOf
+type Headcount struct {
+	AsOf                    string `json:"as_of"`
+	NumeroFuncionarios      int    `json:"numero_funcionarios"`
+	NumeroPCDContratados    int    `json:"numero_pcd_contratados"`
+	NumeroMinimoPCDExigidos int    `json:"numero_minimo_pcd_exigidos"`
+}
+
+type EmployeeImport struct {
+	Created   int       `json:"created"`
+	Updated   int       `json:"updated"`
+	Headcount Headcount `json:"headcount"`
+}
+
+func (s *Companies) employees(ctx context.Context, companyID string) (EmployeeRepository, error) {
+	if s.Employees == nil {
+		return nil, errEmployeesDisabled
+	}
+	if _, err := s.Get(ctx, companyID); err != nil {
+		return nil, err
+	}
+	return s.Employees, nil
+}
+
+// changeEmployees: employees para gravar. Com a regra de queda de funcionários ligada,
+// o recálculo pode virar pedido, então o usuário é exigido antes de mexer em algo.
+func (s *Companies) changeEmployees(ctx context.Context, companyID string) (EmployeeRepository, error) {
+	if err := s.headcountUser(ctx); err != nil {
+		return nil, err
+	}
+	return s.employees(ctx, companyID)
+}
+
+func (s *Companies) headcountUser(ctx context.Context) error {
+	if s.ChangeRequests != nil && s.Approvals.HeadcountDropPercent > 0 && UserFrom(ctx) == "" {
+		return ErrUserRequired
+	}
+	return nil
+}
+
+func (s *Companies) ListEmployees(ctx context.Context, companyID string, f models.EmployeeFilter, limit, skip int64) ([]models.Employee, error) {
+	repo, err := s.employees(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx, companyID, f, limit, skip)
+}
+
+func (s *Companies) GetEmployee(ctx context.Context, companyID, id string) (*models.Employee, error) {
+	repo, err := s.employees(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.Get(ctx, companyID, id)
+}
+
+func (s *Companies) CreateEmployee(ctx context.Context, companyID string, in EmployeeInput) (*models.Employee, error) {
+	repo, err := s.changeEmployees(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	e := in.employee(companyID)
+	if err := repo.Create(ctx, &e); err != nil {
+		return nil, err
+	}
+	_, err = s.syncHeadcount(ctx, repo, companyID)
+	return &e, err
+}
+
+func (s *Companies) ReplaceEmployee(ctx context.Context, companyID, id string, in EmployeeInput) (*models.Employee, error) {
+	repo, err := s.changeEmployees(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := repo.Get(ctx, companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	e := in.employee(companyID)
+	e.ID, e.CreatedAt = current.ID, current.CreatedAt
+	if err := repo.Replace(ctx, &e); err != nil {
+		return nil, err
+	}
+	_, err = s.syncHeadcount(ctx, repo, companyID)
+	return &e, err
+}
+
+func (s *Companies) DeleteEmployee(ctx context.Context, companyID, id string) error {
+	repo, err := s.changeEmployees(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	if err := repo.Delete(ctx, companyID, id); err != nil {
+		return err
+	}
+	_, err = s.syncHeadcount(ctx, repo, companyID)
+	return err
+}
+
+// ImportEmployees grava a lista (importação CSV) por CPF: novos são criados, os
+// existentes atualizados; quem não está na lista não é alterado.
+func (s *Companies) ImportEmployees(ctx context.Context, companyID string, list []EmployeeInput) (*EmployeeImport, error) {
+	repo, err := s.changeEmployees(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]models.Employee, len(list))
+	for i, in := range list {
+		docs[i] = in.employee(companyID)
+	}
+	created, updated, err := repo.Upsert(ctx, companyID, docs)
+	if err != nil {
+		return nil, err
+	}
+	hc, err := s.syncHeadcount(ctx, repo, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return &EmployeeImport{Created: created, Updated: updated, Headcount: *hc}, nil
+}
+
+// SyncHeadcount recalcula os números da empresa pelos funcionários ativos hoje.
+// Só grava (e publica os eventos de edição e de cota PCD) se algo mudou; queda que
+// exige aprovação vira pedido, como no PATCH.
+// Também serve para refletir desligamentos com data futura quando a data chega.
+func (s *Companies) SyncHeadcount(ctx context.Context, companyID string) (*Headcount, error) {
+	if s.Employees == nil {
+		return nil, errEmployeesDisabled
+	}
+	if err := s.headcountUser(ctx); err != nil {
+		return nil, err
+	}
+	return s.syncHeadcount(ctx, s.Employees, companyID)
+}
+
+func (s *Companies) syncHeadcount(ctx context.Context, repo EmployeeRepository, companyID string) (*Headcount, error) {
+	current, err := s.Get(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now().Format(time.DateOnly)
+	total, pcd, err := repo.Count(ctx, companyID, today)
+	if err != nil {
+		return nil, err
+	}
+	hc := &Headcount{
+		AsOf:                    today,
+		NumeroFuncionarios:      total,
+		NumeroPCDContratados:    pcd,
+		NumeroMinimoPCDExigidos: utils.ComputeMinPCD(total),
+	}
+	porte := s.porte(current.FaturamentoAnual, total)
+	if current.NumeroFuncionarios == hc.NumeroFuncionarios &&
+		current.NumeroMinimoPCDExigidos == hc.NumeroMinimoPCDExigidos && current.Porte == porte &&
+		current.NumeroPCDContratados != nil && *current.NumeroPCDContratados == pcd {
+		return hc, nil
+	}
+
+	// só os números e o porte: um PATCH gravado no meio do caminho não é desfeito;
+	// always porque o quadro pode ficar vazio
+	always := []string{models.FieldNumeroFuncionarios, models.FieldNumeroMinimoPCD}
+	upd := models.Company{
+		NumeroFuncionarios:      hc.NumeroFuncionarios,
+		NumeroMinimoPCDExigidos: hc.NumeroMinimoPCDExigidos,
+		NumeroPCDContratados:    &pcd,
+		Porte:                   porte,
+	}
+	if err := s.requireApproval(ctx, current, PatchedCompany(current, &upd, always...)); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Update(ctx, companyID, &upd, always...); err != nil {
+		return nil, err
+	}
+	c2, _ := s.Repo.GetByID(ctx, companyID)
+	if c2 == nil {
+		c2 = PatchedCompany(current, &upd, always...)
+	}
+	s.publishEvent("Edição", c2)
+	s.publishPCDChange(current, c2)
+	return hc, nil
+}
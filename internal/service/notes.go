@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+)
+
+// Notas do atendimento e linha do tempo da empresa: notas e eventos publicados
+// (gravados pelo events.Log), mais recentes primeiro, paginados por cursor.
+
+type NoteRepository interface {
+	List(ctx context.Context, companyID string, limit, skip int64) ([]models.Note, error)
+	Before(ctx context.Context, companyID string, at time.Time, id string, limit int64) ([]models.Note, error)
+	Get(ctx context.Context, companyID, id string) (*models.Note, error)
+	Create(ctx context.Context, n *models.Note) error
+	Replace(ctx context.Context, n *models.Note) error
+	Delete(ctx context.Context, companyID, id string) error
+	DeleteByCompany(ctx context.Context, companyID string) error
+	MoveToCompany(ctx context.Context, from, to string) (int, error)
+}
+
+type EventRepository interface {
+	Before(ctx context.Context, companyID string, at time.Time, id string, limit int64) ([]models.CompanyEvent, error)
+}
+
+var (
+	errNotesDisabled = errors.New("note repository not configured")
+	ErrInvalidCursor = errors.New("invalid timeline cursor")
+)
+
+// Dados de uma nota (já validados pela porta de entrada)
+type NoteInput struct {
+	Autor string
+	Texto string
+	Tipo  string
+}
+
+func (in NoteInput) note(companyID string) models.Note {
+	return models.Note{
+		CompanyID: companyID,
+		Autor:     strings.TrimSpace(in.Autor),
+		Texto:     strings.TrimSpace(in.Texto),
+		Tipo:      in.Tipo,
+	}
+}
+
+func (s *Companies) notes(ctx context.Context, companyID string) (NoteRepository, error) {
+	if s.Notes == nil {
+		return nil, errNotesDisabled
+	}
+	if _, err := s.Get(ctx, companyID); err != nil {
+		return nil, err
+	}
+	return s.Notes, nil
+}
+
+func (s *Companies) ListNotes(ctx context.Context, companyID string, limit, skip int64) ([]models.Note, error) {
+	repo, err := s.notes(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx, companyID, limit, skip)
+}
+
+func (s *Companies) GetNote(ctx context.Context, companyID, id string) (*models.Note, error) {
+	repo, err := s.notes(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.Get(ctx, companyID, id)
+}
+
+func (s *Companies) CreateNote(ctx context.Context, companyID string, in NoteInput) (*models.Note, error) {
+	repo, err := s.notes(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	n := in.note(companyID)
+	if err := repo.Create(ctx, &n); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (s *Companies) ReplaceNote(ctx context.Context, companyID, id string, in NoteInput) (*models.Note, error) {
+	repo, err := s.notes(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := repo.Get(ctx, companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	n := in.note(companyID)
+	n.ID, n.CreatedAt = current.ID, current.CreatedAt
+	if err := repo.Replace(ctx, &n); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (s *Companies) DeleteNote(ctx context.Context, companyID, id string) error {
+	repo, err := s.notes(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	return repo.Delete(ctx, companyID, id)
+}
+
+// Timeline: uma página da linha do tempo a partir do cursor (vazio = do mais recente).
+// Sem o repositório de eventos a linha do tempo só tem as notas.
+func (s *Companies) Timeline(ctx context.Context, companyID, cursor string, limit int64) (*models.TimelinePage, error) {
+	at, id, err := decodeTimelineCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := s.notes(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// limit+1 de cada fonte: o que sobrar indica que há próxima página
+	notes, err := repo.Before(ctx, companyID, at, id, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.TimelineItem, 0, len(notes))
+	for i := range notes {
+		n := &notes[i]
+		items = append(items, models.TimelineItem{Kind: models.TimelineNote, ID: n.ID, At: n.CreatedAt, Note: n})
+	}
+	if s.Events != nil {
+		evs, err := s.Events.Before(ctx, companyID, at, id, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		for i := range evs {
+			ev := &evs[i]
+			items = append(items, models.TimelineItem{Kind: models.TimelineEvent, ID: ev.ID, At: ev.Timestamp, Event: ev})
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].At.Equal(items[j].At) {
+			return items[i].At.After(items[j].At)
+		}
+		return items[i].ID > items[j].ID
+	})
+	page := &models.TimelinePage{Items: items}
+	if int64(len(items)) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = encodeTimelineCursor(last.At, last.ID)
+	}
+	return page, nil
+}
+
+// cursor: base64url de "<unix nano>:<id>" do último item da página anterior
+func encodeTimelineCursor(at time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", at.UnixNano(), id)))
+}
+
+func decodeTimelineCursor(cursor string) (time.Time, string, error) {
+	if cursor == "" {
+		return time.Time{}, "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	ns, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(ns, 10, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	return time.Unix(0, n).UTC(), id, nil
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Grupo econômico: participações (arestas controladora -> controlada, com percentual)
+// entre empresas cadastradas. A soma das controladoras de uma empresa não passa de
+// 100% e uma participação que fecharia um ciclo (A -> B -> A) é recusada.
+
+type OwnershipRepository interface {
+	List(ctx context.Context, parentID string, limit, skip int64) ([]models.Ownership, error)
+	ByParents(ctx context.Context, ids []string) ([]models.Ownership, error)
+	ByChildren(ctx context.Context, ids []string) ([]models.Ownership, error)
+	ShareTotal(ctx context.Context, childID, exceptParentID string) (float64, error)
+	Get(ctx context.Context, parentID, childID string) (*models.Ownership, error)
+	Create(ctx context.Context, o *models.Ownership) error
+	Replace(ctx context.Context, o *models.Ownership) error
+	Delete(ctx context.Context, parentID, childID string) error
+	DeleteByCompany(ctx context.Context, companyID string) error
+	MoveToCompany(ctx context.Context, from, to string) (moved int, dropped []models.Ownership, err error)
+}
+
+// limite de empresas percorridas a partir de uma (subindo ou descendo no grafo)
+const ownershipMaxCompanies = 5000
+
+var (
+	errOwnershipsDisabled = errors.New("ownership repository not configured")
+
+	ErrOwnershipSelf  = errors.New("company cannot own itself")
+	ErrOwnershipCycle = errors.New("ownership would create a cycle")
+)
+
+func (s *Companies) ownerships(ctx context.Context, companyID string) (OwnershipRepository, error) {
+	if s.Ownerships == nil {
+		return nil, errOwnershipsDisabled
+	}
+	if _, err := s.Get(ctx, companyID); err != nil {
+		return nil, err
+	}
+	return s.Ownerships, nil
+}
+
+// ListSubsidiaries: controladas diretas
+func (s *Companies) ListSubsidiaries(ctx context.Context, parentID string, limit, skip int64) ([]models.Ownership, error) {
+	repo, err := s.ownerships(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx, parentID, limit, skip)
+}
+
+func (s *Companies) GetSubsidiary(ctx context.Context, parentID, childID string) (*models.Ownership, error) {
+	repo, err := s.ownerships(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.Get(ctx, parentID, childID)
+}
+
+// CreateSubsidiary: parentID passa a ter percentual da empresa childID
+func (s *Companies) CreateSubsidiary(ctx context.Context, parentID, childID string, percentual float64) (*models.Ownership, error) {
+	repo, err := s.ownerships(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if childID == parentID {
+		return nil, ErrOwnershipSelf
+	}
+	if _, err := s.Get(ctx, childID); err != nil {
+		return nil, err
+	}
+	// parentID já é controlada (direta ou indiretamente) por childID?
+	g, err := s.walkOwnership(ctx, childID, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := g.depth[parentID]; ok {
+		return nil, ErrOwnershipCycle
+	}
+	o := models.Ownership{ParentID: parentID, ChildID: childID, Percentual: percentual}
+	if err := checkOwnershipShare(ctx, repo, &o); err != nil {
+		return nil, err
+	}
+	if err := repo.Create(ctx, &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (s *Companies) ReplaceSubsidiary(ctx context.Context, parentID, childID string, percentual float64) (*models.Ownership, error) {
+	repo, err := s.ownerships(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	o, err := repo.Get(ctx, parentID, childID)
+	if err != nil {
+		return nil, err
+	}
+	o.Percentual = percentual
+	if err := checkOwnershipShare(ctx, repo, o); err != nil {
+		return nil, err
+	}
+	if err := repo.Replace(ctx, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (s *Companies) DeleteSubsidiary(ctx context.Context, parentID, childID string) error {
+	repo, err := s.ownerships(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	return repo.Delete(ctx, parentID, childID)
+}
+
+// Ancestors: controladoras diretas e indiretas, com a participação efetiva de cada uma na empresa
+func (s *Companies) Ancestors(ctx context.Context, id string) ([]models.GroupMember, error) {
+	return s.groupMembers(ctx, id, false)
+}
+
+// Descendants: controladas diretas e indiretas, com a participação efetiva da empresa em cada uma
+func (s *Companies) Descendants(ctx context.Context, id string) ([]models.GroupMember, error) {
+	return s.groupMembers(ctx, id, true)
+}
+
+func (s *Companies) groupMembers(ctx context.Context, id string, down bool) ([]models.GroupMember, error) {
+	if _, err := s.ownerships(ctx, id); err != nil {
+		return nil, err
+	}
+	g, err := s.walkOwnership(ctx, id, down)
+	if err != nil {
+		return nil, err
+	}
+	topo, _ := g.dfs(id)
+	eff := g.effective(topo)
+
+	out := []models.GroupMember{}
+	if len(g.order) == 0 {
+		return out, nil
+	}
+	list, err := s.Repo.GetMany(ctx, g.order)
+	if err != nil {
+		return nil, err
+	}
+	// aresta de empresa já removida: fica de fora
+	for _, c := range list {
+		out = append(out, models.GroupMember{Company: c, Depth: g.depth[c.ID], Percentual: math.Round(eff[c.ID]*1e6) / 1e4})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Depth != out[j].Depth {
+			return out[i].Depth < out[j].Depth
+		}
+		return out[i].Company.ID < out[j].Company.ID
+	})
+	return out, nil
+}
+
+// OwnershipCycles: ciclos alcançáveis a partir da empresa, subindo ou descendo no grafo
+// (um por aresta que fecha o ciclo), no sentido controladora -> controlada.
+func (s *Companies) OwnershipCycles(ctx context.Context, id string) ([]models.OwnershipCycle, error) {
+	if _, err := s.ownerships(ctx, id); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	out := []models.OwnershipCycle{}
+	for _, down := range []bool{true, false} {
+		g, err := s.walkOwnership(ctx, id, down)
+		if err != nil {
+			return nil, err
+		}
+		_, cycles := g.dfs(id)
+		for _, c := range cycles {
+			if !down {
+				slices.Reverse(c)
+			}
+			c = canonicalCycle(c)
+			if key := strings.Join(c, ">"); !seen[key] {
+				seen[key] = true
+				out = append(out, models.OwnershipCycle{Path: c})
+			}
+		}
+	}
+	return out, nil
+}
+
+// ConsolidatedGroup: a empresa e as controladas somadas (funcionários e cota PCD)
+func (s *Companies) ConsolidatedGroup(ctx context.Context, id string) (*models.GroupConsolidated, error) {
+	members, err := s.Descendants(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	members = append([]models.GroupMember{{Company: *c, Depth: 0, Percentual: 100}}, members...)
+
+	out := &models.GroupConsolidated{CompanyID: id, Companies: len(members), Members: members}
+	for _, m := range members {
+		contratados := 0
+		if m.Company.NumeroPCDContratados != nil {
+			contratados = *m.Company.NumeroPCDContratados
+		}
+		out.NumeroFuncionarios += m.Company.NumeroFuncionarios
+		out.NumeroMinimoPCDExigidos += m.Company.NumeroMinimoPCDExigidos
+		out.NumeroPCDContratados += contratados
+		out.DeficitPCD += max(0, m.Company.NumeroMinimoPCDExigidos-contratados)
+	}
+	out.MinimoPCDConsolidado = utils.ComputeMinPCD(out.NumeroFuncionarios)
+	return out, nil
+}
+
+// grafo alcançável a partir de uma empresa, num sentido só
+type ownershipGraph struct {
+	down  bool
+	next  map[string][]models.Ownership // arestas que saem de cada empresa, no sentido da busca
+	depth map[string]int                // menor distância até a raiz (raiz = 0)
+	order []string                      // empresas na ordem da busca em largura, sem a raiz
+}
+
+// to: a empresa do outro lado da aresta, no sentido da busca
+func (g *ownershipGraph) to(o models.Ownership) string {
+	if g.down {
+		return o.ChildID
+	}
+	return o.ParentID
+}
+
+// walkOwnership: busca em largura a partir de root, descendo (controladas) ou subindo (controladoras)
+func (s *Companies) walkOwnership(ctx context.Context, root string, down bool) (*ownershipGraph, error) {
+	g := &ownershipGraph{down: down, next: map[string][]models.Ownership{}, depth: map[string]int{root: 0}}
+	frontier := []string{root}
+	for d := 1; len(frontier) > 0 && len(g.depth) < ownershipMaxCompanies; d++ {
+		var edges []models.Ownership
+		var err error
+		if down {
+			edges, err = s.Ownerships.ByParents(ctx, frontier)
+		} else {
+			edges, err = s.Ownerships.ByChildren(ctx, frontier)
+		}
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(edges, func(i, j int) bool { return g.to(edges[i]) < g.to(edges[j]) })
+
+		frontier = nil
+		for _, e := range edges {
+			from, to := e.ParentID, e.ChildID
+			if !down {
+				from, to = to, from
+			}
+			g.next[from] = append(g.next[from], e)
+			if _, seen := g.depth[to]; !seen {
+				g.depth[to] = d
+				g.order = append(g.order, to)
+				frontier = append(frontier, to)
+			}
+		}
+	}
+	return g, nil
+}
+
+// dfs: ordem topológica (ignorando as arestas que voltam para a pilha) e os ciclos
+// fechados por essas arestas, cada um começando e terminando na mesma empresa
+func (g *ownershipGraph) dfs(root string) (topo []string, cycles [][]string) {
+	const (
+		onStack = 1
+		done    = 2
+	)
+	state := map[string]int{}
+	var stack []string
+	var visit func(v string)
+	visit = func(v string) {
+		state[v] = onStack
+		stack = append(stack, v)
+		for _, e := range g.next[v] {
+			switch w := g.to(e); state[w] {
+			case 0:
+				visit(w)
+			case onStack:
+				i := slices.Index(stack, w)
+				cycles = append(cycles, append(slices.Clone(stack[i:]), w))
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[v] = done
+		topo = append(topo, v)
+	}
+	visit(root)
+	slices.Reverse(topo)
+	return topo, cycles
+}
+
+// effective: participação efetiva (0 a 1) da raiz em cada empresa, somando o produto
+// dos percentuais por todos os caminhos; arestas que fecham ciclo não entram na conta
+func (g *ownershipGraph) effective(topo []string) map[string]float64 {
+	pos := make(map[string]int, len(topo))
+	for i, v := range topo {
+		pos[v] = i
+	}
+	eff := map[string]float64{topo[0]: 1}
+	for _, v := range topo {
+		for _, e := range g.next[v] {
+			if w := g.to(e); pos[w] > pos[v] {
+				eff[w] += eff[v] * e.Percentual / 100
+			}
+		}
+	}
+	return eff
+}
+
+// canonicalCycle: mesmo ciclo, começando pela empresa de menor id (A>B>C>A == B>C>A>B)
+func canonicalCycle(c []string) []string {
+	ring := c[:len(c)-1]
+	i := slices.Index(ring, slices.Min(ring))
+	out := append(slices.Clone(ring[i:]), ring[:i]...)
+	return append(out, out[0])
+}
+
+func checkOwnershipShare(ctx context.Context, repo OwnershipRepository, o *models.Ownership) error {
+	total, err := repo.ShareTotal(ctx, o.ChildID, o.ParentID)
+	if err != nil {
+		return err
+	}
+	if total+o.Percentual > 100+1e-9 {
+		return &ShareExceededError{Available: math.Max(0, math.Round((100-total)*1e4)/1e4)}
+	}
+	return nil
+}
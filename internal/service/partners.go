@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Sócios e administradores da empresa (QSA). A soma de percentual_capital dos
+// sócios de uma empresa não passa de 100.
+
+type PartnerRepository interface {
+	List(ctx context.Context, companyID string, limit, skip int64) ([]models.Partner, error)
+	ByDocument(ctx context.Context, documento string, limit, skip int64) ([]models.Partner, error)
+	ShareTotal(ctx context.Context, companyID, exceptID string) (float64, error)
+	Get(ctx context.Context, companyID, id string) (*models.Partner, error)
+	Create(ctx context.Context, p *models.Partner) error
+	Replace(ctx context.Context, p *models.Partner) error
+	Delete(ctx context.Context, companyID, id string) error
+	DeleteByCompany(ctx context.Context, companyID string) error
+	MoveToCompany(ctx context.Context, from, to string) (moved int, dropped []models.Partner, err error)
+}
+
+var (
+	errPartnersDisabled = errors.New("partner repository not configured")
+
+	ErrUnknownQualification = errors.New("unknown partner qualification")
+)
+
+// ShareExceededError: o percentual informado faria o QSA passar de 100%
+type ShareExceededError struct {
+	Available float64 // quanto ainda cabe
+}
+
+func (e *ShareExceededError) Error() string {
+	return fmt.Sprintf("partner shares exceed 100%% (available: %v%%)", e.Available)
+}
+
+// Dados de um sócio (já validados pela porta de entrada)
+type PartnerInput struct {
+	Nome              string
+	Documento         string // CPF ou CNPJ, com ou sem máscara
+	Qualificacao      int
+	DataEntrada       *string
+	PercentualCapital *float64
+}
+
+func (in PartnerInput) partner(companyID string) (models.Partner, error) {
+	desc, ok := models.PartnerQualifications[in.Qualificacao]
+	if !ok {
+		return models.Partner{}, ErrUnknownQualification
+	}
+	doc := utils.SanitizeCNPJ(in.Documento)
+	return models.Partner{
+		CompanyID:             companyID,
+		Nome:                  in.Nome,
+		Documento:             doc,
+		TipoDocumento:         utils.DocumentType(doc),
+		Qualificacao:          in.Qualificacao,
+		QualificacaoDescricao: desc,
+		DataEntrada:           in.DataEntrada,
+		PercentualCapital:     in.PercentualCapital,
+	}, nil
+}
+
+func (s *Companies) partners(ctx context.Context, companyID string) (PartnerRepository, error) {
+	if s.Partners == nil {
+		return nil, errPartnersDisabled
+	}
+	if _, err := s.Get(ctx, companyID); err != nil {
+		return nil, err
+	}
+	return s.Partners, nil
+}
+
+func (s *Companies) ListPartners(ctx context.Context, companyID string, limit, skip int64) ([]models.Partner, error) {
+	repo, err := s.partners(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.List(ctx, companyID, limit, skip)
+}
+
+func (s *Companies) GetPartner(ctx context.Context, companyID, id string) (*models.Partner, error) {
+	repo, err := s.partners(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.Get(ctx, companyID, id)
+}
+
+func (s *Companies) CreatePartner(ctx context.Context, companyID string, in PartnerInput) (*models.Partner, error) {
+	repo, err := s.partners(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := in.partner(companyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkShare(ctx, repo, &p); err != nil {
+		return nil, err
+	}
+	if err := repo.Create(ctx, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *Companies) ReplacePartner(ctx context.Context, companyID, id string, in PartnerInput) (*models.Partner, error) {
+	repo, err := s.partners(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := repo.Get(ctx, companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	p, err := in.partner(companyID)
+	if err != nil {
+		return nil, err
+	}
+	p.ID, p.CreatedAt = current.ID, current.CreatedAt
+	if err := checkShare(ctx, repo, &p); err != nil {
+		return nil, err
+	}
+	if err := repo.Replace(ctx, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *Companies) DeletePartner(ctx context.Context, companyID, id string) error {
+	repo, err := s.partners(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	return repo.Delete(ctx, companyID, id)
+}
+
+// CompaniesOfPartner: consulta reversa, todas as empresas em que o CPF/CNPJ é sócio
+// (com a participação em cada uma). Participações de empresas já removidas são ignoradas.
+func (s *Companies) CompaniesOfPartner(ctx context.Context, documento string, limit, skip int64) ([]models.PartnerCompany, error) {
+	if s.Partners == nil {
+		return nil, errPartnersDisabled
+	}
+	list, err := s.Partners.ByDocument(ctx, utils.SanitizeCNPJ(documento), limit, skip)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.PartnerCompany, 0, len(list))
+	for _, p := range list {
+		c, err := s.Get(ctx, p.CompanyID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, models.PartnerCompany{Partner: p, Company: *c})
+	}
+	return out, nil
+}
+
+func checkShare(ctx context.Context, repo PartnerRepository, p *models.Partner) error {
+	if p.PercentualCapital == nil || *p.PercentualCapital == 0 {
+		return nil
+	}
+	total, err := repo.ShareTotal(ctx, p.CompanyID, p.ID)
+	if err != nil {
+		return err
+	}
+	// tolerância para somas em ponto flutuante (ex.: 33.33 + 33.33 + 33.34)
+	if total+*p.PercentualCapital > 100+1e-9 {
+		return &ShareExceededError{Available: math.Max(0, math.Round((100-total)*1e4)/1e4)}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Simulação "e se" da cota PCD: obrigação em cada ponto de uma série de
+// números de funcionários. Não lê nem grava empresas.
+
+var ErrUnknownPCDRule = errors.New("unknown pcd rule version")
+
+// MaxSimulatedHeadcount: teto de cada ponto (o mesmo dos headcounts em schema/pcd_simulate.json).
+// Com rate_percent e periods no máximo do schema a curva passa muito do int.
+const MaxSimulatedHeadcount = 10_000_000
+
+// Série informada (Headcounts) ou gerada por uma curva de crescimento (Growth); só uma das duas.
+type PCDSimulationInput struct {
+	Headcounts  []int
+	Growth      *PCDGrowth
+	RuleVersion string // "" = utils.PCDRuleDefault
+	CurrentPCD  int    // PCDs já contratadas
+}
+
+// Ponto k da curva: round(Start * (1 + RatePercent/100)^k) + Increment*k, k = 0..Periods,
+// limitado a 0..MaxSimulatedHeadcount
+type PCDGrowth struct {
+	Start       int
+	Periods     int
+	RatePercent float64
+	Increment   int
+}
+
+type PCDSimulation struct {
+	RuleVersion     string               `json:"rule_version"`
+	CurrentPCD      int                  `json:"current_pcd"`
+	Points          []PCDSimulationPoint `json:"points"`
+	PeakMinPCD      int                  `json:"peak_min_pcd"`     // maior obrigação da série
+	AdditionalHires int                  `json:"additional_hires"` // contratações para cumprir o pico
+}
+
+type PCDSimulationPoint struct {
+	Period          int                 `json:"period"` // posição na série (0 = primeiro ponto)
+	Headcount       int                 `json:"headcount"`
+	Band            string              `json:"band"`
+	Percent         float64             `json:"percent"`
+	MinPCD          int                 `json:"min_pcd"`
+	DeltaMinPCD     int                 `json:"delta_min_pcd"`    // em relação ao ponto anterior
+	AdditionalHires int                 `json:"additional_hires"` // max(0, min_pcd - current_pcd)
+	Crossings       []utils.PCDCrossing `json:"crossings,omitempty"`
+}
+
+func SimulatePCD(in PCDSimulationInput) (*PCDSimulation, error) {
+	version := in.RuleVersion
+	if version == "" {
+		version = utils.PCDRuleDefault
+	}
+	rule, ok := utils.PCDRules[version]
+	if !ok {
+		return nil, ErrUnknownPCDRule
+	}
+
+	headcounts := in.Headcounts
+	if in.Growth != nil {
+		headcounts = in.Growth.series()
+	}
+
+	sim := &PCDSimulation{RuleVersion: version, CurrentPCD: in.CurrentPCD, Points: make([]PCDSimulationPoint, len(headcounts))}
+	for i, n := range headcounts {
+		band := rule.BandFor(n)
+		p := PCDSimulationPoint{
+			Period:          i,
+			Headcount:       n,
+			Band:            band.Code,
+			Percent:         band.Percent,
+			MinPCD:          rule.MinPCD(n),
+			AdditionalHires: max(0, rule.MinPCD(n)-in.CurrentPCD),
+		}
+		if i > 0 {
+			prev := sim.Points[i-1]
+			p.DeltaMinPCD = p.MinPCD - prev.MinPCD
+			p.Crossings = rule.Crossings(prev.Headcount, n)
+		}
+		sim.Points[i] = p
+		sim.PeakMinPCD = max(sim.PeakMinPCD, p.MinPCD)
+	}
+	sim.AdditionalHires = max(0, sim.PeakMinPCD-in.CurrentPCD)
+	return sim, nil
+}
+
+func (g PCDGrowth) series() []int {
+	out := make([]int, g.Periods+1)
+	for k := range out {
+		// satura em float antes de converter: float -> int fora do intervalo não é definido
+		n := math.Round(float64(g.Start)*math.Pow(1+g.RatePercent/100, float64(k))) + float64(g.Increment*k)
+		out[k] = int(min(max(n, 0), MaxSimulatedHeadcount))
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/events"
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+	"github.com/Werneck0live/cadastro-empresa/internal/models"
+	"github.com/Werneck0live/cadastro-empresa/internal/utils"
+)
+
+// Evento de mudança na cota PCD: publicado (além do cadastro/edição) quando o
+// mínimo exigido muda ou o número de funcionários cruza um limite de faixa
+// (100, 201, 501, 1001). O corpo é JSON para o painel WS montar o alerta.
+
+type PCDThresholdEvent struct {
+	Type      string              `json:"type"` // "cota_pcd"
+	Message   string              `json:"message"`
+	CompanyID string              `json:"company_id"`
+	CNPJ      string              `json:"cnpj"`
+	Nome      string              `json:"nome"`
+	Before    PCDObligation       `json:"before"`
+	After     PCDObligation       `json:"after"`
+	Crossings []utils.PCDCrossing `json:"crossings,omitempty"`
+	Timestamp string              `json:"timestamp"`
+}
+
+// Obrigação PCD para um número de funcionários
+type PCDObligation struct {
+	Funcionarios int     `json:"funcionarios"`
+	Band         string  `json:"band"`
+	Percent      float64 `json:"percent"`
+	MinPCD       int     `json:"min_pcd"`
+}
+
+func pcdObligation(rule utils.PCDRule, funcionarios int) PCDObligation {
+	b := rule.BandFor(funcionarios)
+	return PCDObligation{Funcionarios: funcionarios, Band: b.Code, Percent: b.Percent, MinPCD: rule.MinPCD(funcionarios)}
+}
+
+// publishPCDChange compara a obrigação antes (before nil = empresa nova, 0 funcionários) e depois
+func (s *Companies) publishPCDChange(before, after *models.Company) {
+	if s.Pub == nil || after == nil {
+		return
+	}
+	from := 0
+	if before != nil {
+		from = before.NumeroFuncionarios
+	}
+	rule := utils.PCDRules[utils.PCDRuleDefault]
+	ev := PCDThresholdEvent{
+		Type:      events.ActionPCDThreshold,
+		CompanyID: after.ID,
+		CNPJ:      after.CNPJ,
+		Nome:      displayName(after),
+		Before:    pcdObligation(rule, from),
+		After:     pcdObligation(rule, after.NumeroFuncionarios),
+		Crossings: rule.Crossings(from, after.NumeroFuncionarios),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if ev.Before.MinPCD == ev.After.MinPCD && len(ev.Crossings) == 0 {
+		return
+	}
+
+	lang := s.eventLang()
+	ev.Message = i18n.T(lang, "event.pcd_threshold", ev.Nome,
+		ev.Before.Funcionarios, ev.After.Funcionarios, ev.Before.Band, ev.After.Band, ev.Before.MinPCD, ev.After.MinPCD)
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+
+	thresholds := make([]string, len(ev.Crossings))
+	for i, c := range ev.Crossings {
+		thresholds[i] = strconv.Itoa(c.Threshold)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_ = s.Pub.Publish(ctx, string(body), amqp.Table{
+		"action":      events.ActionPCDThreshold,
+		"company_id":  ev.CompanyID,
+		"cnpj":        ev.CNPJ,
+		"nome":        ev.Nome,
+		"lang":        string(lang),
+		"timestamp":   ev.Timestamp,
+		"old_band":    ev.Before.Band,
+		"new_band":    ev.After.Band,
+		"old_min_pcd": ev.Before.MinPCD,
+		"new_min_pcd": ev.After.MinPCD,
+		"thresholds":  strings.Join(thresholds, ","),
+	})
+}
@@ -2,23 +2,102 @@ package utils
 
 import "math"
 
-// computeMinPCD retorna o mínimo de PcDs exigidos pela Lei 8.213/91 (art. 93).
-// Regra: <100 -> 0; 100–200 -> 2%; 201–500 -> 3%; 501–1000 -> 4%; 1001+ -> 5%.
-// Arredondamento: sempre para cima (ceil) quando fracionar
-func ComputeMinPCD(total int) int {
-	if total < 100 {
+// Faixas da Lei 8.213/91 (art. 93) por número de funcionários.
+// Max == 0: sem limite superior.
+type PCDBand struct {
+	Code    string  `json:"code"`
+	Min     int     `json:"min"`
+	Max     int     `json:"max,omitempty"`
+	Percent float64 `json:"percent"`
+}
+
+var PCDBands = []PCDBand{
+	{Code: "0-99", Min: 0, Max: 99, Percent: 0},
+	{Code: "100-200", Min: 100, Max: 200, Percent: 0.02},
+	{Code: "201-500", Min: 201, Max: 500, Percent: 0.03},
+	{Code: "501-1000", Min: 501, Max: 1000, Percent: 0.04},
+	{Code: "1001+", Min: 1001, Percent: 0.05},
+}
+
+// Versões da regra da cota (simulações podem pedir uma versão específica).
+// Hoje só existe a redação vigente do art. 93; novas versões entram aqui.
+type PCDRule struct {
+	Version     string    `json:"version"`
+	Description string    `json:"description"`
+	Bands       []PCDBand `json:"bands"`
+}
+
+const PCDRuleDefault = "lei-8213-1991-art93"
+
+var PCDRules = map[string]PCDRule{
+	PCDRuleDefault: {
+		Version:     PCDRuleDefault,
+		Description: "Lei 8.213/91, art. 93",
+		Bands:       PCDBands,
+	},
+}
+
+// BandFor retorna a faixa de quem tem total funcionários.
+func (r PCDRule) BandFor(total int) PCDBand {
+	for i := len(r.Bands) - 1; i > 0; i-- {
+		if total >= r.Bands[i].Min {
+			return r.Bands[i]
+		}
+	}
+	return r.Bands[0]
+}
+
+// MinPCD: percentual da faixa, arredondado para cima.
+func (r PCDRule) MinPCD(total int) int {
+	p := r.BandFor(total).Percent
+	if p == 0 {
 		return 0
 	}
-	var p float64
+	return int(math.Ceil(float64(total) * p))
+}
+
+// Mudança de faixa entre dois números de funcionários
+type PCDCrossing struct {
+	Threshold int    `json:"threshold"` // início da faixa mais alta (100, 201, 501, 1001)
+	Direction string `json:"direction"` // up | down
+	FromBand  string `json:"from_band"`
+	ToBand    string `json:"to_band"`
+}
+
+const (
+	PCDCrossingUp   = "up"
+	PCDCrossingDown = "down"
+)
+
+// Crossings lista os limites de faixa atravessados de from para to, na ordem em que
+// são cruzados (ex.: 90 -> 600 cruza 100, 201 e 501). Vazio se a faixa não muda.
+func (r PCDRule) Crossings(from, to int) []PCDCrossing {
+	var out []PCDCrossing
 	switch {
-	case total <= 200:
-		p = 0.02
-	case total <= 500:
-		p = 0.03
-	case total <= 1000:
-		p = 0.04
-	default:
-		p = 0.05
+	case to > from:
+		for i := 1; i < len(r.Bands); i++ {
+			if b := r.Bands[i]; from < b.Min && b.Min <= to {
+				out = append(out, PCDCrossing{Threshold: b.Min, Direction: PCDCrossingUp, FromBand: r.Bands[i-1].Code, ToBand: b.Code})
+			}
+		}
+	case to < from:
+		for i := len(r.Bands) - 1; i > 0; i-- {
+			if b := r.Bands[i]; to < b.Min && b.Min <= from {
+				out = append(out, PCDCrossing{Threshold: b.Min, Direction: PCDCrossingDown, FromBand: b.Code, ToBand: r.Bands[i-1].Code})
+			}
+		}
 	}
-	return int(math.Ceil(float64(total) * p))
+	return out
+}
+
+// PCDBandFor retorna a faixa legal (regra vigente) de quem tem total funcionários.
+func PCDBandFor(total int) PCDBand {
+	return PCDRules[PCDRuleDefault].BandFor(total)
+}
+
+// computeMinPCD retorna o mínimo de PcDs exigidos pela Lei 8.213/91 (art. 93).
+// Regra: <100 -> 0; 100–200 -> 2%; 201–500 -> 3%; 501–1000 -> 4%; 1001+ -> 5%.
+// Arredondamento: sempre para cima (ceil) quando fracionar
+func ComputeMinPCD(total int) int {
+	return PCDRules[PCDRuleDefault].MinPCD(total)
 }
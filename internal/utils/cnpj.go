@@ -27,3 +27,12 @@ func ValidateCNPJ(cnpj string) bool {
 	}
 	return !allEq
 }
+
+// FormatCNPJ aplica a máscara 00.000.000/0000-00 (entrada só com dígitos).
+// Fora do tamanho esperado devolve a entrada sem mudança.
+func FormatCNPJ(cnpj string) string {
+	if len(cnpj) != 14 {
+		return cnpj
+	}
+	return cnpj[0:2] + "." + cnpj[2:5] + "." + cnpj[5:8] + "/" + cnpj[8:12] + "-" + cnpj[12:14]
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// ValidateEmail: um único endereço, sem nome ("Fulano <a@b.com>" não vale)
+// e com domínio contendo ponto.
+func ValidateEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" {
+		return false
+	}
+	at := strings.LastIndexByte(email, '@')
+	domain := email[at+1:]
+	return strings.Contains(domain, ".") && !strings.HasPrefix(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+// NormalizeEmail: e-mails são gravados em minúsculas (comparação sem caixa)
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// SanitizePhone: só os dígitos, sem o código do país (+55).
+// "+55 (11) 98765-4321" -> "11987654321"
+func SanitizePhone(phone string) string {
+	d := SanitizeCNPJ(phone)
+	if (len(d) == 12 || len(d) == 13) && strings.HasPrefix(d, "55") {
+		d = d[2:]
+	}
+	return d
+}
+
+// ValidatePhone: telefone brasileiro já sanitizado, DDD (11-99) + 8 dígitos
+// (fixo, começando em 2-5) ou 9 dígitos (celular, começando em 9).
+func ValidatePhone(phone string) bool {
+	if len(phone) != 10 && len(phone) != 11 {
+		return false
+	}
+	for i := 0; i < len(phone); i++ {
+		if phone[i] < '0' || phone[i] > '9' {
+			return false
+		}
+	}
+	if phone[0] == '0' || phone[1] == '0' {
+		return false
+	}
+	if len(phone) == 11 {
+		return phone[2] == '9'
+	}
+	return phone[2] >= '2' && phone[2] <= '5'
+}
+
+// FormatPhone aplica a máscara (11) 98765-4321 / (11) 3456-7890 (entrada sanitizada).
+func FormatPhone(phone string) string {
+	switch len(phone) {
+	case 10:
+		return "(" + phone[:2] + ") " + phone[2:6] + "-" + phone[6:]
+	case 11:
+		return "(" + phone[:2] + ") " + phone[2:7] + "-" + phone[7:]
+	}
+	return phone
+}
@@ -0,0 +1,52 @@
+package utils
+
+/*
+
+go test -run 'TestValidateEmail|TestPhone' -v ./internal/utils -count=1
+
+*/
+
+import "testing"
+
+func TestValidateEmail(t *testing.T) {
+	cases := map[string]bool{
+		"ana@acme.com.br":       true,
+		"ana.lima+rh@acme.com":  true,
+		"ana@acme":              false,
+		"ana@.com":              false,
+		"ana@acme.":             false,
+		"Ana <ana@acme.com>":    false,
+		"ana@acme.com, b@c.com": false,
+		"":                      false,
+	}
+	for email, want := range cases {
+		if got := ValidateEmail(email); got != want {
+			t.Errorf("ValidateEmail(%q) = %v, want %v", email, got, want)
+		}
+	}
+}
+
+func TestPhone(t *testing.T) {
+	cases := []struct {
+		in    string
+		clean string
+		valid bool
+	}{
+		{"+55 (11) 98765-4321", "11987654321", true},
+		{"(21) 3456-7890", "2134567890", true},
+		{"5521 3456-7890", "2134567890", true},
+		{"(11) 8765-4321", "1187654321", false}, // fixo não começa com 8
+		{"(11) 88765-4321", "11887654321", false},
+		{"(01) 3456-7890", "0134567890", false},
+		{"3456-7890", "34567890", false},
+	}
+	for _, tc := range cases {
+		clean := SanitizePhone(tc.in)
+		if clean != tc.clean || ValidatePhone(clean) != tc.valid {
+			t.Errorf("%q: SanitizePhone = %q, ValidatePhone = %v; want %q, %v", tc.in, clean, ValidatePhone(clean), tc.clean, tc.valid)
+		}
+	}
+	if got := FormatPhone("11987654321"); got != "(11) 98765-4321" {
+		t.Errorf("FormatPhone = %s", got)
+	}
+}
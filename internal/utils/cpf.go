@@ -0,0 +1,43 @@
+package utils
+
+// ValidateCPF: 11 dígitos (entrada sanitizada) com os dois dígitos verificadores.
+// Sequências repetidas (000.000.000-00, 111...) passam na conta, mas não são CPFs.
+func ValidateCPF(cpf string) bool {
+	if len(cpf) != 11 {
+		return false
+	}
+	allEq := true
+	for i := 0; i < 11; i++ {
+		if cpf[i] < '0' || cpf[i] > '9' {
+			return false
+		}
+		if cpf[i] != cpf[0] {
+			allEq = false
+		}
+	}
+	if allEq {
+		return false
+	}
+	return cpfDigit(cpf[:9]) == cpf[9] && cpfDigit(cpf[:10]) == cpf[10]
+}
+
+// cpfDigit: dígito verificador dos n primeiros dígitos (pesos n+1 .. 2)
+func cpfDigit(s string) byte {
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		sum += int(s[i]-'0') * (len(s) + 1 - i)
+	}
+	d := 11 - sum%11
+	if d >= 10 {
+		d = 0
+	}
+	return byte('0' + d)
+}
+
+// FormatCPF aplica a máscara 000.000.000-00 (entrada só com dígitos).
+func FormatCPF(cpf string) string {
+	if len(cpf) != 11 {
+		return cpf
+	}
+	return cpf[0:3] + "." + cpf[3:6] + "." + cpf[6:9] + "-" + cpf[9:11]
+}
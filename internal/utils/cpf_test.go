@@ -0,0 +1,29 @@
+package utils
+
+/*
+
+go test -run 'TestValidateCPF' -v ./internal/utils -count=1
+
+*/
+
+import "testing"
+
+func TestValidateCPF(t *testing.T) {
+	cases := map[string]bool{
+		"52998224725": true,
+		"11144477735": true,
+		"52998224724": false, // 2º dígito errado
+		"52998224715": false, // 1º dígito errado
+		"11111111111": false,
+		"5299822472":  false,
+		"5299822472a": false,
+	}
+	for cpf, want := range cases {
+		if got := ValidateCPF(cpf); got != want {
+			t.Errorf("ValidateCPF(%s) = %v, want %v", cpf, got, want)
+		}
+	}
+	if got := FormatCPF("52998224725"); got != "529.982.247-25" {
+		t.Errorf("FormatCPF = %s", got)
+	}
+}
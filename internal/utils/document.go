@@ -0,0 +1,49 @@
+package utils
+
+// ValidateCNPJCheckDigits: 14 dígitos (entrada sanitizada) com os dois dígitos
+// verificadores. Mais rígida que ValidateCNPJ (usada no cadastro de empresas).
+func ValidateCNPJCheckDigits(cnpj string) bool {
+	if !ValidateCNPJ(cnpj) {
+		return false
+	}
+	for i := 0; i < 14; i++ {
+		if cnpj[i] < '0' || cnpj[i] > '9' {
+			return false
+		}
+	}
+	return cnpjDigit(cnpj[:12]) == cnpj[12] && cnpjDigit(cnpj[:13]) == cnpj[13]
+}
+
+// cnpjDigit: dígito verificador dos n primeiros dígitos (pesos 2..9 da direita para a esquerda)
+func cnpjDigit(s string) byte {
+	sum, w := 0, 2
+	for i := len(s) - 1; i >= 0; i-- {
+		sum += int(s[i]-'0') * w
+		if w++; w > 9 {
+			w = 2
+		}
+	}
+	d := 11 - sum%11
+	if d >= 10 {
+		d = 0
+	}
+	return byte('0' + d)
+}
+
+// Tipo de documento (sócio pessoa física ou jurídica)
+const (
+	DocumentCPF  = "cpf"
+	DocumentCNPJ = "cnpj"
+)
+
+// DocumentType: "cpf" (11 dígitos) ou "cnpj" (14) se os dígitos verificadores
+// conferem; "" caso contrário. Entrada sanitizada.
+func DocumentType(doc string) string {
+	switch {
+	case len(doc) == 11 && ValidateCPF(doc):
+		return DocumentCPF
+	case len(doc) == 14 && ValidateCNPJCheckDigits(doc):
+		return DocumentCNPJ
+	}
+	return ""
+}
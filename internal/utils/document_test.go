@@ -0,0 +1,28 @@
+package utils
+
+/*
+
+go test -run 'TestDocumentType' -v ./internal/utils -count=1
+
+*/
+
+import "testing"
+
+func TestDocumentType(t *testing.T) {
+	cases := map[string]string{
+		"52998224725":    DocumentCPF,
+		"11222333000181": DocumentCNPJ,
+		"11444777000161": DocumentCNPJ,
+		"11222333000182": "", // 2º dígito errado
+		"11222333000191": "", // 1º dígito errado
+		"11111111111111": "",
+		"52998224724":    "",
+		"1122233300018":  "",
+		"":               "",
+	}
+	for doc, want := range cases {
+		if got := DocumentType(doc); got != want {
+			t.Errorf("DocumentType(%s) = %q, want %q", doc, got, want)
+		}
+	}
+}
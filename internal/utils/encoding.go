@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Negociação de conteúdo das respostas de sucesso pelo header Accept.
+// Erros continuam sempre em application/problem+json (RFC 7807).
+//
+// Formatos registrados por padrão: JSON (padrão sem Accept ou com */*),
+// XML, CSV (só listas) e MessagePack. Outros podem ser adicionados com RegisterEncoder.
+
+// Encoder escreve uma resposta num formato (media type).
+type Encoder interface {
+	ContentType() string
+	// CanEncode: false quando o formato não representa esse valor (ex.: CSV de um objeto)
+	CanEncode(v any) bool
+	Encode(w io.Writer, v any) error
+}
+
+type registeredEncoder struct {
+	mediaType string // type/subtype, minúsculo
+	enc       Encoder
+}
+
+// Encoders: registro media type -> Encoder; a ordem de registro desempata
+// quando o cliente aceita vários formatos com o mesmo peso (ex.: */*).
+type Encoders struct {
+	mu   sync.RWMutex
+	list []registeredEncoder
+}
+
+func (e *Encoders) Register(enc Encoder, mediaTypes ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, mt := range mediaTypes {
+		e.list = append(e.list, registeredEncoder{mediaType: strings.ToLower(mt), enc: enc})
+	}
+}
+
+// MediaTypes: media types registrados, na ordem de preferência
+func (e *Encoders) MediaTypes() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	out := make([]string, len(e.list))
+	for i, r := range e.list {
+		out[i] = r.mediaType
+	}
+	return out
+}
+
+// Negotiate escolhe o encoder para o Accept informado. v == nil só verifica
+// o media type (útil antes de executar a operação); com v, o encoder também
+// precisa conseguir representar o valor.
+func (e *Encoders) Negotiate(accept string, v any) (Encoder, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	ranges, excluded := parseAccept(accept)
+	for _, rng := range ranges {
+		for _, r := range e.list {
+			if !rng.matches(r.mediaType) || excluded[r.mediaType] {
+				continue
+			}
+			if v == nil || r.enc.CanEncode(v) {
+				return r.enc, true
+			}
+		}
+	}
+	return nil, false
+}
+
+var defaultEncoders = &Encoders{}
+
+func init() {
+	defaultEncoders.Register(JSONEncoder{}, "application/json")
+	defaultEncoders.Register(XMLEncoder{}, "application/xml")
+	defaultEncoders.Register(XMLEncoder{MediaType: "text/xml"}, "text/xml")
+	defaultEncoders.Register(CSVEncoder{}, "text/csv")
+	defaultEncoders.Register(MsgpackEncoder{}, "application/msgpack")
+	defaultEncoders.Register(MsgpackEncoder{MediaType: "application/vnd.msgpack"}, "application/vnd.msgpack")
+	defaultEncoders.Register(MsgpackEncoder{MediaType: "application/x-msgpack"}, "application/x-msgpack")
+}
+
+// RegisterEncoder adiciona um formato ao registro usado por WriteResponse.
+func RegisterEncoder(enc Encoder, mediaTypes ...string) {
+	defaultEncoders.Register(enc, mediaTypes...)
+}
+
+// Acceptable: o Accept da requisição aceita algum formato registrado
+// (a checagem final, que depende do valor, é feita em WriteResponse).
+func Acceptable(r *http.Request) bool {
+	_, ok := defaultEncoders.Negotiate(r.Header.Get("Accept"), nil)
+	return ok
+}
+
+// listShape e objectShape representam o formato da resposta antes de ela existir
+type shapeRow struct{}
+
+var (
+	listShape   = []shapeRow{}
+	objectShape = shapeRow{}
+)
+
+// AcceptableFor: como Acceptable, sabendo se a resposta será uma lista ou um objeto
+// (ex.: CSV só representa listas). Permite recusar o Accept antes de executar a operação.
+func AcceptableFor(r *http.Request, list bool) bool {
+	shape := any(objectShape)
+	if list {
+		shape = listShape
+	}
+	_, ok := defaultEncoders.Negotiate(r.Header.Get("Accept"), shape)
+	return ok
+}
+
+// WriteResponse escreve v no formato pedido pelo Accept; 406 se nenhum servir.
+func WriteResponse(w http.ResponseWriter, r *http.Request, code int, v any) {
+	w.Header().Add("Vary", "Accept")
+	enc, ok := defaultEncoders.Negotiate(r.Header.Get("Accept"), v)
+	if !ok {
+		NotAcceptable(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.WriteHeader(code)
+	_ = enc.Encode(w, v)
+}
+
+func NotAcceptable(w http.ResponseWriter, r *http.Request) {
+	p := NewProblem(http.StatusNotAcceptable, CodeNotAcceptable, "")
+	p.DetailArgs = []any{strings.Join(defaultEncoders.MediaTypes(), ", ")}
+	WriteProblem(w, r, p)
+}
+
+// ---------- Accept
+
+type mediaRange struct {
+	typ, sub string
+	q        float64
+}
+
+func (m mediaRange) matches(mediaType string) bool {
+	typ, sub, _ := strings.Cut(mediaType, "/")
+	return (m.typ == "*" || m.typ == typ) && (m.sub == "*" || m.sub == sub)
+}
+
+// parseAccept: faixas com q > 0, da maior para a menor preferência, e os
+// media types recusados explicitamente (q=0). Sem header = */*.
+// Em caso de empate, a faixa mais específica vence; depois, a ordem do header.
+func parseAccept(header string) ([]mediaRange, map[string]bool) {
+	if strings.TrimSpace(header) == "" {
+		return []mediaRange{{typ: "*", sub: "*", q: 1}}, nil
+	}
+	var out []mediaRange
+	excluded := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if mt == "*" { // "*" sozinho: alguns clientes antigos mandam
+			mt = "*/*"
+		}
+		typ, sub, ok := strings.Cut(mt, "/")
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(qs, 64); err == nil {
+				q = f
+			}
+		}
+		if q <= 0 {
+			if typ != "*" && sub != "*" {
+				excluded[mt] = true
+			}
+			continue
+		}
+		out = append(out, mediaRange{typ: typ, sub: sub, q: q})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].q != out[j].q {
+			return out[i].q > out[j].q
+		}
+		return specificity(out[i]) > specificity(out[j])
+	})
+	return out, excluded
+}
+
+func specificity(m mediaRange) int {
+	switch {
+	case m.typ == "*":
+		return 0
+	case m.sub == "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ---------- JSON
+
+type JSONEncoder struct{}
+
+func (JSONEncoder) ContentType() string { return "application/json" }
+func (JSONEncoder) CanEncode(any) bool  { return true }
+func (JSONEncoder) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CSVEncoder: só listas de structs (uma linha por item, cabeçalho com os nomes json).
+// Structs aninhadas viram colunas "pai.filho" (ex.: endereco.uf); datas em RFC 3339.
+type CSVEncoder struct{}
+
+// Tabular: respostas com envelope expõem a lista que vai para o CSV
+type Tabular interface {
+	Rows() any
+}
+
+func (CSVEncoder) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (CSVEncoder) CanEncode(v any) bool {
+	_, ok := csvRows(v)
+	return ok
+}
+
+func (CSVEncoder) Encode(w io.Writer, v any) error {
+	rows, ok := csvRows(v)
+	if !ok {
+		return fmt.Errorf("csv: %T is not a list", v)
+	}
+	cols := csvColumns(rows.Type().Elem(), "", nil)
+
+	cw := csv.NewWriter(w)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(cols))
+	for i := 0; i < rows.Len(); i++ {
+		item := rows.Index(i)
+		for j, c := range cols {
+			record[j] = csvCell(item, c.index)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvRows: o slice de structs (ou ponteiros para struct) a escrever
+func csvRows(v any) (reflect.Value, bool) {
+	if t, ok := v.(Tabular); ok {
+		v = t.Rows()
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return reflect.Value{}, false
+	}
+	if structType(rv.Type().Elem()) == nil {
+		return reflect.Value{}, false
+	}
+	return rv, true
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structType: o tipo struct de t (ou *t); nil se não for struct "de campos" (time.Time é valor)
+func structType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return nil
+	}
+	return t
+}
+
+type csvColumn struct {
+	name  string
+	index []int // caminho de campos a partir do item
+}
+
+func csvColumns(t reflect.Type, prefix string, path []int) []csvColumn {
+	st := structType(t)
+	var cols []csvColumn
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		idx := append(append([]int{}, path...), i)
+		if structType(f.Type) != nil {
+			cols = append(cols, csvColumns(f.Type, prefix+name+".", idx)...)
+			continue
+		}
+		cols = append(cols, csvColumn{name: prefix + name, index: idx})
+	}
+	return cols
+}
+
+func csvCell(v reflect.Value, index []int) string {
+	for _, i := range index {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return ""
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.UTC().Format(time.RFC3339)
+	case string:
+		return x
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if v.Len() == 0 {
+			return ""
+		}
+		b, _ := json.Marshal(v.Interface())
+		return string(b)
+	}
+	return fmt.Sprint(v.Interface())
+}
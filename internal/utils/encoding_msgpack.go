@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MsgpackEncoder: MessagePack com os mesmos nomes de campo do JSON (tags json);
+// datas no tipo timestamp do MessagePack.
+type MsgpackEncoder struct {
+	MediaType string // padrão application/msgpack
+}
+
+func (e MsgpackEncoder) ContentType() string {
+	if e.MediaType == "" {
+		return "application/msgpack"
+	}
+	return e.MediaType
+}
+
+func (MsgpackEncoder) CanEncode(any) bool { return true }
+
+func (MsgpackEncoder) Encode(w io.Writer, v any) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	enc.SetOmitEmpty(false)
+	return enc.Encode(v)
+}
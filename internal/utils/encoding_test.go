@@ -0,0 +1,156 @@
+package utils
+
+/*
+
+go test -run 'TestEncoding_' -v ./internal/utils -count=1
+
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type encAddress struct {
+	UF  string `json:"uf"`
+	CEP string `json:"cep,omitempty"`
+}
+
+type encItem struct {
+	ID        string      `json:"id"`
+	Nome      string      `json:"nome"`
+	Total     int         `json:"total"`
+	Endereco  *encAddress `json:"endereco"`
+	Interno   string      `json:"-"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type encEnvelope struct {
+	Data any `json:"data"`
+}
+
+func (e encEnvelope) Rows() any { return e.Data }
+
+func TestEncoding_Negotiate(t *testing.T) {
+	list := []encItem{{ID: "1"}}
+	obj := encItem{ID: "1"}
+
+	cases := []struct {
+		accept string
+		v      any
+		want   string // "" = 406
+	}{
+		{"", obj, "application/json"},
+		{"*/*", obj, "application/json"},
+		{"application/xml", obj, "application/xml; charset=utf-8"},
+		{"text/xml", obj, "text/xml; charset=utf-8"},
+		{"application/msgpack", obj, "application/msgpack"},
+		{"application/x-msgpack", obj, "application/x-msgpack"},
+		{"text/csv", list, "text/csv; charset=utf-8"},
+		{"text/csv", encEnvelope{Data: list}, "text/csv; charset=utf-8"},
+		{"text/csv", obj, ""},
+		{"text/csv", encEnvelope{Data: obj}, ""},
+		{"text/csv, application/json;q=0.5", obj, "application/json"},
+		{"application/json;q=0.2, application/xml", obj, "application/xml; charset=utf-8"},
+		{"text/*", list, "text/xml; charset=utf-8"},
+		{"application/json;q=0, */*", obj, "application/xml; charset=utf-8"},
+		{"text/html", obj, ""},
+		{"image/png, text/plain", list, ""},
+	}
+	for _, tc := range cases {
+		enc, ok := defaultEncoders.Negotiate(tc.accept, tc.v)
+		got := ""
+		if ok {
+			got = enc.ContentType()
+		}
+		if got != tc.want {
+			t.Errorf("Accept %q (%T): got %q, want %q", tc.accept, tc.v, got, tc.want)
+		}
+	}
+}
+
+func TestEncoding_WriteResponse_NotAcceptable(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/companies/1", nil)
+	req.Header.Set("Accept", "text/html")
+	rr := httptest.NewRecorder()
+
+	WriteResponse(rr, req, http.StatusOK, encItem{ID: "1"})
+
+	if rr.Code != http.StatusNotAcceptable {
+		t.Fatalf("status = %d, want 406", rr.Code)
+	}
+	var p Problem
+	if err := json.Unmarshal(rr.Body.Bytes(), &p); err != nil {
+		t.Fatalf("problem: %v", err)
+	}
+	if p.Code != CodeNotAcceptable || !strings.Contains(p.Detail, "text/csv") {
+		t.Fatalf("problem = %+v", p)
+	}
+	if rr.Header().Get("Vary") != "Accept" {
+		t.Fatalf("Vary = %q", rr.Header().Get("Vary"))
+	}
+}
+
+func TestEncoding_XML(t *testing.T) {
+	var buf bytes.Buffer
+	v := []encItem{{ID: "1", Nome: "A & B", Total: 2, Endereco: &encAddress{UF: "SP"}}, {ID: "2"}}
+	if err := (XMLEncoder{}).Encode(&buf, v); err != nil {
+		t.Fatal(err)
+	}
+	want := `<response><item><id>1</id><nome>A &amp; B</nome><total>2</total><endereco><uf>SP</uf></endereco><created_at>0001-01-01T00:00:00Z</created_at></item>` +
+		`<item><id>2</id><nome></nome><total>0</total><endereco></endereco><created_at>0001-01-01T00:00:00Z</created_at></item></response>`
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Fatalf("xml:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestEncoding_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	v := encEnvelope{Data: []encItem{
+		{ID: "1", Nome: "ACME, Ltda", Total: 2, Endereco: &encAddress{UF: "SP", CEP: "01000000"}, Interno: "x", CreatedAt: created},
+		{ID: "2", Nome: "Beta"},
+	}}
+	if err := (CSVEncoder{}).Encode(&buf, v); err != nil {
+		t.Fatal(err)
+	}
+	want := "id,nome,total,endereco.uf,endereco.cep,created_at\n" +
+		"1,\"ACME, Ltda\",2,SP,01000000,2025-01-02T03:04:05Z\n" +
+		"2,Beta,0,,,\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("csv:\n got %q\nwant %q", got, want)
+	}
+
+	// lista vazia: só o cabeçalho
+	buf.Reset()
+	if err := (CSVEncoder{}).Encode(&buf, []encItem{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "id,nome,total,endereco.uf,endereco.cep,created_at\n" {
+		t.Fatalf("csv vazio = %q", got)
+	}
+}
+
+func TestEncoding_Msgpack(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (MsgpackEncoder{}).Encode(&buf, encItem{ID: "1", Nome: "ACME", Total: 3, Interno: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := msgpack.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["id"] != "1" || got["nome"] != "ACME" {
+		t.Fatalf("msgpack = %v", got)
+	}
+	if _, ok := got["Interno"]; ok {
+		t.Fatalf("campo json:\"-\" serializado: %v", got)
+	}
+}
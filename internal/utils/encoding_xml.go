@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// XMLEncoder gera o XML a partir do JSON do valor, para manter os mesmos
+// nomes de campo (tags json) sem duplicar tags xml nos modelos:
+//
+//	{"id":"1","endereco":{"uf":"SP"}}  ->  <response><id>1</id><endereco><uf>SP</uf></endereco></response>
+//	[{"id":"1"},{"id":"2"}]            ->  <response><item><id>1</id></item><item><id>2</id></item></response>
+//
+// null vira elemento vazio.
+type XMLEncoder struct {
+	MediaType string // padrão application/xml
+}
+
+const (
+	xmlRoot = "response"
+	xmlItem = "item"
+)
+
+func (e XMLEncoder) ContentType() string {
+	if e.MediaType == "" {
+		return "application/xml; charset=utf-8"
+	}
+	return e.MediaType + "; charset=utf-8"
+}
+
+func (XMLEncoder) CanEncode(any) bool { return true }
+
+func (XMLEncoder) Encode(w io.Writer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	enc := xml.NewEncoder(w)
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if err := writeXMLValue(enc, dec, xmlRoot); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// writeXMLValue lê o próximo valor JSON (token a token, preservando a ordem dos campos)
+// e o escreve como o elemento <name>.
+func writeXMLValue(enc *xml.Encoder, dec *json.Decoder, name string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := writeXMLValue(enc, dec, kt.(string)); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for dec.More() {
+				if err := writeXMLValue(enc, dec, xmlItem); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err := dec.Token(); err != nil { // fecha } ou ]
+			return err
+		}
+	case nil:
+	case string:
+		if err := enc.EncodeToken(xml.CharData(t)); err != nil {
+			return err
+		}
+	case json.Number:
+		if err := enc.EncodeToken(xml.CharData(t.String())); err != nil {
+			return err
+		}
+	case bool:
+		s := "false"
+		if t {
+			s = "true"
+		}
+		if err := enc.EncodeToken(xml.CharData(s)); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// xmlName troca os caracteres que não valem num nome de elemento por "_"
+func xmlName(s string) string {
+	if s == "" {
+		return "_"
+	}
+	var b strings.Builder
+	for i, r := range s {
+		ok := r == '_' || unicode.IsLetter(r) || (i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'))
+		if !ok {
+			if i == 0 && unicode.IsDigit(r) {
+				b.WriteRune('_')
+				b.WriteRune(r)
+				continue
+			}
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
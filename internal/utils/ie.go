@@ -0,0 +1,398 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Inscrição Estadual (IE): cada UF tem tamanho e dígitos verificadores próprios
+// (roteiros de conferência do SINTEGRA). Empresas dispensadas informam "ISENTO".
+
+const IEIsento = "ISENTO"
+
+// UFs aceitas na inscrição estadual (as 26 UFs e o DF)
+var UFs = []string{
+	"AC", "AL", "AP", "AM", "BA", "CE", "DF", "ES", "GO", "MA", "MT", "MS", "MG", "PA",
+	"PB", "PR", "PE", "PI", "RJ", "RN", "RS", "RO", "RR", "SC", "SP", "SE", "TO",
+}
+
+// SanitizeIE remove a máscara. Mantém o "P" inicial do produtor rural de SP
+// e devolve "ISENTO" (maiúsculo) para inscrições dispensadas.
+func SanitizeIE(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == IEIsento {
+		return s
+	}
+	prefix := ""
+	if strings.HasPrefix(s, "P") {
+		prefix, s = "P", s[1:]
+	}
+	return prefix + SanitizeCNPJ(s)
+}
+
+// ValidateIE confere a IE (sanitizada) pelo algoritmo da UF. "ISENTO" é válida em qualquer UF.
+func ValidateIE(uf, ie string) bool {
+	if ie == IEIsento {
+		return ieValidators[strings.ToUpper(uf)] != nil
+	}
+	check, ok := ieValidators[strings.ToUpper(uf)]
+	if !ok || ie == "" {
+		return false
+	}
+	if strings.HasPrefix(ie, "P") && strings.ToUpper(uf) != "SP" {
+		return false
+	}
+	return check(ie)
+}
+
+var ieValidators = map[string]func(string) bool{
+	"AC": ieAC, "AL": ieAL, "AP": ieAP, "AM": ieAM, "BA": ieBA, "CE": ieMod11Simple, "DF": ieDF,
+	"ES": ieMod11Low, "GO": ieGO, "MA": ieMA, "MT": ieMT, "MS": ieMS, "MG": ieMG, "PA": iePA,
+	"PB": ieMod11Simple, "PR": iePR, "PE": iePE, "PI": ieMod11Simple, "RJ": ieRJ, "RN": ieRN, "RS": ieRS,
+	"RO": ieRO, "RR": ieRR, "SC": ieMod11Low, "SP": ieSP, "SE": ieMod11Simple, "TO": ieTO,
+}
+
+// ---------- auxiliares
+
+// weighted: soma dos dígitos de s multiplicados pelos pesos (mesmo tamanho)
+func weighted(s string, weights ...int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += int(s[i]-'0') * w
+	}
+	return sum
+}
+
+// desc: pesos n, n-1, ..., 2
+func desc(n int) []int {
+	out := make([]int, 0, n-1)
+	for w := n; w >= 2; w-- {
+		out = append(out, w)
+	}
+	return out
+}
+
+// dv11: 11 - resto, com resto 0 ou 1 valendo 0 (regra mais comum)
+func dv11(sum int) byte {
+	r := sum % 11
+	if r < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - r)
+}
+
+// dv11Cap: 11 - resto, com 10 e 11 valendo 0
+func dv11Cap(sum int) byte {
+	d := 11 - sum%11
+	if d >= 10 {
+		d = 0
+	}
+	return byte('0' + d)
+}
+
+func digitsOnly(s string, sizes ...int) bool {
+	ok := false
+	for _, n := range sizes {
+		if len(s) == n {
+			ok = true
+		}
+	}
+	if !ok {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ---------- UFs com o mesmo roteiro (9 dígitos, pesos 9..2)
+
+// CE, PB, PI, SE: 11 - resto; 10 ou 11 -> 0
+func ieMod11Simple(ie string) bool {
+	return digitsOnly(ie, 9) && dv11Cap(weighted(ie, desc(9)...)) == ie[8]
+}
+
+// ES, SC: resto 0 ou 1 -> 0
+func ieMod11Low(ie string) bool {
+	return digitsOnly(ie, 9) && dv11(weighted(ie, desc(9)...)) == ie[8]
+}
+
+// ---------- roteiros por UF
+
+// AC e DF: 13 dígitos, dois DVs (pesos 4,3,2,9..2 e 5,4,3,2,9..2)
+func ieTwoDV13(ie, prefix string) bool {
+	if !digitsOnly(ie, 13) || !strings.HasPrefix(ie, prefix) {
+		return false
+	}
+	w1 := append([]int{4, 3, 2}, desc(9)...)
+	w2 := append([]int{5, 4, 3, 2}, desc(9)...)
+	return dv11Cap(weighted(ie, w1...)) == ie[11] && dv11Cap(weighted(ie, w2...)) == ie[12]
+}
+
+func ieAC(ie string) bool { return ieTwoDV13(ie, "01") }
+
+func ieDF(ie string) bool { return ieTwoDV13(ie, "07") }
+
+// AL: 24 + tipo de empresa (0, 3, 5, 7 ou 8); (soma * 10) % 11, 10 -> 0
+func ieAL(ie string) bool {
+	if !digitsOnly(ie, 9) || !strings.HasPrefix(ie, "24") || !strings.ContainsRune("03578", rune(ie[2])) {
+		return false
+	}
+	d := weighted(ie, desc(9)...) * 10 % 11
+	if d == 10 {
+		d = 0
+	}
+	return byte('0'+d) == ie[8]
+}
+
+// AP: 03; p e d dependem da faixa da inscrição
+func ieAP(ie string) bool {
+	if !digitsOnly(ie, 9) || !strings.HasPrefix(ie, "03") {
+		return false
+	}
+	n, _ := strconv.Atoi(ie[:8])
+	p, d := 0, 0
+	switch {
+	case n >= 3000001 && n <= 3017000:
+		p, d = 5, 0
+	case n >= 3017001 && n <= 3019022:
+		p, d = 9, 1
+	}
+	dv := 11 - (p+weighted(ie, desc(9)...))%11
+	switch dv {
+	case 10:
+		dv = 0
+	case 11:
+		dv = d
+	}
+	return byte('0'+dv) == ie[8]
+}
+
+// AM: soma < 11 -> 11 - soma; senão resto 0 ou 1 -> 0
+func ieAM(ie string) bool {
+	if !digitsOnly(ie, 9) {
+		return false
+	}
+	sum := weighted(ie, desc(9)...)
+	if sum < 11 {
+		return byte('0'+11-sum) == ie[8]
+	}
+	return dv11(sum) == ie[8]
+}
+
+// BA: 8 ou 9 dígitos; módulo 10 ou 11 pelo 1º (8 dígitos) ou 2º (9 dígitos) dígito.
+// O 2º DV é calculado primeiro e entra no cálculo do 1º.
+func ieBA(ie string) bool {
+	if !digitsOnly(ie, 8, 9) {
+		return false
+	}
+	n := len(ie) - 2 // dígitos antes dos DVs
+	lead := ie[0]
+	if len(ie) == 9 {
+		lead = ie[1]
+	}
+	mod := 10
+	if strings.ContainsRune("679", rune(lead)) {
+		mod = 11
+	}
+	dv := func(sum int) byte {
+		r := sum % mod
+		if mod == 10 {
+			if r == 0 {
+				return '0'
+			}
+			return byte('0' + 10 - r)
+		}
+		return dv11(sum)
+	}
+	dv2 := dv(weighted(ie, desc(n+1)...))
+	dv1 := dv(weighted(ie[:n]+string(dv2), desc(n+2)...))
+	return dv1 == ie[n] && dv2 == ie[n+1]
+}
+
+// GO: 10, 11, 15 ou 20..29; resto 1 vale 1 na faixa 10103105..10119997
+func ieGO(ie string) bool {
+	if !digitsOnly(ie, 9) {
+		return false
+	}
+	if p := ie[:2]; p != "10" && p != "11" && p != "15" && p[0] != '2' {
+		return false
+	}
+	r := weighted(ie, desc(9)...) % 11
+	dv := 0
+	switch {
+	case r == 1:
+		if n, _ := strconv.Atoi(ie[:8]); n >= 10103105 && n <= 10119997 {
+			dv = 1
+		}
+	case r > 1:
+		dv = 11 - r
+	}
+	return byte('0'+dv) == ie[8]
+}
+
+// MA: 12
+func ieMA(ie string) bool {
+	return strings.HasPrefix(ie, "12") && ieMod11Low(ie)
+}
+
+// MT: 11 dígitos (inscrições antigas com menos dígitos são completadas com zeros)
+func ieMT(ie string) bool {
+	if len(ie) < 11 {
+		ie = strings.Repeat("0", 11-len(ie)) + ie
+	}
+	return digitsOnly(ie, 11) && dv11(weighted(ie, append([]int{3, 2}, desc(9)...)...)) == ie[10]
+}
+
+// MS: 28 ou 50; 11 - resto > 9 -> 0
+func ieMS(ie string) bool {
+	if !digitsOnly(ie, 9) || (!strings.HasPrefix(ie, "28") && !strings.HasPrefix(ie, "50")) {
+		return false
+	}
+	return dv11Cap(weighted(ie, desc(9)...)) == ie[8]
+}
+
+// MG: 13 dígitos. 1º DV: "0" inserido após o município, pesos 1,2 alternados
+// somando os algarismos dos produtos; 2º DV: pesos 3,2,11..2.
+func ieMG(ie string) bool {
+	if !digitsOnly(ie, 13) {
+		return false
+	}
+	base := ie[:3] + "0" + ie[3:11]
+	sum := 0
+	for i := 0; i < len(base); i++ {
+		p := int(base[i]-'0') * (1 + i%2)
+		sum += p/10 + p%10
+	}
+	dv1 := (10 - sum%10) % 10
+	if byte('0'+dv1) != ie[11] {
+		return false
+	}
+	return dv11(weighted(ie, append([]int{3, 2}, desc(11)...)...)) == ie[12]
+}
+
+// PA: 15 ou 75..79
+func iePA(ie string) bool {
+	if !digitsOnly(ie, 9) {
+		return false
+	}
+	if p := ie[:2]; p != "15" && (p < "75" || p > "79") {
+		return false
+	}
+	return dv11(weighted(ie, desc(9)...)) == ie[8]
+}
+
+// PR: 10 dígitos, dois DVs (pesos 3,2,7..2 e 4,3,2,7..2)
+func iePR(ie string) bool {
+	if !digitsOnly(ie, 10) {
+		return false
+	}
+	return dv11(weighted(ie, append([]int{3, 2}, desc(7)...)...)) == ie[8] &&
+		dv11(weighted(ie, append([]int{4, 3, 2}, desc(7)...)...)) == ie[9]
+}
+
+// PE: 9 dígitos (e-Fisco, dois DVs) ou 14 dígitos (formato antigo, 11 - resto > 9 -> -10)
+func iePE(ie string) bool {
+	if digitsOnly(ie, 9) {
+		return dv11(weighted(ie, desc(8)...)) == ie[7] && dv11(weighted(ie, desc(9)...)) == ie[8]
+	}
+	if !digitsOnly(ie, 14) {
+		return false
+	}
+	dv := 11 - weighted(ie, append([]int{5, 4, 3, 2, 1}, desc(9)...)...)%11
+	if dv > 9 {
+		dv -= 10
+	}
+	return byte('0'+dv) == ie[13]
+}
+
+// RJ: 8 dígitos, pesos 2,7..2
+func ieRJ(ie string) bool {
+	return digitsOnly(ie, 8) && dv11(weighted(ie, append([]int{2}, desc(7)...)...)) == ie[7]
+}
+
+// RN: 20; 9 ou 10 dígitos; (soma * 10) % 11, 10 -> 0
+func ieRN(ie string) bool {
+	if !digitsOnly(ie, 9, 10) || !strings.HasPrefix(ie, "20") {
+		return false
+	}
+	n := len(ie)
+	d := weighted(ie, desc(n)...) * 10 % 11
+	if d == 10 {
+		d = 0
+	}
+	return byte('0'+d) == ie[n-1]
+}
+
+// RS: 10 dígitos, pesos 2,9..2
+func ieRS(ie string) bool {
+	return digitsOnly(ie, 10) && dv11Cap(weighted(ie, append([]int{2}, desc(9)...)...)) == ie[9]
+}
+
+// RO: 14 dígitos (desde 2000) ou 9 (antigo: 3 do município + 5 + DV); 11 - resto > 9 -> -10
+func ieRO(ie string) bool {
+	var digits string
+	var weights []int
+	switch {
+	case digitsOnly(ie, 14):
+		digits, weights = ie, append([]int{6, 5, 4, 3, 2}, desc(9)...)
+	case digitsOnly(ie, 9):
+		digits, weights = ie[3:], desc(6)
+	default:
+		return false
+	}
+	dv := 11 - weighted(digits, weights...)%11
+	if dv > 9 {
+		dv -= 10
+	}
+	return byte('0'+dv) == digits[len(digits)-1]
+}
+
+// RR: 24; pesos 1..8, módulo 9
+func ieRR(ie string) bool {
+	if !digitsOnly(ie, 9) || !strings.HasPrefix(ie, "24") {
+		return false
+	}
+	return byte('0'+weighted(ie, 1, 2, 3, 4, 5, 6, 7, 8)%9) == ie[8]
+}
+
+// SP: 12 dígitos (comércio/indústria, DVs na 9ª e 12ª posições) ou
+// P + 12 dígitos (produtor rural, DV na 9ª posição). DV = algarismo das unidades do resto.
+func ieSP(ie string) bool {
+	w1 := []int{1, 3, 4, 5, 6, 7, 8, 10}
+	if strings.HasPrefix(ie, "P") {
+		ie = ie[1:]
+		return digitsOnly(ie, 12) && byte('0'+weighted(ie, w1...)%11%10) == ie[8]
+	}
+	if !digitsOnly(ie, 12) {
+		return false
+	}
+	return byte('0'+weighted(ie, w1...)%11%10) == ie[8] &&
+		byte('0'+weighted(ie, 3, 2, 10, 9, 8, 7, 6, 5, 4, 3, 2)%11%10) == ie[11]
+}
+
+// TO: 9 dígitos ou 11 (antigo, com o tipo 01, 02, 03 ou 99 nas posições 3-4, fora do cálculo)
+func ieTO(ie string) bool {
+	if digitsOnly(ie, 11) {
+		if t := ie[2:4]; t != "01" && t != "02" && t != "03" && t != "99" {
+			return false
+		}
+		ie = ie[:2] + ie[4:]
+	}
+	return digitsOnly(ie, 9) && dv11(weighted(ie, desc(9)...)) == ie[8]
+}
+
+// SanitizeIM: inscrição municipal só com letras (maiúsculas) e dígitos.
+// O formato e o DV variam por município, então não há conferência.
+func SanitizeIM(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package utils
+
+/*
+
+go test -run 'TestValidateIE|TestSanitizeIE' -v ./internal/utils -count=1
+
+*/
+
+import (
+	"strings"
+	"testing"
+)
+
+// exemplos dos roteiros de conferência do SINTEGRA (um ou mais por UF)
+var validIEs = []struct {
+	uf, ie string
+}{
+	{"AC", "0100482300112"},
+	{"AL", "240000048"},
+	{"AP", "030123459"},
+	{"AM", "999999990"},
+	{"BA", "12345663"},  // 8 dígitos, módulo 10
+	{"BA", "61234557"},  // 8 dígitos, módulo 11
+	{"BA", "100000306"}, // 9 dígitos
+	{"CE", "060000015"},
+	{"DF", "0730000100109"},
+	{"ES", "999999990"},
+	{"GO", "109876547"},
+	{"MA", "120000385"},
+	{"MT", "00130000019"},
+	{"MS", "283000015"},
+	{"MG", "0623079040081"},
+	{"PA", "159999995"},
+	{"PB", "060000015"},
+	{"PR", "1234567850"},
+	{"PE", "032141840"},      // e-Fisco
+	{"PE", "18100100000049"}, // formato antigo
+	{"PI", "012345679"},
+	{"RJ", "99999993"},
+	{"RN", "200400401"},  // 9 dígitos
+	{"RN", "2000400400"}, // 10 dígitos
+	{"RS", "2243658792"},
+	{"RO", "00000000625213"}, // desde 2000
+	{"RO", "101625213"},      // formato antigo
+	{"RR", "240066281"},
+	{"SC", "251040852"},
+	{"SP", "110042490114"},
+	{"SP", "P011004243002"}, // produtor rural
+	{"SE", "271234563"},
+	{"TO", "29010227836"}, // formato antigo (tipo 01)
+	{"TO", "290227836"},
+}
+
+func TestValidateIE(t *testing.T) {
+	covered := map[string]bool{}
+	for _, tc := range validIEs {
+		covered[tc.uf] = true
+		if !ValidateIE(tc.uf, tc.ie) {
+			t.Errorf("ValidateIE(%s, %s) = false, want true", tc.uf, tc.ie)
+		}
+		// DV trocado (no produtor rural de SP o DV é o 9º dígito, depois do "P")
+		i := len(tc.ie) - 1
+		if strings.HasPrefix(tc.ie, "P") {
+			i = 9
+		}
+		bad := tc.ie[:i] + string('0'+(tc.ie[i]-'0'+1)%10) + tc.ie[i+1:]
+		if ValidateIE(tc.uf, bad) {
+			t.Errorf("ValidateIE(%s, %s) = true, want false", tc.uf, bad)
+		}
+	}
+	for _, uf := range UFs {
+		if !covered[uf] {
+			t.Errorf("UF %s sem caso de teste", uf)
+		}
+	}
+
+	invalid := []struct {
+		name, uf, ie string
+	}{
+		{"UF inexistente", "XX", "110042490114"},
+		{"UF de outra inscrição", "RJ", "110042490114"},
+		{"tamanho", "SP", "11004249011"},
+		{"prefixo AC", "AC", "0200482300112"},
+		{"prefixo AL", "AL", "250000048"},
+		{"tipo de empresa AL", "AL", "241000048"},
+		{"prefixo MA", "MA", "130000385"},
+		{"produtor rural fora de SP", "PR", "P011004243002"},
+		{"tipo TO", "TO", "29040227836"},
+		{"vazia", "SP", ""},
+	}
+	for _, tc := range invalid {
+		if ValidateIE(tc.uf, tc.ie) {
+			t.Errorf("%s: ValidateIE(%s, %s) = true, want false", tc.name, tc.uf, tc.ie)
+		}
+	}
+
+	if !ValidateIE("sp", IEIsento) || ValidateIE("XX", IEIsento) {
+		t.Error("ISENTO deve valer em qualquer UF existente")
+	}
+}
+
+func TestSanitizeIE(t *testing.T) {
+	cases := map[string]string{
+		"110.042.490.114":  "110042490114",
+		"p-01100424.3/002": "P011004243002",
+		" isento ":         IEIsento,
+		"062.307.904/0081": "0623079040081",
+		"0100482300112":    "0100482300112",
+	}
+	for in, want := range cases {
+		if got := SanitizeIE(in); got != want {
+			t.Errorf("SanitizeIE(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
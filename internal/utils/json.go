@@ -40,10 +40,6 @@ func DecodeStrict(r io.Reader, dst any) error {
 	return nil
 }
 
-func BadRequest(w http.ResponseWriter, msg string) {
-	WriteJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
-}
-
 // O tipo "err" custmoza as mensagens de erro "unknown field"
 func FormatUnknownFieldError(err error) string {
 	// Os erros do stdlib já vêm bons, mas se quiser customizar, faça aqui.
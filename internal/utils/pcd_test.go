@@ -22,3 +22,47 @@ func TestComputeMinPCD(t *testing.T) {
 		}
 	}
 }
+
+func TestPCDBandFor(t *testing.T) {
+	cases := []struct {
+		n    int
+		want string
+	}{
+		{0, "0-99"}, {99, "0-99"}, {100, "100-200"}, {200, "100-200"}, {201, "201-500"},
+		{500, "201-500"}, {501, "501-1000"}, {1000, "501-1000"}, {1001, "1001+"}, {50000, "1001+"},
+	}
+	for _, tc := range cases {
+		if got := PCDBandFor(tc.n).Code; got != tc.want {
+			t.Fatalf("n=%d want=%s got=%s", tc.n, tc.want, got)
+		}
+	}
+}
+
+func TestPCDRule_Crossings(t *testing.T) {
+	rule := PCDRules[PCDRuleDefault]
+	cases := []struct {
+		from, to int
+		want     []int
+		dir      string
+	}{
+		{99, 100, []int{100}, PCDCrossingUp},
+		{90, 600, []int{100, 201, 501}, PCDCrossingUp},
+		{1001, 200, []int{1001, 501, 201}, PCDCrossingDown},
+		{120, 180, nil, ""},
+		{200, 200, nil, ""},
+	}
+	for _, tc := range cases {
+		got := rule.Crossings(tc.from, tc.to)
+		if len(got) != len(tc.want) {
+			t.Fatalf("%d -> %d: %+v", tc.from, tc.to, got)
+		}
+		for i, c := range got {
+			if c.Threshold != tc.want[i] || c.Direction != tc.dir {
+				t.Fatalf("%d -> %d: %+v", tc.from, tc.to, got)
+			}
+		}
+	}
+	if c := rule.Crossings(99, 100)[0]; c.FromBand != "0-99" || c.ToBand != "100-200" {
+		t.Fatalf("faixas = %+v", c)
+	}
+}
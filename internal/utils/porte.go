@@ -0,0 +1,59 @@
+package utils
+
+// Porte da empresa, do menor para o maior
+const (
+	PorteME     = "me"  // microempresa
+	PorteEPP    = "epp" // empresa de pequeno porte
+	PorteMedio  = "medio"
+	PorteGrande = "grande"
+)
+
+var Portes = []string{PorteME, PorteEPP, PorteMedio, PorteGrande}
+
+// Limites (inclusivos) de cada porte por faturamento anual (R$) e por número de funcionários.
+// Acima do limite do médio, grande.
+type PorteThresholds struct {
+	MEMaxFaturamento     float64
+	EPPMaxFaturamento    float64
+	MedioMaxFaturamento  float64
+	MEMaxFuncionarios    int
+	EPPMaxFuncionarios   int
+	MedioMaxFuncionarios int
+}
+
+// Padrão: faturamento da LC 123/2006 (ME e EPP) e do BNDES (médio); funcionários
+// pela faixa do SEBRAE para comércio e serviços.
+var DefaultPorteThresholds = PorteThresholds{
+	MEMaxFaturamento:     360_000,
+	EPPMaxFaturamento:    4_800_000,
+	MedioMaxFaturamento:  300_000_000,
+	MEMaxFuncionarios:    9,
+	EPPMaxFuncionarios:   49,
+	MedioMaxFuncionarios: 99,
+}
+
+// Porte: o maior entre o porte pelo faturamento e o porte pelo número de funcionários.
+// Sem faturamento informado (nil), vale só o número de funcionários.
+func (t PorteThresholds) Porte(faturamento *float64, funcionarios int) string {
+	byHeadcount := porteIndex(float64(funcionarios),
+		float64(t.MEMaxFuncionarios), float64(t.EPPMaxFuncionarios), float64(t.MedioMaxFuncionarios))
+	if faturamento == nil {
+		return Portes[byHeadcount]
+	}
+	byRevenue := porteIndex(*faturamento, t.MEMaxFaturamento, t.EPPMaxFaturamento, t.MedioMaxFaturamento)
+	return Portes[max(byHeadcount, byRevenue)]
+}
+
+// IsZero: limites não configurados (quem usa cai no DefaultPorteThresholds)
+func (t PorteThresholds) IsZero() bool {
+	return t == PorteThresholds{}
+}
+
+func porteIndex(v float64, limits ...float64) int {
+	for i, l := range limits {
+		if v <= l {
+			return i
+		}
+	}
+	return len(limits)
+}
@@ -0,0 +1,50 @@
+package utils
+
+/*
+
+go test -run 'TestPorte' -v ./internal/utils -count=1
+
+*/
+
+import "testing"
+
+func TestPorte(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	cases := []struct {
+		name         string
+		faturamento  *float64
+		funcionarios int
+		want         string
+	}{
+		{"sem faturamento, poucos funcionários", nil, 5, PorteME},
+		{"sem faturamento, limite da ME", nil, 9, PorteME},
+		{"sem faturamento, EPP", nil, 10, PorteEPP},
+		{"sem faturamento, médio", nil, 99, PorteMedio},
+		{"sem faturamento, grande", nil, 100, PorteGrande},
+		{"faturamento no limite da ME", f(360_000), 3, PorteME},
+		{"faturamento de EPP", f(360_000.01), 3, PorteEPP},
+		{"faturamento de médio", f(4_800_001), 3, PorteMedio},
+		{"faturamento de grande", f(300_000_001), 3, PorteGrande},
+		{"funcionários pesam mais que o faturamento", f(100_000), 60, PorteMedio},
+		{"faturamento pesa mais que os funcionários", f(1_000_000), 2, PorteEPP},
+		{"faturamento zero", f(0), 0, PorteME},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DefaultPorteThresholds.Porte(tc.faturamento, tc.funcionarios); got != tc.want {
+				t.Fatalf("want=%s got=%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPorte_CustomThresholds(t *testing.T) {
+	th := DefaultPorteThresholds
+	th.MEMaxFuncionarios, th.EPPMaxFuncionarios, th.MedioMaxFuncionarios = 19, 99, 499 // faixas da indústria
+	if got := th.Porte(nil, 60); got != PorteEPP {
+		t.Fatalf("indústria com 60: got=%s", got)
+	}
+	if got := th.Porte(nil, 500); got != PorteGrande {
+		t.Fatalf("indústria com 500: got=%s", got)
+	}
+}
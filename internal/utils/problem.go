@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Werneck0live/cadastro-empresa/internal/i18n"
+)
+
+// Erros no formato RFC 7807 (application/problem+json).
+// "type" e "code" são estáveis: o cliente deve decidir pelo code, nunca pelo texto.
+// Os textos (title, detail, errors[].message) saem do catálogo i18n, no idioma do Accept-Language.
+const (
+	ProblemContentType = "application/problem+json"
+	ProblemTypePrefix  = "urn:cadastro-empresa:problem:"
+)
+
+// Codes dos problemas (nível da resposta)
+const (
+	CodeValidationFailed    = "validation_failed"
+	CodeInvalidJSON         = "invalid_json"
+	CodeBadRequest          = "bad_request"
+	CodeNotFound            = "not_found"
+	CodeMethodNotAllowed    = "method_not_allowed"
+	CodeNotAcceptable       = "not_acceptable"
+	CodeCNPJConflict        = "cnpj_conflict"
+	CodeCPFConflict         = "cpf_conflict"
+	CodePartnerConflict     = "partner_conflict"
+	CodeCustomFieldConflict = "custom_field_conflict"
+	CodeOwnershipConflict   = "ownership_conflict"
+	CodeOwnershipCycle      = "ownership_cycle"
+	CodeApprovalRequired    = "approval_required"
+	CodeApprovalSameUser    = "approval_same_user"
+	CodeChangeDecided       = "change_request_decided"
+	CodeChangeStale         = "change_request_stale"
+	CodePayloadTooLarge     = "payload_too_large"
+	CodeIdempotencyMismatch = "idempotency_key_mismatch"
+	CodeIdempotencyInFlight = "idempotency_request_in_progress"
+	CodeInternalError       = "internal_error"
+)
+
+// Codes dos erros por campo (errors[].code)
+const (
+	FieldRequired      = "required"
+	FieldRequiredOneOf = "required_one_of"
+	FieldInvalidCNPJ   = "invalid_cnpj"
+	FieldInvalidCPF    = "invalid_cpf"
+	FieldInvalidDate   = "invalid_date"
+	FieldInvalidEmail  = "invalid_email"
+	FieldInvalidPhone  = "invalid_phone"
+	FieldInvalidDoc    = "invalid_document"
+	FieldInvalidCNAE   = "invalid_cnae"
+	FieldInvalidIE     = "invalid_ie"
+	FieldMustBeNonNeg  = "must_be_non_negative"
+	FieldMismatch      = "mismatch"
+	FieldUnknown       = "unknown_field"
+	FieldInvalidType   = "invalid_type"
+	FieldTooLong       = "too_long"
+	FieldTooShort      = "too_short"
+	FieldTooSmall      = "too_small"
+	FieldTooLarge      = "too_large"
+	FieldInvalidFormat = "invalid_format"
+	FieldNotInEnum     = "not_in_enum"
+	FieldTooFewItems   = "too_few_items"
+	FieldTooManyItems  = "too_many_items"
+
+	FieldMutuallyExclusive = "mutually_exclusive"
+	FieldDuplicate         = "duplicate"
+	FieldShareExceeded     = "share_exceeded"
+	FieldNotInTable        = "not_in_table"
+	FieldContentMismatch   = "content_mismatch"
+	FieldSelfReference     = "self_reference"
+)
+
+// Message fica vazio nos validadores; é preenchido na escrita com
+// i18n "field.<code>", usando o nome do campo + Args como parâmetros.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Args    []any  `json:"-"`
+}
+
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Code     string       `json:"code"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+
+	// Sem Detail, usa a chave i18n "problem.<code>.detail" com estes parâmetros.
+	DetailArgs []any `json:"-"`
+}
+
+// detail vazio = texto padrão do catálogo para o code
+func NewProblem(status int, code, detail string) *Problem {
+	return &Problem{
+		Type:   ProblemTypePrefix + code,
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+func WriteProblem(w http.ResponseWriter, r *http.Request, p *Problem) {
+	lang := i18n.FromRequest(r)
+	localizeProblem(p, lang)
+	if p.Instance == "" && r != nil {
+		p.Instance = r.URL.Path
+		// atrás de um prefixo reescrito (/api/v2/...) mostra o path que o cliente pediu
+		if u, err := url.ParseRequestURI(r.RequestURI); err == nil && u.Path != "" {
+			p.Instance = u.Path
+		}
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("Content-Language", string(lang))
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+func localizeProblem(p *Problem, lang i18n.Lang) {
+	if p.Title == "" {
+		p.Title = i18n.T(lang, "problem."+p.Code)
+		if p.Title == "problem."+p.Code {
+			p.Title = http.StatusText(p.Status)
+		}
+	}
+	if p.Detail == "" {
+		key := "problem." + p.Code + ".detail"
+		if d := i18n.T(lang, key, p.DetailArgs...); d != key {
+			p.Detail = d
+		}
+	}
+	for i := range p.Errors {
+		e := &p.Errors[i]
+		if e.Message == "" {
+			e.Message = i18n.T(lang, "field."+e.Code, append([]any{e.Field}, e.Args...)...)
+		}
+	}
+}
+
+// BadRequest: 400 genérico; detailKey é uma chave do catálogo i18n.
+func BadRequest(w http.ResponseWriter, r *http.Request, detailKey string) {
+	WriteProblem(w, r, NewProblem(http.StatusBadRequest, CodeBadRequest, i18n.T(i18n.FromRequest(r), detailKey)))
+}
+
+// ValidationFailed responde 400 listando TODOS os campos inválidos.
+func ValidationFailed(w http.ResponseWriter, r *http.Request, errs []FieldError) {
+	p := NewProblem(http.StatusBadRequest, CodeValidationFailed, "")
+	p.Errors = errs
+	WriteProblem(w, r, p)
+}
+
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	WriteProblem(w, r, NewProblem(http.StatusNotFound, CodeNotFound, ""))
+}
+
+func InternalError(w http.ResponseWriter, r *http.Request, err error) {
+	WriteProblem(w, r, NewProblem(http.StatusInternalServerError, CodeInternalError, err.Error()))
+}
+
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request, allow ...string) {
+	w.Header().Set("Allow", strings.Join(allow, ", "))
+	p := NewProblem(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "")
+	p.DetailArgs = []any{r.Method}
+	WriteProblem(w, r, p)
+}
+
+// InvalidJSON traduz os erros de DecodeStrict; campo desconhecido e tipo errado viram errors[].
+func InvalidJSON(w http.ResponseWriter, r *http.Request, err error) {
+	p := NewProblem(http.StatusBadRequest, CodeInvalidJSON, FormatUnknownFieldError(err))
+
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr):
+		p.Errors = []FieldError{{Field: typeErr.Field, Code: FieldInvalidType, Args: []any{typeErr.Type.String()}}}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		p.Errors = []FieldError{{Field: field, Code: FieldUnknown}}
+	}
+	WriteProblem(w, r, p)
+}
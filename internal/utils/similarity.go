@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Comparação aproximada de textos (busca de empresas duplicadas).
+
+var foldAccents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// FoldText: minúsculas, sem acentos, pontuação vira espaço e espaços repetidos
+// somem. Pontos e barras das siglas letra a letra são descartados ("S.A." e
+// "S/A" -> "sa"), assim como o ponto de milhar ("1.000" -> "1000").
+func FoldText(s string) string {
+	s = foldAccents.Replace(strings.ToLower(s))
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case (r == '.' || r == '/') && i > 0 && unicode.IsLetter(runes[i-1]) && (i == 1 || !unicode.IsLetter(runes[i-2])):
+			// sigla letra a letra: "s.a.", "s/a", "l.t.d.a."
+		case r == '.' && i > 0 && i+1 < len(runes) && unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]):
+			// milhar: "1.000"
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// EditDistance: distância de Damerau-Levenshtein (variante OSA) em runas; a troca
+// de dois caracteres vizinhos conta como uma edição, como a inserção, a remoção e a substituição.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// três linhas da matriz: a anterior à anterior (transposição), a anterior e a atual
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+// Similarity: 1 - distância de edição / tamanho do maior (0 a 1). Textos vazios: 0.
+func Similarity(a, b string) float64 {
+	n := max(len([]rune(a)), len([]rune(b)))
+	if n == 0 || a == "" || b == "" {
+		return 0
+	}
+	return 1 - float64(EditDistance(a, b))/float64(n)
+}
+
+// TokenSimilarity: coeficiente de Dice das palavras (ordem não importa). Vazios: 0.
+func TokenSimilarity(a, b string) float64 {
+	ta, tb := strings.Fields(a), strings.Fields(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	set := map[string]int{}
+	for _, t := range ta {
+		set[t]++
+	}
+	common := 0
+	for _, t := range tb {
+		if set[t] > 0 {
+			set[t]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(ta)+len(tb))
+}
@@ -0,0 +1,68 @@
+package utils
+
+/*
+
+go test -run 'TestFoldText|TestEditDistance|TestSimilarity' -v ./internal/utils -count=1
+
+*/
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFoldText(t *testing.T) {
+	cases := map[string]string{
+		"Padaria São João LTDA.":     "padaria sao joao ltda",
+		"ACME  S.A.":                 "acme sa",
+		"Acme S/A - Filial":          "acme sa filial",
+		"Av. Paulista, 1.000 - Bela": "av paulista 1000 bela",
+		"  Comércio & Indústria ":    "comercio industria",
+		"Av.Paulista":                "av paulista",
+		"":                           "",
+	}
+	for in, want := range cases {
+		if got := FoldText(in); got != want {
+			t.Errorf("FoldText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"11222333000181", "11222333000181", 0},
+		{"11222333000181", "11222333000182", 1}, // dígito trocado
+		{"11222333000181", "11223233000181", 1}, // vizinhos invertidos
+		{"11222333000181", "1122233300018", 1},  // dígito a menos
+		{"11222333000181", "99888777000166", 10},
+		{"", "abc", 3},
+		{"joão", "joao", 1},
+	}
+	for _, tc := range cases {
+		if got := EditDistance(tc.a, tc.b); got != tc.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
+	if got := Similarity("acme", "acme"); got != 1 {
+		t.Errorf("iguais = %v", got)
+	}
+	if got := Similarity("acme", "acne"); !near(got, 0.75) {
+		t.Errorf("uma troca em 4 = %v", got)
+	}
+	if got := Similarity("", ""); got != 0 {
+		t.Errorf("vazios = %v", got)
+	}
+	if got := TokenSimilarity("padaria sao joao", "sao joao padaria"); got != 1 {
+		t.Errorf("ordem trocada = %v", got)
+	}
+	if got := TokenSimilarity("padaria sao joao", "padaria sao jose"); !near(got, 2.0/3) {
+		t.Errorf("duas de três = %v", got)
+	}
+}